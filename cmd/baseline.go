@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/markers"
+	"github.com/igcodinap/manque-ai/pkg/review"
+	"github.com/igcodinap/manque-ai/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Record current findings as known issues",
+	Long: `Reviews local git changes and records every finding in a baseline file (.manque-baseline.json by default).
+Later reviews skip issues found in the baseline, so only new issues are reported.
+
+Issues are matched by their text, not their line. Deterministic findings such as lint,
+secret and breaking-change checks are suppressed reliably; findings written by the model
+are often worded differently on the next run and may be reported again.
+
+Run again to add new findings. Use --prune to also drop entries that are no longer found.`,
+	Run: runBaseline,
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.Flags().String("base", "main", "Base branch to compare against")
+	baselineCmd.Flags().String("head", "HEAD", "Head branch (changes source)")
+	baselineCmd.Flags().String("file", "", "Baseline file to write (default: BASELINE_FILE or .manque-baseline.json)")
+	baselineCmd.Flags().Bool("prune", false, "Remove baseline entries that are no longer found")
+}
+
+func runBaseline(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	internal.InitLogger(debug)
+
+	config, err := internal.LoadConfig()
+	if err != nil {
+		internal.Logger.Error("Failed to load configuration", "error", err)
+		return
+	}
+	config.SkipGitHubValidation = true
+	if err := config.Validate(); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		return
+	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		return
+	}
+	ai.SetMaxConcurrency(config.LLMMaxConcurrency)
+
+	base, _ := cmd.Flags().GetString("base")
+	head, _ := cmd.Flags().GetString("head")
+	path, _ := cmd.Flags().GetString("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+	if path == "" {
+		path = review.BaselinePath(config)
+	}
+
+	ctx, cancel := config.OperationContext()
+	diffContent, err := getLocalDiff(ctx, base, head, -1)
+	cancel()
+	if err != nil {
+		internal.Logger.Error("Failed to get git diff", "error", err)
+		return
+	}
+	if len(diffContent) == 0 {
+		fmt.Println("No changes detected between branches.")
+		return
+	}
+
+	engine, err := review.NewEngine(config)
+	if err != nil {
+		internal.Logger.Error("Failed to initialize engine", "error", err)
+		return
+	}
+	// Review without the existing baseline so already known issues are seen again
+	engine.Baseline = nil
+
+	internal.Logger.Info("Analyzing changes... (this may take a minute)")
+	_, result, err := engine.Review(diffContent)
+	if err != nil {
+		internal.Logger.Error("Review failed", "error", err)
+		return
+	}
+
+	baseline, err := state.LoadBaseline(path)
+	if err != nil {
+		internal.Logger.Error("Failed to load baseline", "error", err)
+		return
+	}
+
+	added, removed := updateBaseline(baseline, result.Comments, prune)
+	if err := baseline.Save(path); err != nil {
+		internal.Logger.Error("Failed to save baseline", "error", err)
+		return
+	}
+
+	fmt.Printf("Baseline %s: %d added, %d removed, %d total\n", path, added, removed, len(baseline.Entries))
+}
+
+// updateBaseline records the comments in the baseline and, when pruning, drops entries
+// that were not found again. It returns the number of entries added and removed.
+func updateBaseline(baseline *state.Baseline, comments []ai.Comment, prune bool) (added, removed int) {
+	current := make(map[string]bool)
+	for _, comment := range comments {
+		current[review.BaselineHash(comment)] = true
+		if baseline.Add(comment.File, comment.Label, comment.Header, comment.Content) {
+			added++
+		}
+	}
+
+	if prune {
+		removed = baseline.Prune(current)
+	}
+	return added, removed
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+func TestUpdateBaseline(t *testing.T) {
+	baseline := state.NewBaseline()
+	baseline.Add("old.go", "bug", "Fixed since", "Fixed since")
+
+	comments := []ai.Comment{
+		{File: "main.go", Label: "bug", Header: "Nil dereference", Content: "`cfg` may be nil here."},
+		{File: "main.go", Label: "bug", Header: "Possible nil pointer", Content: "cfg may be nil here"},
+	}
+
+	added, removed := updateBaseline(baseline, comments, false)
+	if added != 1 || removed != 0 || len(baseline.Entries) != 2 {
+		t.Errorf("Expected 1 added and old entry kept, got added=%d removed=%d entries=%+v", added, removed, baseline.Entries)
+	}
+
+	added, removed = updateBaseline(baseline, comments, true)
+	if added != 0 || removed != 1 || len(baseline.Entries) != 1 {
+		t.Errorf("Expected stale entry pruned, got added=%d removed=%d entries=%+v", added, removed, baseline.Entries)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/review"
+)
+
+// parsePRNumbers parses the --prs list, e.g. "10,12,15". Duplicates are reviewed once.
+func parsePRNumbers(list string) ([]int, error) {
+	var numbers []int
+	seen := make(map[int]bool)
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(field), "#"))
+		if field == "" {
+			continue
+		}
+		number, err := strconv.Atoi(field)
+		if err != nil || number <= 0 {
+			return nil, fmt.Errorf("invalid PR number %q in --prs", field)
+		}
+		if !seen[number] {
+			seen[number] = true
+			numbers = append(numbers, number)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("--prs lists no PR numbers")
+	}
+	return numbers, nil
+}
+
+// reviewBatch reviews each PR in turn, printing one status line per PR to out. Failures don't
+// stop the batch; an error is returned only when every PR failed.
+func reviewBatch(numbers []int, reviewOne func(number int) (string, error), out io.Writer) error {
+	failed := 0
+	for _, number := range numbers {
+		outcome, err := reviewOne(number)
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "PR #%d: failed: %v\n", number, err)
+			continue
+		}
+		fmt.Fprintf(out, "PR #%d: %s\n", number, outcome)
+	}
+
+	fmt.Fprintf(out, "Reviewed %d/%d PRs\n", len(numbers)-failed, len(numbers))
+	if failed == len(numbers) {
+		return fmt.Errorf("all %d PR reviews failed", failed)
+	}
+	return nil
+}
+
+// batchDryRunOutput returns the dry run file for one PR of a batch, e.g.
+// manque-ai-dry-run-12.json, so each PR's results are kept
+func batchDryRunOutput(path string, number int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), number, ext)
+}
+
+// runBatchReview reviews the --prs of --repo with one engine and GitHub client, returning the
+// process exit code
+func runBatchReview(config *internal.Config, engine *review.Engine, githubClient *github.Client) int {
+	numbers, err := parsePRNumbers(prList)
+	if err != nil {
+		internal.Logger.Error("Invalid --prs", "error", err)
+		return 1
+	}
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		internal.Logger.Error("Invalid repository format. Use 'owner/repo'")
+		return 1
+	}
+
+	err = reviewBatch(numbers, func(number int) (string, error) {
+		prInfo, err := githubClient.GetPR(parts[0], parts[1], number)
+		if err != nil {
+			return "", fmt.Errorf("failed to get PR: %w", err)
+		}
+		// The client is shared by the batch, so cached comments don't outlive their PR
+		defer githubClient.InvalidateCommentCache(number)
+
+		var client ReviewClient = githubClient
+		if dryRun {
+			client = newDryRunClient(githubClient, batchDryRunOutput(dryRunOutput, number))
+		}
+
+		internal.Logger.Info("Reviewing PR", "number", prInfo.Number, "title", prInfo.Title)
+		return RunReviewForPR(client, engine, config, prInfo, reviewOptions{})
+	}, os.Stdout)
+	logRateLimit(githubClient)
+	if err != nil {
+		internal.Logger.Error("Batch review failed", "error", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePRNumbers(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []int
+		wantErr bool
+	}{
+		{list: "10,12,15", want: []int{10, 12, 15}},
+		{list: " 10, #12 ,,15,10 ", want: []int{10, 12, 15}},
+		{list: "7", want: []int{7}},
+		{list: "10,abc", wantErr: true},
+		{list: "10,-3", wantErr: true},
+		{list: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePRNumbers(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePRNumbers(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePRNumbers(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestReviewBatch_ContinuesOnErrors(t *testing.T) {
+	var reviewed []int
+	var out bytes.Buffer
+	err := reviewBatch([]int{10, 12, 15}, func(number int) (string, error) {
+		reviewed = append(reviewed, number)
+		if number == 12 {
+			return "", fmt.Errorf("not found")
+		}
+		return "reviewed", nil
+	}, &out)
+
+	if err != nil {
+		t.Errorf("Expected no error when some PRs succeed, got %v", err)
+	}
+	if !reflect.DeepEqual(reviewed, []int{10, 12, 15}) {
+		t.Errorf("Expected every PR to be reviewed, got %v", reviewed)
+	}
+	for _, line := range []string{"PR #10: reviewed", "PR #12: failed: not found", "PR #15: reviewed", "Reviewed 2/3 PRs"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected status line %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestReviewBatch_AllFailed(t *testing.T) {
+	var out bytes.Buffer
+	err := reviewBatch([]int{1, 2}, func(number int) (string, error) {
+		return "", fmt.Errorf("boom")
+	}, &out)
+
+	if err == nil {
+		t.Error("Expected an error when every PR fails")
+	}
+}
+
+func TestBatchDryRunOutput(t *testing.T) {
+	if got := batchDryRunOutput("manque-ai-dry-run.json", 12); got != "manque-ai-dry-run-12.json" {
+		t.Errorf("Expected the PR number before the extension, got %s", got)
+	}
+	if got := batchDryRunOutput("out/results", 3); got != "out/results-3" {
+		t.Errorf("Expected the PR number appended, got %s", got)
+	}
+}
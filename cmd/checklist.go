@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+// requiresFix reports whether a comment is a critical or warning issue the author must resolve
+func requiresFix(comment ai.Comment) bool {
+	return comment.Critical || strings.HasPrefix(comment.Header, "🔴") || strings.HasPrefix(comment.Header, "🟡")
+}
+
+// checklistItems converts the comments that require a fix into checklist items
+func checklistItems(comments []ai.Comment) []state.ChecklistItem {
+	var items []state.ChecklistItem
+	for _, comment := range comments {
+		if !requiresFix(comment) {
+			continue
+		}
+		items = append(items, state.ChecklistItem{
+			Hash:   state.ComputeCommentHash(comment.File, comment.StartLine, comment.EndLine, comment.Content),
+			File:   comment.File,
+			Line:   comment.StartLine,
+			Header: comment.Header,
+		})
+	}
+	return items
+}
+
+// updateChecklist checks off required fixes addressed by the reviewed diff on incremental
+// reviews, and records new ones when the review requests changes. It returns the number of
+// items checked off.
+func updateChecklist(session *state.Session, comments []ai.Comment, diffContent string, isIncremental, requestChanges bool) int {
+	items := checklistItems(comments)
+
+	addressed := 0
+	if isIncremental && len(session.Checklist) > 0 {
+		changedFiles := make(map[string]bool)
+		if files, err := diff.ParseGitDiff(diffContent); err == nil {
+			for _, file := range files {
+				changedFiles[file.Filename] = true
+			}
+		}
+		addressed = len(session.ResolveChecklist(changedFiles, items))
+		if addressed > 0 {
+			internal.Logger.Info("Required fixes addressed since last review", "count", addressed)
+		}
+	}
+
+	if requestChanges {
+		session.AddChecklistItems(items)
+	}
+	return addressed
+}
+
+// formatChecklist renders the session's required fixes as a GitHub task list, with
+// addressed items checked off
+func formatChecklist(session *state.Session) string {
+	if len(session.Checklist) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString("### Required Fixes\n\n")
+	for _, item := range session.Checklist {
+		mark := " "
+		if session.WasAddressed(item.Hash) {
+			mark = "x"
+		}
+		builder.WriteString(fmt.Sprintf("- [%s] %s:%d — %s\n", mark, item.File, item.Line, item.Header))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+func TestUpdateChecklist_ChecksOffAddressedItems(t *testing.T) {
+	internal.InitLogger(false)
+	session := &state.Session{PRNumber: 7, Repository: "owner/repo"}
+
+	first := []ai.Comment{
+		{File: "a.go", StartLine: 10, EndLine: 10, Header: "🔴 Nil dereference", Content: "x may be nil", Critical: true},
+		{File: "b.go", StartLine: 4, EndLine: 4, Header: "🟡 Unclosed file", Content: "close f"},
+		{File: "b.go", StartLine: 8, EndLine: 8, Header: "💅 Naming", Content: "rename"},
+	}
+	updateChecklist(session, first, "", false, true)
+	session.AddReviewRecord("sha1", nil, 40, len(first))
+
+	expected := "### Required Fixes\n\n" +
+		"- [ ] a.go:10 — 🔴 Nil dereference\n" +
+		"- [ ] b.go:4 — 🟡 Unclosed file"
+	if got := formatChecklist(session); got != expected {
+		t.Fatalf("Unexpected checklist:\n%s", got)
+	}
+
+	// The follow-up commit only touches a.go and fixes the nil dereference
+	incrementalDiff := `diff --git a/a.go b/a.go
+index 123..456 100644
+--- a/a.go
++++ b/a.go
+@@ -9,2 +9,4 @@ func run(x *T) {
+ 	defer done()
++	if x == nil {
++		return
++	}
+`
+	if addressed := updateChecklist(session, nil, incrementalDiff, true, false); addressed != 1 {
+		t.Fatalf("Expected 1 addressed item, got %d", addressed)
+	}
+
+	checklist := formatChecklist(session)
+	if !strings.Contains(checklist, "- [x] a.go:10 — 🔴 Nil dereference") {
+		t.Errorf("Expected addressed item to be checked off, got:\n%s", checklist)
+	}
+	if !strings.Contains(checklist, "- [ ] b.go:4 — 🟡 Unclosed file") {
+		t.Errorf("Expected untouched item to stay open, got:\n%s", checklist)
+	}
+}
+
+func TestPostResults_RequiredFixesChecklist(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 95}}
+	config := &internal.Config{AutoApproveThreshold: 90, AllowAutoApprove: true}
+	checklist := "### Required Fixes\n\n- [x] a.go:10 — 🔴 Nil dereference"
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", breakingReportTargets{}, checklist, true); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if publisher.output.Review == nil {
+		t.Fatal("Expected a review to be posted for the checklist")
+	}
+	if !strings.HasSuffix(publisher.output.Review.Body, checklist) {
+		t.Errorf("Expected checklist at the end of the review body, got %q", publisher.output.Review.Body)
+	}
+}
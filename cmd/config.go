@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	fileconfig "github.com/igcodinap/manque-ai/pkg/config"
 	"github.com/igcodinap/manque-ai/pkg/userconfig"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +15,7 @@ import (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage manque-ai configuration",
-	Long:  `Configure LLM provider, API key, and model for manque-ai.`,
+	Long:  `Configure LLM provider, API key, and model for manque-ai, and check .manque.yml files.`,
 }
 
 var configInitCmd = &cobra.Command{
@@ -163,6 +165,43 @@ var configClearCmd = &cobra.Command{
 	},
 }
 
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Check a .manque.yml file for unknown keys and wrong types",
+	Long: `Check a .manque.yml file for unknown keys and values of the wrong type.
+Without a path, the file is looked up from the current directory and its parents.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var path string
+		if len(args) == 1 {
+			path = args[0]
+		} else {
+			dir, _ := os.Getwd()
+			found, err := fileconfig.FindConfigFile(dir)
+			if err != nil {
+				fmt.Println("❌ No .manque.yml found")
+				os.Exit(1)
+			}
+			path = found
+		}
+
+		if err := fileconfig.ValidateFile(path); err != nil {
+			var validationErr *fileconfig.ValidationError
+			if !errors.As(err, &validationErr) {
+				fmt.Printf("❌ Failed to load %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			fmt.Printf("❌ %s has %d problem(s):\n", path, len(validationErr.Problems))
+			for _, problem := range validationErr.Problems {
+				fmt.Printf("   %s\n", problem)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ %s is valid\n", path)
+	},
+}
+
 func getDefaultModel(provider string) string {
 	switch provider {
 	case "openai":
@@ -184,4 +223,5 @@ func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configClearCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
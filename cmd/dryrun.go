@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+// reviewPublisher is the subset of the GitHub client used to publish review results.
+// It lets the Action path run against a local file in dry-run mode.
+type reviewPublisher interface {
+	UpdatePR(owner, repo string, number int, title, body *string) error
+	CreateOrUpdateComment(owner, repo string, number int, body string) error
+	CreateOrUpdateMarkedComment(owner, repo string, number int, marker, body string) error
+	UpdateMarkedComment(owner, repo string, number int, marker, body string) error
+	CreateReviewWithOptions(owner, repo string, number int, comments []*gh.DraftReviewComment, body *string, action string, opts github.CreateReviewOptions) error
+	labelPublisher
+}
+
+// dryRunComment is an inline review comment as it would have been posted
+type dryRunComment struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	Line      int    `json:"line"`
+	Body      string `json:"body"`
+}
+
+// dryRunReview is a PR review as it would have been posted
+type dryRunReview struct {
+	Action        string          `json:"action"`
+	Body          string          `json:"body"`
+	IsIncremental bool            `json:"is_incremental"`
+	Comments      []dryRunComment `json:"comments"`
+}
+
+// dryRunResult is everything the Action would have sent to GitHub
+type dryRunResult struct {
+	Repository string        `json:"repository"`
+	PRNumber   int           `json:"pr_number"`
+	Title      *string       `json:"title,omitempty"`
+	Body       *string       `json:"body,omitempty"`
+	Comment    *string       `json:"comment,omitempty"`
+	Review     *dryRunReview `json:"review,omitempty"`
+
+	// MarkedComments holds sticky comments other than the main bot comment, keyed by marker
+	MarkedComments map[string]string `json:"marked_comments,omitempty"`
+
+	Labels []string `json:"labels,omitempty"`
+
+	// StateMarker and SessionMarker are recorded even when the PR body isn't updated,
+	// so incremental review state can be checked
+	StateMarker   string `json:"state_marker,omitempty"`
+	SessionMarker string `json:"session_marker,omitempty"`
+}
+
+// dryRunPublisher records review results to a local JSON file instead of GitHub
+type dryRunPublisher struct {
+	path   string
+	output dryRunResult
+}
+
+func newDryRunPublisher(path string) *dryRunPublisher {
+	return &dryRunPublisher{path: path}
+}
+
+// dryRunClient reads review state from GitHub like a normal run, but records the results
+// to a file instead of publishing them
+type dryRunClient struct {
+	*dryRunPublisher
+	reader *github.Client
+}
+
+func newDryRunClient(reader *github.Client, path string) *dryRunClient {
+	return &dryRunClient{dryRunPublisher: newDryRunPublisher(path), reader: reader}
+}
+
+func (c *dryRunClient) FindStateComment(owner, repo string, number int) (*gh.IssueComment, error) {
+	return c.reader.FindStateComment(owner, repo, number)
+}
+
+func (c *dryRunClient) CompareCommits(owner, repo, base, head string) (string, error) {
+	return c.reader.CompareCommits(owner, repo, base, head)
+}
+
+func (c *dryRunClient) IsTeamMember(org, team, user string) (bool, error) {
+	return c.reader.IsTeamMember(org, team, user)
+}
+
+func (p *dryRunPublisher) UpdatePR(owner, repo string, number int, title, body *string) error {
+	p.setTarget(owner, repo, number)
+	if title != nil {
+		p.output.Title = title
+	}
+	if body != nil {
+		p.output.Body = body
+	}
+	return p.flush()
+}
+
+func (p *dryRunPublisher) CreateOrUpdateComment(owner, repo string, number int, body string) error {
+	p.setTarget(owner, repo, number)
+	p.output.Comment = &body
+	return p.flush()
+}
+
+func (p *dryRunPublisher) CreateOrUpdateMarkedComment(owner, repo string, number int, marker, body string) error {
+	p.setTarget(owner, repo, number)
+	if p.output.MarkedComments == nil {
+		p.output.MarkedComments = make(map[string]string)
+	}
+	p.output.MarkedComments[marker] = body
+	return p.flush()
+}
+
+// UpdateMarkedComment only updates sticky comments recorded earlier in the run, a dry run has
+// no PR to find older ones on
+func (p *dryRunPublisher) UpdateMarkedComment(owner, repo string, number int, marker, body string) error {
+	if _, ok := p.output.MarkedComments[marker]; !ok {
+		return nil
+	}
+	return p.CreateOrUpdateMarkedComment(owner, repo, number, marker, body)
+}
+
+func (p *dryRunPublisher) CreateReviewWithOptions(owner, repo string, number int, comments []*gh.DraftReviewComment, body *string, action string, opts github.CreateReviewOptions) error {
+	p.setTarget(owner, repo, number)
+
+	review := &dryRunReview{
+		Action:        action,
+		IsIncremental: opts.IsIncremental,
+		Comments:      []dryRunComment{},
+	}
+	if body != nil {
+		review.Body = *body
+	}
+	for _, comment := range comments {
+		review.Comments = append(review.Comments, dryRunComment{
+			Path:      comment.GetPath(),
+			StartLine: comment.GetStartLine(),
+			Line:      comment.GetLine(),
+			Body:      comment.GetBody(),
+		})
+	}
+	p.output.Review = review
+
+	return p.flush()
+}
+
+// ListLabels returns no labels, a dry run has no PR to read them from
+func (p *dryRunPublisher) ListLabels(owner, repo string, number int) ([]string, error) {
+	return nil, nil
+}
+
+func (p *dryRunPublisher) AddLabels(owner, repo string, number int, labels []string) error {
+	p.setTarget(owner, repo, number)
+	p.output.Labels = append(p.output.Labels, labels...)
+	return p.flush()
+}
+
+func (p *dryRunPublisher) RemoveLabel(owner, repo string, number int, label string) error {
+	return nil
+}
+
+// RecordMarkers stores the incremental review state and session markers
+func (p *dryRunPublisher) RecordMarkers(stateMarker, sessionMarker string) error {
+	p.output.StateMarker = stateMarker
+	p.output.SessionMarker = sessionMarker
+	return p.flush()
+}
+
+// Print renders everything that would have been posted as plain text sections
+func (p *dryRunPublisher) Print(w io.Writer) {
+	out := p.output
+	section := func(name, content string) {
+		fmt.Fprintf(w, "=== %s ===\n%s\n\n", name, content)
+	}
+
+	section("Target", fmt.Sprintf("%s#%d", out.Repository, out.PRNumber))
+	if out.Title != nil {
+		section("PR Title", *out.Title)
+	}
+	if out.Body != nil {
+		section("PR Body", *out.Body)
+	}
+	if out.Comment != nil {
+		section("Comment", *out.Comment)
+	}
+
+	markers := make([]string, 0, len(out.MarkedComments))
+	for marker := range out.MarkedComments {
+		markers = append(markers, marker)
+	}
+	sort.Strings(markers)
+	for _, marker := range markers {
+		section("Comment "+marker, out.MarkedComments[marker])
+	}
+
+	if len(out.Labels) > 0 {
+		section("Labels", strings.Join(out.Labels, ", "))
+	}
+
+	if out.Review != nil {
+		section(fmt.Sprintf("Review (%s, incremental: %t)", out.Review.Action, out.Review.IsIncremental), out.Review.Body)
+		for _, comment := range out.Review.Comments {
+			location := fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+			if comment.StartLine > 0 && comment.StartLine != comment.Line {
+				location = fmt.Sprintf("%s:%d-%d", comment.Path, comment.StartLine, comment.Line)
+			}
+			section("Inline Comment "+location, comment.Body)
+		}
+	}
+
+	section("State Marker", out.StateMarker)
+	section("Session Marker", out.SessionMarker)
+}
+
+func (p *dryRunPublisher) setTarget(owner, repo string, number int) {
+	p.output.Repository = fmt.Sprintf("%s/%s", owner, repo)
+	p.output.PRNumber = number
+}
+
+// flush rewrites the output file so partial results survive a later failure
+func (p *dryRunPublisher) flush() error {
+	data, err := json.MarshalIndent(p.output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dry-run output: %w", err)
+	}
+	if err := os.WriteFile(p.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write dry-run output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+func TestDryRunPublisher_WritesResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.json")
+	publisher := newDryRunPublisher(path)
+
+	title := "New title"
+	if err := publisher.UpdatePR("owner", "repo", 7, &title, nil); err != nil {
+		t.Fatalf("UpdatePR failed: %v", err)
+	}
+
+	body := "review body"
+	comments := []*gh.DraftReviewComment{{
+		Path:      gh.String("main.go"),
+		StartLine: gh.Int(3),
+		Line:      gh.Int(5),
+		Body:      gh.String("**Issue**\n\nDetails"),
+	}}
+	opts := github.CreateReviewOptions{IsIncremental: true}
+	if err := publisher.CreateReviewWithOptions("owner", "repo", 7, comments, &body, "COMMENT", opts); err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected dry-run output file: %v", err)
+	}
+
+	var result dryRunResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Invalid dry-run output: %v", err)
+	}
+
+	if result.Repository != "owner/repo" || result.PRNumber != 7 {
+		t.Errorf("Unexpected target: %s#%d", result.Repository, result.PRNumber)
+	}
+	if result.Title == nil || *result.Title != title {
+		t.Errorf("Expected title %q, got %v", title, result.Title)
+	}
+	if result.Body != nil {
+		t.Errorf("Expected no body update, got %q", *result.Body)
+	}
+	if result.Review == nil || result.Review.Action != "COMMENT" || !result.Review.IsIncremental {
+		t.Fatalf("Unexpected review: %+v", result.Review)
+	}
+	if len(result.Review.Comments) != 1 || result.Review.Comments[0].Path != "main.go" || result.Review.Comments[0].Line != 5 {
+		t.Errorf("Unexpected review comments: %+v", result.Review.Comments)
+	}
+}
+
+func TestLoadPRFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	eventPath := filepath.Join(dir, "event.json")
+	diffPath := filepath.Join(dir, "pr.diff")
+
+	event := `{"pull_request":{"number":12,"title":"Add feature","body":"Body","head":{"sha":"abcdef1234567"},"base":{"sha":"1234567abcdef"}},
+		"repository":{"full_name":"owner/repo","name":"repo","owner":{"login":"owner"}}}`
+	if err := os.WriteFile(eventPath, []byte(event), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(diffPath, []byte("diff --git a/x b/x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	prInfo, err := loadPRFromFiles(eventPath, diffPath)
+	if err != nil {
+		t.Fatalf("loadPRFromFiles failed: %v", err)
+	}
+
+	if prInfo.Number != 12 || prInfo.Repository != "owner/repo" || prInfo.HeadSHA != "abcdef1234567" {
+		t.Errorf("Unexpected PR info: %+v", prInfo)
+	}
+	if prInfo.BaseSHA != "1234567abcdef" {
+		t.Errorf("Expected base SHA from event, got %q", prInfo.BaseSHA)
+	}
+	if prInfo.Diff != "diff --git a/x b/x\n" {
+		t.Errorf("Expected diff from file, got %q", prInfo.Diff)
+	}
+}
+
+func TestDryRunPublisher_RecordsComment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.json")
+	publisher := newDryRunPublisher(path)
+
+	if err := publisher.CreateOrUpdateComment("owner", "repo", 7, diffUnavailableNote); err != nil {
+		t.Fatalf("CreateOrUpdateComment failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected dry-run output file: %v", err)
+	}
+	var result dryRunResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Invalid dry-run output: %v", err)
+	}
+	if result.Comment == nil || *result.Comment != diffUnavailableNote {
+		t.Errorf("Expected diff unavailable note, got %v", result.Comment)
+	}
+}
+
+func TestReconstructDiffLocally_MissingSHAs(t *testing.T) {
+	if diff := reconstructDiffLocally(context.Background(), &github.PRInfo{HeadSHA: "abc"}); diff != "" {
+		t.Errorf("Expected no diff without a base SHA, got %q", diff)
+	}
+}
+
+func TestRouteBreakingReport(t *testing.T) {
+	report := "### Breaking changes"
+
+	if got := routeBreakingReport("comment", report); got.Comment != report || got.Body != "" || got.Review != "" {
+		t.Errorf("Expected report routed to comment, got %+v", got)
+	}
+	if got := routeBreakingReport("review", report); got.Review != report || got.Body != "" {
+		t.Errorf("Expected report routed to review, got %+v", got)
+	}
+	if got := routeBreakingReport("", report); got.Body != report {
+		t.Errorf("Expected report routed to body by default, got %+v", got)
+	}
+	if got := routeBreakingReport("off", report); got != (breakingReportTargets{}) {
+		t.Errorf("Expected no report when disabled, got %+v", got)
+	}
+}
+
+func TestDryRunPublisher_RecordsMarkedComment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.json")
+	publisher := newDryRunPublisher(path)
+
+	if err := publisher.CreateOrUpdateMarkedComment("owner", "repo", 7, github.BreakingChangeMarker(), "first"); err != nil {
+		t.Fatalf("CreateOrUpdateMarkedComment failed: %v", err)
+	}
+	if err := publisher.CreateOrUpdateMarkedComment("owner", "repo", 7, github.BreakingChangeMarker(), "second"); err != nil {
+		t.Fatalf("CreateOrUpdateMarkedComment failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected dry-run output file: %v", err)
+	}
+	var result dryRunResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Invalid dry-run output: %v", err)
+	}
+	if result.Comment != nil {
+		t.Errorf("Expected main comment to be untouched, got %q", *result.Comment)
+	}
+	if got := result.MarkedComments[github.BreakingChangeMarker()]; got != "second" {
+		t.Errorf("Expected marked comment to be updated in place, got %q", got)
+	}
+}
+
+func TestPostResults_ClearsBreakingComment(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 50}}
+	config := &internal.Config{AutoApproveThreshold: 90}
+
+	// No earlier report, nothing to clear
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", routeBreakingReport("comment", ""), "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	if _, ok := publisher.output.MarkedComments[github.BreakingChangeMarker()]; ok {
+		t.Error("Expected no breaking change comment without breaking changes")
+	}
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", routeBreakingReport("comment", "### Breaking changes"), "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", routeBreakingReport("comment", ""), "", true); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	if got := publisher.output.MarkedComments[github.BreakingChangeMarker()]; got != noBreakingChangesNote {
+		t.Errorf("Expected the earlier report to be cleared, got %q", got)
+	}
+}
+
+func TestPostResults_AutoApproveDisabled(t *testing.T) {
+	internal.InitLogger(false)
+	path := filepath.Join(t.TempDir(), "result.json")
+	publisher := newDryRunPublisher(path)
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 100}}
+	config := &internal.Config{AutoApproveThreshold: 90, AllowAutoApprove: false}
+	breaking := breakingReportTargets{Review: "### Breaking changes"}
+
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breaking, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if publisher.output.Review == nil {
+		t.Fatal("Expected a review to be posted")
+	}
+	if publisher.output.Review.Action != string(ai.ReviewActionComment) {
+		t.Errorf("Expected approval to be downgraded to COMMENT, got %s", publisher.output.Review.Action)
+	}
+	if !strings.Contains(publisher.output.Review.Body, "Looks Good") {
+		t.Errorf("Expected positive summary to be kept, got %q", publisher.output.Review.Body)
+	}
+
+	config.AllowAutoApprove = true
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breaking, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	if publisher.output.Review.Action != string(ai.ReviewActionApprove) {
+		t.Errorf("Expected APPROVE when auto-approve is allowed, got %s", publisher.output.Review.Action)
+	}
+}
+
+func TestPostResults_RequireHumanApproval(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 100}}
+	config := &internal.Config{AutoApproveThreshold: 90, AllowAutoApprove: true, RequireHumanApproval: true}
+	breaking := breakingReportTargets{Review: "### Breaking changes"}
+
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breaking, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if publisher.output.Review == nil {
+		t.Fatal("Expected a review to be posted")
+	}
+	if publisher.output.Review.Action != string(ai.ReviewActionComment) {
+		t.Errorf("Expected approval to be downgraded to COMMENT, got %s", publisher.output.Review.Action)
+	}
+	if !strings.Contains(publisher.output.Review.Body, "Looks Good (human approval required)") {
+		t.Errorf("Expected the summary to say a human has to approve, got %q", publisher.output.Review.Body)
+	}
+
+	// Without it, a review with nothing to comment on is still submitted as an approval
+	config.RequireHumanApproval = false
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	if publisher.output.Review.Action != string(ai.ReviewActionApprove) {
+		t.Errorf("Expected APPROVE without comments, got %s", publisher.output.Review.Action)
+	}
+}
+
+func TestPostResults_WithheldApprovalWithoutFindings(t *testing.T) {
+	internal.InitLogger(false)
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 100}}
+
+	for _, config := range []*internal.Config{
+		{AutoApproveThreshold: 90, AllowAutoApprove: true, RequireHumanApproval: true},
+		{AutoApproveThreshold: 90, AllowAutoApprove: false},
+	} {
+		publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+		if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+			t.Fatalf("postResultsToGitHub failed: %v", err)
+		}
+		if publisher.output.Review != nil {
+			t.Errorf("Expected no review when the approval is withheld and there is nothing to report, got %+v", publisher.output.Review)
+		}
+	}
+}
+
+func TestPostResults_MovesCommentsOutsideDiffToBody(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7, Diff: `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
++var x = 1
+ func main() {}
+`}
+	result := &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "main.go", StartLine: 2, EndLine: 2, Header: "🟡 Inline", Content: "On an added line"},
+		{File: "main.go", StartLine: 40, EndLine: 42, Header: "🟡 Hallucinated", Content: "Not in the diff\nSecond line"},
+	}}
+	config := &internal.Config{AutoApproveThreshold: 90}
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	review := publisher.output.Review
+	if review == nil {
+		t.Fatal("Expected a review to be posted")
+	}
+	if len(review.Comments) != 1 || review.Comments[0].Line != 2 {
+		t.Fatalf("Expected only the comment in the diff to be posted inline, got %+v", review.Comments)
+	}
+	if !strings.Contains(review.Body, "### Comments Outside the Diff") ||
+		!strings.Contains(review.Body, "- `main.go:40` **🟡 Hallucinated**\n  Not in the diff\n  Second line") {
+		t.Errorf("Expected comment outside the diff in the review body, got %q", review.Body)
+	}
+	if !strings.Contains(review.Body, "Found 2 issues") {
+		t.Errorf("Expected both comments to be counted, got %q", review.Body)
+	}
+}
+
+func TestPostResults_CapsInlineComments(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	result := &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "a.go", StartLine: 1, EndLine: 1, Header: "💡 Rename variable"},
+		{File: "a.go", StartLine: 2, EndLine: 2, Header: "🔴 Nil dereference"},
+		{File: "a.go", StartLine: 3, EndLine: 3, Header: "💅 Typo"},
+	}}
+	config := &internal.Config{AutoApproveThreshold: 90, MaxComments: 1}
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	review := publisher.output.Review
+	if len(review.Comments) != 1 || review.Comments[0].Line != 2 {
+		t.Fatalf("Expected only the critical comment inline, got %+v", review.Comments)
+	}
+	if !strings.Contains(review.Body, "<summary>2 additional lower-priority findings</summary>") ||
+		!strings.Contains(review.Body, "- `a.go:1` **💡 Rename variable**") {
+		t.Errorf("Expected the remaining comments in a collapsible section, got %q", review.Body)
+	}
+}
+
+func TestPostResults_ListsUntestedChanges(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	result := &ai.ReviewResult{
+		Comments:      []ai.Comment{{File: "pkg/order/order.go", StartLine: 1, EndLine: 1, Header: "🟡 Unchecked error"}},
+		UntestedFiles: []string{"pkg/order/order.go", "web/cart.ts"},
+	}
+	config := &internal.Config{AutoApproveThreshold: 90}
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	body := publisher.output.Review.Body
+	if !strings.Contains(body, "### Untested Changes") || !strings.Contains(body, "\n- `pkg/order/order.go`\n- `web/cart.ts`") {
+		t.Errorf("Expected untested files in the review body, got %q", body)
+	}
+}
+
+func TestPostResults_NotesSkippedFiles(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	result := &ai.ReviewResult{SkippedFiles: []string{"db/seed.sql"}}
+	config := &internal.Config{AutoApproveThreshold: 90}
+
+	if err := postResultsToGitHub(publisher, prInfo, &ai.PRSummary{}, result, config, "", "", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if publisher.output.Review == nil || !strings.Contains(publisher.output.Review.Body, "1 file(s) were not reviewed") ||
+		!strings.Contains(publisher.output.Review.Body, "- `db/seed.sql`") {
+		t.Errorf("Expected a review noting the skipped files, got %+v", publisher.output.Review)
+	}
+}
+
+func TestDryRunPublisher_PrintsResults(t *testing.T) {
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	body := "walkthrough"
+	if err := publisher.UpdatePR("owner", "repo", 7, nil, &body); err != nil {
+		t.Fatalf("UpdatePR failed: %v", err)
+	}
+	reviewBody := "review body"
+	comments := []*gh.DraftReviewComment{{
+		Path:      gh.String("main.go"),
+		StartLine: gh.Int(3),
+		Line:      gh.Int(5),
+		Body:      gh.String("**Issue**"),
+	}}
+	if err := publisher.CreateReviewWithOptions("owner", "repo", 7, comments, &reviewBody, "COMMENT", github.CreateReviewOptions{}); err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+	if err := publisher.RecordMarkers("<!-- state -->", "<!-- session -->"); err != nil {
+		t.Fatalf("RecordMarkers failed: %v", err)
+	}
+
+	var out strings.Builder
+	publisher.Print(&out)
+	printed := out.String()
+
+	for _, want := range []string{
+		"=== Target ===\nowner/repo#7",
+		"=== PR Body ===\nwalkthrough",
+		"=== Review (COMMENT, incremental: false) ===\nreview body",
+		"=== Inline Comment main.go:3-5 ===\n**Issue**",
+		"=== State Marker ===\n<!-- state -->",
+		"=== Session Marker ===\n<!-- session -->",
+	} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, printed)
+		}
+	}
+	if strings.Contains(printed, "=== PR Title ===") {
+		t.Errorf("Expected no title section without a title update")
+	}
+}
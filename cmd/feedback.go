@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/commands"
+	"github.com/igcodinap/manque-ai/pkg/feedback"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/markers"
+	"github.com/igcodinap/manque-ai/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+var collectFeedbackCmd = &cobra.Command{
+	Use:   "collect-feedback",
+	Short: "Record 👍/👎 reactions on review comments as feedback",
+	Long: `Reads the reactions on the bot's review comments of a PR and records thumbs up and thumbs
+down as feedback in the feedback store (~/.manque-ai/feedback.jsonl by default).
+Reactions already recorded are skipped, so it is safe to run again.`,
+	Run: runCollectFeedback,
+}
+
+func init() {
+	rootCmd.AddCommand(collectFeedbackCmd)
+	collectFeedbackCmd.Flags().String("url", "", "GitHub PR URL to collect feedback from")
+	collectFeedbackCmd.Flags().String("repo", "", "Repository in format 'owner/repo'")
+	collectFeedbackCmd.Flags().Int("pr", 0, "PR number to collect feedback from")
+	collectFeedbackCmd.Flags().String("store", "", "Feedback store to write (default: ~/.manque-ai/feedback.jsonl)")
+}
+
+// feedbackReactions maps the reactions counted as feedback to whether they are positive
+var feedbackReactions = map[string]bool{
+	"+1": true,
+	"-1": false,
+}
+
+// reactionReader lists a PR's review comments and the reactions on them
+type reactionReader interface {
+	GetExistingCommentsByLocation(owner, repo string, number int) (map[string]*github.ExistingComment, error)
+	GetCommentReactions(owner, repo string, commentID int64) ([]github.Reaction, error)
+}
+
+// collectReactionFeedback records people's 👍/👎 on the bot's review comments in tracker,
+// skipping reactions it already holds. It returns the number of reactions recorded.
+func collectReactionFeedback(reader reactionReader, tracker *feedback.Tracker, owner, repo string, number int) (int, error) {
+	comments, err := reader.GetExistingCommentsByLocation(owner, repo, number)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, comment := range comments {
+		if !comment.IsBot {
+			continue
+		}
+		reactions, err := reader.GetCommentReactions(owner, repo, comment.ID)
+		if err != nil {
+			return recorded, err
+		}
+
+		hash := reviewCommentHash(comment)
+		for _, reaction := range reactions {
+			isPositive, ok := feedbackReactions[reaction.Content]
+			if !ok || reaction.IsBot || tracker.HasReaction(hash, reaction.User, isPositive) {
+				continue
+			}
+			tracker.RecordReaction(hash, comment.Path, comment.EndLine, reaction.User, isPositive)
+			recorded++
+		}
+	}
+	return recorded, nil
+}
+
+// reviewCommentHash identifies a posted review comment by the hash the review computed for
+// it: its content without the marker, header and suggestion, over its line range. GitHub
+// omits the start line of single-line comments.
+func reviewCommentHash(comment *github.ExistingComment) string {
+	start := comment.StartLine
+	if start == 0 {
+		start = comment.EndLine
+	}
+	content := commands.IssueContent(strings.TrimPrefix(comment.Body, github.BotCommentMarker()))
+	return state.ComputeCommentHash(comment.Path, start, comment.EndLine, content)
+}
+
+func runCollectFeedback(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	internal.InitLogger(debug)
+
+	config, err := internal.LoadConfig()
+	if err != nil {
+		internal.Logger.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	if config.GitHubToken == "" {
+		internal.Logger.Error("GitHub token is required (set GH_TOKEN or GITHUB_TOKEN)")
+		os.Exit(1)
+	}
+	githubClient := github.NewClient(config.GitHubToken, config.GitHubAPIURL)
+
+	url, _ := cmd.Flags().GetString("url")
+	repository, _ := cmd.Flags().GetString("repo")
+	number, _ := cmd.Flags().GetInt("pr")
+
+	var prInfo *github.PRInfo
+	if url != "" {
+		prInfo, err = githubClient.GetPRFromURL(url)
+	} else if repository != "" && number > 0 {
+		parts := strings.Split(repository, "/")
+		if len(parts) != 2 {
+			internal.Logger.Error("Invalid repository format. Use 'owner/repo'")
+			os.Exit(1)
+		}
+		prInfo, err = githubClient.GetPR(parts[0], parts[1], number)
+	} else if config.GitHubEventPath != "" {
+		prInfo, err = githubClient.GetPRFromEvent(config.GitHubEventPath)
+	} else {
+		internal.Logger.Error("Must provide --url, --repo with --pr, or GITHUB_EVENT_PATH")
+		os.Exit(1)
+	}
+	if err != nil {
+		internal.Logger.Error("Failed to get PR", "error", err)
+		os.Exit(1)
+	}
+
+	storePath, _ := cmd.Flags().GetString("store")
+	if storePath == "" {
+		storePath, err = feedback.DefaultStorePath()
+		if err != nil {
+			internal.Logger.Error("Failed to locate feedback store", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	tracker := feedback.NewTracker(prInfo.Repository, prInfo.Number)
+	tracker.Store = feedback.NewFileStore(storePath)
+	if err := tracker.LoadFromStore(prInfo.Repository); err != nil {
+		internal.Logger.Error("Failed to load feedback store", "error", err)
+		os.Exit(1)
+	}
+
+	repoParts := strings.Split(prInfo.Repository, "/")
+	recorded, err := collectReactionFeedback(githubClient, tracker, repoParts[0], repoParts[1], prInfo.Number)
+	// Keep what was collected before a failure
+	if flushErr := tracker.Flush(); flushErr != nil {
+		internal.Logger.Error("Failed to write feedback store", "error", flushErr)
+		os.Exit(1)
+	}
+	if err != nil {
+		internal.Logger.Error("Failed to collect reactions", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Recorded %d new reaction(s) from PR #%d in %s\n", recorded, prInfo.Number, storePath)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/feedback"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+type fakeReactionReader struct {
+	comments  map[string]*github.ExistingComment
+	reactions map[int64][]github.Reaction
+	err       error
+}
+
+func (f *fakeReactionReader) GetExistingCommentsByLocation(owner, repo string, number int) (map[string]*github.ExistingComment, error) {
+	return f.comments, nil
+}
+
+func (f *fakeReactionReader) GetCommentReactions(owner, repo string, commentID int64) ([]github.Reaction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.reactions[commentID], nil
+}
+
+func TestCollectReactionFeedback(t *testing.T) {
+	botComment := &github.ExistingComment{
+		ID: 1, Path: "main.go", EndLine: 12, IsBot: true,
+		Body: github.BotCommentMarker() + "\n**🐛 Bug**\n\nNil map write\n\n```suggestion\nm = map[string]int{}\n```",
+	}
+	reader := &fakeReactionReader{
+		comments: map[string]*github.ExistingComment{
+			"main.go:0:12": botComment,
+			"main.go:0:20": {ID: 2, Path: "main.go", EndLine: 20, Body: "Why this?"},
+		},
+		reactions: map[int64][]github.Reaction{
+			1: {
+				{ID: 10, User: "alice", Content: "+1"},
+				{ID: 11, User: "bob", Content: "-1"},
+				{ID: 12, User: "carol", Content: "heart"},
+				{ID: 13, User: "ci[bot]", IsBot: true, Content: "+1"},
+			},
+			2: {{ID: 20, User: "alice", Content: "+1"}},
+		},
+	}
+
+	tracker := feedback.NewTracker("owner/repo", 7)
+	recorded, err := collectReactionFeedback(reader, tracker, "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("collectReactionFeedback failed: %v", err)
+	}
+	if recorded != 2 {
+		t.Fatalf("Expected 2 reactions recorded, got %d", recorded)
+	}
+
+	// Entries carry the hash the review computed for the finding, so they match its comment
+	hash := state.ComputeCommentHash("main.go", 12, 12, "Nil map write")
+	byReactor := make(map[string]feedback.FeedbackEntry)
+	for _, entry := range tracker.Entries {
+		byReactor[entry.Reactor] = entry
+	}
+	alice := byReactor["alice"]
+	if alice.Type != feedback.FeedbackThumbsUp || alice.CommentHash != hash || alice.FilePath != "main.go" || alice.Line != 12 {
+		t.Errorf("Unexpected entry for alice's thumbs up: %+v", alice)
+	}
+	if bob := byReactor["bob"]; bob.Type != feedback.FeedbackThumbsDown {
+		t.Errorf("Expected bob's reaction to be a thumbs down, got %+v", bob)
+	}
+
+	// Collecting again records nothing new
+	recorded, err = collectReactionFeedback(reader, tracker, "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("collectReactionFeedback failed: %v", err)
+	}
+	if recorded != 0 || len(tracker.Entries) != 2 {
+		t.Errorf("Expected reactions to be recorded once, got %d new and %d total", recorded, len(tracker.Entries))
+	}
+}
+
+func TestCollectReactionFeedback_Error(t *testing.T) {
+	reader := &fakeReactionReader{
+		comments: map[string]*github.ExistingComment{
+			"main.go:0:12": {ID: 1, Path: "main.go", EndLine: 12, Body: "Nil map write", IsBot: true},
+		},
+		err: fmt.Errorf("boom"),
+	}
+
+	tracker := feedback.NewTracker("owner/repo", 7)
+	if _, err := collectReactionFeedback(reader, tracker, "owner", "repo", 7); err == nil {
+		t.Error("Expected the reactions error to be returned")
+	}
+}
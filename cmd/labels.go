@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+// Review outcomes labeled with AUTO_LABEL, renamed with REVIEW_LABELS
+const (
+	LabelApproved         = "approved"
+	LabelChangesRequested = "changes-requested"
+	LabelSecurity         = "security"
+	LabelBreaking         = "breaking-change"
+)
+
+// defaultReviewLabels are the label names applied for each review outcome
+var defaultReviewLabels = map[string]string{
+	LabelApproved:         "ai:approved",
+	LabelChangesRequested: "ai:changes-requested",
+	LabelSecurity:         "security",
+	LabelBreaking:         "breaking-change",
+}
+
+// managedLabelPrefix marks labels owned by the bot, which are removed once a review no
+// longer applies them. Other labels, like "security", may have been added by people.
+const managedLabelPrefix = "ai:"
+
+// labelPublisher applies labels to a PR
+type labelPublisher interface {
+	ListLabels(owner, repo string, number int) ([]string, error)
+	AddLabels(owner, repo string, number int, labels []string) error
+	RemoveLabel(owner, repo string, number int, label string) error
+}
+
+// reviewLabels returns the labels for a review's outcome: its action, security comments and
+// breaking changes. overrides rename outcomes and "off" drops one.
+func reviewLabels(overrides map[string]string, action ai.ReviewAction, comments []ai.Comment, breaking bool) []string {
+	var outcomes []string
+	switch action {
+	case ai.ReviewActionApprove:
+		outcomes = append(outcomes, LabelApproved)
+	case ai.ReviewActionRequestChanges:
+		outcomes = append(outcomes, LabelChangesRequested)
+	}
+	for _, comment := range comments {
+		if comment.Category() == ai.CategorySecurity {
+			outcomes = append(outcomes, LabelSecurity)
+			break
+		}
+	}
+	if breaking {
+		outcomes = append(outcomes, LabelBreaking)
+	}
+
+	var labels []string
+	for _, outcome := range outcomes {
+		name := defaultReviewLabels[outcome]
+		if override, ok := overrides[outcome]; ok {
+			name = override
+		}
+		if name != "" && name != "off" {
+			labels = append(labels, name)
+		}
+	}
+	return labels
+}
+
+// staleLabels returns the bot's labels on the PR that the latest review doesn't apply
+func staleLabels(current, labels []string) []string {
+	keep := make(map[string]bool)
+	for _, label := range labels {
+		keep[label] = true
+	}
+
+	var stale []string
+	for _, label := range current {
+		if strings.HasPrefix(label, managedLabelPrefix) && !keep[label] {
+			stale = append(stale, label)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// applyReviewLabels replaces the bot's labels on a PR with labels
+func applyReviewLabels(publisher labelPublisher, owner, repo string, number int, labels []string) error {
+	current, err := publisher.ListLabels(owner, repo, number)
+	if err != nil {
+		return err
+	}
+	for _, label := range staleLabels(current, labels) {
+		if err := publisher.RemoveLabel(owner, repo, number, label); err != nil {
+			return err
+		}
+	}
+	if err := publisher.AddLabels(owner, repo, number, labels); err != nil {
+		return err
+	}
+	internal.Logger.Debug("PR labeled", "labels", labels)
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+func TestReviewLabels(t *testing.T) {
+	security := []ai.Comment{{Label: "bug"}, {Label: "security", Critical: true}}
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		action    ai.ReviewAction
+		comments  []ai.Comment
+		breaking  bool
+		want      []string
+	}{
+		{"approved", nil, ai.ReviewActionApprove, nil, false, []string{"ai:approved"}},
+		{"comment only", nil, ai.ReviewActionComment, []ai.Comment{{Label: "bug"}}, false, nil},
+		{"security and breaking", nil, ai.ReviewActionRequestChanges, security, true, []string{"ai:changes-requested", "security", "breaking-change"}},
+		{"renamed and disabled", map[string]string{"approved": "lgtm", "breaking-change": "off"}, ai.ReviewActionApprove, nil, true, []string{"lgtm"}},
+	}
+
+	for _, tt := range tests {
+		if got := reviewLabels(tt.overrides, tt.action, tt.comments, tt.breaking); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestStaleLabels(t *testing.T) {
+	current := []string{"ai:changes-requested", "security", "ai:approved", "needs-review"}
+	got := staleLabels(current, []string{"ai:approved"})
+	if !reflect.DeepEqual(got, []string{"ai:changes-requested"}) {
+		t.Errorf("Expected only the outdated bot label to be stale, got %v", got)
+	}
+}
+
+func TestPostResults_AutoLabel(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{
+		Review:   ai.ReviewSummary{Score: 40},
+		Comments: []ai.Comment{{File: "main.go", StartLine: 1, EndLine: 1, Label: "security", Critical: true, Header: "🔴 Injection"}},
+	}
+	config := &internal.Config{AutoLabel: true, AutoApproveThreshold: 90, BlockOnCritical: true}
+	breaking := breakingReportTargets{Comment: "### Breaking changes"}
+
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "", "", breaking, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+	want := []string{"ai:changes-requested", "security", "breaking-change"}
+	if !reflect.DeepEqual(publisher.output.Labels, want) {
+		t.Errorf("Expected labels %v, got %v", want, publisher.output.Labels)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,8 +9,8 @@ import (
 
 	"github.com/igcodinap/manque-ai/internal"
 	"github.com/igcodinap/manque-ai/pkg/ai"
-	fileconfig "github.com/igcodinap/manque-ai/pkg/config"
 	"github.com/igcodinap/manque-ai/pkg/discovery"
+	"github.com/igcodinap/manque-ai/pkg/markers"
 	"github.com/igcodinap/manque-ai/pkg/review"
 	"github.com/spf13/cobra"
 )
@@ -17,27 +18,48 @@ import (
 var (
 	baseBranch string
 	headBranch string
+	sinceRef   string
 )
 
 var localCmd = &cobra.Command{
 	Use:   "local",
 	Short: "Run AI review locally on git changes",
-	Long:  `Analyzes changes between two git branches (or commits) and outputs an AI review to the terminal.`,
-	Run:   runLocalReview,
+	Long: `Analyzes changes between two git branches (or commits) and outputs an AI review to the terminal.
+
+Use --since to review the commits made after a given one, e.g. --since HEAD~3 for a stack of
+local commits before pushing.`,
+	Run: runLocalReview,
 }
 
 func init() {
 	rootCmd.AddCommand(localCmd)
 	localCmd.Flags().StringVar(&baseBranch, "base", "main", "Base branch to compare against")
 	localCmd.Flags().StringVar(&headBranch, "head", "HEAD", "Head branch (changes source)")
-	localCmd.Flags().Bool("mock", false, "Run with mock AI response (for testing UI)")
+	localCmd.Flags().StringVar(&sinceRef, "since", "", "Review changes after this commit (e.g. HEAD~3), diffing it directly against head")
+	localCmd.MarkFlagsMutuallyExclusive("base", "since")
+	localCmd.Flags().Bool("mock", false, "Review a sample diff with the mock LLM provider (for testing UI)")
 	localCmd.Flags().Bool("no-discover", false, "Disable auto-discovery of repo practices")
+	localCmd.Flags().Float64("temperature", 0, "Override the LLM sampling temperature for this run (0-2)")
+	localCmd.Flags().Int("max-tokens", 0, "Override the LLM max output tokens for this run")
+	localCmd.Flags().Int("context-lines", -1, "Lines of context around each change in the git diff (default: git's 3)")
+	localCmd.Flags().String("format", "text", "Output format: text or json")
+	localCmd.Flags().String("sarif", "", "Also write the review as SARIF 2.1.0 to this path, for GitHub code scanning")
 }
 
 func runLocalReview(cmd *cobra.Command, args []string) {
 	// 1. Initialize Logger
 	debug, _ := cmd.Flags().GetBool("debug")
-	internal.InitLogger(debug)
+	format, _ := cmd.Flags().GetString("format")
+	if format == "json" {
+		// Keep stdout for the report so it can be piped
+		internal.InitLoggerTo(debug, os.Stderr)
+	} else {
+		internal.InitLogger(debug)
+	}
+	if format != "text" && format != "json" {
+		internal.Logger.Error("Invalid --format, must be one of: text, json", "format", format)
+		return
+	}
 
 	// 2. Load Config
 	config, err := internal.LoadConfig()
@@ -48,36 +70,32 @@ func runLocalReview(cmd *cobra.Command, args []string) {
 
 	// For local review, GH_TOKEN is optional
 	config.SkipGitHubValidation = true
+	mock, err := cmd.Flags().GetBool("mock")
+	if err != nil {
+		internal.Logger.Warn("Could not parse --mock flag", "error", err)
+		mock = false
+	}
+	if mock {
+		config.LLMProvider = "mock"
+	}
+	if err := applySamplingFlags(cmd, config); err != nil {
+		internal.Logger.Error("Invalid flags", "error", err)
+		return
+	}
 	if err := config.Validate(); err != nil {
 		internal.Logger.Error("Invalid configuration", "error", err)
 		return
 	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		return
+	}
+
+	ai.SetMaxConcurrency(config.LLMMaxConcurrency)
 
 	// 2b. Load file-based config (.manque.yml)
-	cwd, err := os.Getwd()
-	if err == nil {
-		fileCfg, loadErr := fileconfig.LoadFromDirectory(cwd)
-		if loadErr != nil {
-			internal.Logger.Warn("Failed to load .manque.yml config", "error", loadErr)
-		} else {
-			// Merge file config into runtime config
-			if fileCfg.Review.AutoApproveThreshold > 0 {
-				config.AutoApproveThreshold = fileCfg.Review.AutoApproveThreshold
-			}
-			config.BlockOnCritical = fileCfg.Review.BlockOnCritical
-			config.IgnorePatterns = fileCfg.Ignore
-
-			// Convert path rules
-			config.PathRules = make(map[string]internal.PathRule)
-			for _, rule := range fileCfg.Rules {
-				config.PathRules[rule.Path] = internal.PathRule{
-					SeverityOverride: rule.SeverityOverride,
-					ExtraRules:       rule.ExtraRules,
-					Ignore:           rule.Ignore,
-				}
-			}
-			internal.Logger.Debug("Loaded file config", "ignore_patterns", len(config.IgnorePatterns), "path_rules", len(config.PathRules))
-		}
+	if err := internal.MergeFileConfig(config, internal.FileConfigDir(config)); err != nil {
+		internal.Logger.Warn("Failed to load .manque.yml config", "error", err)
 	}
 
 	// 3. Discover repo practices (if enabled)
@@ -92,7 +110,7 @@ func runLocalReview(cmd *cobra.Command, args []string) {
 			internal.Logger.Warn("Could not get current directory for discovery", "error", err)
 		} else {
 			internal.Logger.Info("Discovering repo practices...")
-			practices, err := discovery.Discover(cwd)
+			practices, err := discovery.DiscoverExcluding(cwd, config.ExcludedDirs())
 			if err != nil {
 				internal.Logger.Warn("Failed to discover repo practices", "error", err)
 			} else if practices.HasPractices() {
@@ -106,44 +124,31 @@ func runLocalReview(cmd *cobra.Command, args []string) {
 	}
 
 	// 4. Get Git Diff
-	mock, err := cmd.Flags().GetBool("mock")
-	if err != nil {
-		internal.Logger.Warn("Could not parse --mock flag", "error", err)
-		mock = false
-	}
 	var diffContent string
 
 	if mock {
 		internal.Logger.Info("Running in MOCK mode... skipping git diff")
-		diffContent = "mock diff content"
+		diffContent = mockDiff
 	} else {
-		internal.Logger.Info("Getting git diff...", "base", baseBranch, "head", headBranch)
-
-		// Check if git is available
-		if _, err := exec.LookPath("git"); err != nil {
-			internal.Logger.Error("Git not found in PATH")
-			return
-		}
-
-		// Run git diff
-		// Use merge-base to find common ancestor for better diff
-		mergeBaseCmd := exec.Command("git", "merge-base", baseBranch, headBranch)
-		mergeBaseOut, err := mergeBaseCmd.Output()
-		if err != nil {
-			internal.Logger.Error("Failed to find merge base. Are branches valid?", "error", err)
-			return
+		contextLines, _ := cmd.Flags().GetInt("context-lines")
+		ctx, cancel := config.OperationContext()
+		if sinceRef != "" {
+			diffContent, err = getSinceDiff(ctx, sinceRef, headBranch, contextLines)
+		} else {
+			diffContent, err = getLocalDiff(ctx, baseBranch, headBranch, contextLines)
 		}
-		commonAncestor := strings.TrimSpace(string(mergeBaseOut))
-
-		diffCmd := exec.Command("git", "diff", commonAncestor, headBranch)
-		diffOut, err := diffCmd.Output()
+		cancel()
 		if err != nil {
-			internal.Logger.Error("Failed to git diff", "error", err)
+			internal.Logger.Error("Failed to get git diff", "error", err)
 			return
 		}
-
-		diffContent = string(diffOut)
 		if len(diffContent) == 0 {
+			// An empty run lets code scanning close alerts from earlier uploads
+			saveSARIF(cmd, config, nil)
+			if format == "json" {
+				printJSONReport(nil, nil)
+				return
+			}
 			fmt.Println("No changes detected between branches.")
 			return
 		}
@@ -161,66 +166,194 @@ func runLocalReview(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// 4. Run Review
-	var summary *ai.PRSummary
-	var result *ai.ReviewResult
+	// 4. Run Review, printing each chunk's comments as soon as it is reviewed
+	streamed := make(streamedComments)
+	internal.Logger.Info("Analyzing changes... (this may take a minute)")
+	var onChunk func(int, []ai.Comment)
+	if format == "text" {
+		onChunk = streamed.print
+	}
+	summary, result, err := engine.ReviewStream(diffContent, onChunk)
+	if err != nil {
+		internal.Logger.Error("Review extraction failed", "error", err)
+		return
+	}
 
-	if mock {
-		internal.Logger.Info("Running in MOCK mode...")
-		// Use manual PRSummary with fields that match ai/types.go
-		summary = &ai.PRSummary{
-			Description: "This is a **mock review** generated to demonstrate the terminal output format. In a real run, this would be generated by your chosen LLM.",
-			Files: []struct {
-				Filename string `json:"filename"`
-				Summary  string `json:"summary"`
-				Title    string `json:"title"`
-			}{
-				{Filename: "cmd/local.go", Summary: "Added mock mode for easier local verification and testing."},
-				{Filename: "internal/config.go", Summary: "Refactored validation to support optional GitHub tokens."},
-			},
-		}
-		result = &ai.ReviewResult{
-			Review: ai.ReviewSummary{
-				Score:            85,
-				EstimatedEffort:  2,
-				HasRelevantTests: true,
-				SecurityConcerns: "None detected.",
-			},
-			Comments: []ai.Comment{
-				{
-					File:            "internal/payments/service/integration_test.go",
-					StartLine:       104,
-					EndLine:         106,
-					Header:          "🟡 Remove duplicate line",
-					Content:         "Line 105 is a duplicate of line 104. This will cause the payment to be stored twice.",
-					Label:           "bug",
-					HighlightedCode: "	r.payments[p.ID] = p\n	r.payments[p.ID] = p\n	return p, nil",
-					SuggestedCode:   "	r.payments[p.ID] = p\n	return p, nil",
-				},
-				{
-					File:            "internal/app/payments_initializer.go",
-					StartLine:       22,
-					EndLine:         26,
-					Header:          "🔴 Missing validation for required environment variables",
-					Content:         "The initializer reads MERCADOPAGO_ACCESS_TOKEN without validating it's set. An empty access token will cause all payment API calls to fail with unhelpful errors.",
-					Label:           "security",
-					Critical:        true,
-					HighlightedCode: "func initializePayments(sqlDB *sql.DB) PaymentsComponents {\n	mpAccessToken := os.Getenv(\"MERCADOPAGO_ACCESS_TOKEN\")\n	mpWebhookSecret := os.Getenv(\"MERCADOPAGO_WEBHOOK_SECRET\")",
-					SuggestedCode:   "func initializePayments(sqlDB *sql.DB) PaymentsComponents {\n	mpAccessToken := os.Getenv(\"MERCADOPAGO_ACCESS_TOKEN\")\n	if mpAccessToken == \"\" {\n		panic(\"MERCADOPAGO_ACCESS_TOKEN environment variable is required\")\n	}\n	mpWebhookSecret := os.Getenv(\"MERCADOPAGO_WEBHOOK_SECRET\")",
-				},
-			},
+	// 5. Output
+	saveSARIF(cmd, config, result)
+	if format == "json" {
+		printJSONReport(summary, result)
+		return
+	}
+	fmt.Println("\n" + streamed.remainingOutput(summary, result))
+	if usage := ai.FormatUsage(config.LLMProvider, config.LLMModel, result.TokensIn, result.TokensOut); usage != "" {
+		fmt.Println(usage)
+	}
+}
+
+// mockDiff is reviewed by --mock instead of the local changes
+const mockDiff = `diff --git a/internal/app/payments_initializer.go b/internal/app/payments_initializer.go
+index 3b18e51..a1c2f4d 100644
+--- a/internal/app/payments_initializer.go
++++ b/internal/app/payments_initializer.go
+@@ -20,3 +20,6 @@ type PaymentsComponents struct {
+ 
+ func initializePayments(sqlDB *sql.DB) PaymentsComponents {
++	mpAccessToken := os.Getenv("MERCADOPAGO_ACCESS_TOKEN")
++	mpWebhookSecret := os.Getenv("MERCADOPAGO_WEBHOOK_SECRET")
++	client := mercadopago.NewClient(mpAccessToken, mpWebhookSecret)
+ 	return PaymentsComponents{}
+`
+
+// streamedComments records comments already printed while the review was running, counting
+// identical comments separately
+type streamedComments map[string]int
+
+func streamedKey(comment ai.Comment) string {
+	return fmt.Sprintf("%s:%d:%d:%s:%s", comment.File, comment.StartLine, comment.EndLine, comment.Header, comment.Content)
+}
+
+func (s streamedComments) print(_ int, comments []ai.Comment) {
+	for _, comment := range comments {
+		s[streamedKey(comment)]++
+		fmt.Print(review.FormatComment(comment))
+	}
+}
+
+// remainingOutput formats the report without the comments that were already printed
+func (s streamedComments) remainingOutput(summary *ai.PRSummary, result *ai.ReviewResult) string {
+	var builder strings.Builder
+	builder.WriteString(review.FormatSummary(summary, result))
+	for _, comment := range result.Comments {
+		if key := streamedKey(comment); s[key] > 0 {
+			s[key]--
+			continue
 		}
+		builder.WriteString(review.FormatComment(comment))
+	}
+	return builder.String()
+}
+
+// printJSONReport writes the review to stdout as a single JSON document
+func printJSONReport(summary *ai.PRSummary, result *ai.ReviewResult) {
+	data, err := review.FormatJSON(summary, result)
+	if err != nil {
+		internal.Logger.Error("Failed to format JSON report", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// saveSARIF writes the review to the path given with --sarif, if any
+func saveSARIF(cmd *cobra.Command, config *internal.Config, result *ai.ReviewResult) {
+	sarifPath, _ := cmd.Flags().GetString("sarif")
+	if sarifPath == "" {
+		return
+	}
+	ctx, cancel := config.OperationContext()
+	defer cancel()
+	if err := writeSARIF(ctx, sarifPath, result); err != nil {
+		internal.Logger.Error("Failed to write SARIF report", "error", err)
 	} else {
-		internal.Logger.Info("Analyzing changes... (this may take a minute)")
-		var err error
-		summary, result, err = engine.Review(diffContent)
+		internal.Logger.Info("SARIF report written", "path", sarifPath)
+	}
+}
+
+// writeSARIF writes the review as SARIF with paths relative to the repository root, which is
+// what git diff paths are relative to
+func writeSARIF(ctx context.Context, path string, result *ai.ReviewResult) error {
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if out, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		repoRoot = strings.TrimSpace(string(out))
+	}
+
+	data, err := review.FormatSARIF(result, repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to format SARIF: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// getLocalDiff returns the diff of head against its merge base with base. A negative
+// contextLines keeps git's default number of context lines. git is killed when ctx is done.
+func getLocalDiff(ctx context.Context, base, head string, contextLines int) (string, error) {
+	internal.Logger.Info("Getting git diff...", "base", base, "head", head)
+
+	// Check if git is available
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git not found in PATH")
+	}
+
+	// Use merge-base to find common ancestor for better diff
+	mergeBaseCmd := exec.CommandContext(ctx, "git", "merge-base", base, head)
+	mergeBaseOut, err := mergeBaseCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base, are branches valid? %w", err)
+	}
+	commonAncestor := strings.TrimSpace(string(mergeBaseOut))
+
+	diffCmd := exec.CommandContext(ctx, "git", gitDiffArgs(commonAncestor, head, contextLines)...)
+	diffOut, err := diffCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to git diff: %w", err)
+	}
+
+	return string(diffOut), nil
+}
+
+// getSinceDiff returns the changes made after the since commit, diffing it directly against
+// head rather than through the merge base
+func getSinceDiff(ctx context.Context, since, head string, contextLines int) (string, error) {
+	internal.Logger.Info("Getting git diff...", "since", since, "head", head)
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git not found in PATH")
+	}
+
+	if err := exec.CommandContext(ctx, "git", verifyCommitArgs(since)...).Run(); err != nil {
+		return "", fmt.Errorf("--since %s is not a valid commit: %w", since, err)
+	}
+
+	diffOut, err := exec.CommandContext(ctx, "git", gitDiffArgs(since, head, contextLines)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to git diff: %w", err)
+	}
+
+	return string(diffOut), nil
+}
+
+// verifyCommitArgs builds the git rev-parse arguments checking that ref names a commit
+func verifyCommitArgs(ref string) []string {
+	return []string{"rev-parse", "--verify", "--quiet", ref + "^{commit}"}
+}
+
+// gitDiffArgs builds the git diff arguments, adding -U when contextLines is set
+func gitDiffArgs(from, to string, contextLines int) []string {
+	args := []string{"diff"}
+	if contextLines >= 0 {
+		args = append(args, fmt.Sprintf("-U%d", contextLines))
+	}
+	return append(args, from, to)
+}
+
+// applySamplingFlags copies --temperature and --max-tokens into the config when explicitly set
+func applySamplingFlags(cmd *cobra.Command, config *internal.Config) error {
+	if cmd.Flags().Changed("temperature") {
+		temperature, err := cmd.Flags().GetFloat64("temperature")
 		if err != nil {
-			internal.Logger.Error("Review extraction failed", "error", err)
-			return
+			return fmt.Errorf("invalid --temperature: %w", err)
 		}
+		config.LLMTemperature = &temperature
 	}
-
-	// 5. Output
-	output := review.FormatOutput(summary, result)
-	fmt.Println("\n" + output)
+	if cmd.Flags().Changed("max-tokens") {
+		maxTokens, err := cmd.Flags().GetInt("max-tokens")
+		if err != nil {
+			return fmt.Errorf("invalid --max-tokens: %w", err)
+		}
+		config.LLMMaxTokens = &maxTokens
+	}
+	return nil
 }
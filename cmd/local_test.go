@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/spf13/cobra"
+)
+
+func newSamplingTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Float64("temperature", 0, "")
+	cmd.Flags().Int("max-tokens", 0, "")
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	return cmd
+}
+
+func TestSaveSARIF_EmptyRun(t *testing.T) {
+	internal.InitLogger(false)
+	path := filepath.Join(t.TempDir(), "review.sarif")
+	cmd := &cobra.Command{}
+	cmd.Flags().String("sarif", "", "")
+	if err := cmd.ParseFlags([]string{"--sarif", path}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+
+	saveSARIF(cmd, &internal.Config{}, nil)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected a SARIF file for an empty diff: %v", err)
+	}
+	var log struct {
+		Runs []struct {
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Invalid SARIF: %v", err)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Errorf("Expected one run with no results, got %s", data)
+	}
+}
+
+func TestApplySamplingFlags(t *testing.T) {
+	config := &internal.Config{SkipGitHubValidation: true, LLMAPIKey: "key", LLMProvider: "openai"}
+	cmd := newSamplingTestCommand(t, "--temperature", "0.9", "--max-tokens", "2048")
+
+	if err := applySamplingFlags(cmd, config); err != nil {
+		t.Fatalf("applySamplingFlags failed: %v", err)
+	}
+	if config.LLMTemperature == nil || *config.LLMTemperature != 0.9 {
+		t.Errorf("Expected temperature 0.9, got %v", config.LLMTemperature)
+	}
+	if config.LLMMaxTokens == nil || *config.LLMMaxTokens != 2048 {
+		t.Errorf("Expected max tokens 2048, got %v", config.LLMMaxTokens)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
+
+func TestApplySamplingFlags_Unset(t *testing.T) {
+	config := &internal.Config{}
+	if err := applySamplingFlags(newSamplingTestCommand(t), config); err != nil {
+		t.Fatalf("applySamplingFlags failed: %v", err)
+	}
+	if config.LLMTemperature != nil || config.LLMMaxTokens != nil {
+		t.Errorf("Expected no overrides when flags are unset, got %v/%v", config.LLMTemperature, config.LLMMaxTokens)
+	}
+}
+
+func TestApplySamplingFlags_OutOfRange(t *testing.T) {
+	tests := [][]string{
+		{"--temperature", "2.5"},
+		{"--temperature", "-0.1"},
+		{"--max-tokens", "0"},
+	}
+
+	for _, args := range tests {
+		config := &internal.Config{SkipGitHubValidation: true, LLMAPIKey: "key", LLMProvider: "openai"}
+		if err := applySamplingFlags(newSamplingTestCommand(t, args...), config); err != nil {
+			t.Fatalf("applySamplingFlags(%v) failed: %v", args, err)
+		}
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected validation error for %v", args)
+		}
+	}
+}
+
+func TestGitDiffArgs(t *testing.T) {
+	tests := []struct {
+		contextLines int
+		expected     string
+	}{
+		{-1, "diff abc HEAD"},
+		{0, "diff -U0 abc HEAD"},
+		{10, "diff -U10 abc HEAD"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(gitDiffArgs("abc", "HEAD", tt.contextLines), " "); got != tt.expected {
+			t.Errorf("gitDiffArgs(%d) = %q, want %q", tt.contextLines, got, tt.expected)
+		}
+	}
+}
+
+func TestSinceDiffArgs(t *testing.T) {
+	if got := strings.Join(verifyCommitArgs("HEAD~3"), " "); got != "rev-parse --verify --quiet HEAD~3^{commit}" {
+		t.Errorf("verifyCommitArgs = %q", got)
+	}
+	// --since diffs the commit against head directly, without a merge base
+	if got := strings.Join(gitDiffArgs("HEAD~3", "HEAD", -1), " "); got != "diff HEAD~3 HEAD" {
+		t.Errorf("gitDiffArgs = %q", got)
+	}
+}
+
+func TestStreamedCommentsRemainingOutput(t *testing.T) {
+	printed := ai.Comment{File: "a.go", StartLine: 1, Header: "🟡 Streamed"}
+	deterministic := ai.Comment{File: "a.go", StartLine: 2, Header: "🟡 Duplicate line"}
+	result := &ai.ReviewResult{Comments: []ai.Comment{printed, printed, deterministic}}
+
+	streamed := streamedComments{streamedKey(printed): 1}
+	output := streamed.remainingOutput(&ai.PRSummary{Description: "Summary"}, result)
+
+	if !strings.Contains(output, "Summary") || strings.Contains(output, "No issues found") {
+		t.Errorf("Expected the summary without a no-issues note, got:\n%s", output)
+	}
+	if strings.Count(output, "🟡 Streamed") != 1 {
+		t.Errorf("Expected only the identical comment that wasn't streamed to be printed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "🟡 Duplicate line") {
+		t.Errorf("Expected the comment found after streaming to be printed, got:\n%s", output)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+// Modes for SCOPE_TO_AUTHOR_OWNERSHIP
+const (
+	OwnershipScopeOff      = "off"
+	OwnershipScopeSuppress = "suppress"
+	OwnershipScopeFYI      = "fyi"
+)
+
+// teamMembershipChecker resolves CODEOWNERS team entries to their members
+type teamMembershipChecker interface {
+	IsTeamMember(org, team, user string) (bool, error)
+}
+
+// authorOwnership decides whether the PR author owns a file according to CODEOWNERS
+type authorOwnership struct {
+	codeowners *github.Codeowners
+	author     string
+	teams      teamMembershipChecker
+	membership map[string]bool // "@org/team" -> author is a member
+}
+
+// ownsFile reports whether the author owns a file, returning the file's owners. Unowned files,
+// and teams whose membership cannot be checked, count as owned so comments are never dropped
+// on incomplete information.
+func (o *authorOwnership) ownsFile(file string) (bool, []string) {
+	owners := o.codeowners.Owners(file)
+	if len(owners) == 0 {
+		return true, nil
+	}
+
+	for _, owner := range owners {
+		if strings.EqualFold(owner, "@"+o.author) || o.isTeamMember(owner) {
+			return true, owners
+		}
+	}
+	return false, owners
+}
+
+func (o *authorOwnership) isTeamMember(owner string) bool {
+	org, team, isTeam := strings.Cut(strings.TrimPrefix(owner, "@"), "/")
+	if !isTeam || !strings.HasPrefix(owner, "@") {
+		return false
+	}
+
+	if member, ok := o.membership[owner]; ok {
+		return member
+	}
+
+	member, err := o.teams.IsTeamMember(org, team, o.author)
+	if err != nil {
+		// The default Actions token cannot read org teams; treat the team as owned
+		internal.Logger.Debug("Could not check team membership", "team", owner, "error", err)
+		member = true
+	}
+	o.membership[owner] = member
+	return member
+}
+
+// scopeToAuthorOwnership suppresses, or marks as FYI, comments on files the PR author does not
+// own. Critical and security comments are always kept as they are.
+func scopeToAuthorOwnership(comments []ai.Comment, ownership *authorOwnership, mode string) []ai.Comment {
+	if ownership == nil || mode == "" || mode == OwnershipScopeOff {
+		return comments
+	}
+
+	var scoped []ai.Comment
+	suppressed := 0
+	for _, comment := range comments {
+		if comment.Critical || strings.EqualFold(comment.Label, "security") {
+			scoped = append(scoped, comment)
+			continue
+		}
+
+		owned, owners := ownership.ownsFile(comment.File)
+		if owned {
+			scoped = append(scoped, comment)
+			continue
+		}
+
+		if mode == OwnershipScopeSuppress {
+			suppressed++
+			continue
+		}
+		comment.Content = fmt.Sprintf("_FYI: `%s` is owned by %s, so no action is required from you._\n\n%s",
+			comment.File, strings.Join(owners, ", "), comment.Content)
+		scoped = append(scoped, comment)
+	}
+
+	if suppressed > 0 {
+		internal.Logger.Info("Suppressed comments on files outside the author's ownership", "count", suppressed)
+	}
+	return scoped
+}
+
+// loadAuthorOwnership reads CODEOWNERS from the checkout when ownership scoping is enabled,
+// returning nil when scoping does not apply
+func loadAuthorOwnership(config *internal.Config, prInfo *github.PRInfo, teams teamMembershipChecker) *authorOwnership {
+	if config.OwnershipScope == "" || config.OwnershipScope == OwnershipScopeOff || prInfo.Author == "" {
+		return nil
+	}
+
+	dir := config.WorkDir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	codeowners, err := github.LoadCodeowners(dir)
+	if err != nil {
+		internal.Logger.Warn("Failed to load CODEOWNERS, not scoping comments to ownership", "error", err)
+		return nil
+	}
+	if codeowners == nil {
+		internal.Logger.Debug("No CODEOWNERS file found, not scoping comments to ownership")
+		return nil
+	}
+
+	return &authorOwnership{
+		codeowners: codeowners,
+		author:     prInfo.Author,
+		teams:      teams,
+		membership: make(map[string]bool),
+	}
+}
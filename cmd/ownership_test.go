@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+// fakeTeams answers team membership from a fixed map, counting lookups
+type fakeTeams struct {
+	members map[string]bool // "org/team" -> author is a member
+	calls   int
+}
+
+func (f *fakeTeams) IsTeamMember(org, team, user string) (bool, error) {
+	f.calls++
+	member, ok := f.members[org+"/"+team]
+	if !ok {
+		return false, errors.New("resource not accessible by integration")
+	}
+	return member, nil
+}
+
+func newTestOwnership(teams *fakeTeams) *authorOwnership {
+	return &authorOwnership{
+		codeowners: github.ParseCodeowners(`/web/      @org/frontend
+/billing/  @org/payments
+/secret/   @org/private
+/mine/     @alice
+`),
+		author:     "alice",
+		teams:      teams,
+		membership: make(map[string]bool),
+	}
+}
+
+func TestScopeToAuthorOwnership(t *testing.T) {
+	internal.InitLogger(false)
+	comments := []ai.Comment{
+		{File: "mine/a.go", Label: "style", Content: "own file"},
+		{File: "web/app.js", Label: "style", Content: "own team"},
+		{File: "billing/charge.go", Label: "style", Content: "other team"},
+		{File: "billing/refund.go", Label: "security", Content: "other team security"},
+		{File: "billing/refund.go", Label: "bug", Critical: true, Content: "other team critical"},
+		{File: "secret/x.go", Label: "style", Content: "unknown membership"},
+		{File: "README.md", Label: "style", Content: "unowned"},
+	}
+
+	teams := &fakeTeams{members: map[string]bool{"org/frontend": true, "org/payments": false}}
+	suppressed := scopeToAuthorOwnership(comments, newTestOwnership(teams), OwnershipScopeSuppress)
+	if len(suppressed) != 6 {
+		t.Fatalf("Expected only the other team's style comment to be suppressed, got %d comments", len(suppressed))
+	}
+	for _, comment := range suppressed {
+		if comment.Content == "other team" {
+			t.Error("Expected comment on billing/charge.go to be suppressed")
+		}
+	}
+	if teams.calls != 3 {
+		t.Errorf("Expected team membership to be looked up once per team, got %d calls", teams.calls)
+	}
+
+	fyi := scopeToAuthorOwnership(comments, newTestOwnership(teams), OwnershipScopeFYI)
+	if len(fyi) != len(comments) {
+		t.Fatalf("Expected FYI mode to keep all comments, got %d", len(fyi))
+	}
+	if !strings.HasPrefix(fyi[2].Content, "_FYI: `billing/charge.go` is owned by @org/payments") {
+		t.Errorf("Expected FYI note on billing/charge.go, got %q", fyi[2].Content)
+	}
+	if strings.HasPrefix(fyi[3].Content, "_FYI") || strings.HasPrefix(fyi[4].Content, "_FYI") {
+		t.Error("Expected security and critical comments to be left unchanged")
+	}
+
+	if got := scopeToAuthorOwnership(comments, nil, OwnershipScopeSuppress); len(got) != len(comments) {
+		t.Errorf("Expected comments unchanged without CODEOWNERS, got %d", len(got))
+	}
+}
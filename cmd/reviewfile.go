@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+	"github.com/igcodinap/manque-ai/pkg/markers"
+	"github.com/igcodinap/manque-ai/pkg/review"
+	"github.com/spf13/cobra"
+)
+
+var reviewFileCmd = &cobra.Command{
+	Use:   "review-file <path>",
+	Short: "Run AI review on a single file's uncommitted changes",
+	Long: `Reviews the working-tree version of one file against its last committed version (HEAD).
+Untracked files are reviewed in full. Useful for a quick check before staging a file.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReviewFile,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewFileCmd)
+	reviewFileCmd.Flags().Float64("temperature", 0, "Override the LLM sampling temperature for this run (0-2)")
+	reviewFileCmd.Flags().Int("max-tokens", 0, "Override the LLM max output tokens for this run")
+	reviewFileCmd.Flags().Int("context-lines", 3, "Lines of context around each change in the diff")
+}
+
+func runReviewFile(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	internal.InitLogger(debug)
+
+	config, err := internal.LoadConfig()
+	if err != nil {
+		internal.Logger.Error("Failed to load configuration", "error", err)
+		return
+	}
+	config.SkipGitHubValidation = true
+	if err := applySamplingFlags(cmd, config); err != nil {
+		internal.Logger.Error("Invalid flags", "error", err)
+		return
+	}
+	if err := config.Validate(); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		return
+	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		return
+	}
+	ai.SetMaxConcurrency(config.LLMMaxConcurrency)
+
+	if err := internal.MergeFileConfig(config, internal.FileConfigDir(config)); err != nil {
+		internal.Logger.Warn("Failed to load .manque.yml config", "error", err)
+	}
+
+	contextLines, _ := cmd.Flags().GetInt("context-lines")
+	ctx, cancel := config.OperationContext()
+	diffContent, err := getFileDiff(ctx, args[0], contextLines)
+	cancel()
+	if err != nil {
+		internal.Logger.Error("Failed to diff file", "error", err)
+		return
+	}
+	if diffContent == "" {
+		fmt.Printf("No changes detected in %s.\n", args[0])
+		return
+	}
+
+	engine, err := review.NewEngine(config)
+	if err != nil {
+		internal.Logger.Error("Failed to initialize engine", "error", err)
+		return
+	}
+
+	internal.Logger.Info("Analyzing file... (this may take a minute)")
+	summary, result, err := engine.Review(diffContent)
+	if err != nil {
+		internal.Logger.Error("Review failed", "error", err)
+		return
+	}
+
+	fmt.Println("\n" + review.FormatOutput(summary, result))
+}
+
+// getFileDiff builds a unified diff of a working-tree file against its HEAD version.
+// Untracked files, and files in a repository without commits, are diffed against empty
+// content. A deleted file has nothing left to review and yields "". git is killed when ctx is done.
+func getFileDiff(ctx context.Context, path string, contextLines int) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git not found in PATH")
+	}
+
+	newContent, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		internal.Logger.Info("File was deleted, nothing to review", "path", path)
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	filename, err := repoRelativePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	oldContent, err := exec.CommandContext(ctx, "git", "show", "HEAD:"+filename).Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("git show timed out: %w", ctx.Err())
+	}
+	if err != nil {
+		internal.Logger.Debug("File not in HEAD, reviewing it as new", "path", filename)
+		oldContent = nil
+	}
+
+	return diff.Unified(filename, string(oldContent), string(newContent), contextLines), nil
+}
+
+// repoRelativePath returns path relative to the repository root with forward slashes,
+// as git names files in diffs and in "git show HEAD:<path>"
+func repoRelativePath(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %w", err)
+	}
+	root := strings.TrimSpace(string(out))
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	// Resolve symlinks on both sides, e.g. /tmp on macOS, so the paths share a prefix
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the repository %s", path, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -1,23 +1,34 @@
 package cmd
 
 import (
+	stdcontext "context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	gh "github.com/google/go-github/v60/github"
 	"github.com/igcodinap/manque-ai/internal"
 	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/context"
+	"github.com/igcodinap/manque-ai/pkg/diff"
 	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/markers"
 	"github.com/igcodinap/manque-ai/pkg/review"
 	"github.com/igcodinap/manque-ai/pkg/state"
 	"github.com/spf13/cobra"
 )
 
 var (
-	prNumber   int
-	prURL      string
-	repository string
+	prNumber     int
+	prList       string
+	prURL        string
+	repository   string
+	eventFile    string
+	diffFile     string
+	dryRun       bool
+	dryRunOutput string
 )
 
 var rootCmd = &cobra.Command{
@@ -37,8 +48,13 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging")
 	rootCmd.Flags().IntVar(&prNumber, "pr", 0, "PR number to review")
+	rootCmd.Flags().StringVar(&prList, "prs", "", "Comma-separated PR numbers to review one after another (with --repo), e.g. 10,12,15")
 	rootCmd.Flags().StringVar(&prURL, "url", "", "GitHub PR URL to review")
 	rootCmd.Flags().StringVar(&repository, "repo", "", "Repository in format 'owner/repo'")
+	rootCmd.Flags().StringVar(&eventFile, "event-file", "", "GitHub event payload to review (overrides GITHUB_EVENT_PATH)")
+	rootCmd.Flags().StringVar(&diffFile, "diff-file", "", "Read the PR diff from a file instead of the GitHub API (used with --event-file)")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print review results and write them to a local file instead of posting to GitHub (or DRY_RUN=true)")
+	rootCmd.Flags().StringVar(&dryRunOutput, "dry-run-output", "manque-ai-dry-run.json", "File to write results to in --dry-run mode")
 }
 
 func runReview(cmd *cobra.Command, args []string) {
@@ -51,11 +67,31 @@ func runReview(cmd *cobra.Command, args []string) {
 		internal.Logger.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
-	// Action/Remote CLI always requires GitHub Token
+	if eventFile != "" {
+		config.GitHubEventPath = eventFile
+	}
+	if config.DryRun {
+		dryRun = true
+	}
+	// Action/Remote CLI always requires GitHub Token, except for offline dry runs
+	if dryRun {
+		config.SkipGitHubValidation = true
+	}
 	if err := config.Validate(); err != nil {
 		internal.Logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ai.SetMaxConcurrency(config.LLMMaxConcurrency)
+
+	// Load file-based config (.manque.yml) from the checkout
+	if err := internal.MergeFileConfig(config, internal.FileConfigDir(config)); err != nil {
+		internal.Logger.Warn("Failed to load .manque.yml config", "error", err)
+	}
 
 	// Initialize clients
 	githubClient := github.NewClient(config.GitHubToken, config.GitHubAPIURL)
@@ -65,9 +101,26 @@ func runReview(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Backfill several PRs with the same clients and engine
+	if repository != "" && prList != "" {
+		os.Exit(runBatchReview(config, engine, githubClient))
+	}
+
+	var client ReviewClient = githubClient
+	if dryRun {
+		client = newDryRunClient(githubClient, dryRunOutput)
+	}
+
 	// Get PR information
 	var prInfo *github.PRInfo
-	if config.GitHubEventPath != "" {
+	if config.GitHubEventPath != "" && diffFile != "" {
+		// Offline Action run with a saved event payload and diff
+		prInfo, err = loadPRFromFiles(config.GitHubEventPath, diffFile)
+		if err != nil {
+			internal.Logger.Error("Failed to load PR from event and diff files", "error", err)
+			os.Exit(1)
+		}
+	} else if config.GitHubEventPath != "" {
 		// Running as GitHub Action
 		prInfo, err = githubClient.GetPRFromEvent(config.GitHubEventPath)
 		if err != nil {
@@ -94,34 +147,114 @@ func runReview(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 	} else {
-		internal.Logger.Error("Must provide either GITHUB_EVENT_PATH (for Actions) or --url/--repo+--pr (for CLI)")
+		internal.Logger.Error("Must provide either GITHUB_EVENT_PATH (for Actions) or --url/--repo+--pr/--prs (for CLI)")
 		os.Exit(1)
 	}
 
 	internal.Logger.Info("Reviewing PR", "number", prInfo.Number, "title", prInfo.Title)
+	if _, err := RunReviewForPR(client, engine, config, prInfo, reviewOptions{Offline: diffFile != ""}); err != nil {
+		internal.Logger.Error("Failed to review PR", "number", prInfo.Number, "error", err)
+		os.Exit(1)
+	}
+	logRateLimit(githubClient)
+}
+
+// logRateLimit logs the GitHub API budget left after a run
+func logRateLimit(githubClient *github.Client) {
+	if limit, ok := githubClient.RateLimit(); ok {
+		internal.Logger.Debug("GitHub API rate limit", "remaining", limit.Remaining, "limit", limit.Limit, "reset", limit.Reset)
+	}
+}
+
+// ReviewClient is the GitHub access a PR review needs: reading earlier review state and the
+// changes made since, resolving CODEOWNERS teams and publishing the results
+type ReviewClient interface {
+	reviewPublisher
+	stateCommentFinder
+	diffComparer
+	teamMembershipChecker
+}
+
+// reviewOptions describes how a review was started
+type reviewOptions struct {
+	OnCommand bool // Asked for with a command, so REVIEW_DRAFT_ON_COMMAND lets drafts through
+	Offline   bool // The PR was read from files, so GitHub isn't asked for state or changes
+}
+
+// RunReviewForPR reviews one PR and publishes the results with client, returning a short
+// outcome for status lines. It is shared by the CLI, batch and webhook reviews. Review state
+// and session memory are loaded from and stored on the PR itself, so PRs reviewed with the
+// same engine don't share them. Checks that read the local checkout are skipped when the
+// engine has none.
+func RunReviewForPR(client ReviewClient, engine *review.Engine, config *internal.Config, prInfo *github.PRInfo, opts reviewOptions) (string, error) {
+	if skipDraftReview(config, prInfo.Draft, opts.OnCommand) {
+		internal.Logger.Info("Skipping draft PR (SKIP_DRAFTS is set)", "number", prInfo.Number)
+		return "skipped draft", nil
+	}
+
+	// The API omits diffs that are too large; fall back to the local checkout, then to a note
+	if strings.TrimSpace(prInfo.Diff) == "" {
+		if !engine.NoCheckout {
+			ctx, cancel := config.OperationContext()
+			prInfo.Diff = reconstructDiffLocally(ctx, prInfo)
+			cancel()
+		}
+		if strings.TrimSpace(prInfo.Diff) == "" {
+			parts := strings.Split(prInfo.Repository, "/")
+			if err := client.CreateOrUpdateComment(parts[0], parts[1], prInfo.Number, diffUnavailableNote); err != nil {
+				return "", fmt.Errorf("failed to post diff unavailable note: %w", err)
+			}
+			internal.Logger.Warn("PR diff unavailable, posted note instead of reviewing")
+			return "skipped, diff unavailable", nil
+		}
+	}
+
+	// Read earlier review state from where STATE_STORAGE keeps it; offline runs only have
+	// the description
+	var finder stateCommentFinder = client
+	if opts.Offline {
+		finder = nil
+	}
+	repoParts := strings.Split(prInfo.Repository, "/")
+	stateSource, err := loadStateSource(config.StateStorage, finder, repoParts[0], repoParts[1], prInfo.Number, prInfo.Description)
+	if err != nil {
+		internal.Logger.Warn("Failed to read review state, performing a full review", "error", err)
+	}
 
 	// Check for incremental review
 	tracker := state.NewTracker(prInfo.Repository, prInfo.Number)
-	isIncremental, previousState := tracker.IsIncrementalReview(prInfo.Description, prInfo.HeadSHA)
+	isIncremental, previousState := tracker.IsIncrementalReview(stateSource, prInfo.HeadSHA)
 
 	// Load or create session for memory across reviews
 	sessionManager := state.NewSessionManager(prInfo.Repository, prInfo.Number)
-	session := sessionManager.GetOrCreateSession(prInfo.Description)
+	session := sessionManager.GetOrCreateSession(stateSource)
 	if len(session.Reviews) > 0 {
 		internal.Logger.Info("Session loaded", "previous_reviews", len(session.Reviews), "dismissed_issues", len(session.Dismissed))
 	}
+	if session.MarkStale(config.SessionMaxAge, time.Now()) {
+		internal.Logger.Info("Session is stale, ignoring dismissals and performing a full review",
+			"updated_at", session.UpdatedAt, "max_age", config.SessionMaxAge)
+		isIncremental = false
+	}
 
 	var diffToReview string
 	if isIncremental && previousState != nil {
 		// Get incremental diff
 		internal.Logger.Info("Incremental review detected", "previous_sha", previousState.LastReviewedSHA[:7], "current_sha", prInfo.HeadSHA[:7])
-		incrementalDiff, err := state.GetIncrementalDiff(previousState.LastReviewedSHA, prInfo.HeadSHA)
+		// Offline runs have no GitHub access, so only the local checkout is used
+		var comparer diffComparer = client
+		if opts.Offline {
+			comparer = nil
+		}
+		ctx, cancel := config.OperationContext()
+		incrementalDiff, err := getIncrementalDiff(ctx, comparer, !engine.NoCheckout, prInfo, previousState.LastReviewedSHA)
+		cancel()
 		if err != nil {
 			internal.Logger.Warn("Failed to get incremental diff, falling back to full review", "error", err)
 			diffToReview = prInfo.Diff
 		} else if incrementalDiff == "" {
 			internal.Logger.Info("No new changes to review")
-			return
+			return "no new changes", nil
 		} else {
 			diffToReview = incrementalDiff
 		}
@@ -133,8 +266,24 @@ func runReview(cmd *cobra.Command, args []string) {
 	// Note: We use ReviewWithContext since we have the full PR details
 	summary, result, err := engine.ReviewWithContext(prInfo.Title, prInfo.Description, diffToReview)
 	if err != nil {
-		internal.Logger.Error("Review failed", "error", err)
-		os.Exit(1)
+		return "", fmt.Errorf("review failed: %w", err)
+	}
+
+	// Findings from the local checkout go through the same directives, baseline and severity
+	// overrides as the engine's
+	var extraComments []ai.Comment
+	var breakingReports []*ast.BreakingChangeReport
+	if !engine.NoCheckout {
+		extraComments = detectStaleDocs(prInfo, config, diffToReview)
+		breakingReports = detectBreakingChanges(prInfo, config)
+		extraComments = append(extraComments, review.BreakingChangeComments(breakingReports)...)
+		summary.APIChanges = detectSymbolChanges(prInfo, config)
+	}
+	impact := engine.AnalyzeImpact(prInfo.Diff, prInfo.BaseSHA, prInfo.HeadSHA)
+	extraComments = append(extraComments, impact.Comments...)
+	result.Comments = append(result.Comments, engine.FinalizeComments(prInfo.Diff, extraComments)...)
+	if !engine.NoCheckout {
+		result.Comments = scopeToAuthorOwnership(result.Comments, loadAuthorOwnership(config, prInfo, client), config.OwnershipScope)
 	}
 
 	// Filter out dismissed issues from session memory
@@ -148,6 +297,14 @@ func runReview(cmd *cobra.Command, args []string) {
 		commentHashes = append(commentHashes, hash)
 	}
 
+	// Check off required fixes from earlier reviews before recording this one, so they are
+	// marked as addressed in the review that raised them
+	requestChanges := result.GetReviewAction(config.AutoApproveThreshold, config.BlockOnCritical) == ai.ReviewActionRequestChanges
+	var checklist string
+	if addressed := updateChecklist(session, result.Comments, diffToReview, isIncremental, requestChanges); requestChanges || addressed > 0 {
+		checklist = formatChecklist(session)
+	}
+
 	// Update session with this review
 	session.AddReviewRecord(prInfo.HeadSHA, commentHashes, result.Review.Score, len(result.Comments))
 	session.TrimSession(10) // Keep last 10 reviews
@@ -157,23 +314,225 @@ func runReview(cmd *cobra.Command, args []string) {
 	newState := tracker.CreateNewState(prInfo.HeadSHA, len(result.Comments))
 	stateMarker := state.CreateStateMarker(newState)
 
+	breaking := routeBreakingReport(config.BreakingOutput, review.FormatBreakingChanges(breakingReports))
+	breaking.Impact = review.FormatImpact(impact.Impacts)
+
 	// Post results to GitHub
-	err = postResultsToGitHub(githubClient, prInfo, summary, result, config, stateMarker, sessionMarker, isIncremental)
+	err = postResultsToGitHub(client, prInfo, summary, result, config, stateMarker, sessionMarker, breaking, checklist, isIncremental)
 	if err != nil {
-		internal.Logger.Error("Failed to post results to GitHub", "error", err)
-		os.Exit(1)
+		return "", fmt.Errorf("failed to post results to GitHub: %w", err)
 	}
 
+	if dryRunResults, ok := client.(*dryRunClient); ok {
+		if err := dryRunResults.RecordMarkers(stateMarker, sessionMarker); err != nil {
+			return "", fmt.Errorf("failed to write dry run results: %w", err)
+		}
+		dryRunResults.Print(os.Stdout)
+		internal.Logger.Info("Dry run results written", "path", dryRunResults.path)
+	}
+
+	outcome := fmt.Sprintf("reviewed, score %d, %d comment(s)", result.Review.Score, len(result.Comments))
 	if isIncremental {
 		internal.Logger.Info("✅ Incremental review completed successfully!")
+		outcome = "incrementally " + outcome
 	} else {
 		internal.Logger.Info("✅ Review completed successfully!")
 	}
+	return outcome, nil
+}
+
+// draftSkippedNote answers review commands on draft PRs that are not reviewed
+const draftSkippedNote = "This PR is a draft, so I'm skipping the review. Mark it as ready for review to get one."
+
+// skipDraftReview reports whether a PR isn't reviewed because it is a draft and SKIP_DRAFTS
+// is set. Reviews requested with a command still run with REVIEW_DRAFT_ON_COMMAND.
+func skipDraftReview(config *internal.Config, draft, onCommand bool) bool {
+	if !draft || !config.SkipDrafts {
+		return false
+	}
+	return !onCommand || !config.ReviewDraftOnCommand
+}
+
+// diffUnavailableNote is posted when neither the GitHub API nor the local checkout provide a diff
+const diffUnavailableNote = "⚠️ **AI review skipped: diff unavailable**\n\n" +
+	"GitHub did not return a diff for this PR (it may be too large) and it could not be reconstructed from a local checkout. " +
+	"To review large PRs, run the Action with `actions/checkout` and `fetch-depth: 0` so the diff can be computed locally."
+
+// diffComparer fetches the diff between two commits from GitHub
+type diffComparer interface {
+	CompareCommits(owner, repo, base, head string) (string, error)
+}
+
+// getIncrementalDiff returns the changes since the last reviewed commit. The compare API is
+// preferred since Action checkouts are often shallow or missing; local git is the fallback
+// when there is a checkout, killed when ctx is done.
+func getIncrementalDiff(ctx stdcontext.Context, comparer diffComparer, checkout bool, prInfo *github.PRInfo, lastReviewedSHA string) (string, error) {
+	if comparer != nil {
+		parts := strings.Split(prInfo.Repository, "/")
+		if len(parts) == 2 {
+			diff, err := comparer.CompareCommits(parts[0], parts[1], lastReviewedSHA, prInfo.HeadSHA)
+			if err == nil {
+				return diff, nil
+			}
+			if !checkout {
+				return "", fmt.Errorf("failed to get incremental diff from GitHub: %w", err)
+			}
+			internal.Logger.Warn("Failed to get incremental diff from GitHub, trying local checkout", "error", err)
+		}
+	}
+	if !checkout {
+		return "", fmt.Errorf("no local checkout to compute the incremental diff")
+	}
+	return state.GetIncrementalDiff(ctx, lastReviewedSHA, prInfo.HeadSHA)
+}
+
+// reconstructDiffLocally rebuilds the PR diff from git when a checkout with both commits exists
+func reconstructDiffLocally(ctx stdcontext.Context, prInfo *github.PRInfo) string {
+	if prInfo.BaseSHA == "" || prInfo.HeadSHA == "" {
+		return ""
+	}
+
+	localDiff, err := state.GetLocalPRDiff(ctx, prInfo.BaseSHA, prInfo.HeadSHA)
+	if err != nil {
+		internal.Logger.Warn("Failed to reconstruct diff from local checkout", "error", err)
+		return ""
+	}
+	if localDiff != "" {
+		internal.Logger.Info("Reconstructed PR diff from local checkout", "bytes", len(localDiff))
+	}
+	return localDiff
 }
 
-// filterDismissedComments removes comments that were previously dismissed by users
+// loadPRFromFiles builds PR info from a saved event payload and diff file without calling GitHub
+func loadPRFromFiles(eventPath, diffPath string) (*github.PRInfo, error) {
+	event, err := github.ParseEventFile(eventPath)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := os.ReadFile(diffPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff file: %w", err)
+	}
+
+	return github.PRInfoFromEvent(event, string(diff)), nil
+}
+
+// detectBreakingChanges compares changed files between the PR base and head in the local
+// checkout and returns the reports with findings, or nil unless DETECT_BREAKING is set or
+// when the revisions are unavailable
+func detectBreakingChanges(prInfo *github.PRInfo, config *internal.Config) []*ast.BreakingChangeReport {
+	if !config.DetectBreaking || config.BreakingOutput == review.BreakingOutputOff || prInfo.BaseSHA == "" || prInfo.HeadSHA == "" {
+		return nil
+	}
+
+	files, err := parseReviewedFiles(prInfo.Diff, config)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse diff for breaking change detection", "error", err)
+		return nil
+	}
+
+	reports := review.DetectBreakingChanges(files, revisionLoader(config), prInfo.BaseSHA, prInfo.HeadSHA)
+	if len(reports) > 0 {
+		internal.Logger.Info("Breaking changes detected", "files", len(reports))
+	}
+
+	return reports
+}
+
+// detectSymbolChanges renders the exported API changes of the PR's Go files for the
+// walkthrough, keyed by file name. It is empty when the PR revisions are unavailable.
+func detectSymbolChanges(prInfo *github.PRInfo, config *internal.Config) map[string]string {
+	if prInfo.BaseSHA == "" || prInfo.HeadSHA == "" {
+		return nil
+	}
+
+	files, err := parseReviewedFiles(prInfo.Diff, config)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse diff for symbol summary", "error", err)
+		return nil
+	}
+
+	changes := make(map[string]string)
+	for file, set := range review.DetectSymbolChanges(files, revisionLoader(config), prInfo.BaseSHA, prInfo.HeadSHA) {
+		changes[file] = review.FormatSymbolChanges(set)
+	}
+	return changes
+}
+
+// detectStaleDocs flags exported functions in the reviewed diff whose signature changed
+// without a matching doc comment update
+func detectStaleDocs(prInfo *github.PRInfo, config *internal.Config, diffContent string) []ai.Comment {
+	if prInfo.BaseSHA == "" || prInfo.HeadSHA == "" {
+		return nil
+	}
+
+	files, err := parseReviewedFiles(diffContent, config)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse diff for stale doc detection", "error", err)
+		return nil
+	}
+
+	return review.DetectStaleDocs(files, revisionLoader(config), prInfo.BaseSHA, prInfo.HeadSHA)
+}
+
+// parseReviewedFiles parses a diff and drops the files the review skips, so checks run on
+// the local checkout honor the same include, ignore and generated file settings
+func parseReviewedFiles(diffContent string, config *internal.Config) ([]diff.FileDiff, error) {
+	files, err := diff.ParseGitDiff(diffContent)
+	if err != nil {
+		return nil, err
+	}
+	return review.FilterReviewedFiles(config, files), nil
+}
+
+// revisionLoader reads files at a revision from the local checkout
+func revisionLoader(config *internal.Config) review.FileLoader {
+	return func(rev, path string) (string, error) {
+		ctx, cancel := config.OperationContext()
+		defer cancel()
+		return context.GetFileAtRevision(ctx, config.WorkDir, rev, path)
+	}
+}
+
+// breakingReportTargets holds the breaking change report for the one destination it is posted
+// to, along with the impact analysis, which always goes to the review body
+type breakingReportTargets struct {
+	Body    string
+	Comment string
+	Review  string
+	Impact  string
+
+	// ClearComment is set when the report goes to the sticky comment but is empty, so an
+	// earlier report is cleared
+	ClearComment bool
+}
+
+// detected reports whether breaking changes were found, wherever the report goes
+func (t breakingReportTargets) detected() bool {
+	return t.Body != "" || t.Comment != "" || t.Review != ""
+}
+
+// routeBreakingReport assigns the report to the destination selected by BREAKING_OUTPUT
+func routeBreakingReport(output, report string) breakingReportTargets {
+	switch output {
+	case review.BreakingOutputComment:
+		return breakingReportTargets{Comment: report, ClearComment: report == ""}
+	case review.BreakingOutputReview:
+		return breakingReportTargets{Review: report}
+	case review.BreakingOutputOff:
+		return breakingReportTargets{}
+	default:
+		return breakingReportTargets{Body: report}
+	}
+}
+
+// noBreakingChangesNote replaces a breaking change report once the PR no longer has any
+const noBreakingChangesNote = "✅ No breaking changes detected in the latest review."
+
+// filterDismissedComments removes comments that were previously dismissed or resolved by users
 func filterDismissedComments(comments []ai.Comment, session *state.Session) []ai.Comment {
-	if session == nil || len(session.Dismissed) == 0 {
+	if session == nil {
 		return comments
 	}
 
@@ -181,7 +540,8 @@ func filterDismissedComments(comments []ai.Comment, session *state.Session) []ai
 	dismissedCount := 0
 	for _, comment := range comments {
 		hash := state.ComputeCommentHash(comment.File, comment.StartLine, comment.EndLine, comment.Content)
-		if session.IsDismissed(hash) {
+		fuzzyHash := state.ComputeFuzzyHash(comment.File, comment.Content)
+		if session.IsDismissed(hash, fuzzyHash) || session.IsResolved(hash, fuzzyHash) || session.WasAddressed(hash) {
 			dismissedCount++
 			internal.Logger.Debug("Skipping dismissed issue", "file", comment.File, "line", comment.StartLine)
 			continue
@@ -196,10 +556,22 @@ func filterDismissedComments(comments []ai.Comment, session *state.Session) []ai
 	return filtered
 }
 
-func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, summary *ai.PRSummary, review *ai.ReviewResult, config *internal.Config, stateMarker, sessionMarker string, isIncremental bool) error {
+func postResultsToGitHub(githubClient reviewPublisher, prInfo *github.PRInfo, summary *ai.PRSummary, result *ai.ReviewResult, config *internal.Config, stateMarker, sessionMarker string, breaking breakingReportTargets, checklist string, isIncremental bool) error {
 	parts := strings.Split(prInfo.Repository, "/")
 	owner, repo := parts[0], parts[1]
 
+	// Post the breaking change report as its own sticky comment, updated in place across reviews.
+	// Once later pushes remove the breaking changes, an earlier report says so instead.
+	if breaking.Comment != "" {
+		if err := githubClient.CreateOrUpdateMarkedComment(owner, repo, prInfo.Number, github.BreakingChangeMarker(), breaking.Comment); err != nil {
+			return fmt.Errorf("failed to post breaking change report: %w", err)
+		}
+	} else if breaking.ClearComment {
+		if err := githubClient.UpdateMarkedComment(owner, repo, prInfo.Number, github.BreakingChangeMarker(), noBreakingChangesNote); err != nil {
+			return fmt.Errorf("failed to clear breaking change report: %w", err)
+		}
+	}
+
 	// Update PR title if configured (only on first review, not incremental)
 	if config.UpdatePRTitle && !isIncremental {
 		if err := githubClient.UpdatePR(owner, repo, prInfo.Number, &summary.Title, nil); err != nil {
@@ -210,10 +582,10 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 	// Update PR body with full report if configured
 	if config.UpdatePRBody {
 		// Build the AI summary section
-		walkthrough := formatWalkthrough(summary, review)
+		walkthrough := formatWalkthrough(summary, result)
 
 		var aiSection strings.Builder
-		aiSection.WriteString("\n\n<!-- ai-review-start -->\n")
+		aiSection.WriteString("\n\n" + markers.Tag("review-start") + "\n")
 		if isIncremental {
 			aiSection.WriteString("# 🤖 AI Code Review (Incremental)\n\n")
 		} else {
@@ -221,17 +593,31 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 		}
 		aiSection.WriteString(walkthrough)
 		aiSection.WriteString("\n")
-		// Add state marker for future incremental reviews
-		if stateMarker != "" {
-			aiSection.WriteString(stateMarker)
+		if breaking.Body != "" {
 			aiSection.WriteString("\n")
-		}
-		// Add session marker for memory across reviews
-		if sessionMarker != "" {
-			aiSection.WriteString(sessionMarker)
+			aiSection.WriteString(breaking.Body)
 			aiSection.WriteString("\n")
 		}
-		aiSection.WriteString("<!-- ai-review-end -->")
+		if config.ShowCost {
+			if usage := ai.FormatUsage(config.LLMProvider, config.LLMModel, result.TokensIn, result.TokensOut); usage != "" {
+				aiSection.WriteString("\n<sub>")
+				aiSection.WriteString(usage)
+				aiSection.WriteString("</sub>\n")
+			}
+		}
+		if storesStateInBody(config.StateStorage) {
+			// Add state marker for future incremental reviews
+			if stateMarker != "" {
+				aiSection.WriteString(stateMarker)
+				aiSection.WriteString("\n")
+			}
+			// Add session marker for memory across reviews
+			if sessionMarker != "" {
+				aiSection.WriteString(sessionMarker)
+				aiSection.WriteString("\n")
+			}
+		}
+		aiSection.WriteString(markers.Tag("review-end"))
 
 		// Strip any existing AI summary, state marker, and session marker from the description
 		cleanDescription := state.StripSessionMarker(state.StripStateMarker(stripAISummary(prInfo.Description)))
@@ -242,15 +628,61 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 		}
 	}
 
-	// Create review with inline comments
-	if len(review.Comments) > 0 {
-		internal.Logger.Debug("AI returned comments", "count", len(review.Comments))
+	// Keep the state in a dedicated comment when the PR body must not hold it
+	if config.StateStorage == StateStoragePRComment && (stateMarker != "" || sessionMarker != "") {
+		if err := githubClient.CreateOrUpdateMarkedComment(owner, repo, prInfo.Number, github.StateCommentMarker(), formatStateComment(stateMarker, sessionMarker)); err != nil {
+			return fmt.Errorf("failed to store review state: %w", err)
+		}
+	}
+
+	// Label the PR by review outcome; missing label permissions shouldn't fail the review
+	if config.AutoLabel {
+		action := result.GetReviewAction(config.AutoApproveThreshold, config.BlockOnCritical)
+		labels := reviewLabels(config.ReviewLabels, action, result.Comments, breaking.detected())
+		if err := applyReviewLabels(githubClient, owner, repo, prInfo.Number, labels); err != nil {
+			internal.Logger.Warn("Failed to label PR", "error", err)
+		}
+	}
+
+	// Determine review action based on score and critical issues
+	reviewAction := result.GetReviewAction(config.AutoApproveThreshold, config.BlockOnCritical)
+	internal.Logger.Debug("Review action determined", "action", reviewAction, "score", result.Review.Score, "threshold", config.AutoApproveThreshold)
+
+	// Keep the positive summary but never submit a formal approval when disallowed
+	approvalWithheld := ""
+	if reviewAction == ai.ReviewActionApprove {
+		switch {
+		case config.RequireHumanApproval:
+			approvalWithheld = "human approval required"
+		case !config.AllowAutoApprove:
+			approvalWithheld = "auto-approve disabled"
+		}
+	}
+	if approvalWithheld != "" {
+		reviewAction = reviewAction.WithoutApproval()
+		internal.Logger.Info("Withholding approval", "reason", approvalWithheld)
+	}
+
+	// Create review with inline comments, or just the breaking change report in review mode,
+	// the impact analysis, the checklist of required fixes, or the files skipped for size.
+	// Approvals are submitted without any of them, since some orgs require one to merge; an
+	// approval that was withheld has nothing to submit.
+	if reviewAction == ai.ReviewActionApprove || len(result.Comments) > 0 || breaking.Review != "" || breaking.Impact != "" || checklist != "" || len(result.SkippedFiles) > 0 {
+		internal.Logger.Debug("AI returned comments", "count", len(result.Comments))
+
+		// GitHub rejects the whole review if any inline comment is outside the diff, so those
+		// are moved to the review body
+		inlineComments, outsideComments := splitByDiffLines(prInfo.Diff, result.Comments)
+		if len(outsideComments) > 0 {
+			internal.Logger.Info("Moved comments outside the diff to the review body", "count", len(outsideComments))
+		}
+		inlineComments, overflowComments := limitComments(inlineComments, config.MaxComments)
 
 		var reviewComments []*gh.DraftReviewComment
 		seenComments := make(map[string]bool) // Deduplicate before sending
 		batchDuplicates := 0
 
-		for _, comment := range review.Comments {
+		for _, comment := range inlineComments {
 			// Combine header and content for a complete, unique comment
 			var body strings.Builder
 			body.WriteString(fmt.Sprintf("**%s**\n\n%s", comment.Header, comment.Content))
@@ -286,17 +718,16 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 		}
 		internal.Logger.Debug("Batch deduplication complete", "unique_comments", len(reviewComments), "batch_duplicates", batchDuplicates)
 
-		// Determine review action based on score and critical issues
-		reviewAction := review.GetReviewAction(config.AutoApproveThreshold, config.BlockOnCritical)
-		internal.Logger.Debug("Review action determined", "action", reviewAction, "score", review.Review.Score, "threshold", config.AutoApproveThreshold)
-
 		actionEmoji := "💬"
 		actionText := "Comment"
-		switch reviewAction {
-		case ai.ReviewActionApprove:
+		switch {
+		case approvalWithheld != "":
+			actionEmoji = "✅"
+			actionText = fmt.Sprintf("Looks Good (%s)", approvalWithheld)
+		case reviewAction == ai.ReviewActionApprove:
 			actionEmoji = "✅"
 			actionText = "Approved"
-		case ai.ReviewActionRequestChanges:
+		case reviewAction == ai.ReviewActionRequestChanges:
 			actionEmoji = "🚫"
 			actionText = "Changes Requested"
 		}
@@ -309,15 +740,46 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 			"Found %d issues requiring attention.\n\n"+
 			"**Review Action**: %s %s",
 			actionEmoji,
-			review.Review.EstimatedEffort,
-			review.Review.Score,
-			review.Review.HasRelevantTests,
-			review.Review.SecurityConcerns,
-			len(review.Comments),
+			result.Review.EstimatedEffort,
+			result.Review.Score,
+			result.Review.HasRelevantTests,
+			result.Review.SecurityConcerns,
+			len(result.Comments),
 			actionEmoji,
 			actionText)
 
-		opts := github.CreateReviewOptions{IsIncremental: isIncremental}
+		if result.FailedChunks > 0 {
+			reviewBody += fmt.Sprintf("\n\n⚠️ Reviewed %d/%d chunks; the rest failed and were not reviewed.",
+				result.Chunks-result.FailedChunks, result.Chunks)
+		}
+		if skipped := result.SkippedFiles; len(skipped) > 0 {
+			reviewBody += "\n\n" + strings.TrimSpace(review.FormatSkippedFiles(skipped))
+		}
+		if len(result.UntestedFiles) > 0 {
+			reviewBody += "\n\n### Untested Changes\nThese source files changed without a matching test file change:\n" +
+				review.FormatFileList(result.UntestedFiles)
+		}
+		if len(outsideComments) > 0 {
+			reviewBody += "\n\n### Comments Outside the Diff\n" + formatCommentList(outsideComments)
+		}
+		if len(overflowComments) > 0 {
+			reviewBody += fmt.Sprintf("\n\n<details>\n<summary>%d additional lower-priority findings</summary>\n%s\n\n</details>",
+				len(overflowComments), formatCommentList(overflowComments))
+		}
+		if breaking.Review != "" {
+			reviewBody += "\n\n" + breaking.Review
+		}
+		if breaking.Impact != "" {
+			reviewBody += "\n\n" + breaking.Impact
+		}
+		if checklist != "" {
+			reviewBody += "\n\n" + checklist
+		}
+
+		opts := github.CreateReviewOptions{IsIncremental: isIncremental, ReplyPrefix: config.ReplyPrefix}
+		if config.ReplyPrefix == "off" {
+			opts.ReplyPrefix = ""
+		}
 		if err := githubClient.CreateReviewWithOptions(owner, repo, prInfo.Number, reviewComments, &reviewBody, string(reviewAction), opts); err != nil {
 			return fmt.Errorf("failed to create review: %w", err)
 		}
@@ -326,13 +788,73 @@ func postResultsToGitHub(githubClient *github.Client, prInfo *github.PRInfo, sum
 	return nil
 }
 
-// stripAISummary removes any existing AI Summary section from the PR description
+// splitByDiffLines separates comments that can be posted inline on the PR diff from those
+// outside it. Every comment is inline when the diff is unknown.
+func splitByDiffLines(diffText string, comments []ai.Comment) (inline, outside []ai.Comment) {
+	if diffText == "" {
+		return comments, nil
+	}
+	files, err := diff.ParseGitDiff(diffText)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse PR diff, skipping comment line validation", "error", err)
+		return comments, nil
+	}
+	return review.SplitByDiffLines(files, comments)
+}
+
+// limitComments keeps the maxComments most severe comments for posting inline
+func limitComments(comments []ai.Comment, maxComments int) (kept, overflow []ai.Comment) {
+	kept, overflow = review.LimitComments(comments, maxComments)
+	if len(overflow) > 0 {
+		internal.Logger.Info("Comment cap reached, moving lower-priority comments to the review body",
+			"max_comments", maxComments, "moved", len(overflow))
+	}
+	return kept, overflow
+}
+
+// formatCommentList renders comments that are not posted inline as a list for the review body
+func formatCommentList(comments []ai.Comment) string {
+	var builder strings.Builder
+	for _, comment := range comments {
+		location := comment.File
+		if comment.StartLine > 0 {
+			location = fmt.Sprintf("%s:%d", comment.File, comment.StartLine)
+		}
+		builder.WriteString(fmt.Sprintf("\n- `%s` **%s**", location, comment.Header))
+		if content := strings.TrimSpace(comment.Content); content != "" {
+			builder.WriteString("\n  ")
+			builder.WriteString(strings.ReplaceAll(content, "\n", "\n  "))
+		}
+	}
+	return builder.String()
+}
+
+// Markers around the review section before markers were namespaced
+const (
+	legacyReviewStartMarker = "<!-- ai-review-start -->"
+	legacyReviewEndMarker   = "<!-- ai-review-end -->"
+)
+
+// stripAISummary removes any existing AI Summary section from the PR description. Only this
+// instance's section is removed, so text after it, such as another namespace's section, is kept.
 func stripAISummary(description string) string {
 	// 1. Try to find the new robust HTML markers
-	startMarker := "<!-- ai-review-start -->"
-	idx := strings.Index(description, startMarker)
+	idx, start := markers.Index(description, markers.Tag("review-start"), legacyReviewStartMarker)
 	if idx != -1 {
-		return strings.TrimSpace(description[:idx])
+		end := markers.Tag("review-end")
+		if start == legacyReviewStartMarker {
+			end = legacyReviewEndMarker
+		}
+		before := strings.TrimSpace(description[:idx])
+		endIdx := strings.Index(description[idx:], end)
+		if endIdx == -1 {
+			return before
+		}
+		after := strings.TrimSpace(description[idx+endIdx+len(end):])
+		if before == "" || after == "" {
+			return before + after
+		}
+		return before + "\n\n" + after
 	}
 
 	// 2. Fallback: Find the old markdown header marker
@@ -352,20 +874,29 @@ func formatWalkthrough(summary *ai.PRSummary, review *ai.ReviewResult) string {
 	builder.WriteString(summary.Description + "\n\n")
 
 	builder.WriteString("🔍 **Walkthrough**\n")
-	builder.WriteString("| File | Summary |\n")
-	builder.WriteString("|------|----------|\n")
-	for _, file := range summary.Files {
-		builder.WriteString(fmt.Sprintf("| `%s` | %s |\n", file.Filename, file.Summary))
+	if len(summary.Files) == 0 {
+		// A header-only table renders awkwardly when the model returned no per-file summaries
+		builder.WriteString("_Per-file summary unavailable._\n")
+	} else {
+		builder.WriteString("| File | Summary |\n")
+		builder.WriteString("|------|----------|\n")
+		for _, file := range summary.Files {
+			fileSummary := file.Summary
+			if changes := summary.APIChanges[file.Filename]; changes != "" {
+				fileSummary += "<br>🧩 **API**: " + changes
+			}
+			builder.WriteString(fmt.Sprintf("| `%s` | %s |\n", file.Filename, fileSummary))
+		}
 	}
 	builder.WriteString("\n")
 
-	// Group comments by severity
+	// Group comments by severity: security issues are critical and bugs are warnings
 	var critical, warnings, suggestions []ai.Comment
 	for _, comment := range review.Comments {
-		switch {
-		case comment.Critical || comment.Label == "security" || strings.Contains(comment.Header, "🔴"):
+		switch category := comment.Category(); {
+		case comment.Critical || category == ai.CategorySecurity:
 			critical = append(critical, comment)
-		case comment.Label == "bug" || strings.Contains(comment.Header, "🟡"):
+		case category == ai.CategoryBug:
 			warnings = append(warnings, comment)
 		default:
 			suggestions = append(suggestions, comment)
@@ -396,6 +927,10 @@ func formatWalkthrough(summary *ai.PRSummary, review *ai.ReviewResult) string {
 		builder.WriteString("\n")
 	}
 
+	if counts := formatCategoryCounts(review); counts != "" {
+		builder.WriteString("📊 **Issues by category**: " + counts + "\n\n")
+	}
+
 	builder.WriteString(fmt.Sprintf("**Quality Score**: %d/100 | **Review Effort**: %d/5 | **Security**: %s",
 		review.Review.Score,
 		review.Review.EstimatedEffort,
@@ -403,3 +938,18 @@ func formatWalkthrough(summary *ai.PRSummary, review *ai.ReviewResult) string {
 
 	return builder.String()
 }
+
+// formatCategoryCounts lists the number of comments per category, e.g. "1 security, 2 bug",
+// or returns "" when there are none. The comments are counted when the summary is built, so
+// findings filtered out after the engine ran aren't reported.
+func formatCategoryCounts(review *ai.ReviewResult) string {
+	counts := ai.CountByCategory(review.Comments)
+
+	var parts []string
+	for _, category := range ai.CommentCategories {
+		if count := counts[category]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, category))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
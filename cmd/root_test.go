@@ -1,8 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/review"
+	"github.com/igcodinap/manque-ai/pkg/state"
 )
 
 func TestStripAISummary_NoExistingSummary(t *testing.T) {
@@ -113,6 +124,17 @@ func TestStripAISummary_NewHTMLMarkers(t *testing.T) {
 	}
 }
 
+func TestStripAISummary_KeepsTextAfterSection(t *testing.T) {
+	description := "Original PR description.\n\n<!-- ai-review-start -->\nOur review\n<!-- ai-review-end -->\n\n" +
+		"<!-- staging-bot-review-start -->\nStaging review\n<!-- staging-bot-review-end -->"
+
+	result := stripAISummary(description)
+	expected := "Original PR description.\n\n<!-- staging-bot-review-start -->\nStaging review\n<!-- staging-bot-review-end -->"
+	if result != expected {
+		t.Errorf("Expected only this instance's section to be removed, got: %q", result)
+	}
+}
+
 func TestStripAISummary_LegacyMarkerFallback(t *testing.T) {
 	description := `Original PR description.
 
@@ -145,3 +167,210 @@ Old content inside new content`
 		t.Errorf("Expected '%s', got: '%s'", expected, result)
 	}
 }
+
+func TestFormatWalkthrough_NoFiles(t *testing.T) {
+	summary := &ai.PRSummary{Description: "Adds retries"}
+	result := formatWalkthrough(summary, &ai.ReviewResult{})
+
+	if strings.Contains(result, "| File | Summary |") {
+		t.Errorf("Expected no file table without per-file summaries, got:\n%s", result)
+	}
+	if !strings.Contains(result, "Per-file summary unavailable") {
+		t.Errorf("Expected note about missing per-file summary, got:\n%s", result)
+	}
+}
+
+func TestFormatWalkthrough_WithFiles(t *testing.T) {
+	var summary ai.PRSummary
+	if err := json.Unmarshal([]byte(`{"description": "Adds retries", "files": [{"filename": "client.go", "summary": "Retry loop"}]}`), &summary); err != nil {
+		t.Fatal(err)
+	}
+	result := formatWalkthrough(&summary, &ai.ReviewResult{})
+
+	if !strings.Contains(result, "| File | Summary |") || !strings.Contains(result, "| `client.go` | Retry loop |") {
+		t.Errorf("Expected file table with client.go, got:\n%s", result)
+	}
+	if strings.Contains(result, "Per-file summary unavailable") {
+		t.Error("Expected no unavailable note when files are present")
+	}
+}
+
+func TestFormatWalkthrough_APIChanges(t *testing.T) {
+	var summary ai.PRSummary
+	if err := json.Unmarshal([]byte(`{"description": "Adds retries", "files": [{"filename": "client.go", "summary": "Retry loop"}, {"filename": "README.md", "summary": "Docs"}]}`), &summary); err != nil {
+		t.Fatal(err)
+	}
+	summary.APIChanges = map[string]string{"client.go": "added `New`"}
+	result := formatWalkthrough(&summary, &ai.ReviewResult{})
+
+	if !strings.Contains(result, "| `client.go` | Retry loop<br>🧩 **API**: added `New` |") {
+		t.Errorf("Expected the API changes in the client.go row, got:\n%s", result)
+	}
+	if !strings.Contains(result, "| `README.md` | Docs |") {
+		t.Errorf("Expected files without API changes to keep their summary, got:\n%s", result)
+	}
+}
+
+func TestFormatWalkthrough_GroupsByCategory(t *testing.T) {
+	review := &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "auth.go", StartLine: 3, Header: "Token logged", Label: "Security"},
+		{File: "retry.go", StartLine: 10, Header: "🔴 Off-by-one", Label: "bug"},
+		{File: "retry.go", StartLine: 20, Header: "Rename variable", Label: "nitpick"},
+	}}
+	result := formatWalkthrough(&ai.PRSummary{Description: "Adds retries"}, review)
+
+	critical := result[strings.Index(result, "Critical Issues"):strings.Index(result, "Warnings")]
+	if !strings.Contains(critical, "Token logged") || strings.Contains(critical, "Off-by-one") {
+		t.Errorf("Expected only the security comment to be critical, got:\n%s", result)
+	}
+	if !strings.Contains(result[strings.Index(result, "Warnings"):], "Off-by-one") {
+		t.Errorf("Expected the bug to be a warning regardless of its header emoji, got:\n%s", result)
+	}
+	if !strings.Contains(result, "**Issues by category**: 1 security, 1 bug, 1 style") {
+		t.Errorf("Expected category counts, got:\n%s", result)
+	}
+}
+
+func TestFileConfig_IgnoresFilesInActionFlow(t *testing.T) {
+	internal.InitLogger(false)
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, ".manque.yml"), []byte("ignore:\n  - \"docs/**\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+
+	config := &internal.Config{}
+	if dir := internal.FileConfigDir(config); dir != workspace {
+		t.Fatalf("Expected GITHUB_WORKSPACE to be used, got %s", dir)
+	}
+	if err := internal.MergeFileConfig(config, internal.FileConfigDir(config)); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+
+	diffContent := `diff --git a/docs/guide/setup.md b/docs/guide/setup.md
+index 123..456 100644
+--- a/docs/guide/setup.md
++++ b/docs/guide/setup.md
+@@ -1 +1 @@
+-old
++new
+`
+	engine := &review.Engine{Config: config}
+	summary, result, err := engine.Review(diffContent)
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if summary.Description != "No reviewable files" || len(result.Comments) != 0 {
+		t.Errorf("Expected docs/ to be ignored per .manque.yml, got %q", summary.Description)
+	}
+	if config.ShouldIgnoreFile("main.go") {
+		t.Error("Expected files outside the ignore patterns to be reviewed")
+	}
+}
+
+func TestParseReviewedFiles(t *testing.T) {
+	internal.InitLogger(false)
+	diffContent := "diff --git a/vendor/lib/lib.go b/vendor/lib/lib.go\n--- a/vendor/lib/lib.go\n+++ b/vendor/lib/lib.go\n@@ -1 +1 @@\n-old\n+new\n" +
+		"diff --git a/api/api.pb.go b/api/api.pb.go\n--- a/api/api.pb.go\n+++ b/api/api.pb.go\n@@ -1 +1 @@\n-old\n+new\n" +
+		"diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n"
+	config := &internal.Config{IgnorePatterns: []string{"**/vendor/**"}, SkipGenerated: true}
+
+	files, err := parseReviewedFiles(diffContent, config)
+	if err != nil {
+		t.Fatalf("parseReviewedFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Filename != "main.go" {
+		t.Errorf("Expected vendored and generated files to be skipped like in the review, got %+v", files)
+	}
+}
+
+// fakeComparer returns a fixed compare diff or error, recording the compared range
+type fakeComparer struct {
+	diff     string
+	err      error
+	compared string
+}
+
+func (f *fakeComparer) CompareCommits(owner, repo, base, head string) (string, error) {
+	f.compared = owner + "/" + repo + ":" + base + "..." + head
+	return f.diff, f.err
+}
+
+func TestSkipDraftReview(t *testing.T) {
+	tests := []struct {
+		name         string
+		draft        bool
+		skipDrafts   bool
+		onCommand    bool
+		draftCommand bool
+		want         bool
+	}{
+		{"ready PR", false, true, false, true, false},
+		{"draft, drafts reviewed", true, false, false, true, false},
+		{"draft, drafts skipped", true, true, false, true, true},
+		{"draft, review command", true, true, true, true, false},
+		{"draft, review command disabled", true, true, true, false, true},
+	}
+
+	for _, tt := range tests {
+		config := &internal.Config{SkipDrafts: tt.skipDrafts, ReviewDraftOnCommand: tt.draftCommand}
+		if got := skipDraftReview(config, tt.draft, tt.onCommand); got != tt.want {
+			t.Errorf("%s: expected skip %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFilterDismissedComments_LineShift(t *testing.T) {
+	session := state.NewSessionManager("owner/repo", 1).GetOrCreateSession("")
+	dismissed := ai.Comment{File: "main.go", StartLine: 10, EndLine: 10, Content: "Possible nil dereference"}
+	session.DismissIssue(state.ComputeCommentHash(dismissed.File, dismissed.StartLine, dismissed.EndLine, dismissed.Content),
+		state.ComputeFuzzyHash(dismissed.File, dismissed.Content), "false positive")
+
+	comments := []ai.Comment{
+		{File: "main.go", StartLine: 14, EndLine: 14, Content: "Possible nil dereference"},
+		{File: "main.go", StartLine: 20, EndLine: 20, Content: "Unchecked error"},
+	}
+	filtered := filterDismissedComments(comments, session)
+	if len(filtered) != 1 || filtered[0].Content != "Unchecked error" {
+		t.Errorf("Expected the shifted dismissed issue to be filtered, got %+v", filtered)
+	}
+}
+
+func TestGetIncrementalDiff_PrefersCompareAPI(t *testing.T) {
+	internal.InitLogger(false)
+	comparer := &fakeComparer{diff: "diff --git a/main.go b/main.go\n"}
+	prInfo := &github.PRInfo{Repository: "acme/repo", HeadSHA: "def456"}
+
+	diff, err := getIncrementalDiff(context.Background(), comparer, true, prInfo, "abc123")
+	if err != nil {
+		t.Fatalf("getIncrementalDiff failed: %v", err)
+	}
+	if diff != comparer.diff {
+		t.Errorf("Expected the compare API diff, got %q", diff)
+	}
+	if comparer.compared != "acme/repo:abc123...def456" {
+		t.Errorf("Unexpected compared range %s", comparer.compared)
+	}
+}
+
+func TestGetIncrementalDiff_CompareFails(t *testing.T) {
+	internal.InitLogger(false)
+	comparer := &fakeComparer{err: errors.New("404 Not Found")}
+	prInfo := &github.PRInfo{Repository: "acme/repo", HeadSHA: "0000000000000000000000000000000000000002"}
+
+	// Neither commit exists locally either, so the caller falls back to the full diff
+	if _, err := getIncrementalDiff(context.Background(), comparer, true, prInfo, "0000000000000000000000000000000000000001"); err == nil {
+		t.Error("Expected an error when neither GitHub nor the local checkout has the commits")
+	}
+}
+
+func TestGetIncrementalDiff_WithoutCheckout(t *testing.T) {
+	internal.InitLogger(false)
+	comparer := &fakeComparer{err: errors.New("404 Not Found")}
+	prInfo := &github.PRInfo{Repository: "acme/repo", HeadSHA: "HEAD"}
+
+	// The working directory is a git checkout, but not of this repository
+	if _, err := getIncrementalDiff(context.Background(), comparer, false, prInfo, "HEAD"); err == nil {
+		t.Error("Expected an error instead of a diff from the working directory")
+	}
+}
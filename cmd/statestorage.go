@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/igcodinap/manque-ai/pkg/github"
+)
+
+// Modes for STATE_STORAGE
+const (
+	StateStoragePRBody    = "pr_body"
+	StateStoragePRComment = "pr_comment"
+	StateStorageNone      = "none"
+)
+
+// stateCommentNote is the visible text of the state comment, the markers themselves are hidden
+const stateCommentNote = "🤖 Review state used for incremental reviews. Please don't edit this comment."
+
+// stateCommentFinder finds the comment storing review state
+type stateCommentFinder interface {
+	FindStateComment(owner, repo string, number int) (*gh.IssueComment, error)
+}
+
+// storesStateInBody reports whether the state and session markers go in the PR body
+func storesStateInBody(storage string) bool {
+	return storage == "" || storage == StateStoragePRBody
+}
+
+// loadStateSource returns the text holding the state and session markers of earlier reviews:
+// the PR description, the body of the state comment, or "" when state isn't kept. finder is
+// nil for offline runs, which cannot read the state comment.
+func loadStateSource(storage string, finder stateCommentFinder, owner, repo string, number int, description string) (string, error) {
+	switch storage {
+	case StateStorageNone:
+		return "", nil
+	case StateStoragePRComment:
+		if finder == nil {
+			return "", nil
+		}
+		comment, err := finder.FindStateComment(owner, repo, number)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(comment.GetBody(), github.StateCommentMarker()+"\n"), nil
+	default:
+		return description, nil
+	}
+}
+
+// formatStateComment builds the body of the state comment from the review markers
+func formatStateComment(stateMarker, sessionMarker string) string {
+	var body strings.Builder
+	body.WriteString(stateCommentNote)
+	for _, marker := range []string{stateMarker, sessionMarker} {
+		if marker != "" {
+			body.WriteString("\n")
+			body.WriteString(marker)
+		}
+	}
+	return body.String()
+}
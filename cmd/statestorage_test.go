@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+// publishedStateComment serves the state comment recorded by a dry run publisher, as GitHub
+// would return it with its marker
+type publishedStateComment struct {
+	publisher *dryRunPublisher
+}
+
+func (f publishedStateComment) FindStateComment(owner, repo string, number int) (*gh.IssueComment, error) {
+	body, ok := f.publisher.output.MarkedComments[github.StateCommentMarker()]
+	if !ok {
+		return nil, nil
+	}
+	marked := github.StateCommentMarker() + "\n" + body
+	return &gh.IssueComment{Body: &marked}, nil
+}
+
+func TestStateStorage_PRCommentRoundTrip(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7, Description: "Original description", HeadSHA: "abc1234"}
+	tracker := state.NewTracker(prInfo.Repository, prInfo.Number)
+	session := state.NewSessionManager(prInfo.Repository, prInfo.Number).GetOrCreateSession("")
+	session.DismissIssue("hash1", "", "false positive")
+	stateMarker := state.CreateStateMarker(tracker.CreateNewState(prInfo.HeadSHA, 0))
+	sessionMarker := state.CreateSessionMarker(session)
+
+	config := &internal.Config{UpdatePRBody: true, StateStorage: StateStoragePRComment}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 80}}
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, stateMarker, sessionMarker, breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if publisher.output.Body == nil {
+		t.Fatal("Expected the PR body to still get the summary")
+	}
+	if strings.Contains(*publisher.output.Body, stateMarker) || strings.Contains(*publisher.output.Body, sessionMarker) {
+		t.Errorf("Expected no markers in the PR body, got %q", *publisher.output.Body)
+	}
+
+	source, err := loadStateSource(config.StateStorage, publishedStateComment{publisher}, "owner", "repo", 7, *publisher.output.Body)
+	if err != nil {
+		t.Fatalf("loadStateSource failed: %v", err)
+	}
+	isIncremental, previous := tracker.IsIncrementalReview(source, "def5678")
+	if !isIncremental || previous.LastReviewedSHA != "abc1234" {
+		t.Errorf("Expected the state to round-trip through the comment, got %v %+v", isIncremental, previous)
+	}
+	restored := state.NewSessionManager(prInfo.Repository, prInfo.Number).GetOrCreateSession(source)
+	if !restored.IsDismissed("hash1", "") {
+		t.Errorf("Expected the session to round-trip through the comment, got %+v", restored.Dismissed)
+	}
+}
+
+func TestStateStorage_None(t *testing.T) {
+	internal.InitLogger(false)
+	publisher := newDryRunPublisher(filepath.Join(t.TempDir(), "result.json"))
+
+	prInfo := &github.PRInfo{Repository: "owner/repo", Number: 7, Description: "Description"}
+	config := &internal.Config{UpdatePRBody: true, StateStorage: StateStorageNone}
+	summary := &ai.PRSummary{Title: "Title"}
+	result := &ai.ReviewResult{Review: ai.ReviewSummary{Score: 80}}
+	if err := postResultsToGitHub(publisher, prInfo, summary, result, config, "<!-- manque-ai-state:{}-->", "<!-- manque-ai-session:{}-->", breakingReportTargets{}, "", false); err != nil {
+		t.Fatalf("postResultsToGitHub failed: %v", err)
+	}
+
+	if strings.Contains(*publisher.output.Body, "manque-ai-state") || strings.Contains(*publisher.output.Body, "manque-ai-session") {
+		t.Errorf("Expected no markers in the PR body, got %q", *publisher.output.Body)
+	}
+	if len(publisher.output.MarkedComments) != 0 {
+		t.Errorf("Expected no state comment, got %v", publisher.output.MarkedComments)
+	}
+
+	source, _ := loadStateSource(config.StateStorage, nil, "owner", "repo", 7, "<!-- manque-ai-state:{}-->")
+	if source != "" {
+		t.Errorf("Expected no state to be read, got %q", source)
+	}
+}
@@ -6,16 +6,22 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/igcodinap/manque-ai/internal"
 	"github.com/igcodinap/manque-ai/pkg/ai"
 	"github.com/igcodinap/manque-ai/pkg/commands"
+	"github.com/igcodinap/manque-ai/pkg/diff"
 	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/markers"
+	"github.com/igcodinap/manque-ai/pkg/review"
 	"github.com/igcodinap/manque-ai/pkg/state"
 	"github.com/spf13/cobra"
 )
@@ -25,10 +31,18 @@ var (
 	webhookSecret string
 )
 
+const (
+	// readyCheckTimeout bounds the LLM ping made by the readiness probe
+	readyCheckTimeout = 10 * time.Second
+	// readyCacheTTL is how long a readiness result is reused, so frequent probes don't
+	// each cost an LLM request
+	readyCacheTTL = 60 * time.Second
+)
+
 var webhookCmd = &cobra.Command{
 	Use:   "webhook",
-	Short: "Start webhook server for interactive commands",
-	Long:  `Starts an HTTP server that listens for GitHub webhook events and responds to @manque commands in PR comments.`,
+	Short: "Start webhook server for interactive commands and PR reviews",
+	Long:  `Starts an HTTP server that listens for GitHub webhook events, responds to @manque commands in PR comments and reviews PRs when they are opened, reopened or pushed to.`,
 	Run:   runWebhook,
 }
 
@@ -45,11 +59,13 @@ type WebhookPayload struct {
 		Number int    `json:"number"`
 		Title  string `json:"title"`
 		Body   string `json:"body"`
+		Draft  bool   `json:"draft"`
 	} `json:"issue"`
 	PullRequest struct {
 		Number int    `json:"number"`
 		Title  string `json:"title"`
 		Body   string `json:"body"`
+		Draft  bool   `json:"draft"`
 		Head   struct {
 			SHA string `json:"sha"`
 		} `json:"head"`
@@ -60,9 +76,10 @@ type WebhookPayload struct {
 		User struct {
 			Login string `json:"login"`
 		} `json:"user"`
-		Path     *string `json:"path"`     // File path for review comments
-		Position *int    `json:"position"` // Line position for review comments
-		Line     *int    `json:"line"`     // Line number for review comments
+		Path      *string `json:"path"`       // File path for review comments
+		Position  *int    `json:"position"`   // Line position for review comments
+		Line      *int    `json:"line"`       // Line number for review comments
+		StartLine *int    `json:"start_line"` // First line of multi-line review comments
 	} `json:"comment"`
 	Repository struct {
 		FullName string `json:"full_name"`
@@ -87,6 +104,17 @@ func runWebhook(cmd *cobra.Command, args []string) {
 		internal.Logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
+	if err := markers.SetNamespace(config.MarkerNamespace); err != nil {
+		internal.Logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ai.SetMaxConcurrency(config.LLMMaxConcurrency)
+
+	// Load file-based config (.manque.yml); BOT_ALIASES takes precedence over its bot_aliases
+	if err := internal.MergeFileConfig(config, internal.FileConfigDir(config)); err != nil {
+		internal.Logger.Warn("Failed to load .manque.yml config", "error", err)
+	}
 
 	// Get webhook secret from flag or env
 	secret := webhookSecret
@@ -94,14 +122,10 @@ func runWebhook(cmd *cobra.Command, args []string) {
 		secret = os.Getenv("GITHUB_WEBHOOK_SECRET")
 	}
 
-	// Initialize clients
+	// Initialize clients; the LLM client is set up like the review engine's, so commands and
+	// reviews share the review language, prompt templates and response cache
 	githubClient := github.NewClient(config.GitHubToken, config.GitHubAPIURL)
-	aiClient, err := ai.NewClient(ai.Config{
-		Provider: config.LLMProvider,
-		APIKey:   config.LLMAPIKey,
-		Model:    config.LLMModel,
-		BaseURL:  config.LLMBaseURL,
-	})
+	aiClient, err := review.NewAIClient(config)
 	if err != nil {
 		internal.Logger.Error("Failed to initialize AI client", "error", err)
 		os.Exit(1)
@@ -110,10 +134,12 @@ func runWebhook(cmd *cobra.Command, args []string) {
 	handler := NewWebhookHandler(githubClient, aiClient, config, secret)
 
 	http.HandleFunc("/webhook", handler.HandleWebhook)
+	// Liveness only: the process is up. Readiness, including LLM connectivity, is /ready.
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	http.HandleFunc("/ready", handler.HandleReady)
 
 	addr := fmt.Sprintf(":%d", webhookPort)
 	internal.Logger.Info("Starting webhook server", "port", webhookPort)
@@ -131,6 +157,24 @@ type WebhookHandler struct {
 	webhookSecret  string
 	commandParser  *commands.Parser
 	commandHandler *commands.Handler
+
+	// Last readiness check, reused for readyCacheTTL
+	readyMu        sync.Mutex
+	readyCheckedAt time.Time
+	readyErr       error
+	readyPing      chan struct{} // Closed when the LLM ping in flight finishes
+
+	reviews     sync.WaitGroup // Reviews running in the background
+	reviewingMu sync.Mutex
+	reviewing   map[string]*runningReview // PRs with a review running, by reviewKey
+}
+
+// runningReview is a PR's review in progress and the one queued to follow it, if any
+type runningReview struct {
+	headSHA   string // Head commit being reviewed; empty when the review was requested by command
+	rerun     bool   // Another review runs once this one finishes
+	rerunSHA  string // Newest head commit pushed during the review
+	rerunOpts reviewOptions
 }
 
 // NewWebhookHandler creates a new webhook handler
@@ -140,8 +184,9 @@ func NewWebhookHandler(ghClient *github.Client, aiClient ai.Client, config *inte
 		aiClient:       aiClient,
 		config:         config,
 		webhookSecret:  secret,
-		commandParser:  commands.NewParser("manque"),
+		commandParser:  commands.NewParser("manque", config.BotAliases...),
 		commandHandler: commands.NewHandler(aiClient, config),
+		reviewing:      make(map[string]*runningReview),
 	}
 }
 
@@ -177,12 +222,73 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 		h.handleIssueComment(body, w)
 	case "pull_request_review_comment":
 		h.handleReviewComment(body, w)
+	case "pull_request":
+		h.handlePullRequest(body, w)
 	default:
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Event type not handled"))
 	}
 }
 
+// HandleReady reports whether the server can serve commands, checking that the LLM API is
+// reachable with the configured credentials. It returns 503 with the error if it is not.
+func (h *WebhookHandler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	if err := h.checkReady(r.Context()); err != nil {
+		http.Error(w, fmt.Sprintf("LLM unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// checkReady pings the LLM, reusing the previous result for readyCacheTTL. Concurrent
+// probes share one ping, which outlives a probe that gives up waiting on ctx.
+func (h *WebhookHandler) checkReady(ctx context.Context) error {
+	h.readyMu.Lock()
+	if !h.readyCheckedAt.IsZero() && time.Since(h.readyCheckedAt) < readyCacheTTL {
+		err := h.readyErr
+		h.readyMu.Unlock()
+		return err
+	}
+	done := h.readyPing
+	if done == nil {
+		done = make(chan struct{})
+		h.readyPing = done
+		go h.pingLLM(done)
+	}
+	h.readyMu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	h.readyMu.Lock()
+	defer h.readyMu.Unlock()
+	return h.readyErr
+}
+
+// pingLLM runs the readiness ping with its own timeout and records the result, closing done
+// when finished. A canceled ping says nothing about the LLM, so it isn't cached.
+func (h *WebhookHandler) pingLLM(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), readyCheckTimeout)
+	defer cancel()
+	_, err := h.aiClient.GenerateResponse(ctx, "ping")
+	if err != nil {
+		internal.Logger.Warn("Readiness check failed", "error", err)
+	}
+
+	h.readyMu.Lock()
+	defer h.readyMu.Unlock()
+	h.readyErr = err
+	if !errors.Is(err, context.Canceled) {
+		h.readyCheckedAt = time.Now()
+	}
+	h.readyPing = nil
+	close(done)
+}
+
 func (h *WebhookHandler) verifySignature(body []byte, signature string) bool {
 	if !strings.HasPrefix(signature, "sha256=") {
 		return false
@@ -250,8 +356,10 @@ func (h *WebhookHandler) handleIssueComment(body []byte, w http.ResponseWriter)
 	}
 
 	// Build command context
+	ctx, cancel := h.config.OperationContext()
+	defer cancel()
 	cmdCtx := &commands.CommandContext{
-		Ctx:                 context.Background(),
+		Ctx:                 ctx,
 		PRTitle:             payload.Issue.Title,
 		PRDescription:       payload.Issue.Body,
 		PRNumber:            prNumber,
@@ -262,9 +370,10 @@ func (h *WebhookHandler) handleIssueComment(body []byte, w http.ResponseWriter)
 
 	// Load session if exists
 	sessionManager := state.NewSessionManager(payload.Repository.FullName, prNumber)
-	cmdCtx.Session = sessionManager.GetOrCreateSession(payload.Issue.Body)
+	cmdCtx.Session = sessionManager.GetOrCreateSession(h.sessionSource(owner, repo, prNumber, payload.Issue.Body))
 
-	// Process commands
+	// Process commands, collecting session changes to persist once they are all handled
+	var changes []func(*state.Session)
 	for _, cmd := range cmds {
 		result, err := h.commandHandler.Handle(cmd, cmdCtx)
 		if err != nil {
@@ -272,28 +381,41 @@ func (h *WebhookHandler) handleIssueComment(body []byte, w http.ResponseWriter)
 			continue
 		}
 
+		if result.TriggerReview && skipDraftReview(h.config, payload.Issue.Draft, true) {
+			result.TriggerReview = false
+			result.Response = draftSkippedNote
+		}
+
 		// Post response as comment
-		if result.Response != "" {
-			err = h.githubClient.CreateComment(owner, repo, prNumber, result.Response)
-			if err != nil {
+		reply := func(response string) {
+			if err := h.githubClient.ReplyToIssueComment(owner, repo, prNumber, payload.Comment.User.Login, response); err != nil {
 				internal.Logger.Error("Failed to post response", "error", err)
 			}
 		}
+		if result.ReviewFile != "" {
+			h.startFileReview(owner, repo, prNumber, result.ReviewFile, reply)
+		}
+		if result.Response != "" {
+			reply(result.Response)
+		}
 
-		// Handle dismiss action
-		if result.DismissIssue && result.DismissedHash != "" && cmdCtx.Session != nil {
-			cmdCtx.Session.DismissIssue(result.DismissedHash, result.DismissReason)
-			// Note: Session would need to be persisted back to PR body
-			// This requires updating the PR description with the new session marker
+		// Handle dismiss and resolve actions
+		for _, change := range sessionChanges(result) {
+			change(cmdCtx.Session)
+			changes = append(changes, change)
 		}
 
-		// Handle regenerate action
+		// Handle regenerate action; drafts were already checked against REVIEW_DRAFT_ON_COMMAND
 		if result.TriggerReview {
 			internal.Logger.Info("Triggering full review", "pr", prNumber)
-			// This would trigger a new review - could be done async
+			h.startReview(owner, repo, prNumber, "", reviewOptions{OnCommand: true})
 		}
 	}
 
+	if err := h.persistSession(owner, repo, prNumber, sessionManager, changes); err != nil {
+		internal.Logger.Error("Failed to persist session", "error", err)
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Commands processed"))
 }
@@ -332,6 +454,10 @@ func (h *WebhookHandler) handleReviewComment(body []byte, w http.ResponseWriter)
 	if payload.Comment.Line != nil {
 		line = *payload.Comment.Line
 	}
+	startLine := 0
+	if payload.Comment.StartLine != nil {
+		startLine = *payload.Comment.StartLine
+	}
 
 	// Parse commands from comment
 	cmds := h.commandParser.Parse(payload.Comment.Body, payload.Comment.ID, file, line)
@@ -347,9 +473,14 @@ func (h *WebhookHandler) handleReviewComment(body []byte, w http.ResponseWriter)
 	// Fetch conversation thread for context
 	thread, err := h.githubClient.GetCommentThread(owner, repo, prNumber, payload.Comment.ID)
 	var conversationHistory []commands.ConversationMessage
+	originalIssue := ""
 	if err != nil {
 		internal.Logger.Warn("Failed to fetch conversation thread", "error", err)
 	} else {
+		// The thread root is the bot's original finding when the user replies to a review comment
+		if len(thread) > 0 && thread[0].IsBot {
+			originalIssue = strings.TrimSpace(strings.TrimPrefix(thread[0].Body, github.BotCommentMarker()))
+		}
 		// Convert to commands.ConversationMessage
 		for _, msg := range thread {
 			conversationHistory = append(conversationHistory, commands.ConversationMessage{
@@ -361,8 +492,10 @@ func (h *WebhookHandler) handleReviewComment(body []byte, w http.ResponseWriter)
 	}
 
 	// Build command context with file context
+	ctx, cancel := h.config.OperationContext()
+	defer cancel()
 	cmdCtx := &commands.CommandContext{
-		Ctx:                 context.Background(),
+		Ctx:                 ctx,
 		PRTitle:             payload.PullRequest.Title,
 		PRDescription:       payload.PullRequest.Body,
 		PRNumber:            prNumber,
@@ -370,14 +503,17 @@ func (h *WebhookHandler) handleReviewComment(body []byte, w http.ResponseWriter)
 		CommentBody:         payload.Comment.Body,
 		FilePath:            file,
 		FileLine:            line,
+		StartLine:           startLine,
+		OriginalIssue:       originalIssue,
 		ConversationHistory: conversationHistory,
 	}
 
 	// Load session
 	sessionManager := state.NewSessionManager(payload.Repository.FullName, prNumber)
-	cmdCtx.Session = sessionManager.GetOrCreateSession(payload.PullRequest.Body)
+	cmdCtx.Session = sessionManager.GetOrCreateSession(h.sessionSource(owner, repo, prNumber, payload.PullRequest.Body))
 
-	// Process commands
+	// Process commands, collecting session changes to persist once they are all handled
+	var changes []func(*state.Session)
 	for _, cmd := range cmds {
 		result, err := h.commandHandler.Handle(cmd, cmdCtx)
 		if err != nil {
@@ -386,21 +522,290 @@ func (h *WebhookHandler) handleReviewComment(body []byte, w http.ResponseWriter)
 		}
 
 		// Reply to the review comment thread
-		if result.Response != "" {
-			err = h.githubClient.ReplyToComment(owner, repo, prNumber, payload.Comment.ID, result.Response)
-			if err != nil {
+		reply := func(response string) {
+			if err := h.githubClient.ReplyToComment(owner, repo, prNumber, payload.Comment.ID, response); err != nil {
 				internal.Logger.Error("Failed to reply to comment", "error", err)
 				// Fall back to issue comment
-				_ = h.githubClient.CreateComment(owner, repo, prNumber, result.Response)
+				_ = h.githubClient.CreateComment(owner, repo, prNumber, response)
 			}
 		}
+		if result.ReviewFile != "" {
+			h.startFileReview(owner, repo, prNumber, result.ReviewFile, reply)
+		}
+		if result.Response != "" {
+			reply(result.Response)
+		}
 
-		// Handle dismiss action
-		if result.DismissIssue && result.DismissedHash != "" && cmdCtx.Session != nil {
-			cmdCtx.Session.DismissIssue(result.DismissedHash, result.DismissReason)
+		// Handle dismiss and resolve actions
+		for _, change := range sessionChanges(result) {
+			change(cmdCtx.Session)
+			changes = append(changes, change)
 		}
+
+		// Resolve the thread on GitHub too, so it collapses in the PR
+		if result.ResolveIssue {
+			if err := h.resolveThread(owner, repo, prNumber, payload.Comment.ID); err != nil {
+				internal.Logger.Warn("Failed to resolve review thread", "error", err)
+			}
+		}
+	}
+
+	if err := h.persistSession(owner, repo, prNumber, sessionManager, changes); err != nil {
+		internal.Logger.Error("Failed to persist session", "error", err)
 	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Commands processed"))
 }
+
+// reviewedPRActions are the pull_request event actions that trigger a review. Pushes to a
+// reviewed PR (synchronize) get an incremental review of the new commits.
+var reviewedPRActions = map[string]bool{
+	"opened":      true,
+	"reopened":    true,
+	"synchronize": true,
+}
+
+func (h *WebhookHandler) handlePullRequest(body []byte, w http.ResponseWriter) {
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		internal.Logger.Error("Failed to parse webhook payload", "error", err)
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if !reviewedPRActions[payload.Action] {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	owner := payload.Repository.Owner.Login
+	repo := payload.Repository.Name
+	prNumber := payload.PullRequest.Number
+	internal.Logger.Info("Pull request event", "repo", payload.Repository.FullName, "pr", prNumber, "action", payload.Action)
+
+	// GitHub stops waiting after 10 seconds and redelivers, so the review runs after responding
+	if !h.startReview(owner, repo, prNumber, payload.PullRequest.Head.SHA, reviewOptions{}) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("Review already running"))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("Review started"))
+}
+
+// startReview reviews a PR in the background, since a review takes longer than GitHub waits
+// for a webhook response. Only one review runs per PR: a new head commit or a command that
+// arrives during it queues one more review, which fetches the PR again and so reviews the
+// latest head. It returns false when headSHA is already being reviewed or queued.
+func (h *WebhookHandler) startReview(owner, repo string, number int, headSHA string, opts reviewOptions) bool {
+	key := reviewKey(owner, repo, number)
+	started, queued := h.claimReview(key, headSHA, opts)
+	if !started {
+		if queued {
+			internal.Logger.Info("Review queued after the running one", "pr", number, "sha", headSHA)
+		} else {
+			internal.Logger.Info("Review already running", "pr", number, "sha", headSHA)
+		}
+		return queued
+	}
+
+	h.reviews.Add(1)
+	go func() {
+		defer h.reviews.Done()
+		for {
+			h.reviewPR(owner, repo, number, opts)
+			var rerun bool
+			if opts, rerun = h.nextReview(key); !rerun {
+				return
+			}
+		}
+	}()
+	return true
+}
+
+// reviewPR fetches a PR and runs a full review of its current head
+func (h *WebhookHandler) reviewPR(owner, repo string, number int, opts reviewOptions) {
+	// Events carry no diff, so the PR is fetched like a CLI review
+	prInfo, err := h.githubClient.GetPR(owner, repo, number)
+	if err != nil {
+		internal.Logger.Error("Failed to get PR", "error", err, "pr", number)
+		return
+	}
+
+	// The server's client outlives reviews, and people comment in between
+	h.githubClient.InvalidateCommentCache(number)
+	outcome, err := RunReviewForPR(h.githubClient, h.reviewEngine(), h.config, prInfo, opts)
+	if err != nil {
+		internal.Logger.Error("Failed to review PR", "error", err, "pr", number)
+		return
+	}
+	internal.Logger.Info("Review finished", "pr", number, "outcome", outcome)
+}
+
+// reviewKey identifies a PR among the running reviews
+func reviewKey(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
+
+// claimReview marks the PR as being reviewed at headSHA. If a review is already running,
+// started is false and queued reports whether a review was queued to follow it; a head
+// commit already being reviewed or queued isn't queued again.
+func (h *WebhookHandler) claimReview(key, headSHA string, opts reviewOptions) (started, queued bool) {
+	h.reviewingMu.Lock()
+	defer h.reviewingMu.Unlock()
+
+	running, ok := h.reviewing[key]
+	if !ok {
+		h.reviewing[key] = &runningReview{headSHA: headSHA}
+		return true, false
+	}
+
+	if headSHA != "" && (headSHA == running.headSHA || (running.rerun && headSHA == running.rerunSHA)) {
+		return false, false
+	}
+	running.rerun = true
+	if headSHA != "" {
+		running.rerunSHA = headSHA
+	}
+	running.rerunOpts.OnCommand = running.rerunOpts.OnCommand || opts.OnCommand
+	return false, true
+}
+
+// nextReview finishes the PR's running review. It returns the options of the review queued
+// during it, and false when none was queued and the PR is released.
+func (h *WebhookHandler) nextReview(key string) (reviewOptions, bool) {
+	h.reviewingMu.Lock()
+	defer h.reviewingMu.Unlock()
+
+	running := h.reviewing[key]
+	if !running.rerun {
+		delete(h.reviewing, key)
+		return reviewOptions{}, false
+	}
+
+	opts := running.rerunOpts
+	*running = runningReview{headSHA: running.rerunSHA}
+	return opts, true
+}
+
+// startFileReview reviews one changed file in the background and posts the reply with reply
+func (h *WebhookHandler) startFileReview(owner, repo string, number int, path string, reply func(string)) {
+	h.reviews.Add(1)
+	go func() {
+		defer h.reviews.Done()
+		reply(h.reviewFile(owner, repo, number, path))
+	}()
+}
+
+// reviewFile reviews one changed file of a PR on its own, for `@manque review <path>`, and
+// returns the reply with its findings
+func (h *WebhookHandler) reviewFile(owner, repo string, number int, path string) string {
+	prInfo, err := h.githubClient.GetPR(owner, repo, number)
+	if err != nil {
+		internal.Logger.Error("Failed to get PR", "error", err, "pr", number)
+		return fmt.Sprintf("Sorry, I couldn't fetch this PR to review `%s`.", path)
+	}
+
+	fileDiff := diff.FileSection(prInfo.Diff, path)
+	if fileDiff == "" {
+		return fmt.Sprintf("`%s` isn't changed in this PR, so there is nothing to review. Use the file's full path from the PR, e.g. `@manque review pkg/api/client.go`.", path)
+	}
+
+	internal.Logger.Info("Reviewing file", "pr", number, "file", path)
+	_, result, err := h.reviewEngine().ReviewWithContext(prInfo.Title, prInfo.Description, fileDiff)
+	if err != nil {
+		internal.Logger.Error("Failed to review file", "error", err, "file", path)
+		return fmt.Sprintf("Sorry, the review of `%s` failed. Please try again later.", path)
+	}
+	return formatFileReview(path, result)
+}
+
+// formatFileReview builds the reply to `@manque review <path>` from the file's review
+func formatFileReview(path string, result *ai.ReviewResult) string {
+	if len(result.Comments) == 0 {
+		return fmt.Sprintf("✅ I reviewed `%s` again and found no issues.", path)
+	}
+	return fmt.Sprintf("I reviewed `%s` again and found %d issue(s):\n%s", path, len(result.Comments), formatCommentList(result.Comments))
+}
+
+// reviewEngine returns the engine for reviews triggered by events. The server has no
+// checkout of the repository, so nothing is read from git or its working directory.
+func (h *WebhookHandler) reviewEngine() *review.Engine {
+	return &review.Engine{
+		AIClient:   h.aiClient,
+		Config:     h.config,
+		NoCheckout: true,
+	}
+}
+
+// resolveThread marks the review thread holding commentID as resolved, unless it already is
+func (h *WebhookHandler) resolveThread(owner, repo string, number int, commentID int64) error {
+	thread, err := h.githubClient.FindReviewThread(owner, repo, number, commentID)
+	if err != nil {
+		return err
+	}
+	if thread == nil || thread.IsResolved {
+		return nil
+	}
+	return h.githubClient.ResolveReviewThread(thread.ID)
+}
+
+// sessionChanges returns the session updates requested by a command result
+func sessionChanges(result *commands.CommandResult) []func(*state.Session) {
+	var changes []func(*state.Session)
+	if result.DismissIssue && result.DismissedHash != "" {
+		hash, fuzzyHash, reason := result.DismissedHash, result.DismissedFuzzyHash, result.DismissReason
+		changes = append(changes, func(s *state.Session) { s.DismissIssue(hash, fuzzyHash, reason) })
+	}
+	if result.ResolveIssue && result.ResolvedHash != "" {
+		hash, fuzzyHash := result.ResolvedHash, result.ResolvedFuzzyHash
+		changes = append(changes, func(s *state.Session) { s.ResolveIssue(hash, fuzzyHash) })
+	}
+	return changes
+}
+
+// sessionSource returns the text holding the session, read from where STATE_STORAGE keeps it
+func (h *WebhookHandler) sessionSource(owner, repo string, number int, description string) string {
+	source, err := loadStateSource(h.config.StateStorage, h.githubClient, owner, repo, number, description)
+	if err != nil {
+		internal.Logger.Warn("Failed to read review state", "error", err)
+	}
+	return source
+}
+
+// persistSession applies changes to the stored session and writes it back, to the PR body or
+// the state comment per STATE_STORAGE. The session is fetched again right before writing, so
+// edits and reviews made while the commands were handled are kept.
+func (h *WebhookHandler) persistSession(owner, repo string, number int, manager *state.SessionManager, changes []func(*state.Session)) error {
+	if len(changes) == 0 || h.config.StateStorage == StateStorageNone {
+		return nil
+	}
+
+	inComment := h.config.StateStorage == StateStoragePRComment
+	var body string
+	var err error
+	if inComment {
+		body, err = loadStateSource(h.config.StateStorage, h.githubClient, owner, repo, number, "")
+		if body == "" {
+			body = stateCommentNote
+		}
+	} else {
+		body, err = h.githubClient.GetPRBody(owner, repo, number)
+	}
+	if err != nil {
+		return err
+	}
+
+	session := manager.GetOrCreateSession(body)
+	for _, change := range changes {
+		change(session)
+	}
+
+	updated := state.ReplaceSessionMarker(body, session)
+	if inComment {
+		return h.githubClient.CreateOrUpdateMarkedComment(owner, repo, number, github.StateCommentMarker(), updated)
+	}
+	return h.githubClient.UpdatePR(owner, repo, number, nil, &updated)
+}
@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/github"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+// postedFinding is the review comment the bot posted for finding, as postResultsToGitHub
+// formats it
+var postedFinding = ai.Comment{
+	File:          "main.go",
+	StartLine:     5,
+	EndLine:       5,
+	Header:        "🐛 Possible nil dereference",
+	Content:       "`cfg` may be nil when the file is missing.",
+	SuggestedCode: "if cfg == nil {\n\treturn nil\n}\n",
+}
+
+// sessionServer serves a PR whose current body is prBody and a bot review thread on
+// main.go:5 holding postedFinding, recording PR body updates
+func sessionServer(t *testing.T, prBody string, updates *[]string) *github.Client {
+	t.Helper()
+	rootBody := github.BotCommentMarker() + "\n**" + postedFinding.Header + "**\n\n" + postedFinding.Content +
+		"\n\n```suggestion\n" + postedFinding.SuggestedCode + "```"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/graphql"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1/comments"):
+			w.Write([]byte(`[
+				{"id":10,"path":"main.go","line":5,"body":` + jsonString(rootBody) + `},
+				{"id":11,"path":"main.go","line":5,"in_reply_to_id":10,"body":"@manque ignore handled upstream"}
+			]`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls/1/comments"):
+			w.Write([]byte(`{"id":12}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"number": 1, "body": prBody})
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			body, _ := io.ReadAll(r.Body)
+			var update struct {
+				Body string `json:"body"`
+			}
+			json.Unmarshal(body, &update)
+			*updates = append(*updates, update.Body)
+			w.Write([]byte(`{"number":1}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return github.NewClient("token", server.URL)
+}
+
+func reviewCommentEvent(t *testing.T, body string) *http.Request {
+	t.Helper()
+	payload := `{
+		"action": "created",
+		"pull_request": {"number": 1, "body": "Stale description"},
+		"comment": {"id": 11, "body": ` + jsonString(body) + `, "path": "main.go", "line": 5, "user": {"login": "alice"}},
+		"repository": {"full_name": "owner/repo", "name": "repo", "owner": {"login": "owner"}}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "pull_request_review_comment")
+	return req
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func TestWebhook_PersistsDismissal(t *testing.T) {
+	internal.InitLogger(false)
+
+	// The body changed since the event was sent: a review recorded an earlier dismissal
+	current := state.NewSessionManager("owner/repo", 1).GetOrCreateSession("")
+	current.DismissIssue("earlier", "", "duplicate")
+	prBody := "Description\n<!-- ai-review-start -->\n" + state.CreateSessionMarker(current) + "\n<!-- ai-review-end -->"
+
+	var updates []string
+	handler := NewWebhookHandler(sessionServer(t, prBody, &updates), nil, &internal.Config{}, "")
+
+	recorder := httptest.NewRecorder()
+	handler.HandleWebhook(recorder, reviewCommentEvent(t, "@manque ignore handled upstream"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", recorder.Code)
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("Expected the PR body to be updated once, got %d updates", len(updates))
+	}
+	if !strings.HasPrefix(updates[0], "Description\n<!-- ai-review-start -->\n") || !strings.HasSuffix(updates[0], "<!-- ai-review-end -->") {
+		t.Errorf("Expected the marker to be replaced in the current body, got %q", updates[0])
+	}
+
+	session := state.ExtractSessionFromBody(updates[0])
+	if session == nil {
+		t.Fatal("Expected a session marker in the updated body")
+	}
+	exact := state.ComputeCommentHash(postedFinding.File, postedFinding.StartLine, postedFinding.EndLine, postedFinding.Content)
+	if !session.IsDismissed(exact, "") {
+		t.Errorf("Expected the finding to be dismissed by the hash the review computes, got dismissals %+v", session.Dismissed)
+	}
+	if !session.IsDismissed("earlier", "") {
+		t.Error("Expected the dismissal already in the current body to be kept")
+	}
+}
+
+func TestWebhook_PersistsResolution(t *testing.T) {
+	internal.InitLogger(false)
+	var updates []string
+	handler := NewWebhookHandler(sessionServer(t, "Description", &updates), nil, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), reviewCommentEvent(t, "@manque resolve"))
+
+	if len(updates) != 1 {
+		t.Fatalf("Expected the PR body to be updated once, got %d updates", len(updates))
+	}
+	session := state.ExtractSessionFromBody(updates[0])
+	if session == nil {
+		t.Fatal("Expected a session marker in the updated body")
+	}
+	if kept := filterDismissedComments([]ai.Comment{postedFinding}, session); len(kept) != 0 {
+		t.Errorf("Expected the next review to skip the resolved finding, got resolutions %v", session.Resolved)
+	}
+	moved := postedFinding
+	moved.StartLine, moved.EndLine = 9, 9
+	if kept := filterDismissedComments([]ai.Comment{moved}, session); len(kept) != 0 {
+		t.Errorf("Expected the resolved finding to stay skipped after moving, got resolutions %v", session.ResolvedFuzzy)
+	}
+}
+
+func TestWebhook_NoSessionChange(t *testing.T) {
+	internal.InitLogger(false)
+	var updates []string
+	handler := NewWebhookHandler(sessionServer(t, "Description", &updates), nil, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), reviewCommentEvent(t, "@manque help"))
+	if len(updates) != 0 {
+		t.Errorf("Expected no PR body update for a command that leaves the session alone, got %q", updates)
+	}
+}
+
+// pingClient answers GenerateResponse with err, counting the calls
+type pingClient struct {
+	ai.Client
+	err   error
+	calls int
+}
+
+func (c *pingClient) GenerateResponse(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.err != nil {
+		return "", c.err
+	}
+	return "pong", nil
+}
+
+func TestWebhook_ReadyFailsWhenLLMUnavailable(t *testing.T) {
+	internal.InitLogger(false)
+	client := &pingClient{err: errors.New("401 invalid api key")}
+	handler := NewWebhookHandler(nil, client, &internal.Config{}, "")
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.HandleReady(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected 503, got %d", recorder.Code)
+		}
+		if !strings.Contains(recorder.Body.String(), "invalid api key") {
+			t.Errorf("Expected the LLM error in the response, got %q", recorder.Body.String())
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected the readiness result to be cached, got %d LLM calls", client.calls)
+	}
+}
+
+func TestWebhook_ReadyDoesNotCacheCancellation(t *testing.T) {
+	internal.InitLogger(false)
+	client := &pingClient{err: context.Canceled}
+	handler := NewWebhookHandler(nil, client, &internal.Config{}, "")
+
+	if err := handler.checkReady(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the canceled ping to be reported, got %v", err)
+	}
+	client.err = nil
+	if err := handler.checkReady(context.Background()); err != nil {
+		t.Errorf("Expected a fresh ping after a canceled one, got %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("Expected 2 LLM calls, got %d", client.calls)
+	}
+}
+
+func TestWebhook_ReadyOutlivesProbe(t *testing.T) {
+	internal.InitLogger(false)
+	client := &pingClient{}
+	handler := NewWebhookHandler(nil, client, &internal.Config{}, "")
+
+	// The probe gives up at once, but the ping still completes and is cached
+	probeCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler.checkReady(probeCtx)
+	if err := handler.checkReady(context.Background()); err != nil {
+		t.Fatalf("Expected the LLM to be ready, got %v", err)
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected the ping to be shared and cached, got %d LLM calls", client.calls)
+	}
+}
+
+func TestWebhook_Ready(t *testing.T) {
+	internal.InitLogger(false)
+	handler := NewWebhookHandler(nil, &pingClient{}, &internal.Config{}, "")
+
+	recorder := httptest.NewRecorder()
+	handler.HandleReady(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+// reviewServer serves PR 1 at head "new5678" with prBody, recording the requests and the
+// bodies of those that aren't GETs
+func reviewServer(t *testing.T, prBody string, paths *[]string) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			request += " " + string(body)
+		}
+		*paths = append(*paths, request)
+		isDiff := strings.Contains(r.Header.Get("Accept"), "diff")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/compare/old1234...new5678"):
+			w.Write([]byte("diff --git a/new.go b/new.go\n--- a/new.go\n+++ b/new.go\n@@ -1 +1 @@\n-a\n+pushed\n"))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1") && isDiff:
+			w.Write([]byte("diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+full\n" +
+				"diff --git a/other.go b/other.go\n--- a/other.go\n+++ b/other.go\n@@ -1 +1 @@\n-b\n+other\n"))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"number": 1, "title": "Add feature", "body": prBody, "head": map[string]string{"sha": "new5678"},
+			})
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return github.NewClient("token", server.URL)
+}
+
+func pullRequestEvent(action, headSHA string) *http.Request {
+	payload := `{
+		"action": "` + action + `",
+		"pull_request": {"number": 1, "head": {"sha": "` + headSHA + `"}},
+		"repository": {"full_name": "owner/repo", "name": "repo", "owner": {"login": "owner"}}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	return req
+}
+
+func TestWebhook_SynchronizeReviewsIncrementally(t *testing.T) {
+	internal.InitLogger(false)
+	previous := state.NewTracker("owner/repo", 1).CreateNewState("old1234", 1)
+	prBody := "Description\n<!-- ai-review-start -->\n" + state.CreateStateMarker(previous) + "\n<!-- ai-review-end -->"
+
+	var paths []string
+	aiClient := ai.NewMockClient()
+	aiClient.Review = &ai.ReviewResult{}
+	handler := NewWebhookHandler(reviewServer(t, prBody, &paths), aiClient, &internal.Config{}, "")
+
+	recorder := httptest.NewRecorder()
+	handler.HandleWebhook(recorder, pullRequestEvent("synchronize", "new5678"))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	handler.reviews.Wait()
+
+	var reviewedDiff string
+	for _, prompt := range aiClient.Prompts() {
+		if strings.HasPrefix(prompt.Method, "GenerateCodeReview") {
+			reviewedDiff = prompt.Diff
+		}
+	}
+	if !strings.Contains(reviewedDiff, "pushed") || strings.Contains(reviewedDiff, "full") {
+		t.Errorf("Expected only the pushed changes to be reviewed, got %q", reviewedDiff)
+	}
+}
+
+func TestWebhook_OneReviewPerPR(t *testing.T) {
+	handler := NewWebhookHandler(nil, nil, &internal.Config{}, "")
+	key := reviewKey("owner", "repo", 1)
+
+	if started, _ := handler.claimReview(key, "new5678", reviewOptions{}); !started {
+		t.Fatal("Expected the first delivery to start a review")
+	}
+	if started, queued := handler.claimReview(key, "new5678", reviewOptions{}); started || queued {
+		t.Error("Expected a redelivery not to review the same commit again")
+	}
+	if started, queued := handler.claimReview(key, "next9012", reviewOptions{}); started || !queued {
+		t.Error("Expected a push during the review to queue a review after it")
+	}
+	if _, queued := handler.claimReview(key, "next9012", reviewOptions{}); queued {
+		t.Error("Expected a redelivery of the push not to queue another review")
+	}
+
+	if _, rerun := handler.nextReview(key); !rerun {
+		t.Fatal("Expected the queued review to run once the first finished")
+	}
+	if _, queued := handler.claimReview(key, "next9012", reviewOptions{}); queued {
+		t.Error("Expected the commit under review not to be queued again")
+	}
+	if _, rerun := handler.nextReview(key); rerun {
+		t.Error("Expected no review to follow when nothing was pushed")
+	}
+	if started, _ := handler.claimReview(key, "next9012", reviewOptions{}); !started {
+		t.Error("Expected the PR to be reviewable again once its reviews finished")
+	}
+}
+
+func TestWebhook_PushDuringReviewRunsAfterIt(t *testing.T) {
+	internal.InitLogger(false)
+	release := make(chan struct{})
+	var fetches, released atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1") && strings.Contains(r.Header.Get("Accept"), "diff"):
+			w.Write([]byte("diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+full\n"))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			// Hold the first review until the second push has been delivered
+			if fetches.Add(1) == 1 {
+				<-release
+			} else if released.Load() == 0 {
+				t.Error("Expected the second review to wait for the first")
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"number": 1, "title": "Add feature", "head": map[string]string{"sha": "next9012"},
+			})
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	aiClient := ai.NewMockClient()
+	aiClient.Review = &ai.ReviewResult{}
+	handler := NewWebhookHandler(github.NewClient("token", server.URL), aiClient, &internal.Config{}, "")
+
+	for _, sha := range []string{"new5678", "next9012"} {
+		recorder := httptest.NewRecorder()
+		handler.HandleWebhook(recorder, pullRequestEvent("synchronize", sha))
+		if recorder.Code != http.StatusAccepted {
+			t.Fatalf("Expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+	}
+	released.Store(1)
+	close(release)
+	handler.reviews.Wait()
+
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("Expected the PR to be reviewed twice, one after the other, got %d reviews", got)
+	}
+}
+
+func TestWebhook_IgnoresOtherPullRequestActions(t *testing.T) {
+	internal.InitLogger(false)
+	var paths []string
+	aiClient := ai.NewMockClient()
+	handler := NewWebhookHandler(reviewServer(t, "Description", &paths), aiClient, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), pullRequestEvent("closed", "new5678"))
+	if len(paths) != 0 || len(aiClient.Prompts()) != 0 {
+		t.Errorf("Expected closed PRs not to be reviewed, got requests %q", paths)
+	}
+}
+
+func issueCommentEvent(body string) *http.Request {
+	payload := `{
+		"action": "created",
+		"issue": {"number": 1},
+		"comment": {"id": 20, "body": ` + jsonString(body) + `, "user": {"login": "alice"}},
+		"repository": {"full_name": "owner/repo", "name": "repo", "owner": {"login": "owner"}}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "issue_comment")
+	return req
+}
+
+// postedBodies returns the bodies of the recorded POST requests
+func postedBodies(paths []string) string {
+	var bodies []string
+	for _, request := range paths {
+		if strings.HasPrefix(request, http.MethodPost) {
+			bodies = append(bodies, request)
+		}
+	}
+	return strings.Join(bodies, "\n")
+}
+
+func TestWebhook_ReviewFile(t *testing.T) {
+	internal.InitLogger(false)
+	var paths []string
+	aiClient := ai.NewMockClient()
+	aiClient.Review = &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "main.go", StartLine: 1, EndLine: 1, Header: "🟡 Unused value", Content: "full is never read"},
+	}}
+	handler := NewWebhookHandler(reviewServer(t, "Description", &paths), aiClient, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), issueCommentEvent("@manque review `main.go`"))
+	handler.reviews.Wait()
+
+	var reviewedDiff string
+	for _, prompt := range aiClient.Prompts() {
+		if strings.HasPrefix(prompt.Method, "GenerateCodeReview") {
+			reviewedDiff = prompt.Diff
+		}
+	}
+	if !strings.Contains(reviewedDiff, "main.go") || strings.Contains(reviewedDiff, "other.go") {
+		t.Errorf("Expected only main.go to be reviewed, got %q", reviewedDiff)
+	}
+	if replies := postedBodies(paths); !strings.Contains(replies, "found 1 issue(s)") || !strings.Contains(replies, "Unused value") {
+		t.Errorf("Expected a reply with the findings, got %q", replies)
+	}
+}
+
+func TestWebhook_ReviewFileNotInPR(t *testing.T) {
+	internal.InitLogger(false)
+	var paths []string
+	aiClient := ai.NewMockClient()
+	handler := NewWebhookHandler(reviewServer(t, "Description", &paths), aiClient, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), issueCommentEvent("@manque check docs/guide.md"))
+	handler.reviews.Wait()
+
+	if len(aiClient.Prompts()) != 0 {
+		t.Errorf("Expected no review of a file the PR doesn't change, got %d LLM requests", len(aiClient.Prompts()))
+	}
+	if replies := postedBodies(paths); !strings.Contains(replies, "isn't changed in this PR") {
+		t.Errorf("Expected a reply that the file isn't changed, got %q", replies)
+	}
+}
+
+func TestWebhook_RegenerateReviewsPR(t *testing.T) {
+	internal.InitLogger(false)
+	var paths []string
+	aiClient := ai.NewMockClient()
+	aiClient.Review = &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "main.go", StartLine: 1, EndLine: 1, Header: "🟡 Unused value", Content: "full is never read"},
+	}}
+	handler := NewWebhookHandler(reviewServer(t, "Description", &paths), aiClient, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), issueCommentEvent("@manque regenerate"))
+	handler.reviews.Wait()
+
+	var reviewedDiff string
+	for _, prompt := range aiClient.Prompts() {
+		if strings.HasPrefix(prompt.Method, "GenerateCodeReview") {
+			reviewedDiff = prompt.Diff
+		}
+	}
+	if !strings.Contains(reviewedDiff, "main.go") || !strings.Contains(reviewedDiff, "other.go") {
+		t.Errorf("Expected the whole PR to be reviewed, got %q", reviewedDiff)
+	}
+	var reviewed bool
+	for _, request := range paths {
+		if strings.HasPrefix(request, "POST /api/v3/repos/owner/repo/pulls/1/reviews") && strings.Contains(request, "Unused value") {
+			reviewed = true
+		}
+	}
+	if !reviewed {
+		t.Errorf("Expected the findings to be posted as a review, got requests %q", paths)
+	}
+}
+
+func TestWebhook_ResolveResolvesThread(t *testing.T) {
+	internal.InitLogger(false)
+	var resolved []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/graphql"):
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), "resolveReviewThread") {
+				resolved = append(resolved, string(body))
+				w.Write([]byte(`{"data":{}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+				"pageInfo":{"hasNextPage":false},
+				"nodes":[{"id":"PRRT_1","isResolved":false,"path":"main.go","line":5,"comments":{"nodes":[
+					{"databaseId":10,"body":"<!-- manque-ai-bot -->\nPossible nil dereference","author":{"login":"manque-ai"}},
+					{"databaseId":11,"body":"@manque resolve","author":{"login":"alice"}}
+				]}}]
+			}}}}}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			w.Write([]byte(`{"number":1,"body":"Description"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	handler := NewWebhookHandler(github.NewClient("token", server.URL), nil, &internal.Config{}, "")
+
+	handler.HandleWebhook(httptest.NewRecorder(), reviewCommentEvent(t, "@manque resolve"))
+
+	if len(resolved) != 1 || !strings.Contains(resolved[0], "PRRT_1") {
+		t.Errorf("Expected thread PRRT_1 to be resolved, got %q", resolved)
+	}
+}
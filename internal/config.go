@@ -1,12 +1,16 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/igcodinap/manque-ai/pkg/markers"
 	"github.com/joho/godotenv"
 )
 
@@ -21,42 +25,126 @@ type Config struct {
 	LLMProvider string
 	LLMBaseURL  string
 
+	// LLMMaxConcurrency bounds in-flight LLM requests across all reviews in the process
+	LLMMaxConcurrency int
+	// LLMTemperature and LLMMaxTokens override provider sampling defaults when set
+	LLMTemperature *float64
+	LLMMaxTokens   *int
+	// LLMMaxRetries and LLMRetryBaseDelay control retries of transient LLM API errors
+	LLMMaxRetries     int
+	LLMRetryBaseDelay time.Duration
+	// LLMCache reuses responses to identical prompts for LLMCacheTTL, stored in LLMCacheDir
+	// (~/.manque-ai/cache if empty)
+	LLMCache    bool
+	LLMCacheTTL time.Duration
+	LLMCacheDir string
+	// LLMJSONMode requests the provider's native JSON output mode where supported
+	LLMJSONMode bool
+	// OperationTimeout bounds each LLM request and git command, so one slow chunk or file is
+	// skipped instead of stalling the run; 0 disables it
+	OperationTimeout time.Duration
+	// LLMMaxInputTokens overrides the model's context window used to size diff chunks; 0 looks it up
+	LLMMaxInputTokens int
+	// MaxParseFailures aborts the review once more chunks than this return invalid JSON, even
+	// after a retry, so a model that can't follow the format doesn't burn through every chunk;
+	// 0 disables it
+	MaxParseFailures int
+	// MaxTotalDiffBytes caps the diff sent for review after ignore filtering; larger diffs are
+	// reviewed up to it, source files first. 0 disables it
+	MaxTotalDiffBytes int
+
 	// Review settings
-	StyleGuideRules string
+	StyleGuideRules   string
+	ReviewLanguage    string            // Locale summaries and comments are written in, e.g. "es"; unsupported locales fall back to English
+	ChunkStrategy     string            // How files are packed into LLM requests: size, by-dir, or by-lang
+	WordDiff          bool              // Annotate replaced lines in the prompt with a word diff against the removed line
+	SingleCallMaxSize int               // Diffs up to this many chars get summary and review in one LLM request; 0 disables
+	DetectBreaking    bool              // Compare changed Go files against the PR base to report breaking changes
+	BreakingOutput    string            // Where the breaking change report goes: off, body, comment, or review
+	AnalyzeImpact     bool              // Index the checkout to report files referencing changed symbols
+	IncludeBlame      bool              // Add the age and authors of changed lines, from git blame, to the review prompt
+	ShowCost          bool              // Add token usage and estimated cost to the PR body
+	LabelTones        map[string]string // Tone per comment label, e.g. "security" -> "authoritative"
+	BotAliases        []string          // Extra handles the webhook responds to, e.g. "@acme-reviewer"
+	MarkerNamespace   string            // Prefix of the HTML comment markers, so several bot instances can share a repository
 
 	// CLI/Action context
 	PRNumber        int
 	Repository      string
 	GitHubEventPath string
 
+	// Checkout layout, for Actions running from a monorepo subdirectory
+	WorkDir    string // Local checkout root used for context and blame (default: cwd)
+	PathPrefix string // Location of WorkDir within the repository (e.g. "services/api")
+
+	// Referenced file context settings
+	ContextDepth    int // Import hops to follow when fetching referenced files; 0 disables (default: 1)
+	ContextMaxFiles int // Maximum referenced files per chunk (default: 10)
+	ContextMaxBytes int // Maximum total size of referenced files per chunk (default: 50000)
+
 	// Output settings
 	UpdatePRTitle bool
 	UpdatePRBody  bool
+	MaxComments   int     // Inline comments posted per review, most severe first; 0 is unlimited (default: 25)
+	MinConfidence float64 // Comments the LLM is less confident about are dropped, 0-1 (default: 0.8)
+	StateStorage  string  // Where incremental review state and session memory are kept: pr_body, pr_comment, or none
 
 	// Review action settings
-	AutoApproveThreshold int  // Score threshold for auto-approve (default: 90)
-	BlockOnCritical      bool // Request changes when critical issues found (default: true)
+	AutoApproveThreshold int    // Score threshold for auto-approve (default: 90)
+	AllowAutoApprove     bool   // Submit APPROVE reviews; when false approvals are posted as comments (default: true)
+	RequireHumanApproval bool   // Post approvals as comments so a person has to approve, e.g. when bots can't (default: false)
+	BlockOnCritical      bool   // Request changes when critical issues found (default: true)
+	RequireTests         bool   // Warn when changed source files have no matching test changes (default: false)
+	ReviewMarkdownCode   bool   // Review fenced code samples in changed Markdown files (default: false)
+	CheckErrorStrings    bool   // Flag added Go error strings that are capitalized or end with punctuation (default: true)
+	RedactSecrets        bool   // Mask likely secrets in the diff before sending it to the LLM and flag them (default: true)
+	BaselineFile         string // Known issues to suppress, relative to WorkDir (default: .manque-baseline.json)
+	OwnershipScope       string // Comments on files the PR author doesn't own per CODEOWNERS: off, suppress, or fyi
+	ReplyPrefix          string // Prepended to threaded replies on re-review; "off" omits it
+	SkipDrafts           bool   // Skip reviews of draft PRs (default: false)
+	ReviewDraftOnCommand bool   // Still review drafts when asked with a review command (default: true)
+
+	// AutoLabel labels PRs by review outcome. ReviewLabels renames the labels per outcome
+	// (approved, changes-requested, security, breaking-change); "off" drops one.
+	AutoLabel    bool
+	ReviewLabels map[string]string
+
+	// SessionMaxAge is how long a session can go without updates before the next review
+	// ignores its dismissals and reviews the whole PR again (default: 0, never)
+	SessionMaxAge time.Duration
 
 	// CLI settings
 	Debug                bool
 	SkipGitHubValidation bool
+	DryRun               bool // Print results instead of posting them to GitHub (default: false)
 
 	// Discovery settings
 	AutoDiscoverPractices bool   // Enable auto-discovery of repo practices (default: true)
 	DiscoveredPractices   string // Content discovered from repo practice files
 
+	// Vendored dependency settings
+	ExcludeVendorDirs bool     // Skip vendored dependency directories in discovery and review (default: true)
+	VendorDirs        []string // Directory names treated as vendored dependencies
+	SkipGenerated     bool     // Skip files produced by code generators during review (default: true)
+
 	// File-based config
-	IgnorePatterns []string            // Patterns to ignore during review
-	PathRules      map[string]PathRule // Path-specific rules
+	IncludePatterns []string   // When set, only files matching one of these are reviewed
+	IgnorePatterns  []string   // Patterns to ignore during review
+	PathRules       []PathRule // Path-specific rules, in file order; the first match wins
 }
 
 // PathRule defines rules for specific file paths (mirrored from pkg/config)
 type PathRule struct {
+	Path             string
 	SeverityOverride string
 	ExtraRules       string
 	Ignore           bool
+	SkipTestCheck    bool
 }
 
+// DefaultVendorDirs are the dependency directories excluded from discovery and review by default
+var DefaultVendorDirs = []string{"vendor", "node_modules", "bower_components", ".venv", "venv", "Pods"}
+
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
@@ -71,13 +159,63 @@ func LoadConfig() (*Config, error) {
 		LLMModel:              getEnvOrUserConfig("LLM_MODEL", userCfg.Model, "mistralai/mistral-7b-instruct:free"),
 		LLMProvider:           getEnvOrUserConfig("LLM_PROVIDER", userCfg.Provider, "openrouter"),
 		LLMBaseURL:            getEnvWithDefault("LLM_BASE_URL", ""),
+		LLMMaxConcurrency:     getEnvAsInt("LLM_MAX_CONCURRENCY", 4),
+		LLMMaxRetries:         getEnvAsInt("LLM_MAX_RETRIES", 3),
+		LLMRetryBaseDelay:     getEnvAsDuration("LLM_RETRY_BASE_DELAY", time.Second),
+		LLMCache:              getEnvWithDefault("LLM_CACHE", "false") == "true",
+		LLMCacheTTL:           getEnvAsDuration("LLM_CACHE_TTL", 24*time.Hour),
+		OperationTimeout:      getEnvAsDuration("OPERATION_TIMEOUT", 120*time.Second),
+		LLMCacheDir:           getEnvWithDefault("LLM_CACHE_DIR", ""),
+		LLMJSONMode:           getEnvWithDefault("LLM_JSON_MODE", "true") == "true",
+		LLMMaxInputTokens:     getEnvAsInt("LLM_MAX_INPUT_TOKENS", 0),
+		MaxParseFailures:      getEnvAsInt("MAX_PARSE_FAILURES", 3),
+		MaxTotalDiffBytes:     getEnvAsInt("MAX_TOTAL_DIFF_BYTES", 1000000),
+		MaxComments:           getEnvAsInt("MAX_COMMENTS", 25),
 		StyleGuideRules:       getEnvWithDefault("STYLE_GUIDE_RULES", ""),
+		ReviewLanguage:        getEnvWithDefault("REVIEW_LANGUAGE", "en"),
+		ChunkStrategy:         getEnvWithDefault("CHUNK_STRATEGY", "size"),
+		WordDiff:              getEnvWithDefault("WORD_DIFF", "false") == "true",
+		SingleCallMaxSize:     getEnvAsInt("SINGLE_CALL_MAX_SIZE", 20000),
+		DetectBreaking:        getEnvWithDefault("DETECT_BREAKING", "false") == "true",
+		BreakingOutput:        getEnvWithDefault("BREAKING_OUTPUT", "body"),
+		AnalyzeImpact:         getEnvWithDefault("ANALYZE_IMPACT", "false") == "true",
+		IncludeBlame:          getEnvWithDefault("INCLUDE_BLAME", "false") == "true",
+		ShowCost:              getEnvWithDefault("SHOW_COST", "false") == "true",
+		LabelTones:            getEnvAsMap("LABEL_TONES"),
+		BotAliases:            getEnvAsList("BOT_ALIASES", nil),
+		MarkerNamespace:       getEnvWithDefault("MARKER_NAMESPACE", "manque-ai"),
 		GitHubEventPath:       getEnvWithDefault("GITHUB_EVENT_PATH", ""),
+		WorkDir:               getEnvWithDefault("WORKDIR", ""),
+		PathPrefix:            getEnvWithDefault("PATH_PREFIX", ""),
+		ContextDepth:          getEnvAsInt("CONTEXT_DEPTH", 1),
+		ContextMaxFiles:       getEnvAsInt("CONTEXT_MAX_FILES", 10),
+		ContextMaxBytes:       getEnvAsInt("CONTEXT_MAX_BYTES", 50000),
 		UpdatePRTitle:         getEnvWithDefault("UPDATE_PR_TITLE", "true") == "true",
 		UpdatePRBody:          getEnvWithDefault("UPDATE_PR_BODY", "true") == "true",
+		StateStorage:          getEnvWithDefault("STATE_STORAGE", "pr_body"),
+		MinConfidence:         getEnvAsFloat("MIN_CONFIDENCE", 0.8),
 		AutoApproveThreshold:  getEnvAsInt("AUTO_APPROVE_THRESHOLD", 90),
+		AllowAutoApprove:      getEnvWithDefault("ALLOW_AUTO_APPROVE", "true") == "true",
+		RequireHumanApproval:  getEnvWithDefault("REQUIRE_HUMAN_APPROVAL", "false") == "true",
 		BlockOnCritical:       getEnvWithDefault("BLOCK_ON_CRITICAL", "true") == "true",
+		RequireTests:          getEnvWithDefault("REQUIRE_TESTS", "false") == "true",
+		ReviewMarkdownCode:    getEnvWithDefault("REVIEW_MARKDOWN_CODE", "false") == "true",
+		CheckErrorStrings:     getEnvWithDefault("CHECK_ERROR_STRINGS", "true") == "true",
+		RedactSecrets:         getEnvWithDefault("REDACT_SECRETS", "true") == "true",
+		BaselineFile:          getEnvWithDefault("BASELINE_FILE", ".manque-baseline.json"),
+		OwnershipScope:        getEnvWithDefault("SCOPE_TO_AUTHOR_OWNERSHIP", "off"),
+		ReplyPrefix:           getEnvWithDefault("REREVIEW_REPLY_PREFIX", "**Update on re-review:**"),
+		DryRun:                getEnvWithDefault("DRY_RUN", "false") == "true",
+		SessionMaxAge:         getEnvAsDuration("SESSION_MAX_AGE", 0),
+		SkipDrafts:            getEnvWithDefault("SKIP_DRAFTS", "false") == "true",
+		ReviewDraftOnCommand:  getEnvWithDefault("REVIEW_DRAFT_ON_COMMAND", "true") == "true",
+		AutoLabel:             getEnvWithDefault("AUTO_LABEL", "false") == "true",
+		ReviewLabels:          getEnvAsMap("REVIEW_LABELS"),
 		AutoDiscoverPractices: getEnvWithDefault("AUTO_DISCOVER_PRACTICES", "true") == "true",
+		ExcludeVendorDirs:     getEnvWithDefault("EXCLUDE_VENDOR_DIRS", "true") == "true",
+		VendorDirs:            getEnvAsList("VENDOR_DIRS", DefaultVendorDirs),
+		SkipGenerated:         getEnvWithDefault("SKIP_GENERATED", "true") == "true",
+		IncludePatterns:       getEnvAsList("INCLUDE_PATTERNS", nil),
 	}
 
 	return config, nil
@@ -87,7 +225,8 @@ func (c *Config) Validate() error {
 	if !c.SkipGitHubValidation && c.GitHubToken == "" {
 		return fmt.Errorf("GitHub token is required (set GH_TOKEN or GITHUB_TOKEN)")
 	}
-	if c.LLMAPIKey == "" {
+	// The mock provider returns canned results without calling an LLM
+	if c.LLMAPIKey == "" && c.LLMProvider != "mock" {
 		return fmt.Errorf("LLM API key is required (set LLM_API_KEY, OPENAI_API_KEY, ANTHROPIC_API_KEY, GOOGLE_API_KEY, or OPENROUTER_API_KEY)")
 	}
 
@@ -96,9 +235,96 @@ func (c *Config) Validate() error {
 		"anthropic":  true,
 		"google":     true,
 		"openrouter": true,
+		"mock":       true,
 	}
 	if !validProviders[c.LLMProvider] {
-		return fmt.Errorf("invalid LLM_PROVIDER: %s. Must be one of: openai, anthropic, google, openrouter", c.LLMProvider)
+		return fmt.Errorf("invalid LLM_PROVIDER: %s. Must be one of: openai, anthropic, google, openrouter, mock", c.LLMProvider)
+	}
+
+	validChunkStrategies := map[string]bool{
+		"":        true,
+		"size":    true,
+		"by-dir":  true,
+		"by-lang": true,
+	}
+	if !validChunkStrategies[c.ChunkStrategy] {
+		return fmt.Errorf("invalid CHUNK_STRATEGY: %s. Must be one of: size, by-dir, by-lang", c.ChunkStrategy)
+	}
+
+	validBreakingOutputs := map[string]bool{
+		"":        true,
+		"off":     true,
+		"body":    true,
+		"comment": true,
+		"review":  true,
+	}
+	if !validBreakingOutputs[c.BreakingOutput] {
+		return fmt.Errorf("invalid BREAKING_OUTPUT: %s. Must be one of: off, body, comment, review", c.BreakingOutput)
+	}
+
+	if c.MarkerNamespace != "" && !markers.ValidNamespace(c.MarkerNamespace) {
+		return fmt.Errorf("invalid MARKER_NAMESPACE: %s. Must contain only letters, digits, '.', '_' and '-'", c.MarkerNamespace)
+	}
+
+	validOwnershipScopes := map[string]bool{
+		"":         true,
+		"off":      true,
+		"suppress": true,
+		"fyi":      true,
+	}
+	if !validOwnershipScopes[c.OwnershipScope] {
+		return fmt.Errorf("invalid SCOPE_TO_AUTHOR_OWNERSHIP: %s. Must be one of: off, suppress, fyi", c.OwnershipScope)
+	}
+
+	validStateStorages := map[string]bool{
+		"":           true,
+		"pr_body":    true,
+		"pr_comment": true,
+		"none":       true,
+	}
+	if !validStateStorages[c.StateStorage] {
+		return fmt.Errorf("invalid STATE_STORAGE: %s. Must be one of: pr_body, pr_comment, none", c.StateStorage)
+	}
+
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("invalid MIN_CONFIDENCE: %g. Must be between 0 and 1", c.MinConfidence)
+	}
+
+	if c.LLMMaxInputTokens < 0 {
+		return fmt.Errorf("invalid LLM_MAX_INPUT_TOKENS: %d. Must be 0 or greater", c.LLMMaxInputTokens)
+	}
+
+	if c.SessionMaxAge < 0 {
+		return fmt.Errorf("invalid SESSION_MAX_AGE: %s. Must be 0 or greater", c.SessionMaxAge)
+	}
+
+	if c.ContextDepth < 0 {
+		return fmt.Errorf("invalid CONTEXT_DEPTH: %d. Must be 0 or greater", c.ContextDepth)
+	}
+
+	if c.LLMTemperature != nil && (*c.LLMTemperature < 0 || *c.LLMTemperature > 2) {
+		return fmt.Errorf("invalid temperature: %g. Must be between 0 and 2", *c.LLMTemperature)
+	}
+	if c.LLMMaxTokens != nil && *c.LLMMaxTokens <= 0 {
+		return fmt.Errorf("invalid max tokens: %d. Must be a positive integer", *c.LLMMaxTokens)
+	}
+	if c.LLMMaxRetries < 0 {
+		return fmt.Errorf("invalid LLM_MAX_RETRIES: %d. Must be 0 or greater", c.LLMMaxRetries)
+	}
+	if c.MaxComments < 0 {
+		return fmt.Errorf("invalid MAX_COMMENTS: %d. Must be 0 or greater", c.MaxComments)
+	}
+	if c.LLMCacheTTL < 0 {
+		return fmt.Errorf("invalid LLM_CACHE_TTL: %s. Must be 0 or greater", c.LLMCacheTTL)
+	}
+	if c.OperationTimeout < 0 {
+		return fmt.Errorf("invalid OPERATION_TIMEOUT: %s. Must be 0 or greater", c.OperationTimeout)
+	}
+	if c.MaxTotalDiffBytes < 0 {
+		return fmt.Errorf("invalid MAX_TOTAL_DIFF_BYTES: %d. Must be 0 or greater", c.MaxTotalDiffBytes)
+	}
+	if c.MaxParseFailures < 0 {
+		return fmt.Errorf("invalid MAX_PARSE_FAILURES: %d. Must be 0 or greater", c.MaxParseFailures)
 	}
 
 	return nil
@@ -122,6 +348,16 @@ func getEnvWithFallbacks(keys ...string) string {
 	return ""
 }
 
+// getEnvAsDuration returns an environment variable as a duration (e.g. "500ms", "2s"), or the default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 // getEnvAsInt returns an environment variable as an integer, or the default value
 func getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -132,6 +368,53 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat returns an environment variable as a float, or the default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList parses a comma-separated environment variable, or returns the default value
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvAsMap parses an environment variable of the form "key:value,key:value" into a map.
+// Keys are lowercased; malformed entries are skipped.
+func getEnvAsMap(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, found := strings.Cut(pair, ":")
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.TrimSpace(v)
+		if !found || k == "" || v == "" {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
 // userConfigData holds values loaded from ~/.manque-ai/config.yaml
 type userConfigData struct {
 	Provider string
@@ -179,8 +462,63 @@ func getEnvOrUserConfig(envKey, userConfigValue, fallback string) string {
 	return fallback
 }
 
+// OperationContext returns a context for a single LLM request or git command, cancelled after
+// OperationTimeout when one is set
+func (c *Config) OperationContext() (context.Context, context.CancelFunc) {
+	if c == nil || c.OperationTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.OperationTimeout)
+}
+
+// ExcludedDirs returns the vendored directory names to skip, or nil if exclusion is disabled
+func (c *Config) ExcludedDirs() []string {
+	if !c.ExcludeVendorDirs {
+		return nil
+	}
+	return c.VendorDirs
+}
+
+// IsVendored checks if a file lives inside a vendored dependency directory
+func (c *Config) IsVendored(filename string) bool {
+	excluded := c.ExcludedDirs()
+	if len(excluded) == 0 {
+		return false
+	}
+
+	segments := strings.Split(filepath.ToSlash(filepath.Dir(filename)), "/")
+	for _, segment := range segments {
+		for _, dir := range excluded {
+			if segment == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ShouldIncludeFile checks if a file matches the include patterns. Every file is included
+// when there are none.
+func (c *Config) ShouldIncludeFile(filename string) bool {
+	if len(c.IncludePatterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range c.IncludePatterns {
+		matched, err := matchPattern(pattern, filename)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ShouldIgnoreFile checks if a file should be ignored based on ignore patterns
 func (c *Config) ShouldIgnoreFile(filename string) bool {
+	if c.IsVendored(filename) {
+		return true
+	}
+
 	for _, pattern := range c.IgnorePatterns {
 		matched, err := matchPattern(pattern, filename)
 		if err == nil && matched {
@@ -189,9 +527,9 @@ func (c *Config) ShouldIgnoreFile(filename string) bool {
 	}
 
 	// Check path-specific ignore rules
-	for path, rule := range c.PathRules {
+	for _, rule := range c.PathRules {
 		if rule.Ignore {
-			matched, err := matchPattern(path, filename)
+			matched, err := matchPattern(rule.Path, filename)
 			if err == nil && matched {
 				return true
 			}
@@ -203,8 +541,8 @@ func (c *Config) ShouldIgnoreFile(filename string) bool {
 
 // GetExtraRulesForFile returns extra rules that apply to a specific file
 func (c *Config) GetExtraRulesForFile(filename string) string {
-	for path, rule := range c.PathRules {
-		matched, err := matchPattern(path, filename)
+	for _, rule := range c.PathRules {
+		matched, err := matchPattern(rule.Path, filename)
 		if err == nil && matched && rule.ExtraRules != "" {
 			return rule.ExtraRules
 		}
@@ -212,6 +550,31 @@ func (c *Config) GetExtraRulesForFile(filename string) string {
 	return ""
 }
 
+// GetSeverityOverrideForFile returns the severity override (strict, relaxed or off) of the
+// first rule, in file order, that matches a specific file, or "" if none does
+func (c *Config) GetSeverityOverrideForFile(filename string) string {
+	for _, rule := range c.PathRules {
+		matched, err := matchPattern(rule.Path, filename)
+		if err == nil && matched && rule.SeverityOverride != "" {
+			return rule.SeverityOverride
+		}
+	}
+	return ""
+}
+
+// IsTestCheckExempt checks if a file is exempt from the REQUIRE_TESTS check
+func (c *Config) IsTestCheckExempt(filename string) bool {
+	for _, rule := range c.PathRules {
+		if rule.SkipTestCheck {
+			matched, err := matchPattern(rule.Path, filename)
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // matchPattern is a helper to match glob-like patterns against filenames
 func matchPattern(pattern, filename string) (bool, error) {
 	// Try direct match
@@ -222,5 +585,33 @@ func matchPattern(pattern, filename string) (bool, error) {
 	if matched, err := filepath.Match(pattern, filepath.Base(filename)); err == nil && matched {
 		return true, nil
 	}
+	// filepath.Match has no "**", which patterns like "**/vendor/**" rely on
+	if strings.Contains(pattern, "**") {
+		return globstarRegex(pattern).MatchString(filename), nil
+	}
 	return false, nil
 }
+
+// globstarRegex converts a glob where "**" spans directories into a regular expression
+func globstarRegex(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
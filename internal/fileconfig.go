@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"os"
+	"strings"
+
+	fileconfig "github.com/igcodinap/manque-ai/pkg/config"
+)
+
+// validSeverityOverrides are the accepted values of a path rule's severity_override
+var validSeverityOverrides = map[string]bool{
+	"strict":  true,
+	"relaxed": true,
+	"off":     true,
+}
+
+// MergeFileConfig applies the .manque.yml found in dir or its parents to config. Without
+// a file only the default ignore patterns are applied, so environment settings are kept.
+func MergeFileConfig(config *Config, dir string) error {
+	path, err := fileconfig.FindConfigFile(dir)
+	if err != nil {
+		config.IgnorePatterns = fileconfig.DefaultConfig().Ignore
+		return nil
+	}
+
+	fileCfg, err := fileconfig.LoadFromFile(path)
+	if err != nil {
+		// Keep skipping vendored and generated files while the file is broken
+		config.IgnorePatterns = fileconfig.DefaultConfig().Ignore
+		return err
+	}
+	// Typos only drop the keys they are in, but are worth fixing
+	if err := fileconfig.ValidateFile(path); err != nil {
+		Logger.Warn("Skipping invalid .manque.yml settings, run `manque-ai config validate` for details", "error", err)
+	}
+
+	// Environment settings take precedence, so only keys written in the file are applied
+	if fileCfg.IsSet("review.auto_approve_threshold") && !envSet("AUTO_APPROVE_THRESHOLD") && fileCfg.Review.AutoApproveThreshold > 0 {
+		config.AutoApproveThreshold = fileCfg.Review.AutoApproveThreshold
+	}
+	if fileCfg.IsSet("review.block_on_critical") && !envSet("BLOCK_ON_CRITICAL") {
+		config.BlockOnCritical = fileCfg.Review.BlockOnCritical
+	}
+	if len(fileCfg.Review.LabelTones) > 0 {
+		// Labels are matched lowercased, like LABEL_TONES keys, which win for the same label
+		tones := make(map[string]string, len(fileCfg.Review.LabelTones)+len(config.LabelTones))
+		for label, tone := range fileCfg.Review.LabelTones {
+			tones[strings.ToLower(strings.TrimSpace(label))] = tone
+		}
+		for label, tone := range config.LabelTones {
+			tones[label] = tone
+		}
+		config.LabelTones = tones
+	}
+	if len(config.BotAliases) == 0 {
+		config.BotAliases = fileCfg.BotAliases
+	}
+	if len(config.IncludePatterns) == 0 {
+		config.IncludePatterns = fileCfg.Include
+	}
+	config.IgnorePatterns = fileCfg.Ignore
+
+	// Convert path rules
+	config.PathRules = make([]PathRule, 0, len(fileCfg.Rules))
+	for _, rule := range fileCfg.Rules {
+		if rule.SeverityOverride != "" && !validSeverityOverrides[rule.SeverityOverride] {
+			Logger.Warn("Ignoring unknown severity_override, must be one of: strict, relaxed, off", "path", rule.Path, "value", rule.SeverityOverride)
+			rule.SeverityOverride = ""
+		}
+		config.PathRules = append(config.PathRules, PathRule{
+			Path:             rule.Path,
+			SeverityOverride: rule.SeverityOverride,
+			ExtraRules:       rule.ExtraRules,
+			Ignore:           rule.Ignore,
+			SkipTestCheck:    rule.SkipTestCheck,
+		})
+	}
+	Logger.Debug("Loaded file config", "path", path, "include_patterns", len(config.IncludePatterns), "ignore_patterns", len(config.IgnorePatterns), "path_rules", len(config.PathRules))
+	return nil
+}
+
+// envSet reports whether an environment variable was given a non-empty value
+func envSet(key string) bool {
+	return os.Getenv(key) != ""
+}
+
+// FileConfigDir returns the directory to look up .manque.yml from: the configured work dir,
+// the Actions checkout (GITHUB_WORKSPACE), or the current directory
+func FileConfigDir(config *Config) string {
+	if config.WorkDir != "" {
+		return config.WorkDir
+	}
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
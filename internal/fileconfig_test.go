@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFileConfig writes a .manque.yml with content to a temp dir and returns the dir
+func writeFileConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".manque.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	return dir
+}
+
+func TestMergeFileConfig_LabelTonesLowercased(t *testing.T) {
+	InitLogger(false)
+	dir := writeFileConfig(t, `review:
+  label_tones:
+    Security: firm
+    " Nitpick ": light
+`)
+
+	config := &Config{}
+	if err := MergeFileConfig(config, dir); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+	if config.LabelTones["security"] != "firm" || config.LabelTones["nitpick"] != "light" {
+		t.Errorf("Expected lowercased label keys, got %v", config.LabelTones)
+	}
+}
+
+func TestMergeFileConfig_EnvWins(t *testing.T) {
+	InitLogger(false)
+	dir := writeFileConfig(t, `review:
+  auto_approve_threshold: 70
+  label_tones:
+    security: firm
+    style: light
+`)
+
+	// Keys missing from the file keep their configured values
+	config := &Config{AutoApproveThreshold: 90, BlockOnCritical: false}
+	if err := MergeFileConfig(config, dir); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+	if config.AutoApproveThreshold != 70 || config.BlockOnCritical {
+		t.Errorf("Expected only the threshold to come from the file, got threshold=%d block=%v", config.AutoApproveThreshold, config.BlockOnCritical)
+	}
+
+	t.Setenv("AUTO_APPROVE_THRESHOLD", "95")
+	config = &Config{AutoApproveThreshold: 95, LabelTones: map[string]string{"security": "authoritative"}}
+	if err := MergeFileConfig(config, dir); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+	if config.AutoApproveThreshold != 95 {
+		t.Errorf("Expected AUTO_APPROVE_THRESHOLD to win, got %d", config.AutoApproveThreshold)
+	}
+	if config.LabelTones["security"] != "authoritative" || config.LabelTones["style"] != "light" {
+		t.Errorf("Expected LABEL_TONES to win per label, got %v", config.LabelTones)
+	}
+}
+
+func TestMergeFileConfig_PathRulesKeepFileOrder(t *testing.T) {
+	InitLogger(false)
+	dir := writeFileConfig(t, `rules:
+  - path: "internal/payments/**"
+    severity_override: strict
+  - path: "internal/**"
+    severity_override: relaxed
+`)
+
+	config := &Config{}
+	if err := MergeFileConfig(config, dir); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if got := config.GetSeverityOverrideForFile("internal/payments/charge.go"); got != "strict" {
+			t.Fatalf("Expected the first matching rule to win, got %q", got)
+		}
+	}
+	if got := config.GetSeverityOverrideForFile("internal/auth/login.go"); got != "relaxed" {
+		t.Errorf("Expected the later rule for other files, got %q", got)
+	}
+}
+
+func TestMergeFileConfig_KeepsValidKeysAroundTypos(t *testing.T) {
+	InitLogger(false)
+	dir := writeFileConfig(t, `review:
+  auto_aprove_threshold: 85
+ignore: ["*.snap"]
+rules:
+  - path: "internal/**"
+    severity_override: strict
+`)
+
+	config := &Config{}
+	if err := MergeFileConfig(config, dir); err != nil {
+		t.Fatalf("MergeFileConfig failed: %v", err)
+	}
+	if len(config.IgnorePatterns) != 1 || config.IgnorePatterns[0] != "*.snap" {
+		t.Errorf("Expected the file's ignore patterns, got %v", config.IgnorePatterns)
+	}
+	if got := config.GetSeverityOverrideForFile("internal/auth/login.go"); got != "strict" {
+		t.Errorf("Expected the file's path rules, got %q", got)
+	}
+}
@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 var (
@@ -10,13 +12,46 @@ var (
 )
 
 func InitLogger(debug bool) {
+	InitLoggerTo(debug, os.Stdout)
+}
+
+// InitLoggerTo is InitLogger writing to w, e.g. stderr when stdout carries machine-readable output.
+// The level comes from LOG_LEVEL (debug, info, warn or error; default info), which debug overrides,
+// and the format from LOG_FORMAT (text or json; default text).
+func InitLoggerTo(debug bool, w io.Writer) {
+	level := os.Getenv("LOG_LEVEL")
+	if debug {
+		level = "debug"
+	}
+	var invalidLevel bool
+	Logger, invalidLevel = NewLogger(w, os.Getenv("LOG_FORMAT"), level)
+	if invalidLevel {
+		Logger.Warn("Invalid LOG_LEVEL, using info", "level", level)
+	}
+}
+
+// NewLogger returns a logger writing text or JSON records to w at or above level. It reports
+// whether level was invalid, in which case the info level is used.
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, bool) {
 	opts := &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}
-	if debug {
-		opts.Level = slog.LevelDebug
+
+	var invalidLevel bool
+	if level != "" {
+		var parsed slog.Level
+		if err := parsed.UnmarshalText([]byte(level)); err != nil {
+			invalidLevel = true
+		} else {
+			opts.Level = parsed
+		}
+	}
+	if opts.Level.Level() <= slog.LevelDebug {
 		opts.AddSource = true
 	}
 
-	Logger = slog.New(slog.NewTextHandler(os.Stdout, opts))
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(w, opts)), invalidLevel
+	}
+	return slog.New(slog.NewTextHandler(w, opts)), invalidLevel
 }
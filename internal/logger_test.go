@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, invalid := NewLogger(&buf, "json", "warn")
+	if invalid {
+		t.Fatal("Expected warn to be a valid level")
+	}
+
+	logger.Info("Filtered out")
+	logger.Warn("Review failed", "pr", 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning to be logged, got %q", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", lines[0], err)
+	}
+	for _, key := range []string{"time", "level", "msg", "pr"} {
+		if _, ok := record[key]; !ok {
+			t.Errorf("Expected key %q in %v", key, record)
+		}
+	}
+	if record["level"] != "WARN" || record["msg"] != "Review failed" || record["pr"] != float64(42) {
+		t.Errorf("Unexpected record %v", record)
+	}
+}
+
+func TestNewLogger_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := NewLogger(&buf, "", "debug")
+	logger.Debug("Chunk reviewed")
+	if !strings.Contains(buf.String(), "level=DEBUG") || !strings.Contains(buf.String(), "source=") {
+		t.Errorf("Expected a text debug record with its source, got %q", buf.String())
+	}
+
+	buf.Reset()
+	logger, invalid := NewLogger(&buf, "text", "verbose")
+	if !invalid {
+		t.Error("Expected verbose to be an invalid level")
+	}
+	logger.Debug("Hidden")
+	logger.Info("Shown")
+	if strings.Contains(buf.String(), "Hidden") || !strings.Contains(buf.String(), "Shown") {
+		t.Errorf("Expected an invalid level to log at info, got %q", buf.String())
+	}
+}
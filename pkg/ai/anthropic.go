@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -9,11 +10,17 @@ type AnthropicClient struct {
 	*BaseClient
 }
 
+var (
+	_ Client           = (*AnthropicClient)(nil)
+	_ CombinedReviewer = (*AnthropicClient)(nil)
+)
+
 type AnthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	Messages  []AnthropicMessage `json:"messages"`
-	System    string             `json:"system,omitempty"`
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Messages    []AnthropicMessage `json:"messages"`
+	System      string             `json:"system,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
 }
 
 type AnthropicMessage struct {
@@ -26,12 +33,24 @@ type AnthropicResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage,omitempty"`
 	Error *struct {
 		Type    string `json:"type"`
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
+// tokens returns the input and output tokens of the response, zero if not reported
+func (r *AnthropicResponse) tokens() (int, int) {
+	if r.Usage == nil {
+		return 0, 0
+	}
+	return r.Usage.InputTokens, r.Usage.OutputTokens
+}
+
 func NewAnthropicClient(config Config) *AnthropicClient {
 	baseURL := config.BaseURL
 	if baseURL == "" {
@@ -43,25 +62,28 @@ func NewAnthropicClient(config Config) *AnthropicClient {
 		"anthropic-version": "2023-06-01",
 	}
 
-	return &AnthropicClient{
+	client := &AnthropicClient{
 		BaseClient: NewBaseClient(config.APIKey, config.Model, baseURL, headers),
 	}
+	client.configure(config)
+	return client
 }
 
-func (c *AnthropicClient) GeneratePRSummary(prTitle, prDescription, diff string) (*PRSummary, error) {
-	systemPrompt := GetPRSummaryPrompt()
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+func (c *AnthropicClient) GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error) {
+	systemPrompt := c.summaryPrompt()
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
 	request := AnthropicRequest{
-		Model:     c.model,
-		MaxTokens: 4096,
-		System:    systemPrompt,
+		Model:       c.model,
+		MaxTokens:   c.maxTokensOr(4096),
+		Temperature: c.temperature,
+		System:      systemPrompt,
 		Messages: []AnthropicMessage{
 			{Role: "user", Content: userPrompt},
 		},
 	}
 
-	respBytes, err := c.makeRequest("/v1/messages", request)
+	respBytes, err := c.makeRequest(ctx, "/v1/messages", request)
 	if err != nil {
 		return nil, err
 	}
@@ -86,33 +108,39 @@ func (c *AnthropicClient) GeneratePRSummary(prTitle, prDescription, diff string)
 		return nil, fmt.Errorf("failed to parse PR summary JSON: %w", err)
 	}
 
+	summary.TokensIn, summary.TokensOut = response.tokens()
 	return &summary, nil
 }
 
-func (c *AnthropicClient) GenerateCodeReview(prTitle, prDescription, diff string) (*ReviewResult, error) {
-	return c.GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, "")
+func (c *AnthropicClient) GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error) {
+	return c.GenerateCodeReviewWithStyleGuide(ctx, prTitle, prDescription, diff, "")
 }
 
-func (c *AnthropicClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
-	var systemPrompt string
-	if styleGuide != "" {
-		systemPrompt = GetCodeReviewPromptWithStyleGuide(styleGuide)
-	} else {
-		systemPrompt = GetCodeReviewPrompt()
-	}
+func (c *AnthropicClient) GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
+	systemPrompt := c.codeReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		review, err = c.requestCodeReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return review, err
+}
 
+// requestCodeReview sends one code review request and parses its result
+func (c *AnthropicClient) requestCodeReview(ctx context.Context, systemPrompt, userPrompt string) (*ReviewResult, error) {
 	request := AnthropicRequest{
-		Model:     c.model,
-		MaxTokens: 4096,
-		System:    systemPrompt,
+		Model:       c.model,
+		MaxTokens:   c.maxTokensOr(4096),
+		Temperature: c.temperature,
+		System:      systemPrompt,
 		Messages: []AnthropicMessage{
 			{Role: "user", Content: userPrompt},
 		},
 	}
 
-	respBytes, err := c.makeRequest("/v1/messages", request)
+	respBytes, err := c.makeRequest(ctx, "/v1/messages", request)
 	if err != nil {
 		return nil, err
 	}
@@ -134,22 +162,77 @@ func (c *AnthropicClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescriptio
 
 	var review ReviewResult
 	if err := json.Unmarshal([]byte(content), &review); err != nil {
-		return nil, fmt.Errorf("failed to parse review JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse review JSON: %w: %w", ErrInvalidJSON, err)
 	}
 
+	review.TokensIn, review.TokensOut = response.tokens()
 	return &review, nil
 }
 
-func (c *AnthropicClient) GenerateResponse(prompt string) (string, error) {
+func (c *AnthropicClient) GenerateCombinedReview(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*PRSummary, *ReviewResult, error) {
+	systemPrompt := c.combinedReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
+
+	var summary *PRSummary
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		summary, review, err = c.requestCombinedReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return summary, review, err
+}
+
+// requestCombinedReview sends one combined summary and review request and parses its result
+func (c *AnthropicClient) requestCombinedReview(ctx context.Context, systemPrompt, userPrompt string) (*PRSummary, *ReviewResult, error) {
+	request := AnthropicRequest{
+		Model:       c.model,
+		MaxTokens:   c.maxTokensOr(4096),
+		Temperature: c.temperature,
+		System:      systemPrompt,
+		Messages: []AnthropicMessage{
+			{Role: "user", Content: userPrompt},
+		},
+	}
+
+	respBytes, err := c.makeRequest(ctx, "/v1/messages", request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response AnthropicResponse
+	if err := json.Unmarshal(respBytes, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, nil, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	if len(response.Content) == 0 {
+		return nil, nil, fmt.Errorf("no response content returned")
+	}
+
+	content := extractJSONFromResponse(response.Content[0].Text)
+
+	summary, review, err := ParseCombinedResponse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	review.TokensIn, review.TokensOut = response.tokens()
+	return summary, review, nil
+}
+
+func (c *AnthropicClient) GenerateResponse(ctx context.Context, prompt string) (string, error) {
 	request := AnthropicRequest{
-		Model:     c.model,
-		MaxTokens: 4096,
+		Model:       c.model,
+		MaxTokens:   c.maxTokensOr(4096),
+		Temperature: c.temperature,
 		Messages: []AnthropicMessage{
 			{Role: "user", Content: prompt},
 		},
 	}
 
-	respBytes, err := c.makeRequest("/v1/messages", request)
+	respBytes, err := c.makeRequest(ctx, "/v1/messages", request)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,210 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+// CacheBackend stores parsed LLM responses by key, so reruns of the same review aren't
+// charged twice
+type CacheBackend interface {
+	// Get returns the cached value and whether it was found and still fresh
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte) error
+}
+
+// FileCache stores each response as a file named after its key
+type FileCache struct {
+	Dir string
+	TTL time.Duration // Entries older than this are ignored; 0 keeps them forever
+}
+
+// NewFileCache creates a cache in dir
+func NewFileCache(dir string, ttl time.Duration) *FileCache {
+	return &FileCache{Dir: dir, TTL: ttl}
+}
+
+// DefaultCacheDir returns ~/.manque-ai/cache
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".manque-ai", "cache"), nil
+}
+
+func (c *FileCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if c.TTL > 0 && time.Since(info.ModTime()) > c.TTL {
+		os.Remove(path)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set writes the entry through a temporary file, so concurrent readers never see a partial one
+func (c *FileCache) Set(key string, value []byte) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// cachingClient serves summaries and reviews from the cache when the same prompt was sent
+// to the same model before. Conversational responses are not cached.
+type cachingClient struct {
+	Client
+	cache      CacheBackend
+	provider   string
+	model      string
+	labelTones map[string]string
+	language   string
+	overrides  *PromptOverrides
+}
+
+var (
+	_ Client           = (*cachingClient)(nil)
+	_ CombinedReviewer = (*cachingClient)(nil)
+)
+
+// combinedResult is the cached form of a combined summary and review
+type combinedResult struct {
+	Summary *PRSummary    `json:"summary"`
+	Review  *ReviewResult `json:"review"`
+}
+
+func newCachingClient(client Client, config Config) *cachingClient {
+	return &cachingClient{
+		Client:     client,
+		cache:      config.Cache,
+		provider:   config.Provider,
+		model:      config.Model,
+		labelTones: config.LabelTones,
+		language:   config.ReviewLanguage,
+		overrides:  config.PromptOverrides,
+	}
+}
+
+// cacheKey is sha256(provider + model + system prompt + user prompt)
+func (c *cachingClient) cacheKey(systemPrompt, userPrompt string) string {
+	hash := sha256.New()
+	for _, part := range []string{c.provider, c.model, systemPrompt, userPrompt} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// lookup decodes a cached value into v, reporting whether it was found
+func (c *cachingClient) lookup(key string, v interface{}) bool {
+	data, ok := c.cache.Get(key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		internal.Logger.Debug("Ignoring unreadable LLM cache entry", "key", key, "error", err)
+		return false
+	}
+	internal.Logger.Debug("LLM cache hit", "key", key)
+	return true
+}
+
+// store caches v; failures only cost a future request, so they are logged and ignored
+func (c *cachingClient) store(key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = c.cache.Set(key, data)
+	}
+	if err != nil {
+		internal.Logger.Warn("Failed to cache LLM response", "error", err)
+	}
+}
+
+func (c *cachingClient) GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error) {
+	key := c.cacheKey(summarySystemPrompt(c.language, c.overrides), reviewUserPrompt(prTitle, prDescription, diff))
+	var summary PRSummary
+	if c.lookup(key, &summary) {
+		return &summary, nil
+	}
+
+	result, err := c.Client.GeneratePRSummary(ctx, prTitle, prDescription, diff)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, result)
+	return result, nil
+}
+
+func (c *cachingClient) GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error) {
+	return c.GenerateCodeReviewWithStyleGuide(ctx, prTitle, prDescription, diff, "")
+}
+
+func (c *cachingClient) GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
+	key := c.cacheKey(codeReviewSystemPrompt(styleGuide, c.language, c.labelTones, c.overrides), reviewUserPrompt(prTitle, prDescription, diff))
+	var review ReviewResult
+	if c.lookup(key, &review) {
+		return &review, nil
+	}
+
+	result, err := c.Client.GenerateCodeReviewWithStyleGuide(ctx, prTitle, prDescription, diff, styleGuide)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, result)
+	return result, nil
+}
+
+func (c *cachingClient) GenerateCombinedReview(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*PRSummary, *ReviewResult, error) {
+	combined, ok := c.Client.(CombinedReviewer)
+	if !ok {
+		return nil, nil, fmt.Errorf("provider %s does not support combined reviews", c.provider)
+	}
+
+	key := c.cacheKey(WithCombinedOutput(codeReviewSystemPrompt(styleGuide, c.language, c.labelTones, c.overrides)), reviewUserPrompt(prTitle, prDescription, diff))
+	var cached combinedResult
+	if c.lookup(key, &cached) && cached.Summary != nil && cached.Review != nil {
+		return cached.Summary, cached.Review, nil
+	}
+
+	summary, review, err := combined.GenerateCombinedReview(ctx, prTitle, prDescription, diff, styleGuide)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.store(key, combinedResult{Summary: summary, Review: review})
+	return summary, review, nil
+}
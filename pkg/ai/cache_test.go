@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+// countingServer replies to every completion request with content, counting the requests
+func countingServer(t *testing.T, content string, requests *int) *httptest.Server {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		*requests++
+		w.Write(payload)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCachingClient_CodeReview(t *testing.T) {
+	internal.InitLogger(false)
+	var requests int
+	server := countingServer(t, `{"review": {"score": 80}, "comments": [{"file": "a.go", "start_line": 3, "header": "🟡 Fix"}]}`, &requests)
+	cache := NewFileCache(t.TempDir(), time.Hour)
+
+	client, err := NewClient(Config{Provider: "openai", BaseURL: server.URL, Cache: cache})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	first, err := client.GenerateCodeReview(context.Background(), "Title", "Desc", "diff")
+	if err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+	second, err := client.GenerateCodeReview(context.Background(), "Title", "Desc", "diff")
+	if err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second identical call to be served from the cache, got %d requests", requests)
+	}
+	if second.Review.Score != 80 || len(second.Comments) != 1 || second.Comments[0].Header != first.Comments[0].Header {
+		t.Errorf("Expected cached review to match the original, got %+v", second)
+	}
+
+	if _, err := client.GenerateCodeReview(context.Background(), "Title", "Desc", "other diff"); err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected a different diff to miss the cache, got %d requests", requests)
+	}
+}
+
+func TestCachingClient_PRSummary(t *testing.T) {
+	internal.InitLogger(false)
+	var requests int
+	server := countingServer(t, `{"title": "Add cache", "description": "Caches responses", "type": ["ENHANCEMENT"]}`, &requests)
+
+	client, err := NewClient(Config{Provider: "openai", BaseURL: server.URL, Cache: NewFileCache(t.TempDir(), 0)})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		summary, err := client.GeneratePRSummary(context.Background(), "Title", "Desc", "diff")
+		if err != nil {
+			t.Fatalf("GeneratePRSummary failed: %v", err)
+		}
+		if summary.Title != "Add cache" {
+			t.Errorf("Expected title 'Add cache', got %q", summary.Title)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestCachingClient_KeyIncludesModel(t *testing.T) {
+	a := newCachingClient(nil, Config{Provider: "openai", Model: "gpt-4o"})
+	b := newCachingClient(nil, Config{Provider: "openai", Model: "gpt-4o-mini"})
+
+	if a.cacheKey("system", "user") == b.cacheKey("system", "user") {
+		t.Error("Expected different models to use different cache keys")
+	}
+	if a.cacheKey("system", "user") == a.cacheKey("systemuser", "") {
+		t.Error("Expected prompt boundaries to be part of the key")
+	}
+}
+
+func TestFileCache_TTL(t *testing.T) {
+	cache := NewFileCache(t.TempDir(), time.Hour)
+	if err := cache.Set("key", []byte("value")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if value, ok := cache.Get("key"); !ok || string(value) != "value" {
+		t.Fatalf("Expected fresh entry, got %q, %v", value, ok)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(cache.Dir, "key.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected expired entry to be a miss")
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected missing entry to be a miss")
+	}
+}
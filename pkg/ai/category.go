@@ -0,0 +1,84 @@
+package ai
+
+import "strings"
+
+// CommentCategory is the kind of issue a comment raises, parsed from its label
+type CommentCategory string
+
+const (
+	CategorySecurity        CommentCategory = "security"
+	CategoryBug             CommentCategory = "bug"
+	CategoryPerformance     CommentCategory = "performance"
+	CategoryStyle           CommentCategory = "style"
+	CategoryMaintainability CommentCategory = "maintainability"
+	CategoryTest            CommentCategory = "test"
+	CategoryDocs            CommentCategory = "docs"
+	CategoryOther           CommentCategory = "other" // Labels that match no category
+)
+
+// CommentCategories lists the categories in the order summaries report them
+var CommentCategories = []CommentCategory{
+	CategorySecurity,
+	CategoryBug,
+	CategoryPerformance,
+	CategoryMaintainability,
+	CategoryTest,
+	CategoryDocs,
+	CategoryStyle,
+	CategoryOther,
+}
+
+// categoryAliases maps labels used by older prompts and deterministic checks to a category
+var categoryAliases = map[string]CommentCategory{
+	"vulnerability":  CategorySecurity,
+	"secret":         CategorySecurity,
+	"secrets":        CategorySecurity,
+	"correctness":    CategoryBug,
+	"logic":          CategoryBug,
+	"error-handling": CategoryBug,
+	"perf":           CategoryPerformance,
+	"nitpick":        CategoryStyle,
+	"formatting":     CategoryStyle,
+	"naming":         CategoryStyle,
+	"readability":    CategoryMaintainability,
+	"refactor":       CategoryMaintainability,
+	"best-practice":  CategoryMaintainability,
+	"best-practices": CategoryMaintainability,
+	"duplication":    CategoryMaintainability,
+	"tests":          CategoryTest,
+	"testing":        CategoryTest,
+	"doc":            CategoryDocs,
+	"documentation":  CategoryDocs,
+	"comments":       CategoryDocs,
+}
+
+// ParseCommentCategory returns the category of a raw comment label, case-insensitively and
+// accepting common aliases. Unknown labels are CategoryOther.
+func ParseCommentCategory(label string) CommentCategory {
+	label = strings.ToLower(strings.TrimSpace(label))
+	label = strings.ReplaceAll(label, "_", "-")
+	label = strings.ReplaceAll(label, " ", "-")
+	for _, category := range CommentCategories {
+		if label == string(category) {
+			return category
+		}
+	}
+	if category, ok := categoryAliases[label]; ok {
+		return category
+	}
+	return CategoryOther
+}
+
+// Category returns the comment's category, parsed from its label
+func (c Comment) Category() CommentCategory {
+	return ParseCommentCategory(c.Label)
+}
+
+// CountByCategory counts comments per category
+func CountByCategory(comments []Comment) map[CommentCategory]int {
+	counts := make(map[CommentCategory]int)
+	for _, comment := range comments {
+		counts[comment.Category()]++
+	}
+	return counts
+}
@@ -0,0 +1,50 @@
+package ai
+
+import "testing"
+
+func TestParseCommentCategory(t *testing.T) {
+	tests := []struct {
+		label string
+		want  CommentCategory
+	}{
+		{"security", CategorySecurity},
+		{" Security ", CategorySecurity},
+		{"BUG", CategoryBug},
+		{"performance", CategoryPerformance},
+		{"perf", CategoryPerformance},
+		{"nitpick", CategoryStyle},
+		{"best_practice", CategoryMaintainability},
+		{"Best Practice", CategoryMaintainability},
+		{"testing", CategoryTest},
+		{"documentation", CategoryDocs},
+		{"", CategoryOther},
+		{"question", CategoryOther},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCommentCategory(tt.label); got != tt.want {
+			t.Errorf("ParseCommentCategory(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestCountByCategory(t *testing.T) {
+	counts := CountByCategory([]Comment{
+		{Label: "bug"},
+		{Label: "Bug"},
+		{Label: "security"},
+		{Label: "nitpick"},
+		{Label: "style"},
+		{Label: "unknown"},
+	})
+
+	want := map[CommentCategory]int{CategoryBug: 2, CategorySecurity: 1, CategoryStyle: 2, CategoryOther: 1}
+	if len(counts) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, counts)
+	}
+	for category, count := range want {
+		if counts[category] != count {
+			t.Errorf("Expected %d %s comments, got %d", count, category, counts[category])
+		}
+	}
+}
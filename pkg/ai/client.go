@@ -2,34 +2,82 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+const (
+	// DefaultRetryBaseDelay is the backoff before the first retry when none is configured
+	DefaultRetryBaseDelay = time.Second
+	// maxRetryDelay caps the backoff and any Retry-After requested by the provider
+	maxRetryDelay = time.Minute
 )
 
 type Config struct {
-	Provider string
-	APIKey   string
-	Model    string
-	BaseURL  string
+	Provider   string
+	APIKey     string
+	Model      string
+	BaseURL    string
+	LabelTones map[string]string // label -> tone directive, e.g. "security" -> "authoritative"
+
+	// Sampling overrides; nil keeps each provider's defaults
+	Temperature *float64
+	MaxTokens   *int
+
+	// DisableJSONMode stops requesting the provider's native JSON output mode, for
+	// OpenAI-compatible endpoints that reject response_format
+	DisableJSONMode bool
+
+	// Retries for transient API errors (429, 5xx, timeouts); 0 disables retrying
+	MaxRetries     int
+	RetryBaseDelay time.Duration // Backoff before the first retry, doubled on each attempt (default: 1s)
+
+	// Cache serves repeated summary and review requests without calling the provider; nil disables it
+	Cache CacheBackend
+
+	// ReviewLanguage is the locale summaries and comments are written in, e.g. "es";
+	// unsupported locales fall back to English
+	ReviewLanguage string
+
+	// PromptOverrides replace the built-in system prompts with the repository's templates
+	PromptOverrides *PromptOverrides
 }
 
 func NewClient(config Config) (Client, error) {
+	var client Client
 	switch strings.ToLower(config.Provider) {
 	case "openai":
-		return NewOpenAIClient(config), nil
+		client = NewOpenAIClient(config)
 	case "anthropic":
-		return NewAnthropicClient(config), nil
+		client = NewAnthropicClient(config)
 	case "google":
-		return NewGoogleClient(config), nil
+		client = NewGoogleClient(config)
 	case "openrouter":
-		return NewOpenRouterClient(config), nil
+		client = NewOpenRouterClient(config)
+	case "mock":
+		client = NewMockClient()
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
+
+	if err := ValidateModel(client, config); err != nil {
+		return nil, err
+	}
+	if config.Cache != nil {
+		client = newCachingClient(client, config)
+	}
+	return client, nil
 }
 
 // Base HTTP client for LLM providers
@@ -39,36 +87,156 @@ type BaseClient struct {
 	model      string
 	baseURL    string
 	headers    map[string]string
+	labelTones map[string]string
+	language   string
+	overrides  *PromptOverrides
+
+	temperature *float64
+	maxTokens   *int
+
+	maxRetries     int
+	retryBaseDelay time.Duration
+
+	jsonMode bool
+}
+
+// APIError is a non-200 response from an LLM provider
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Delay requested by the provider's Retry-After header, if any
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
 }
 
+// NewBaseClient creates a client for baseURL. Endpoint paths are appended to it, so a
+// trailing slash is dropped to keep custom gateway paths intact.
 func NewBaseClient(apiKey, model, baseURL string, headers map[string]string) *BaseClient {
 	return &BaseClient{
 		httpClient: &http.Client{Timeout: 600 * time.Second},
 		apiKey:     apiKey,
 		model:      model,
-		baseURL:    baseURL,
+		baseURL:    strings.TrimRight(baseURL, "/"),
 		headers:    headers,
 	}
 }
 
-func (c *BaseClient) makeRequest(endpoint string, payload interface{}) ([]byte, error) {
+// configure applies the provider-independent settings from the client config
+func (c *BaseClient) configure(config Config) {
+	c.labelTones = config.LabelTones
+	c.language = config.ReviewLanguage
+	c.overrides = config.PromptOverrides
+	c.temperature = config.Temperature
+	c.maxTokens = config.MaxTokens
+	c.maxRetries = config.MaxRetries
+	c.retryBaseDelay = config.RetryBaseDelay
+	c.jsonMode = !config.DisableJSONMode
+}
+
+// temperatureOr returns the configured temperature override, or the given default
+func (c *BaseClient) temperatureOr(defaultValue float64) *float64 {
+	if c.temperature != nil {
+		return c.temperature
+	}
+	return &defaultValue
+}
+
+// maxTokensOr returns the configured max tokens override, or the given default
+func (c *BaseClient) maxTokensOr(defaultValue int) int {
+	if c.maxTokens != nil {
+		return *c.maxTokens
+	}
+	return defaultValue
+}
+
+// responseFormat returns the OpenAI-compatible JSON mode setting for structured requests.
+// Responses are still run through extractJSONFromResponse for providers that ignore it.
+func (c *BaseClient) responseFormat() *ResponseFormat {
+	if !c.jsonMode {
+		return nil
+	}
+	return &ResponseFormat{Type: "json_object"}
+}
+
+// responseMimeType returns the Gemini JSON mode setting for structured requests
+func (c *BaseClient) responseMimeType() string {
+	if !c.jsonMode {
+		return ""
+	}
+	return "application/json"
+}
+
+func (c *BaseClient) makeRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set default headers
-	req.Header.Set("Content-Type", "application/json")
+	return c.send(req)
+}
 
-	// Set custom headers
+// makeGetRequest sends a GET request to the provider API, e.g. to list models. It is
+// attempted once: lookups like these are optional and must not hold up a review.
+func (c *BaseClient) makeGetRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	return c.sendOnce(req)
+}
+
+// setHeaders applies the default and custom provider headers to a request
+func (c *BaseClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
 	for key, value := range c.headers {
 		req.Header.Set(key, value)
 	}
+}
+
+// send applies the provider headers and executes a request, retrying transient failures
+// with exponential backoff. It gives up as soon as the request's context is done.
+func (c *BaseClient) send(req *http.Request) ([]byte, error) {
+	c.setHeaders(req)
+
+	for attempt := 0; ; attempt++ {
+		body, err := c.sendOnce(req)
+		if err == nil || attempt >= c.maxRetries || !isRetryable(err) || req.Context().Err() != nil {
+			return body, err
+		}
+
+		delay := c.retryDelay(attempt, err)
+		internal.Logger.Debug("Retrying LLM request", "attempt", attempt+1, "max_retries", c.maxRetries, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("gave up retrying: %w", req.Context().Err())
+		}
+
+		// The body was consumed by the failed attempt
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+		}
+	}
+}
+
+// sendOnce executes a single request within the concurrency limit
+func (c *BaseClient) sendOnce(req *http.Request) ([]byte, error) {
+	release, err := requestLimiter.acquire(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for a request slot: %w", err)
+	}
+	defer release()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -82,38 +250,180 @@ func (c *BaseClient) makeRequest(endpoint string, payload interface{}) ([]byte,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	return body, nil
 }
 
+// isRetryable reports whether a request error is transient: rate limiting, a server error
+// from an overloaded or restarting provider, or a network timeout
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay returns the wait before the next attempt: the provider's Retry-After when given,
+// otherwise exponential backoff with jitter so concurrent chunks don't retry in lockstep
+func (c *BaseClient) retryDelay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, maxRetryDelay)
+	}
+
+	base := c.retryBaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	delay := min(base<<attempt, maxRetryDelay)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// summaryPrompt builds the PR summary system prompt in the configured language
+func (c *BaseClient) summaryPrompt() string {
+	return summarySystemPrompt(c.language, c.overrides)
+}
+
+// codeReviewPrompt builds the code review system prompt with the style guide, language and
+// label tones
+func (c *BaseClient) codeReviewPrompt(styleGuide string) string {
+	return codeReviewSystemPrompt(styleGuide, c.language, c.labelTones, c.overrides)
+}
+
+// combinedReviewPrompt builds the code review prompt extended to also return the PR summary
+func (c *BaseClient) combinedReviewPrompt(styleGuide string) string {
+	return WithCombinedOutput(c.codeReviewPrompt(styleGuide))
+}
+
+// ErrInvalidJSON is returned when an LLM response can't be parsed as JSON, even after repair
+var ErrInvalidJSON = errors.New("invalid JSON in LLM response")
+
+// strictJSONReminder is appended to the prompt when a response had to be retried for invalid JSON
+const strictJSONReminder = "\n\nIMPORTANT: Your previous response was not valid JSON. Return ONLY the JSON object, with no markdown fences, explanations or trailing commas."
+
+// retryInvalidJSON calls generate with userPrompt and, if the response wasn't valid JSON,
+// once more with a stricter reminder appended to it
+func retryInvalidJSON(userPrompt string, generate func(userPrompt string) error) error {
+	err := generate(userPrompt)
+	if !errors.Is(err, ErrInvalidJSON) {
+		return err
+	}
+	internal.Logger.Warn("LLM response was not valid JSON, retrying", "error", err)
+	return generate(userPrompt + strictJSONReminder)
+}
+
+// extractJSONFromResponse returns the JSON object in an LLM response, repairing common
+// mistakes of weaker models: markdown fences, surrounding prose and trailing commas
 func extractJSONFromResponse(content string) string {
-	// Try to find JSON content between ```json and ``` markers
-	if start := strings.Index(content, "```json"); start != -1 {
-		start += 7
+	// Prefer the content of a ```json fence, or of a plain ``` fence wrapping the response.
+	// Plain fences elsewhere may be code blocks inside the JSON strings.
+	content = strings.TrimSpace(content)
+	fence := "```json"
+	if strings.HasPrefix(content, "```") && !strings.HasPrefix(content, fence) {
+		fence = "```"
+	}
+	if start := strings.Index(content, fence); start != -1 {
+		start += len(fence)
 		if end := strings.Index(content[start:], "```"); end != -1 {
-			return strings.TrimSpace(content[start : start+end])
+			content = strings.TrimSpace(content[start : start+end])
 		}
 	}
 
-	// Try to find JSON content between { and } markers
+	if object, ok := outermostJSONObject(content); ok {
+		content = object
+	}
+	return removeTrailingCommas(content)
+}
+
+// outermostJSONObject returns content from its first { to the matching }, ignoring braces
+// inside strings
+func outermostJSONObject(content string) (string, bool) {
 	start := strings.Index(content, "{")
 	if start == -1 {
-		return content
+		return "", false
 	}
 
-	braceCount := 0
-	for i, char := range content[start:] {
-		if char == '{' {
-			braceCount++
-		} else if char == '}' {
-			braceCount--
-			if braceCount == 0 {
-				return content[start : start+i+1]
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(content); i++ {
+		char := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if char == '\\' {
+				escaped = true
+			} else if char == '"' {
+				inString = false
+			}
+		case char == '"':
+			inString = true
+		case char == '{':
+			depth++
+		case char == '}':
+			depth--
+			if depth == 0 {
+				return content[start : i+1], true
 			}
 		}
 	}
 
-	return content
+	// Truncated response: return the rest and let the parser report it
+	return content[start:], true
+}
+
+// removeTrailingCommas drops commas directly followed by a closing } or ], outside strings
+func removeTrailingCommas(content string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(content); i++ {
+		char := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if char == '\\' {
+				escaped = true
+			} else if char == '"' {
+				inString = false
+			}
+		case char == '"':
+			inString = true
+		case char == ',':
+			next := strings.TrimLeft(content[i+1:], " \t\r\n")
+			if strings.HasPrefix(next, "}") || strings.HasPrefix(next, "]") {
+				continue
+			}
+		}
+		b.WriteByte(char)
+	}
+	return b.String()
 }
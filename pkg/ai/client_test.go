@@ -0,0 +1,562 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+// captureRequest starts a server that records the last JSON request body and replies with response
+func captureRequest(t *testing.T, response string, captured *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, captured); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCustomBaseURL_AllProviders(t *testing.T) {
+	googleResponse, _ := json.Marshal(map[string]interface{}{
+		"candidates": []map[string]interface{}{{"content": map[string]interface{}{"parts": []map[string]string{{"text": "ok"}}}}},
+	})
+	openAIResponse := `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`
+
+	tests := []struct {
+		name      string
+		newClient func(Config) Client
+		response  string
+		wantPath  string
+	}{
+		{"openai", func(c Config) Client { return NewOpenAIClient(c) }, openAIResponse, "/llm-gateway/chat/completions"},
+		{"openrouter", func(c Config) Client { return NewOpenRouterClient(c) }, openAIResponse, "/llm-gateway/chat/completions"},
+		{"anthropic", func(c Config) Client { return NewAnthropicClient(c) }, `{"content":[{"type":"text","text":"ok"}]}`, "/llm-gateway/v1/messages"},
+		{"google", func(c Config) Client { return NewGoogleClient(c) }, string(googleResponse), "/llm-gateway/models/gemini-pro:generateContent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			// Trailing slash must not produce a double slash in the request path
+			client := tt.newClient(Config{APIKey: "key", Model: "gemini-pro", BaseURL: server.URL + "/llm-gateway/"})
+			if _, err := client.GenerateResponse(context.Background(), "hi"); err != nil {
+				t.Fatalf("GenerateResponse failed: %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("Expected request to %s, got %s", tt.wantPath, gotPath)
+			}
+		})
+	}
+}
+
+func TestStyleGuide_AllProviders(t *testing.T) {
+	reviewContent := `{"review": {"score": 90}, "comments": []}`
+	combinedContent := `{"summary": {"title": "Title"}, "code_review": {"review": {"score": 90}, "comments": []}}`
+
+	openAIResponse := func(content string) string {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+		return string(payload)
+	}
+	anthropicResponse := func(content string) string {
+		payload, _ := json.Marshal(map[string]interface{}{"content": []map[string]string{{"type": "text", "text": content}}})
+		return string(payload)
+	}
+	googleResponse := func(content string) string {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"candidates": []map[string]interface{}{{"content": map[string]interface{}{"parts": []map[string]string{{"text": content}}}}},
+		})
+		return string(payload)
+	}
+
+	providers := []struct {
+		name      string
+		newClient func(Config) Client
+		response  func(content string) string
+	}{
+		{"openai", func(c Config) Client { return NewOpenAIClient(c) }, openAIResponse},
+		{"openrouter", func(c Config) Client { return NewOpenRouterClient(c) }, openAIResponse},
+		{"anthropic", func(c Config) Client { return NewAnthropicClient(c) }, anthropicResponse},
+		{"google", func(c Config) Client { return NewGoogleClient(c) }, googleResponse},
+	}
+
+	const rule = "Prefer table-driven tests"
+	for _, provider := range providers {
+		calls := []struct {
+			method  string
+			content string
+			call    func(Client) error
+		}{
+			{"GenerateCodeReviewWithStyleGuide", reviewContent, func(c Client) error {
+				_, err := c.GenerateCodeReviewWithStyleGuide(context.Background(), "Title", "Desc", "diff", rule)
+				return err
+			}},
+			{"GenerateCombinedReview", combinedContent, func(c Client) error {
+				_, _, err := c.(CombinedReviewer).GenerateCombinedReview(context.Background(), "Title", "Desc", "diff", rule)
+				return err
+			}},
+		}
+
+		for _, tt := range calls {
+			t.Run(provider.name+"/"+tt.method, func(t *testing.T) {
+				var body []byte
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ = io.ReadAll(r.Body)
+					w.Write([]byte(provider.response(tt.content)))
+				}))
+				defer server.Close()
+
+				if err := tt.call(provider.newClient(Config{APIKey: "key", Model: "model", BaseURL: server.URL})); err != nil {
+					t.Fatalf("%s failed: %v", tt.method, err)
+				}
+
+				// Decode the body, since JSON encoding escapes the style guide's angle brackets
+				var request interface{}
+				if err := json.Unmarshal(body, &request); err != nil {
+					t.Fatalf("invalid request body: %v", err)
+				}
+				text := fmt.Sprint(request)
+				if !strings.Contains(text, "<custom_style_guide>") || !strings.Contains(text, rule) {
+					t.Errorf("Expected style guide in request, got %s", body)
+				}
+			})
+		}
+	}
+}
+
+func TestSamplingOverrides_OpenAI(t *testing.T) {
+	var captured map[string]interface{}
+	server := captureRequest(t, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`, &captured)
+
+	temperature, maxTokens := 1.3, 512
+	client := NewOpenAIClient(Config{BaseURL: server.URL, Temperature: &temperature, MaxTokens: &maxTokens})
+	if _, err := client.GenerateResponse(context.Background(), "hi"); err != nil {
+		t.Fatalf("GenerateResponse failed: %v", err)
+	}
+
+	if captured["temperature"] != 1.3 || captured["max_tokens"] != float64(512) {
+		t.Errorf("Expected overrides in request, got %v", captured)
+	}
+}
+
+func TestSamplingDefaults_OpenAI(t *testing.T) {
+	var captured map[string]interface{}
+	server := captureRequest(t, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`, &captured)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL})
+	if _, err := client.GenerateResponse(context.Background(), "hi"); err != nil {
+		t.Fatalf("GenerateResponse failed: %v", err)
+	}
+
+	if captured["temperature"] != 0.7 {
+		t.Errorf("Expected default temperature 0.7, got %v", captured["temperature"])
+	}
+	if _, ok := captured["max_tokens"]; ok {
+		t.Errorf("Expected max_tokens to be omitted by default, got %v", captured["max_tokens"])
+	}
+}
+
+func TestSamplingOverrides_Anthropic(t *testing.T) {
+	var captured map[string]interface{}
+	server := captureRequest(t, `{"content":[{"type":"text","text":"ok"}]}`, &captured)
+
+	temperature, maxTokens := 0.2, 1024
+	client := NewAnthropicClient(Config{BaseURL: server.URL, Temperature: &temperature, MaxTokens: &maxTokens})
+	if _, err := client.GenerateResponse(context.Background(), "hi"); err != nil {
+		t.Fatalf("GenerateResponse failed: %v", err)
+	}
+
+	if captured["temperature"] != 0.2 || captured["max_tokens"] != float64(1024) {
+		t.Errorf("Expected overrides in request, got %v", captured)
+	}
+}
+
+func TestGenerateCombinedReview_OpenAI(t *testing.T) {
+	var captured map[string]interface{}
+	content := `{"summary": {"title": "Title", "description": "Desc"}, "code_review": {"review": {"score": 90}, "comments": []}}`
+	payload, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+	})
+	server := captureRequest(t, string(payload), &captured)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL})
+	summary, review, err := client.GenerateCombinedReview(context.Background(), "Title", "Desc", "diff", "")
+	if err != nil {
+		t.Fatalf("GenerateCombinedReview failed: %v", err)
+	}
+	if summary.Title != "Title" || review.Review.Score != 90 {
+		t.Errorf("Unexpected result: %+v %+v", summary, review)
+	}
+
+	messages := captured["messages"].([]interface{})
+	system := messages[0].(map[string]interface{})["content"].(string)
+	if !strings.Contains(system, "<combined_output>") {
+		t.Error("Expected the system prompt to request the combined output format")
+	}
+}
+
+func TestReviewLanguage_SystemPrompt(t *testing.T) {
+	var captured map[string]interface{}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": `{"review": {"score": 90}, "comments": []}`}}},
+	})
+	server := captureRequest(t, string(payload), &captured)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, ReviewLanguage: "es"})
+	if _, err := client.GenerateCodeReview(context.Background(), "Title", "Desc", "diff"); err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+
+	messages := captured["messages"].([]interface{})
+	system := messages[0].(map[string]interface{})["content"].(string)
+	if !strings.Contains(system, "Language: Spanish.") {
+		t.Errorf("Expected the system prompt to ask for Spanish, got:\n%s", system)
+	}
+}
+
+func TestTokenUsage_AllProviders(t *testing.T) {
+	review := `{"review": {"score": 90}, "comments": []}`
+	openAIResponse, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": review}}},
+		"usage":   map[string]int{"prompt_tokens": 1200, "completion_tokens": 300},
+	})
+	anthropicResponse, _ := json.Marshal(map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": review}},
+		"usage":   map[string]int{"input_tokens": 1200, "output_tokens": 300},
+	})
+	googleResponse, _ := json.Marshal(map[string]interface{}{
+		"candidates":    []map[string]interface{}{{"content": map[string]interface{}{"parts": []map[string]string{{"text": review}}}}},
+		"usageMetadata": map[string]int{"promptTokenCount": 1200, "candidatesTokenCount": 300, "totalTokenCount": 1500},
+	})
+
+	tests := []struct {
+		name     string
+		newFn    func(Config) Client
+		response []byte
+	}{
+		{"openai", func(c Config) Client { return NewOpenAIClient(c) }, openAIResponse},
+		{"openrouter", func(c Config) Client { return NewOpenRouterClient(c) }, openAIResponse},
+		{"anthropic", func(c Config) Client { return NewAnthropicClient(c) }, anthropicResponse},
+		{"google", func(c Config) Client { return NewGoogleClient(c) }, googleResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured map[string]interface{}
+			server := captureRequest(t, string(tt.response), &captured)
+
+			result, err := tt.newFn(Config{BaseURL: server.URL, Model: "model"}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff")
+			if err != nil {
+				t.Fatalf("GenerateCodeReview failed: %v", err)
+			}
+			if result.TokensIn != 1200 || result.TokensOut != 300 {
+				t.Errorf("Expected 1200/300 tokens, got %d/%d", result.TokensIn, result.TokensOut)
+			}
+		})
+	}
+}
+
+// reviewJSON is an empty code review as the model would return it
+const reviewJSON = `{"review": {"score": 90}, "comments": []}`
+
+func TestJSONMode_OpenAICompatible(t *testing.T) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": reviewJSON}}},
+	})
+
+	clients := map[string]func(Config) Client{
+		"openai":     func(c Config) Client { return NewOpenAIClient(c) },
+		"openrouter": func(c Config) Client { return NewOpenRouterClient(c) },
+	}
+	for name, newClient := range clients {
+		var captured map[string]interface{}
+		server := captureRequest(t, string(payload), &captured)
+
+		if _, err := newClient(Config{BaseURL: server.URL}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff"); err != nil {
+			t.Fatalf("%s: GenerateCodeReview failed: %v", name, err)
+		}
+		format, _ := captured["response_format"].(map[string]interface{})
+		if format["type"] != "json_object" {
+			t.Errorf("%s: expected response_format json_object, got %v", name, captured["response_format"])
+		}
+
+		// Unmarshal merges into an existing map, so start each request from a fresh one
+		captured = nil
+		if _, err := newClient(Config{BaseURL: server.URL}).GenerateResponse(context.Background(), "hi"); err != nil {
+			t.Fatalf("%s: GenerateResponse failed: %v", name, err)
+		}
+		if _, ok := captured["response_format"]; ok {
+			t.Errorf("%s: expected free-form responses without response_format", name)
+		}
+
+		captured = nil
+		if _, err := newClient(Config{BaseURL: server.URL, DisableJSONMode: true}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff"); err != nil {
+			t.Fatalf("%s: GenerateCodeReview failed: %v", name, err)
+		}
+		if _, ok := captured["response_format"]; ok {
+			t.Errorf("%s: expected response_format to be omitted when JSON mode is disabled", name)
+		}
+	}
+}
+
+func TestJSONMode_Google(t *testing.T) {
+	var captured map[string]interface{}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"candidates": []map[string]interface{}{{"content": map[string]interface{}{"parts": []map[string]string{{"text": reviewJSON}}}}},
+	})
+	server := captureRequest(t, string(payload), &captured)
+
+	if _, err := NewGoogleClient(Config{BaseURL: server.URL}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff"); err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+
+	genConfig := captured["generationConfig"].(map[string]interface{})
+	if genConfig["responseMimeType"] != "application/json" {
+		t.Errorf("Expected responseMimeType application/json, got %v", genConfig["responseMimeType"])
+	}
+}
+
+func TestJSONMode_FencedResponseFallback(t *testing.T) {
+	var captured map[string]interface{}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "```json\n" + reviewJSON + "\n```"}}},
+	})
+	server := captureRequest(t, string(payload), &captured)
+
+	review, err := NewOpenAIClient(Config{BaseURL: server.URL}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff")
+	if err != nil || review.Review.Score != 90 {
+		t.Errorf("Expected fenced JSON to still be parsed when the hint is ignored, got %v, %v", review, err)
+	}
+}
+
+func TestExtractJSONFromResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain JSON", `{"a": 1}`, `{"a": 1}`},
+		{"json fence", "```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"plain fence", "```\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"leading apology", "I apologize for the confusion. Here is the review:\n{\"a\": 1}\nLet me know if you need more.", `{"a": 1}`},
+		{"apology and fence", "Sorry about that!\n```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"trailing commas", `{"a": [1, 2,], "b": {"c": 3,},}`, `{"a": [1, 2], "b": {"c": 3}}`},
+		{"braces and commas in strings", `{"code": "if x {,}", "b": "\"}"}`, `{"code": "if x {,}", "b": "\"}"}`},
+		{"code fence in strings", "{\"content\": \"```go\\nx()\\n```\"}", "{\"content\": \"```go\\nx()\\n```\"}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractJSONFromResponse(tt.content)
+			if got != tt.want {
+				t.Errorf("extractJSONFromResponse() = %q, want %q", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("Expected valid JSON, got %q", got)
+			}
+		})
+	}
+}
+
+func TestInvalidJSON_RetriesWithStrictReminder(t *testing.T) {
+	internal.InitLogger(false)
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		prompts = append(prompts, request.Messages[len(request.Messages)-1].Content)
+
+		content := "Sorry, I can't produce JSON right now."
+		if len(prompts) > 1 {
+			content = reviewJSON
+		}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	review, err := NewOpenAIClient(Config{BaseURL: server.URL}).GenerateCodeReview(context.Background(), "Title", "Desc", "diff")
+	if err != nil || review.Review.Score != 90 {
+		t.Fatalf("Expected the retry to succeed, got %v, %v", review, err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(prompts))
+	}
+	if strings.Contains(prompts[0], "Return ONLY the JSON object") || !strings.Contains(prompts[1], "Return ONLY the JSON object") {
+		t.Errorf("Expected only the retry to carry the strict JSON reminder, got %q", prompts)
+	}
+}
+
+func TestInvalidJSON_GivesUpAfterOneRetry(t *testing.T) {
+	internal.InitLogger(false)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"not JSON"}}]}`))
+	}))
+	defer server.Close()
+
+	_, _, err := NewOpenAIClient(Config{BaseURL: server.URL}).GenerateCombinedReview(context.Background(), "Title", "Desc", "diff", "")
+	if !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("Expected ErrInvalidJSON, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+// statusSequenceServer replies with the given status codes in order, then 200 with response
+func statusSequenceServer(t *testing.T, statuses []int, response string, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Error("Expected request body to be resent on retry")
+		}
+		*requests++
+		if *requests <= len(statuses) {
+			w.WriteHeader(statuses[*requests-1])
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetry_TransientErrors(t *testing.T) {
+	internal.InitLogger(false)
+	var requests int
+	server := statusSequenceServer(t, []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`, &requests)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, MaxRetries: 3, RetryBaseDelay: time.Millisecond})
+	response, err := client.GenerateResponse(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Expected request to succeed after retries, got %v", err)
+	}
+	if response != "ok" || requests != 3 {
+		t.Errorf("Expected success on the third attempt, got %q after %d requests", response, requests)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	internal.InitLogger(false)
+	var requests int
+	server := statusSequenceServer(t, []int{502, 502, 502, 502}, `{}`, &requests)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, MaxRetries: 2, RetryBaseDelay: time.Millisecond})
+	_, err := client.GenerateResponse(context.Background(), "hi")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 502 {
+		t.Fatalf("Expected APIError with status 502, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 1 attempt and 2 retries, got %d requests", requests)
+	}
+}
+
+func TestRetry_FailsFastOnClientErrors(t *testing.T) {
+	var requests int
+	server := statusSequenceServer(t, []int{http.StatusUnauthorized}, `{}`, &requests)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, MaxRetries: 3, RetryBaseDelay: time.Millisecond})
+	if _, err := client.GenerateResponse(context.Background(), "hi"); err == nil {
+		t.Fatal("Expected error for 401 response")
+	}
+	if requests != 1 {
+		t.Errorf("Expected no retries for 401, got %d requests", requests)
+	}
+}
+
+func TestRequest_CancelledContext(t *testing.T) {
+	var requests int
+	server := statusSequenceServer(t, nil, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`, &requests)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewOpenAIClient(Config{BaseURL: server.URL}).GenerateResponse(ctx, "hi")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request with a cancelled context, got %d", requests)
+	}
+}
+
+func TestRetry_StopsWhenContextDone(t *testing.T) {
+	internal.InitLogger(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel() // Cancelled while the client waits to retry
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, MaxRetries: 3, RetryBaseDelay: time.Minute})
+	start := time.Now()
+	_, err := client.GenerateResponse(ctx, "hi")
+	if err == nil {
+		t.Fatal("Expected error when the context is cancelled")
+	}
+	if requests != 1 || time.Since(start) > 10*time.Second {
+		t.Errorf("Expected to give up without waiting to retry, got %d requests after %s", requests, time.Since(start))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &BaseClient{retryBaseDelay: 100 * time.Millisecond}
+
+	for attempt := 0; attempt < 4; attempt++ {
+		ceiling := 100 * time.Millisecond << attempt
+		delay := client.retryDelay(attempt, &APIError{StatusCode: 503})
+		if delay < ceiling/2 || delay > ceiling {
+			t.Errorf("attempt %d: expected delay between %v and %v, got %v", attempt, ceiling/2, ceiling, delay)
+		}
+	}
+
+	if delay := client.retryDelay(0, &APIError{StatusCode: 429, RetryAfter: 7 * time.Second}); delay != 7*time.Second {
+		t.Errorf("Expected Retry-After to take precedence, got %v", delay)
+	}
+	if delay := client.retryDelay(20, &APIError{StatusCode: 503}); delay > maxRetryDelay {
+		t.Errorf("Expected delay capped at %v, got %v", maxRetryDelay, delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("30"); got != 30*time.Second {
+		t.Errorf("Expected 30s, got %v", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("Expected 0 for missing header, got %v", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("Expected about a minute for HTTP date, got %v", got)
+	}
+}
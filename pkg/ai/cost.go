@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modelPrice is the list price of a model in USD per million tokens
+type modelPrice struct {
+	input  float64
+	output float64
+}
+
+// modelPrices maps "provider/model" prefixes to list prices. OpenRouter model IDs already
+// have this form, so they are looked up without their provider.
+var modelPrices = map[string]modelPrice{
+	"openai/gpt-3.5-turbo":        {0.50, 1.50},
+	"openai/gpt-4":                {30, 60},
+	"openai/gpt-4-turbo":          {10, 30},
+	"openai/gpt-4o":               {2.50, 10},
+	"openai/gpt-4o-mini":          {0.15, 0.60},
+	"openai/gpt-4.1":              {2, 8},
+	"openai/gpt-4.1-mini":         {0.40, 1.60},
+	"openai/gpt-4.1-nano":         {0.10, 0.40},
+	"openai/gpt-5":                {1.25, 10},
+	"openai/gpt-5-mini":           {0.25, 2},
+	"openai/o3":                   {2, 8},
+	"openai/o4-mini":              {1.10, 4.40},
+	"anthropic/claude-3-haiku":    {0.25, 1.25},
+	"anthropic/claude-3-5-haiku":  {0.80, 4},
+	"anthropic/claude-3-5-sonnet": {3, 15},
+	"anthropic/claude-3-7-sonnet": {3, 15},
+	"anthropic/claude-sonnet-4":   {3, 15},
+	"anthropic/claude-3-opus":     {15, 75},
+	"anthropic/claude-opus-4":     {15, 75},
+	"google/gemini-1.5-flash":     {0.075, 0.30},
+	"google/gemini-1.5-pro":       {1.25, 5},
+	"google/gemini-2.0-flash":     {0.10, 0.40},
+	"google/gemini-2.5-flash":     {0.30, 2.50},
+	"google/gemini-2.5-pro":       {1.25, 10},
+}
+
+// EstimateCost returns the estimated USD cost of the tokens at the model's list price,
+// matching the longest known prefix. It returns false for models without a known price.
+func EstimateCost(provider, model string, tokensIn, tokensOut int) (float64, bool) {
+	candidates := []string{strings.ToLower(provider + "/" + model), strings.ToLower(model)}
+
+	var price modelPrice
+	longest := 0
+	for prefix, p := range modelPrices {
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, prefix) && len(prefix) > longest {
+				price, longest = p, len(prefix)
+			}
+		}
+	}
+	if longest == 0 {
+		return 0, false
+	}
+	return (float64(tokensIn)*price.input + float64(tokensOut)*price.output) / 1_000_000, true
+}
+
+// FormatUsage describes the tokens used by a review and their estimated cost, or returns
+// "" when the provider reported no usage
+func FormatUsage(provider, model string, tokensIn, tokensOut int) string {
+	if tokensIn == 0 && tokensOut == 0 {
+		return ""
+	}
+	usage := fmt.Sprintf("🔢 Tokens: %d in / %d out", tokensIn, tokensOut)
+	if cost, ok := EstimateCost(provider, model, tokensIn, tokensOut); ok {
+		usage += fmt.Sprintf(" · estimated cost $%.4f", cost)
+	}
+	return usage
+}
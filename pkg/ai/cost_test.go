@@ -0,0 +1,44 @@
+package ai
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		provider, model string
+		expected        float64
+	}{
+		{"openai", "gpt-4o", 2.50 + 10},
+		{"openai", "gpt-4o-mini-2024-07-18", 0.15 + 0.60},
+		{"anthropic", "claude-3-5-sonnet-20241022", 3 + 15},
+		{"openrouter", "google/gemini-2.5-flash", 0.30 + 2.50},
+	}
+
+	for _, tt := range tests {
+		cost, ok := EstimateCost(tt.provider, tt.model, 1_000_000, 1_000_000)
+		if !ok || math.Abs(cost-tt.expected) > 1e-9 {
+			t.Errorf("EstimateCost(%s, %s) = %v, %v, want %v", tt.provider, tt.model, cost, ok, tt.expected)
+		}
+	}
+
+	if _, ok := EstimateCost("openai", "my-finetune", 100, 100); ok {
+		t.Error("Expected no estimate for an unknown model")
+	}
+}
+
+func TestFormatUsage(t *testing.T) {
+	if got := FormatUsage("openai", "gpt-4o", 0, 0); got != "" {
+		t.Errorf("Expected no usage line without tokens, got %q", got)
+	}
+
+	got := FormatUsage("openai", "gpt-4o", 12000, 800)
+	if !strings.Contains(got, "12000 in / 800 out") || !strings.Contains(got, "$0.0380") {
+		t.Errorf("Unexpected usage line %q", got)
+	}
+	if got := FormatUsage("ollama", "llama3", 10, 5); strings.Contains(got, "$") {
+		t.Errorf("Expected no cost for an unpriced model, got %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -9,6 +10,11 @@ type GoogleClient struct {
 	*BaseClient
 }
 
+var (
+	_ Client           = (*GoogleClient)(nil)
+	_ CombinedReviewer = (*GoogleClient)(nil)
+)
+
 type GoogleRequest struct {
 	Contents          []GoogleContent  `json:"contents"`
 	SystemInstruction *GoogleContent   `json:"systemInstruction,omitempty"`
@@ -25,8 +31,9 @@ type GooglePart struct {
 }
 
 type GoogleGenConfig struct {
-	Temperature     *float64 `json:"temperature,omitempty"`
-	MaxOutputTokens *int     `json:"maxOutputTokens,omitempty"`
+	Temperature      *float64 `json:"temperature,omitempty"`
+	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType string   `json:"responseMimeType,omitempty"` // "application/json" enables JSON mode
 }
 
 type GoogleResponse struct {
@@ -37,6 +44,10 @@ type GoogleResponse struct {
 		} `json:"content"`
 		FinishReason string `json:"finishReason"`
 	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata,omitempty"`
 	Error *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -44,6 +55,14 @@ type GoogleResponse struct {
 	} `json:"error,omitempty"`
 }
 
+// tokens returns the prompt and candidate tokens of the response, zero if not reported
+func (r *GoogleResponse) tokens() (int, int) {
+	if r.UsageMetadata == nil {
+		return 0, 0
+	}
+	return r.UsageMetadata.PromptTokenCount, r.UsageMetadata.CandidatesTokenCount
+}
+
 func NewGoogleClient(config Config) *GoogleClient {
 	baseURL := config.BaseURL
 	if baseURL == "" {
@@ -52,14 +71,16 @@ func NewGoogleClient(config Config) *GoogleClient {
 
 	headers := map[string]string{}
 
-	return &GoogleClient{
+	client := &GoogleClient{
 		BaseClient: NewBaseClient(config.APIKey, config.Model, baseURL, headers),
 	}
+	client.configure(config)
+	return client
 }
 
-func (c *GoogleClient) GeneratePRSummary(prTitle, prDescription, diff string) (*PRSummary, error) {
-	systemPrompt := GetPRSummaryPrompt()
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+func (c *GoogleClient) GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error) {
+	systemPrompt := c.summaryPrompt()
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
 	request := GoogleRequest{
 		SystemInstruction: &GoogleContent{
@@ -72,13 +93,14 @@ func (c *GoogleClient) GeneratePRSummary(prTitle, prDescription, diff string) (*
 			},
 		},
 		GenerationConfig: &GoogleGenConfig{
-			Temperature:     &[]float64{0.1}[0],
-			MaxOutputTokens: &[]int{4096}[0],
+			Temperature:      c.temperatureOr(0.1),
+			MaxOutputTokens:  &[]int{c.maxTokensOr(4096)}[0],
+			ResponseMimeType: c.responseMimeType(),
 		},
 	}
 
 	endpoint := fmt.Sprintf("/models/%s:generateContent?key=%s", c.model, c.apiKey)
-	respBytes, err := c.makeRequest(endpoint, request)
+	respBytes, err := c.makeRequest(ctx, endpoint, request)
 	if err != nil {
 		return nil, err
 	}
@@ -103,23 +125,28 @@ func (c *GoogleClient) GeneratePRSummary(prTitle, prDescription, diff string) (*
 		return nil, fmt.Errorf("failed to parse PR summary JSON: %w", err)
 	}
 
+	summary.TokensIn, summary.TokensOut = response.tokens()
 	return &summary, nil
 }
 
-func (c *GoogleClient) GenerateCodeReview(prTitle, prDescription, diff string) (*ReviewResult, error) {
-	return c.GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, "")
+func (c *GoogleClient) GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error) {
+	return c.GenerateCodeReviewWithStyleGuide(ctx, prTitle, prDescription, diff, "")
 }
 
-func (c *GoogleClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
-	var systemPrompt string
-	if styleGuide != "" {
-		systemPrompt = GetCodeReviewPromptWithStyleGuide(styleGuide)
-	} else {
-		systemPrompt = GetCodeReviewPrompt()
-	}
+func (c *GoogleClient) GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
+	systemPrompt := c.codeReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		review, err = c.requestCodeReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return review, err
+}
 
+// requestCodeReview sends one code review request and parses its result
+func (c *GoogleClient) requestCodeReview(ctx context.Context, systemPrompt, userPrompt string) (*ReviewResult, error) {
 	request := GoogleRequest{
 		SystemInstruction: &GoogleContent{
 			Parts: []GooglePart{{Text: systemPrompt}},
@@ -131,13 +158,14 @@ func (c *GoogleClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescription,
 			},
 		},
 		GenerationConfig: &GoogleGenConfig{
-			Temperature:     &[]float64{0.1}[0],
-			MaxOutputTokens: &[]int{4096}[0],
+			Temperature:      c.temperatureOr(0.1),
+			MaxOutputTokens:  &[]int{c.maxTokensOr(4096)}[0],
+			ResponseMimeType: c.responseMimeType(),
 		},
 	}
 
 	endpoint := fmt.Sprintf("/models/%s:generateContent?key=%s", c.model, c.apiKey)
-	respBytes, err := c.makeRequest(endpoint, request)
+	respBytes, err := c.makeRequest(ctx, endpoint, request)
 	if err != nil {
 		return nil, err
 	}
@@ -159,13 +187,75 @@ func (c *GoogleClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescription,
 
 	var review ReviewResult
 	if err := json.Unmarshal([]byte(content), &review); err != nil {
-		return nil, fmt.Errorf("failed to parse review JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse review JSON: %w: %w", ErrInvalidJSON, err)
 	}
 
+	review.TokensIn, review.TokensOut = response.tokens()
 	return &review, nil
 }
 
-func (c *GoogleClient) GenerateResponse(prompt string) (string, error) {
+func (c *GoogleClient) GenerateCombinedReview(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*PRSummary, *ReviewResult, error) {
+	systemPrompt := c.combinedReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
+
+	var summary *PRSummary
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		summary, review, err = c.requestCombinedReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return summary, review, err
+}
+
+// requestCombinedReview sends one combined summary and review request and parses its result
+func (c *GoogleClient) requestCombinedReview(ctx context.Context, systemPrompt, userPrompt string) (*PRSummary, *ReviewResult, error) {
+	request := GoogleRequest{
+		SystemInstruction: &GoogleContent{
+			Parts: []GooglePart{{Text: systemPrompt}},
+		},
+		Contents: []GoogleContent{
+			{
+				Role:  "user",
+				Parts: []GooglePart{{Text: userPrompt}},
+			},
+		},
+		GenerationConfig: &GoogleGenConfig{
+			Temperature:      c.temperatureOr(0.1),
+			MaxOutputTokens:  &[]int{c.maxTokensOr(4096)}[0],
+			ResponseMimeType: c.responseMimeType(),
+		},
+	}
+
+	endpoint := fmt.Sprintf("/models/%s:generateContent?key=%s", c.model, c.apiKey)
+	respBytes, err := c.makeRequest(ctx, endpoint, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response GoogleResponse
+	if err := json.Unmarshal(respBytes, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, nil, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
+		return nil, nil, fmt.Errorf("no response candidates returned")
+	}
+
+	content := extractJSONFromResponse(response.Candidates[0].Content.Parts[0].Text)
+
+	summary, review, err := ParseCombinedResponse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	review.TokensIn, review.TokensOut = response.tokens()
+	return summary, review, nil
+}
+
+func (c *GoogleClient) GenerateResponse(ctx context.Context, prompt string) (string, error) {
 	request := GoogleRequest{
 		Contents: []GoogleContent{
 			{
@@ -174,13 +264,13 @@ func (c *GoogleClient) GenerateResponse(prompt string) (string, error) {
 			},
 		},
 		GenerationConfig: &GoogleGenConfig{
-			Temperature:     &[]float64{0.7}[0],
-			MaxOutputTokens: &[]int{4096}[0],
+			Temperature:     c.temperatureOr(0.7),
+			MaxOutputTokens: &[]int{c.maxTokensOr(4096)}[0],
 		},
 	}
 
 	endpoint := fmt.Sprintf("/models/%s:generateContent?key=%s", c.model, c.apiKey)
-	respBytes, err := c.makeRequest(endpoint, request)
+	respBytes, err := c.makeRequest(ctx, endpoint, request)
 	if err != nil {
 		return "", err
 	}
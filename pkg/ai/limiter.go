@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxConcurrency is the default number of LLM requests allowed in flight at once
+const DefaultMaxConcurrency = 4
+
+// requestLimiter bounds in-flight LLM requests across every client in the process,
+// so concurrent reviews (e.g. in webhook mode) don't exceed provider limits
+var requestLimiter = newConcurrencyLimiter(DefaultMaxConcurrency)
+
+type concurrencyLimiter struct {
+	mu    sync.Mutex
+	slots chan struct{}
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	l := &concurrencyLimiter{}
+	l.setMax(max)
+	return l
+}
+
+// setMax replaces the semaphore. Requests already in flight release into the old one.
+func (l *concurrencyLimiter) setMax(max int) {
+	if max < 1 {
+		max = DefaultMaxConcurrency
+	}
+	l.mu.Lock()
+	l.slots = make(chan struct{}, max)
+	l.mu.Unlock()
+}
+
+// acquire blocks until a slot is free and returns the function that releases it, or fails
+// when ctx is done first
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	slots := l.slots
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SetMaxConcurrency sets the process-wide limit on concurrent LLM requests.
+// Values below 1 reset it to DefaultMaxConcurrency.
+func SetMaxConcurrency(max int) {
+	requestLimiter.setMax(max)
+}
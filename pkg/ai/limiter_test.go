@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiterBoundsInFlight(t *testing.T) {
+	limiter := newConcurrencyLimiter(2)
+
+	var inFlight, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, _ := limiter.acquire(context.Background())
+			defer release()
+
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
+
+func TestConcurrencyLimiterInvalidMax(t *testing.T) {
+	limiter := newConcurrencyLimiter(0)
+	if cap(limiter.slots) != DefaultMaxConcurrency {
+		t.Errorf("Expected default capacity %d, got %d", DefaultMaxConcurrency, cap(limiter.slots))
+	}
+}
+
+func TestConcurrencyLimiterAcquireCancelled(t *testing.T) {
+	limiter := newConcurrencyLimiter(1)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx); err == nil {
+		t.Error("Expected acquire to fail once the context is done while all slots are taken")
+	}
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// MockPrompt is a request received by a MockClient
+type MockPrompt struct {
+	Method      string // Client method called, e.g. "GenerateCodeReview"
+	Title       string
+	Description string
+	Diff        string // The diff, or the prompt for GenerateResponse
+	StyleGuide  string
+}
+
+// MockClient is a Client returning canned results without calling an LLM, selected with
+// LLM_PROVIDER=mock. It makes engine tests and output format checks possible offline.
+type MockClient struct {
+	Summary  *PRSummary
+	Review   *ReviewResult
+	Response string
+	Err      error // Returned by every method when set
+
+	mu      sync.Mutex
+	prompts []MockPrompt
+}
+
+// NewMockClient returns a MockClient with a sample summary and review
+func NewMockClient() *MockClient {
+	return &MockClient{
+		Summary:  MockSummary(),
+		Review:   MockReview(),
+		Response: "This is a mock response.",
+	}
+}
+
+// MockSummary returns the sample summary of a new MockClient
+func MockSummary() *PRSummary {
+	summary := &PRSummary{
+		Title:       "Add payments initializer",
+		Description: "This is a **mock review** generated to demonstrate the terminal output format. In a real run, this would be generated by your chosen LLM.",
+		Type:        []string{"ENHANCEMENT"},
+	}
+	summary.Files = append(summary.Files, struct {
+		Filename string `json:"filename"`
+		Summary  string `json:"summary"`
+		Title    string `json:"title"`
+	}{Filename: "internal/app/payments_initializer.go", Summary: "Wires the payment provider from environment variables.", Title: "Initialize payments"})
+	return summary
+}
+
+// MockReview returns the sample review of a new MockClient
+func MockReview() *ReviewResult {
+	return &ReviewResult{
+		Review: ReviewSummary{
+			Score:            85,
+			EstimatedEffort:  2,
+			HasRelevantTests: true,
+			SecurityConcerns: "None detected.",
+		},
+		Comments: []Comment{
+			{
+				File:            "internal/payments/service/integration_test.go",
+				StartLine:       104,
+				EndLine:         106,
+				Header:          "🟡 Remove duplicate line",
+				Content:         "Line 105 is a duplicate of line 104. This will cause the payment to be stored twice.",
+				Label:           "bug",
+				HighlightedCode: "	r.payments[p.ID] = p\n	r.payments[p.ID] = p\n	return p, nil",
+				SuggestedCode:   "	r.payments[p.ID] = p\n	return p, nil",
+			},
+			{
+				File:            "internal/app/payments_initializer.go",
+				StartLine:       22,
+				EndLine:         26,
+				Header:          "🔴 Missing validation for required environment variables",
+				Content:         "The initializer reads MERCADOPAGO_ACCESS_TOKEN without validating it's set. An empty access token will cause all payment API calls to fail with unhelpful errors.",
+				Label:           "security",
+				Critical:        true,
+				HighlightedCode: "func initializePayments(sqlDB *sql.DB) PaymentsComponents {\n	mpAccessToken := os.Getenv(\"MERCADOPAGO_ACCESS_TOKEN\")\n	mpWebhookSecret := os.Getenv(\"MERCADOPAGO_WEBHOOK_SECRET\")",
+				SuggestedCode:   "func initializePayments(sqlDB *sql.DB) PaymentsComponents {\n	mpAccessToken := os.Getenv(\"MERCADOPAGO_ACCESS_TOKEN\")\n	if mpAccessToken == \"\" {\n		panic(\"MERCADOPAGO_ACCESS_TOKEN environment variable is required\")\n	}\n	mpWebhookSecret := os.Getenv(\"MERCADOPAGO_WEBHOOK_SECRET\")",
+			},
+		},
+	}
+}
+
+// Prompts returns the requests received so far, in order
+func (c *MockClient) Prompts() []MockPrompt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]MockPrompt(nil), c.prompts...)
+}
+
+func (c *MockClient) record(prompt MockPrompt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prompts = append(c.prompts, prompt)
+}
+
+// summary returns a copy of the canned summary, so callers can modify it
+func (c *MockClient) summary() *PRSummary {
+	if c.Summary == nil {
+		return &PRSummary{}
+	}
+	summary := *c.Summary
+	return &summary
+}
+
+// review returns a copy of the canned review, so the engine can modify each chunk's comments
+func (c *MockClient) review() *ReviewResult {
+	if c.Review == nil {
+		return &ReviewResult{}
+	}
+	review := *c.Review
+	review.Comments = append([]Comment(nil), c.Review.Comments...)
+	return &review
+}
+
+func (c *MockClient) GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error) {
+	c.record(MockPrompt{Method: "GeneratePRSummary", Title: prTitle, Description: prDescription, Diff: diff})
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return c.summary(), nil
+}
+
+func (c *MockClient) GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error) {
+	c.record(MockPrompt{Method: "GenerateCodeReview", Title: prTitle, Description: prDescription, Diff: diff})
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return c.review(), nil
+}
+
+func (c *MockClient) GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
+	c.record(MockPrompt{Method: "GenerateCodeReviewWithStyleGuide", Title: prTitle, Description: prDescription, Diff: diff, StyleGuide: styleGuide})
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return c.review(), nil
+}
+
+func (c *MockClient) GenerateResponse(ctx context.Context, prompt string) (string, error) {
+	c.record(MockPrompt{Method: "GenerateResponse", Diff: prompt})
+	if c.Err != nil {
+		return "", c.Err
+	}
+	return c.Response, nil
+}
@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewClient_Mock(t *testing.T) {
+	client, err := NewClient(Config{Provider: "mock"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if _, ok := client.(*MockClient); !ok {
+		t.Errorf("Expected a MockClient, got %T", client)
+	}
+}
+
+func TestMockClient_ReturnsCopies(t *testing.T) {
+	client := NewMockClient()
+
+	first, err := client.GenerateCodeReview(context.Background(), "Title", "Description", "diff")
+	if err != nil {
+		t.Fatalf("GenerateCodeReview failed: %v", err)
+	}
+	first.Comments[0].Header = "changed"
+
+	second, _ := client.GenerateCodeReview(context.Background(), "Title", "Description", "diff")
+	if second.Comments[0].Header == "changed" {
+		t.Error("Expected each review to be a copy of the canned one")
+	}
+	if prompts := client.Prompts(); len(prompts) != 2 || prompts[0].Diff != "diff" {
+		t.Errorf("Expected both requests to be recorded, got %+v", prompts)
+	}
+}
+
+func TestMockClient_Err(t *testing.T) {
+	client := &MockClient{Err: errors.New("unavailable")}
+	if _, err := client.GeneratePRSummary(context.Background(), "", "", ""); err == nil {
+		t.Error("Expected the configured error")
+	}
+	if _, err := client.GenerateResponse(context.Background(), "ping"); err == nil {
+		t.Error("Expected the configured error")
+	}
+}
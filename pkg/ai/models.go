@@ -0,0 +1,214 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// modelListTTL is how long a provider's model list is reused before fetching it again
+const modelListTTL = 10 * time.Minute
+
+// modelListTimeout bounds the model list lookup made when a client is created
+const modelListTimeout = 5 * time.Second
+
+// maxModelSuggestions is the number of close matches offered for an unknown model
+const maxModelSuggestions = 3
+
+// ModelLister is implemented by providers that can list the models available to the API key
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+type cachedModelList struct {
+	models    []string
+	fetchedAt time.Time
+}
+
+var (
+	modelListCache   = make(map[string]cachedModelList)
+	modelListCacheMu sync.Mutex
+)
+
+// ValidateModel checks the configured model against the provider's model list, returning an
+// error with close-match suggestions if it is not available. Providers without a model list,
+// or whose list cannot be fetched within modelListTimeout, are not validated so a review is
+// never blocked by the check.
+func ValidateModel(client Client, config Config) error {
+	lister, ok := client.(ModelLister)
+	if !ok || config.Model == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+	defer cancel()
+	models, err := cachedModels(ctx, config.Provider+"|"+config.BaseURL, lister)
+	if err != nil || len(models) == 0 {
+		return nil
+	}
+
+	if modelAvailable(config.Model, models) {
+		return nil
+	}
+
+	if suggestions := suggestModels(config.Model, models); len(suggestions) > 0 {
+		return fmt.Errorf("model %s not available for provider %s; did you mean %s?",
+			config.Model, config.Provider, strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("model %s not available for provider %s", config.Model, config.Provider)
+}
+
+// cachedModels returns the model list for a provider, fetching it when missing or expired.
+// The lock is only held around the cache, so a slow provider doesn't stall other clients.
+func cachedModels(ctx context.Context, key string, lister ModelLister) ([]string, error) {
+	modelListCacheMu.Lock()
+	cached, ok := modelListCache[key]
+	modelListCacheMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < modelListTTL {
+		return cached.models, nil
+	}
+
+	models, err := lister.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modelListCacheMu.Lock()
+	modelListCache[key] = cachedModelList{models: models, fetchedAt: time.Now()}
+	modelListCacheMu.Unlock()
+	return models, nil
+}
+
+// modelAvailable checks if a model is in the list. A variant suffix such as ":free" on
+// OpenRouter is accepted when the base model is listed.
+func modelAvailable(model string, models []string) bool {
+	base := model
+	if idx := strings.LastIndex(model, ":"); idx != -1 {
+		base = model[:idx]
+	}
+	for _, available := range models {
+		if available == model || available == base {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestModels returns the listed models closest to the requested one by edit distance
+func suggestModels(model string, models []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	// Allow roughly one typo per three characters
+	maxDistance := len(model)/3 + 1
+	lowerModel := strings.ToLower(model)
+
+	var candidates []candidate
+	for _, available := range models {
+		distance := levenshtein(lowerModel, strings.ToLower(available))
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{available, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxModelSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// openAIModelList is the model list response shared by OpenAI-compatible APIs
+type openAIModelList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// listOpenAIModels fetches the model IDs from an OpenAI-compatible /models endpoint
+func (c *BaseClient) listOpenAIModels(ctx context.Context) ([]string, error) {
+	respBytes, err := c.makeGetRequest(ctx, "/models")
+	if err != nil {
+		return nil, err
+	}
+
+	var list openAIModelList
+	if err := json.Unmarshal(respBytes, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %w", err)
+	}
+
+	models := make([]string, 0, len(list.Data))
+	for _, model := range list.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+// ListModels returns the models available from the OpenAI API
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]string, error) {
+	return c.listOpenAIModels(ctx)
+}
+
+// ListModels returns the models available through OpenRouter
+func (c *OpenRouterClient) ListModels(ctx context.Context) ([]string, error) {
+	return c.listOpenAIModels(ctx)
+}
+
+// ListModels returns the Gemini models available to the API key, without the "models/" prefix
+func (c *GoogleClient) ListModels(ctx context.Context) ([]string, error) {
+	respBytes, err := c.makeGetRequest(ctx, fmt.Sprintf("/models?pageSize=1000&key=%s", c.apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(respBytes, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %w", err)
+	}
+
+	models := make([]string, 0, len(list.Models))
+	for _, model := range list.Models {
+		models = append(models, strings.TrimPrefix(model.Name, "models/"))
+	}
+	return models, nil
+}
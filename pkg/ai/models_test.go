@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// modelListServer serves an OpenAI-style model list and counts the requests it receives
+func modelListServer(t *testing.T, response string, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		*requests++
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewClient_UnknownModel(t *testing.T) {
+	var requests int
+	server := modelListServer(t, `{"data":[{"id":"gpt-4o"},{"id":"gpt-4o-mini"},{"id":"o1"}]}`, &requests)
+
+	_, err := NewClient(Config{Provider: "openai", Model: "gpt-4p", BaseURL: server.URL})
+	if err == nil {
+		t.Fatal("Expected error for unknown model")
+	}
+	expected := "model gpt-4p not available for provider openai; did you mean gpt-4o"
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("Expected error containing %q, got %q", expected, err.Error())
+	}
+}
+
+func TestNewClient_KnownModelCached(t *testing.T) {
+	var requests int
+	server := modelListServer(t, `{"data":[{"id":"mistralai/mistral-7b-instruct"}]}`, &requests)
+
+	for i := 0; i < 2; i++ {
+		if _, err := NewClient(Config{Provider: "openrouter", Model: "mistralai/mistral-7b-instruct:free", BaseURL: server.URL}); err != nil {
+			t.Fatalf("Expected model variant to be accepted, got %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected model list to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestNewClient_ModelListUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(Config{Provider: "openai", Model: "llama3.2:latest", BaseURL: server.URL}); err != nil {
+		t.Errorf("Expected validation to be skipped when the model list is unavailable, got %v", err)
+	}
+}
+
+func TestNewClient_ModelListNotRetried(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := Config{Provider: "openai", Model: "gpt-4o", BaseURL: server.URL, MaxRetries: 3, RetryBaseDelay: time.Millisecond}
+	if _, err := NewClient(config); err != nil {
+		t.Errorf("Expected validation to be skipped when the model list is unavailable, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the model list to be requested once, got %d requests", requests)
+	}
+}
+
+func TestSuggestModels(t *testing.T) {
+	models := []string{"gemini-1.5-pro", "gemini-1.5-flash", "gemini-2.0-flash", "text-embedding-004"}
+
+	suggestions := suggestModels("gemini-1.5-flsh", models)
+	if len(suggestions) == 0 || suggestions[0] != "gemini-1.5-flash" {
+		t.Errorf("Expected gemini-1.5-flash as closest match, got %v", suggestions)
+	}
+
+	if suggestions := suggestModels("claude-3-opus", models); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions for unrelated model, got %v", suggestions)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"gpt-4o", "gpt-4o", 0},
+		{"gpt-4p", "gpt-4o", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -9,6 +10,11 @@ type OpenRouterClient struct {
 	*BaseClient
 }
 
+var (
+	_ Client           = (*OpenRouterClient)(nil)
+	_ CombinedReviewer = (*OpenRouterClient)(nil)
+)
+
 func NewOpenRouterClient(config Config) *OpenRouterClient {
 	baseURL := config.BaseURL
 	if baseURL == "" {
@@ -21,14 +27,16 @@ func NewOpenRouterClient(config Config) *OpenRouterClient {
 		"X-Title":       "manque-ai",                              // Optional: for tracking
 	}
 
-	return &OpenRouterClient{
+	client := &OpenRouterClient{
 		BaseClient: NewBaseClient(config.APIKey, config.Model, baseURL, headers),
 	}
+	client.configure(config)
+	return client
 }
 
-func (c *OpenRouterClient) GeneratePRSummary(prTitle, prDescription, diff string) (*PRSummary, error) {
-	systemPrompt := GetPRSummaryPrompt()
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+func (c *OpenRouterClient) GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error) {
+	systemPrompt := c.summaryPrompt()
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
 	request := ChatCompletionRequest{
 		Model: c.model,
@@ -36,10 +44,12 @@ func (c *OpenRouterClient) GeneratePRSummary(prTitle, prDescription, diff string
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userPrompt},
 		},
-		Temperature: &[]float64{0.1}[0],
+		Temperature:    c.temperatureOr(0.1),
+		MaxTokens:      c.maxTokens,
+		ResponseFormat: c.responseFormat(),
 	}
 
-	respBytes, err := c.makeRequest("/chat/completions", request)
+	respBytes, err := c.makeRequest(ctx, "/chat/completions", request)
 	if err != nil {
 		return nil, err
 	}
@@ -64,33 +74,40 @@ func (c *OpenRouterClient) GeneratePRSummary(prTitle, prDescription, diff string
 		return nil, fmt.Errorf("failed to parse PR summary JSON: %w", err)
 	}
 
+	summary.TokensIn, summary.TokensOut = response.tokens()
 	return &summary, nil
 }
 
-func (c *OpenRouterClient) GenerateCodeReview(prTitle, prDescription, diff string) (*ReviewResult, error) {
-	return c.GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, "")
+func (c *OpenRouterClient) GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error) {
+	return c.GenerateCodeReviewWithStyleGuide(ctx, prTitle, prDescription, diff, "")
 }
 
-func (c *OpenRouterClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
-	var systemPrompt string
-	if styleGuide != "" {
-		systemPrompt = GetCodeReviewPromptWithStyleGuide(styleGuide)
-	} else {
-		systemPrompt = GetCodeReviewPrompt()
-	}
+func (c *OpenRouterClient) GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error) {
+	systemPrompt := c.codeReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
 
-	userPrompt := fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		review, err = c.requestCodeReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return review, err
+}
 
+// requestCodeReview sends one code review request and parses its result
+func (c *OpenRouterClient) requestCodeReview(ctx context.Context, systemPrompt, userPrompt string) (*ReviewResult, error) {
 	request := ChatCompletionRequest{
 		Model: c.model,
 		Messages: []ChatMessage{
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userPrompt},
 		},
-		Temperature: &[]float64{0.1}[0],
+		Temperature:    c.temperatureOr(0.1),
+		MaxTokens:      c.maxTokens,
+		ResponseFormat: c.responseFormat(),
 	}
 
-	respBytes, err := c.makeRequest("/chat/completions", request)
+	respBytes, err := c.makeRequest(ctx, "/chat/completions", request)
 	if err != nil {
 		return nil, err
 	}
@@ -112,22 +129,78 @@ func (c *OpenRouterClient) GenerateCodeReviewWithStyleGuide(prTitle, prDescripti
 
 	var review ReviewResult
 	if err := json.Unmarshal([]byte(content), &review); err != nil {
-		return nil, fmt.Errorf("failed to parse review JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse review JSON: %w: %w", ErrInvalidJSON, err)
 	}
 
+	review.TokensIn, review.TokensOut = response.tokens()
 	return &review, nil
 }
 
-func (c *OpenRouterClient) GenerateResponse(prompt string) (string, error) {
+func (c *OpenRouterClient) GenerateCombinedReview(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*PRSummary, *ReviewResult, error) {
+	systemPrompt := c.combinedReviewPrompt(styleGuide)
+	userPrompt := reviewUserPrompt(prTitle, prDescription, diff)
+
+	var summary *PRSummary
+	var review *ReviewResult
+	err := retryInvalidJSON(userPrompt, func(userPrompt string) (err error) {
+		summary, review, err = c.requestCombinedReview(ctx, systemPrompt, userPrompt)
+		return err
+	})
+	return summary, review, err
+}
+
+// requestCombinedReview sends one combined summary and review request and parses its result
+func (c *OpenRouterClient) requestCombinedReview(ctx context.Context, systemPrompt, userPrompt string) (*PRSummary, *ReviewResult, error) {
+	request := ChatCompletionRequest{
+		Model: c.model,
+		Messages: []ChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Temperature:    c.temperatureOr(0.1),
+		MaxTokens:      c.maxTokens,
+		ResponseFormat: c.responseFormat(),
+	}
+
+	respBytes, err := c.makeRequest(ctx, "/chat/completions", request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response ChatCompletionResponse
+	if err := json.Unmarshal(respBytes, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, nil, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, nil, fmt.Errorf("no response choices returned")
+	}
+
+	content := extractJSONFromResponse(response.Choices[0].Message.Content)
+
+	summary, review, err := ParseCombinedResponse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	review.TokensIn, review.TokensOut = response.tokens()
+	return summary, review, nil
+}
+
+func (c *OpenRouterClient) GenerateResponse(ctx context.Context, prompt string) (string, error) {
 	request := ChatCompletionRequest{
 		Model: c.model,
 		Messages: []ChatMessage{
 			{Role: "user", Content: prompt},
 		},
-		Temperature: &[]float64{0.7}[0],
+		Temperature: c.temperatureOr(0.7),
+		MaxTokens:   c.maxTokens,
 	}
 
-	respBytes, err := c.makeRequest("/chat/completions", request)
+	respBytes, err := c.makeRequest(ctx, "/chat/completions", request)
 	if err != nil {
 		return "", err
 	}
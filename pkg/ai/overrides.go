@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PromptOverridesDir is where a repository keeps its prompt templates, relative to its root
+const PromptOverridesDir = ".manque/prompts"
+
+// StyleGuidePlaceholder marks where a review prompt template gets the style guide rules.
+// Templates without it have the rules appended.
+const StyleGuidePlaceholder = "{{STYLE_GUIDE}}"
+
+// PromptOverrides replace the built-in system prompts with templates from the repository.
+// Empty fields keep the built-in prompt.
+type PromptOverrides struct {
+	Review  string // .manque/prompts/review.md
+	Summary string // .manque/prompts/summary.md
+}
+
+// LoadPromptOverrides reads the prompt templates under repoRoot. It returns nil when there
+// are none, and an error when a template doesn't ask for JSON output, since the responses
+// are parsed as JSON.
+func LoadPromptOverrides(repoRoot string) (*PromptOverrides, error) {
+	dir := filepath.Join(repoRoot, PromptOverridesDir)
+	review, err := readPromptTemplate(filepath.Join(dir, "review.md"))
+	if err != nil {
+		return nil, err
+	}
+	summary, err := readPromptTemplate(filepath.Join(dir, "summary.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	if review == "" && summary == "" {
+		return nil, nil
+	}
+	return &PromptOverrides{Review: review, Summary: summary}, nil
+}
+
+// readPromptTemplate returns a template's content, or "" if the file doesn't exist
+func readPromptTemplate(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	prompt := strings.TrimSpace(string(data))
+	if prompt == "" {
+		return "", nil
+	}
+	if !strings.Contains(strings.ToLower(prompt), "json") {
+		return "", fmt.Errorf("prompt template %s must instruct the model to return only JSON", path)
+	}
+	return prompt, nil
+}
+
+// summaryTemplate returns the summary prompt template, the built-in one unless overridden
+func (o *PromptOverrides) summaryTemplate() string {
+	if o == nil || o.Summary == "" {
+		return GetPRSummaryPrompt()
+	}
+	return o.Summary
+}
+
+// reviewTemplate returns the code review prompt template with the style guide, the built-in
+// one unless overridden
+func (o *PromptOverrides) reviewTemplate(styleGuide string) string {
+	if o == nil || o.Review == "" {
+		return GetCodeReviewPromptWithStyleGuide(styleGuide)
+	}
+	if strings.Contains(o.Review, StyleGuidePlaceholder) {
+		return strings.ReplaceAll(o.Review, StyleGuidePlaceholder, styleGuide)
+	}
+	if styleGuide == "" {
+		return o.Review
+	}
+	return o.Review + "\n\nAdditional project-specific rules to consider during review:\n\n" + styleGuide
+}
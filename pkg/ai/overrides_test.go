@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePromptTemplate writes a template under root's .manque/prompts
+func writePromptTemplate(t *testing.T, root, name, content string) {
+	t.Helper()
+	dir := filepath.Join(root, PromptOverridesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create prompts dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+}
+
+func TestLoadPromptOverrides(t *testing.T) {
+	root := t.TempDir()
+	if overrides, err := LoadPromptOverrides(root); err != nil || overrides != nil {
+		t.Fatalf("Expected no overrides without templates, got %+v, %v", overrides, err)
+	}
+
+	writePromptTemplate(t, root, "review.md", "You are a terse reviewer.\n{{STYLE_GUIDE}}\nReturn ONLY valid JSON.\n")
+	overrides, err := LoadPromptOverrides(root)
+	if err != nil {
+		t.Fatalf("LoadPromptOverrides failed: %v", err)
+	}
+	if overrides.Review != "You are a terse reviewer.\n{{STYLE_GUIDE}}\nReturn ONLY valid JSON." || overrides.Summary != "" {
+		t.Errorf("Expected only the review template, got %+v", overrides)
+	}
+
+	writePromptTemplate(t, root, "summary.md", "Summarize the PR as json.")
+	if overrides, err = LoadPromptOverrides(root); err != nil || overrides.Summary != "Summarize the PR as json." {
+		t.Errorf("Expected the summary template, got %+v, %v", overrides, err)
+	}
+}
+
+func TestLoadPromptOverrides_RequiresJSONInstruction(t *testing.T) {
+	root := t.TempDir()
+	writePromptTemplate(t, root, "summary.md", "Summarize the PR in a friendly paragraph.")
+
+	if _, err := LoadPromptOverrides(root); err == nil || !strings.Contains(err.Error(), "summary.md") {
+		t.Errorf("Expected a template without a JSON instruction to be rejected, got %v", err)
+	}
+}
+
+func TestCodeReviewSystemPrompt_Override(t *testing.T) {
+	overrides := &PromptOverrides{Review: "Review strictly.\n{{STYLE_GUIDE}}\nLanguage: English.\nReturn ONLY JSON."}
+
+	prompt := codeReviewSystemPrompt("Use tabs.", "es", map[string]string{"security": "firm"}, overrides)
+	if !strings.HasPrefix(prompt, "Review strictly.\nUse tabs.\n") || strings.Contains(prompt, StyleGuidePlaceholder) {
+		t.Errorf("Expected the style guide in place of the placeholder, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "Language: Spanish") || !strings.Contains(prompt, "- security: firm") {
+		t.Errorf("Expected language and label tones applied to the template, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Senior Staff Software Engineer") {
+		t.Error("Expected the built-in prompt to be replaced")
+	}
+
+	// Without the placeholder the rules are appended
+	overrides.Review = "Review strictly. Return ONLY JSON."
+	if prompt := codeReviewSystemPrompt("Use tabs.", "en", nil, overrides); !strings.HasSuffix(prompt, "Use tabs.") {
+		t.Errorf("Expected the style guide appended, got:\n%s", prompt)
+	}
+
+	if combined := WithCombinedOutput(overrides.Review); !strings.Contains(combined, "<combined_output>") {
+		t.Errorf("Expected combined output rules appended to a template, got:\n%s", combined)
+	}
+}
+
+func TestPromptOverrides_SentToProvider(t *testing.T) {
+	var captured map[string]interface{}
+	server := captureRequest(t, `{"choices":[{"message":{"role":"assistant","content":"{\"title\":\"t\",\"description\":\"d\"}"}}]}`, &captured)
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, PromptOverrides: &PromptOverrides{Summary: "Custom summary persona. Return JSON."}})
+	if _, err := client.GeneratePRSummary(context.Background(), "Title", "Desc", "diff"); err != nil {
+		t.Fatalf("GeneratePRSummary failed: %v", err)
+	}
+
+	messages := captured["messages"].([]interface{})
+	system := messages[0].(map[string]interface{})["content"].(string)
+	if system != "Custom summary persona. Return JSON." {
+		t.Errorf("Expected the summary template as system prompt, got %q", system)
+	}
+}
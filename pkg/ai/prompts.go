@@ -1,6 +1,30 @@
 package ai
 
-import "strings"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultReviewLanguage is the locale reviews are written in when none, or an unsupported
+// one, is configured
+const DefaultReviewLanguage = "en"
+
+// reviewLanguages maps the supported review locales to the language named in the prompts
+var reviewLanguages = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"pt": "Portuguese",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"ja": "Japanese",
+	"zh": "Chinese",
+}
+
+// languageDirective matches the output language directive of the summary and review prompts
+var languageDirective = regexp.MustCompile(`Language: English((?:, [a-z ]+)?)\.`)
 
 const prSummaryPrompt = `<system_configuration>
 <role>
@@ -110,8 +134,8 @@ Do not just check for PEP8/Linting (assume a linter does that).
 Check for architectural consistency. (e.g., "We use repository pattern here, but you injected the DB directly").
 
 Noise Filtering (Crucial):
-Before outputting a comment, assign it a "Confidence Score" (0-1).
-If Confidence < 0.8, DISCARD the comment.
+Before outputting a comment, assign it a "Confidence Score" (0-1) and report it in the "confidence" field.
+Score honestly: comments below the project's confidence threshold are discarded after the review.
 If the issue is a "nitpick" (formatting, variable name preference) and does not affect maintainability, DISCARD it.
 </analysis_strategy>
 
@@ -135,12 +159,14 @@ Return ONLY valid JSON in the following exact format:
       "content": "This fetch call lacks error handling. Consider adding proper error handling and status checking.",
       "label": "bug",
       "critical": false,
+      "confidence": 0.9,
       "suggested_code": "const result = await fetch(url).catch(err => { console.error('Fetch failed:', err); throw err; });"
     }
   ]
 }
 
 Format: JSON only - no markdown, no explanations.
+Label: one of "security", "bug", "performance", "style", "maintainability", "test", "docs".
 Language: English.
 
 CRITICAL - Suggested Code Rules:
@@ -157,10 +183,33 @@ Reasoning: Always explain WHY a change is needed (e.g., "This causes a re-render
 
 Analyze the provided Git Diff and generate actionable code review comments focusing only on high-confidence, high-impact issues.`
 
+// reviewUserPrompt is the user message sent with the summary and review prompts
+func reviewUserPrompt(prTitle, prDescription, diff string) string {
+	return fmt.Sprintf("PR Title: %s\n\nPR Description: %s\n\nGit Diff:\n%s", prTitle, prDescription, diff)
+}
+
+// codeReviewSystemPrompt builds the code review system prompt with the style guide, output
+// language and label tones, from the repository's template if overridden
+func codeReviewSystemPrompt(styleGuide, lang string, labelTones map[string]string, overrides *PromptOverrides) string {
+	return WithLabelTones(withLanguage(overrides.reviewTemplate(styleGuide), lang), labelTones)
+}
+
+// summarySystemPrompt builds the PR summary system prompt in the output language, from the
+// repository's template if overridden
+func summarySystemPrompt(lang string, overrides *PromptOverrides) string {
+	return withLanguage(overrides.summaryTemplate(), lang)
+}
+
 func GetPRSummaryPrompt() string {
 	return strings.TrimSpace(prSummaryPrompt)
 }
 
+// GetPRSummaryPromptForLanguage returns the PR summary prompt asking for output in the
+// language of lang
+func GetPRSummaryPromptForLanguage(lang string) string {
+	return withLanguage(GetPRSummaryPrompt(), lang)
+}
+
 func GetCodeReviewPrompt() string {
 	return strings.TrimSpace(codeReviewPrompt)
 }
@@ -183,3 +232,109 @@ Additional project-specific rules to consider during review:
 
 	return prompt
 }
+
+// GetCodeReviewPromptForLanguage returns the code review prompt with the style guide, asking
+// for comments in the language of lang
+func GetCodeReviewPromptForLanguage(lang, styleGuide string) string {
+	return withLanguage(GetCodeReviewPromptWithStyleGuide(styleGuide), lang)
+}
+
+// ReviewLanguageName returns the language of a locale such as "es" or "es-CL", and whether
+// it is supported
+func ReviewLanguageName(lang string) (string, bool) {
+	base := strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(base, "-_"); i != -1 {
+		base = base[:i]
+	}
+	name, ok := reviewLanguages[base]
+	return name, ok
+}
+
+// SupportedReviewLanguages lists the supported review locales
+func SupportedReviewLanguages() []string {
+	locales := make([]string, 0, len(reviewLanguages))
+	for locale := range reviewLanguages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// withLanguage swaps the English output directive of a prompt for the language of lang.
+// Labels and JSON keys stay in English since they are parsed. Unsupported locales keep English.
+func withLanguage(prompt, lang string) string {
+	name, ok := ReviewLanguageName(lang)
+	if !ok || name == "English" {
+		return prompt
+	}
+	directive := fmt.Sprintf("Language: %s${1}. Write every title, description, summary, header and comment in %s; "+
+		"keep code, identifiers, labels and JSON keys unchanged.", name, name)
+	return languageDirective.ReplaceAllString(prompt, directive)
+}
+
+// combinedOutputRules extends the code review prompt to also summarize the PR, replacing
+// its output format with one JSON object holding both results
+const combinedOutputRules = `<combined_output>
+In the same response, also summarize the PR: classify the change (FEATURE, BUG, ENHANCEMENT, REFACTOR, TEST, DOCS, SECURITY, CHORE),
+describe what it accomplishes and why, and summarize each file's purpose. Focus on value, not implementation details.
+
+This replaces the output format above. Return ONLY valid JSON in the following exact format:
+
+{
+  "summary": {
+    "title": "Brief descriptive title (max 10 words)",
+    "description": "Clear description of what this PR accomplishes and why",
+    "type": ["PRIMARY_TYPE", "SECONDARY_TYPE"],
+    "files": [
+      {
+        "filename": "path/to/file.ext",
+        "summary": "What changed in this file and why (max 70 words)",
+        "title": "Brief change description (5-10 words)"
+      }
+    ]
+  },
+  "code_review": {
+    "review": { ...review object as described above... },
+    "comments": [ ...comment objects as described above... ]
+  }
+}
+</combined_output>`
+
+// WithCombinedOutput extends a code review prompt to return the PR summary and review together
+func WithCombinedOutput(prompt string) string {
+	return insertSection(prompt, combinedOutputRules)
+}
+
+// WithLabelTones appends a per-label tone directive to a code review prompt, so the model
+// adjusts phrasing by label (e.g. firm for security, light for nitpicks)
+func WithLabelTones(prompt string, tones map[string]string) string {
+	if len(tones) == 0 {
+		return prompt
+	}
+
+	labels := make([]string, 0, len(tones))
+	for label := range tones {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var directive strings.Builder
+	directive.WriteString("\n\n<label_tone>\n")
+	directive.WriteString("Adjust the phrasing of each comment to the tone configured for its label:\n")
+	for _, label := range labels {
+		directive.WriteString(fmt.Sprintf("- %s: %s\n", label, tones[label]))
+	}
+	directive.WriteString("Assign the label based on the issue first, then write the comment in that label's tone. Never change a label to soften or harden the tone.\n")
+	directive.WriteString("</label_tone>")
+
+	return insertSection(prompt, directive.String())
+}
+
+// insertSection adds a section before the closing </system_configuration> tag of a prompt,
+// or at its end for prompts without one, like repository templates
+func insertSection(prompt, section string) string {
+	if !strings.Contains(prompt, "</system_configuration>") {
+		return prompt + "\n\n" + strings.TrimLeft(section, "\n")
+	}
+	return strings.Replace(prompt, "</system_configuration>", section+"\n</system_configuration>", 1)
+}
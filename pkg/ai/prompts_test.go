@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithLabelTones(t *testing.T) {
+	prompt := WithLabelTones(GetCodeReviewPrompt(), map[string]string{
+		"security": "authoritative",
+		"nitpick":  "casual",
+	})
+
+	if !strings.Contains(prompt, "<label_tone>") {
+		t.Fatal("Expected label tone directive in prompt")
+	}
+	if !strings.Contains(prompt, "- security: authoritative") || !strings.Contains(prompt, "- nitpick: casual") {
+		t.Errorf("Expected configured tones in directive, got:\n%s", prompt)
+	}
+	if strings.Index(prompt, "- nitpick") > strings.Index(prompt, "- security") {
+		t.Error("Expected labels in sorted order")
+	}
+	if !strings.HasSuffix(strings.Split(prompt, "</system_configuration>")[0], "</label_tone>\n") {
+		t.Error("Expected directive inserted before </system_configuration>")
+	}
+}
+
+func TestWithLabelTones_Empty(t *testing.T) {
+	prompt := GetCodeReviewPrompt()
+	if WithLabelTones(prompt, nil) != prompt {
+		t.Error("Expected prompt unchanged when no tones are configured")
+	}
+}
+
+func TestCodeReviewPromptIncludesTones(t *testing.T) {
+	client := NewOpenAIClient(Config{APIKey: "key", LabelTones: map[string]string{"security": "firm"}})
+
+	prompt := client.codeReviewPrompt("Use tabs")
+	if !strings.Contains(prompt, "<custom_style_guide>") || !strings.Contains(prompt, "- security: firm") {
+		t.Errorf("Expected style guide and label tone in prompt, got:\n%s", prompt)
+	}
+}
+
+func TestGetCodeReviewPromptForLanguage(t *testing.T) {
+	prompt := GetCodeReviewPromptForLanguage("es-CL", "Use tabs")
+	if !strings.Contains(prompt, "Language: Spanish.") || strings.Contains(prompt, "Language: English") {
+		t.Errorf("Expected Spanish language directive, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "<custom_style_guide>") {
+		t.Error("Expected style guide to be kept")
+	}
+
+	summary := GetPRSummaryPromptForLanguage("pt")
+	if !strings.Contains(summary, "Language: Portuguese, professional tone.") {
+		t.Errorf("Expected Portuguese summary directive, got:\n%s", summary)
+	}
+}
+
+func TestGetCodeReviewPromptForLanguage_FallsBackToEnglish(t *testing.T) {
+	for _, lang := range []string{"", "en", "klingon"} {
+		if got := GetCodeReviewPromptForLanguage(lang, ""); got != GetCodeReviewPrompt() {
+			t.Errorf("Expected English prompt for %q", lang)
+		}
+	}
+}
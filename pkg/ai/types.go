@@ -1,5 +1,11 @@
 package ai
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
 type PRSummary struct {
 	Title       string   `json:"title"` // Max 10 words
 	Description string   `json:"description"`
@@ -9,11 +15,36 @@ type PRSummary struct {
 		Summary  string `json:"summary"` // Max 70 words
 		Title    string `json:"title"`   // 5-10 words
 	} `json:"files"`
+
+	// TokensIn and TokensOut are the prompt and completion tokens of the request, as reported
+	// by the provider. They are not parsed from the LLM output.
+	TokensIn  int `json:"-"`
+	TokensOut int `json:"-"`
+
+	// APIChanges lists the exported symbols added, removed or modified per Go file, rendered
+	// for the walkthrough. They come from parsing both revisions, not from the LLM output.
+	APIChanges map[string]string `json:"-"`
 }
 
 type ReviewResult struct {
 	Review   ReviewSummary `json:"review"`
 	Comments []Comment     `json:"comments"`
+
+	// Chunks and FailedChunks count the per-chunk review requests behind an aggregated
+	// review. They are set by the engine, not parsed from the LLM output.
+	Chunks       int `json:"-"`
+	FailedChunks int `json:"-"`
+
+	// SkippedFiles were left out of the review to keep the diff within its size limit
+	SkippedFiles []string `json:"-"`
+
+	// UntestedFiles are changed source files whose tests weren't changed, set by the engine
+	UntestedFiles []string `json:"-"`
+
+	// TokensIn and TokensOut are the prompt and completion tokens reported by the provider.
+	// The engine sums them over the summary and chunk review requests.
+	TokensIn  int `json:"-"`
+	TokensOut int `json:"-"`
 }
 
 type ReviewSummary struct {
@@ -57,16 +88,34 @@ func (r *ReviewResult) GetReviewAction(autoApproveThreshold int, blockOnCritical
 	return ReviewActionComment
 }
 
+// WithoutApproval downgrades an approval to a comment, for orgs that forbid bots from approving PRs
+func (a ReviewAction) WithoutApproval() ReviewAction {
+	if a == ReviewActionApprove {
+		return ReviewActionComment
+	}
+	return a
+}
+
 type Comment struct {
-	File            string `json:"file"`
-	StartLine       int    `json:"start_line"`
-	EndLine         int    `json:"end_line"`
-	HighlightedCode string `json:"highlighted_code"`
-	Header          string `json:"header"`
-	Content         string `json:"content"`
-	Label           string `json:"label"` // e.g. "bug", "security"
-	Critical        bool   `json:"critical"`
-	SuggestedCode   string `json:"suggested_code,omitempty"` // GitHub suggestion block content
+	File            string  `json:"file"`
+	StartLine       int     `json:"start_line"`
+	EndLine         int     `json:"end_line"`
+	HighlightedCode string  `json:"highlighted_code"`
+	Header          string  `json:"header"`
+	Content         string  `json:"content"`
+	Label           string  `json:"label"` // e.g. "bug", "security"; see Category
+	Critical        bool    `json:"critical"`
+	Confidence      float64 `json:"confidence,omitempty"`     // 0-1, as reported by the LLM
+	SuggestedCode   string  `json:"suggested_code,omitempty"` // GitHub suggestion block content
+}
+
+// ConfidenceScore returns how confident the LLM is in the comment, 0-1. Comments without a
+// confidence, from older prompts or deterministic checks, count as fully confident.
+func (c Comment) ConfidenceScore() float64 {
+	if c.Confidence <= 0 {
+		return 1
+	}
+	return c.Confidence
 }
 
 type ChatMessage struct {
@@ -75,17 +124,55 @@ type ChatMessage struct {
 }
 
 type Client interface {
-	GeneratePRSummary(prTitle, prDescription, diff string) (*PRSummary, error)
-	GenerateCodeReview(prTitle, prDescription, diff string) (*ReviewResult, error)
-	GenerateCodeReviewWithStyleGuide(prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error)
-	GenerateResponse(prompt string) (string, error) // For conversational responses
+	GeneratePRSummary(ctx context.Context, prTitle, prDescription, diff string) (*PRSummary, error)
+	GenerateCodeReview(ctx context.Context, prTitle, prDescription, diff string) (*ReviewResult, error)
+	GenerateCodeReviewWithStyleGuide(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*ReviewResult, error)
+	GenerateResponse(ctx context.Context, prompt string) (string, error) // For conversational responses
+}
+
+// CombinedReviewer is implemented by clients that can return the PR summary and code review
+// from a single request, halving latency and cost for small PRs
+type CombinedReviewer interface {
+	GenerateCombinedReview(ctx context.Context, prTitle, prDescription, diff, styleGuide string) (*PRSummary, *ReviewResult, error)
+}
+
+// CombinedResponse is the JSON returned by a combined summary and review request
+type CombinedResponse struct {
+	Summary    *PRSummary    `json:"summary"`
+	CodeReview *ReviewResult `json:"code_review"`
+}
+
+// ParseCombinedResponse parses and validates a combined summary and review response
+func ParseCombinedResponse(content string) (*PRSummary, *ReviewResult, error) {
+	var response CombinedResponse
+	if err := json.Unmarshal([]byte(extractJSONFromResponse(content)), &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse combined review JSON: %w: %w", ErrInvalidJSON, err)
+	}
+
+	if response.Summary == nil || (response.Summary.Title == "" && response.Summary.Description == "") {
+		return nil, nil, fmt.Errorf("combined review response is missing the summary")
+	}
+	if response.CodeReview == nil {
+		return nil, nil, fmt.Errorf("combined review response is missing the code review")
+	}
+	if score := response.CodeReview.Review.Score; score < 0 || score > 100 {
+		return nil, nil, fmt.Errorf("combined review response has invalid score: %d", score)
+	}
+
+	return response.Summary, response.CodeReview, nil
 }
 
 type ChatCompletionRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	Temperature *float64      `json:"temperature,omitempty"`
-	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	Model          string          `json:"model"`
+	Messages       []ChatMessage   `json:"messages"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	MaxTokens      *int            `json:"max_tokens,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat asks OpenAI-compatible APIs for a specific output format
+type ResponseFormat struct {
+	Type string `json:"type"` // "json_object" guarantees valid JSON output
 }
 
 type ChatCompletionResponse struct {
@@ -96,9 +183,21 @@ type ChatCompletionResponse struct {
 		} `json:"message"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 		Code    string `json:"code"`
 	} `json:"error,omitempty"`
 }
+
+// tokens returns the prompt and completion tokens of the response, zero if not reported
+func (r *ChatCompletionResponse) tokens() (int, int) {
+	if r.Usage == nil {
+		return 0, 0
+	}
+	return r.Usage.PromptTokens, r.Usage.CompletionTokens
+}
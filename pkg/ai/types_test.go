@@ -81,3 +81,53 @@ func TestGetReviewAction(t *testing.T) {
 		})
 	}
 }
+
+func TestReviewActionWithoutApproval(t *testing.T) {
+	tests := map[ReviewAction]ReviewAction{
+		ReviewActionApprove:        ReviewActionComment,
+		ReviewActionComment:        ReviewActionComment,
+		ReviewActionRequestChanges: ReviewActionRequestChanges,
+	}
+
+	for action, expected := range tests {
+		if got := action.WithoutApproval(); got != expected {
+			t.Errorf("%s.WithoutApproval() = %s, want %s", action, got, expected)
+		}
+	}
+}
+
+func TestParseCombinedResponse(t *testing.T) {
+	content := "```json\n" + `{
+  "summary": {"title": "Add retries", "description": "Retries failed uploads", "type": ["ENHANCEMENT"], "files": []},
+  "code_review": {
+    "review": {"estimated_effort_to_review": 2, "score": 88, "has_relevant_tests": true, "security_concerns": "None"},
+    "comments": [{"file": "upload.go", "start_line": 3, "end_line": 4, "header": "Unbounded retries", "content": "Add a limit", "label": "bug"}]
+  }
+}` + "\n```"
+
+	summary, review, err := ParseCombinedResponse(content)
+	if err != nil {
+		t.Fatalf("ParseCombinedResponse failed: %v", err)
+	}
+	if summary.Title != "Add retries" {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if review.Review.Score != 88 || len(review.Comments) != 1 || review.Comments[0].File != "upload.go" {
+		t.Errorf("Unexpected review: %+v", review)
+	}
+}
+
+func TestParseCombinedResponse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":        "sorry, I cannot help",
+		"missing summary": `{"code_review": {"review": {"score": 80}, "comments": []}}`,
+		"missing review":  `{"summary": {"title": "Title", "description": "Desc"}}`,
+		"invalid score":   `{"summary": {"title": "Title"}, "code_review": {"review": {"score": 180}, "comments": []}}`,
+	}
+
+	for name, content := range tests {
+		if _, _, err := ParseCombinedResponse(content); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
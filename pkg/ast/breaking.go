@@ -47,87 +47,87 @@ type BreakingChangeReport struct {
 // BreakingChangeDetector detects breaking API changes
 type BreakingChangeDetector struct {
 	parser *Parser
+
+	// KeywordArgLanguages lists languages whose callers may pass arguments by name.
+	// Parameters for these languages are compared as a set keyed by name, so a
+	// reorder is not reported as a change.
+	KeywordArgLanguages map[Language]bool
 }
 
 // NewBreakingChangeDetector creates a new breaking change detector
 func NewBreakingChangeDetector() *BreakingChangeDetector {
 	return &BreakingChangeDetector{
 		parser: NewParser(),
+		KeywordArgLanguages: map[Language]bool{
+			LangPython: true,
+		},
 	}
 }
 
 // DetectBreakingChanges compares old and new code to find breaking changes
 func (d *BreakingChangeDetector) DetectBreakingChanges(oldContent, newContent, filename string) (*BreakingChangeReport, error) {
-	// Parse both versions
-	oldSymbols, err := d.parser.ParseFile(filename, oldContent)
+	changes, err := d.parser.ChangedSymbols(oldContent, newContent, filename)
 	if err != nil {
-		oldSymbols = []Symbol{} // Might be a new file
+		return nil, err
 	}
 
-	newSymbols, err := d.parser.ParseFile(filename, newContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse new content: %w", err)
-	}
+	return d.DetectFromChanges(changes), nil
+}
 
+// DetectFromChanges finds breaking changes in already computed symbol changes,
+// allowing callers that also run impact analysis to parse each file only once
+func (d *BreakingChangeDetector) DetectFromChanges(changes *SymbolChanges) *BreakingChangeReport {
+	filename := changes.FileName
 	report := &BreakingChangeReport{
 		FileName: filename,
 		Changes:  []BreakingChange{},
 	}
 
-	// Build maps for comparison
-	oldMap := d.buildSymbolMap(oldSymbols)
-	newMap := d.buildSymbolMap(newSymbols)
-
 	// Check for removed symbols
-	for key, oldSym := range oldMap {
-		if _, exists := newMap[key]; !exists {
-			// Only flag exported/public symbols as breaking
-			if oldSym.Exported {
-				// Check if there's a renamed version (same name but different case)
-				foundRenamed := false
-				for _, newSym := range newSymbols {
-					if strings.EqualFold(oldSym.Name, newSym.Name) && oldSym.Kind == newSym.Kind && oldSym.Name != newSym.Name {
-						// This is a visibility change (e.g., GetUser -> getUser)
-						foundRenamed = true
-						change := BreakingChange{
-							Type:        BreakingVisibilityChange,
-							Symbol:      newSym,
-							OldValue:    "exported",
-							NewValue:    "unexported",
-							FilePath:    filename,
-							Line:        newSym.StartLine,
-							Severity:    "critical",
-							Description: fmt.Sprintf("%s '%s' changed from exported to unexported (renamed to '%s')", oldSym.Kind, oldSym.Name, newSym.Name),
-							Suggestion:  "This breaks all external consumers. Consider keeping it exported or deprecating first",
-						}
-						report.Changes = append(report.Changes, change)
-						break
-					}
-				}
-
-				if !foundRenamed {
+	for _, oldSym := range changes.Removed {
+		// Only flag exported/public symbols as breaking
+		if oldSym.Exported {
+			// Check if there's a renamed version (same name but different case)
+			foundRenamed := false
+			for _, newSym := range changes.NewSymbols {
+				if strings.EqualFold(oldSym.Name, newSym.Name) && oldSym.Kind == newSym.Kind && oldSym.Name != newSym.Name {
+					// This is a visibility change (e.g., GetUser -> getUser)
+					foundRenamed = true
 					change := BreakingChange{
-						Type:        BreakingRemoval,
-						Symbol:      oldSym,
-						OldValue:    oldSym.Signature,
+						Type:        BreakingVisibilityChange,
+						Symbol:      newSym,
+						OldValue:    "exported",
+						NewValue:    "unexported",
 						FilePath:    filename,
-						Line:        oldSym.StartLine,
+						Line:        newSym.StartLine,
 						Severity:    "critical",
-						Description: fmt.Sprintf("Exported %s '%s' was removed", oldSym.Kind, oldSym.Name),
-						Suggestion:  "If this removal is intentional, consider deprecating first or updating documentation",
+						Description: fmt.Sprintf("%s '%s' changed from exported to unexported (renamed to '%s')", oldSym.Kind, oldSym.Name, newSym.Name),
+						Suggestion:  "This breaks all external consumers. Consider keeping it exported or deprecating first",
 					}
 					report.Changes = append(report.Changes, change)
+					break
+				}
+			}
+
+			if !foundRenamed {
+				change := BreakingChange{
+					Type:        BreakingRemoval,
+					Symbol:      oldSym,
+					OldValue:    oldSym.Signature,
+					FilePath:    filename,
+					Line:        oldSym.StartLine,
+					Severity:    "critical",
+					Description: fmt.Sprintf("Exported %s '%s' was removed", oldSym.Kind, oldSym.Name),
+					Suggestion:  "If this removal is intentional, consider deprecating first or updating documentation",
 				}
+				report.Changes = append(report.Changes, change)
 			}
 		}
 	}
 
 	// Check for modified symbols
-	for key, newSym := range newMap {
-		oldSym, existed := oldMap[key]
-		if !existed {
-			continue // New symbol, not breaking
-		}
+	for _, modified := range changes.Modified {
+		oldSym, newSym := modified.Old, modified.New
 
 		// Only check exported symbols for breaking changes
 		if !oldSym.Exported && !newSym.Exported {
@@ -173,6 +173,26 @@ func (d *BreakingChangeDetector) DetectBreakingChanges(oldContent, newContent, f
 			}
 		}
 
+		// Check for declared type changes on constants and variables
+		if oldSym.ValueType != newSym.ValueType && oldSym.ValueType != "" {
+			change := BreakingChange{
+				Type:        BreakingTypeChange,
+				Symbol:      newSym,
+				OldValue:    oldSym.ValueType,
+				NewValue:    newSym.ValueType,
+				FilePath:    filename,
+				Line:        newSym.StartLine,
+				Severity:    "error",
+				Description: fmt.Sprintf("%s '%s' type changed from '%s' to '%s'", newSym.Kind, newSym.Name, oldSym.ValueType, newSym.ValueType),
+				Suggestion:  "Callers relying on the declared type may no longer compile",
+			}
+			if newSym.ValueType == "" {
+				change.Description = fmt.Sprintf("%s '%s' lost its declared type '%s'", newSym.Kind, newSym.Name, oldSym.ValueType)
+				change.Suggestion = "Untyped values lose the methods and type checks of the declared type; consider keeping it"
+			}
+			report.Changes = append(report.Changes, change)
+		}
+
 		// Check for signature changes
 		if oldSym.Signature != newSym.Signature && oldSym.Signature != "" && newSym.Signature != "" {
 			// Only flag if not already covered by parameter/return changes
@@ -220,22 +240,15 @@ func (d *BreakingChangeDetector) DetectBreakingChanges(oldContent, newContent, f
 	report.HasBreaking = report.CriticalCount > 0 || report.ErrorCount > 0
 	report.Summary = d.generateSummary(report)
 
-	return report, nil
-}
-
-// buildSymbolMap creates a map of symbols by their unique key
-func (d *BreakingChangeDetector) buildSymbolMap(symbols []Symbol) map[string]Symbol {
-	result := make(map[string]Symbol)
-	for _, sym := range symbols {
-		// Key includes name, kind, and parent to distinguish overloaded methods
-		key := fmt.Sprintf("%s:%s:%s", sym.Name, sym.Kind, sym.Parent)
-		result[key] = sym
-	}
-	return result
+	return report
 }
 
 // detectParameterChanges detects changes in function parameters
 func (d *BreakingChangeDetector) detectParameterChanges(oldSym, newSym Symbol) []BreakingChange {
+	if d.KeywordArgLanguages[DetectLanguage(newSym.FilePath)] {
+		return d.detectNamedParameterChanges(oldSym, newSym)
+	}
+
 	var changes []BreakingChange
 
 	oldParams := oldSym.Parameters
@@ -302,6 +315,173 @@ func (d *BreakingChangeDetector) detectParameterChanges(oldSym, newSym Symbol) [
 	return changes
 }
 
+// namedParameter is a parameter decomposed into the parts relevant for by-name comparison
+type namedParameter struct {
+	name       string
+	typ        string
+	hasDefault bool
+}
+
+// parseNamedParameter splits a parameter like "name: str = 'x'" into name, type and default presence
+func parseNamedParameter(param string) namedParameter {
+	p := namedParameter{}
+
+	decl := param
+	if idx := strings.Index(decl, "="); idx != -1 {
+		p.hasDefault = true
+		decl = decl[:idx]
+	}
+	if idx := strings.Index(decl, ":"); idx != -1 {
+		p.typ = strings.TrimSpace(decl[idx+1:])
+		decl = decl[:idx]
+	}
+	p.name = strings.TrimSpace(decl)
+
+	return p
+}
+
+// splitPositionalOnly splits parameters at Python's "/" marker into those that can only be
+// passed by position and the rest. Without a marker every parameter can be passed by name.
+func splitPositionalOnly(params []string) (positional, named []string) {
+	for i, param := range params {
+		if param == "/" {
+			return params[:i], params[i+1:]
+		}
+	}
+	return nil, params
+}
+
+// detectPositionalOnlyChanges compares parameters callers can't pass by name. Their names
+// don't matter, so only their count and types are compared.
+func (d *BreakingChangeDetector) detectPositionalOnlyChanges(newSym Symbol, oldParams, newParams []string) []BreakingChange {
+	var changes []BreakingChange
+	for i, raw := range newParams {
+		p := parseNamedParameter(raw)
+		if i >= len(oldParams) {
+			if p.hasDefault {
+				continue
+			}
+			changes = append(changes, BreakingChange{
+				Type:        BreakingRequiredParameter,
+				Symbol:      newSym,
+				NewValue:    p.name,
+				FilePath:    newSym.FilePath,
+				Line:        newSym.StartLine,
+				Severity:    "error",
+				Description: fmt.Sprintf("%s '%s' added required positional parameter '%s'", newSym.Kind, newSym.Name, p.name),
+				Suggestion:  "Consider giving the new parameter a default value",
+			})
+			continue
+		}
+
+		oldParam := parseNamedParameter(oldParams[i])
+		if oldParam.typ != p.typ && oldParam.typ != "" && p.typ != "" {
+			changes = append(changes, BreakingChange{
+				Type:        BreakingParameterChange,
+				Symbol:      newSym,
+				OldValue:    oldParam.typ,
+				NewValue:    p.typ,
+				FilePath:    newSym.FilePath,
+				Line:        newSym.StartLine,
+				Severity:    "error",
+				Description: fmt.Sprintf("%s '%s' positional parameter %d type changed from '%s' to '%s'", newSym.Kind, newSym.Name, i+1, oldParam.typ, p.typ),
+				Suggestion:  "Consider if this change is backward compatible",
+			})
+		}
+	}
+
+	if len(newParams) < len(oldParams) {
+		changes = append(changes, BreakingChange{
+			Type:        BreakingParameterChange,
+			Symbol:      newSym,
+			OldValue:    fmt.Sprintf("%d positional parameters", len(oldParams)),
+			NewValue:    fmt.Sprintf("%d positional parameters", len(newParams)),
+			FilePath:    newSym.FilePath,
+			Line:        newSym.StartLine,
+			Severity:    "warning",
+			Description: fmt.Sprintf("%s '%s' removed %d positional parameter(s)", newSym.Kind, newSym.Name, len(oldParams)-len(newParams)),
+			Suggestion:  "Verify callers don't rely on removed parameters",
+		})
+	}
+	return changes
+}
+
+// detectNamedParameterChanges compares parameters by name rather than position,
+// so only genuine additions, removals, and type changes are reported. Python's
+// positional-only parameters, before "/", are still compared by position.
+func (d *BreakingChangeDetector) detectNamedParameterChanges(oldSym, newSym Symbol) []BreakingChange {
+	oldPositional, oldNamed := splitPositionalOnly(oldSym.Parameters)
+	newPositional, newNamed := splitPositionalOnly(newSym.Parameters)
+	changes := d.detectPositionalOnlyChanges(newSym, oldPositional, newPositional)
+
+	oldParams := make(map[string]namedParameter)
+	for _, raw := range oldNamed {
+		p := parseNamedParameter(raw)
+		oldParams[p.name] = p
+	}
+
+	var newOrder []namedParameter
+	newParams := make(map[string]namedParameter)
+	for _, raw := range newNamed {
+		p := parseNamedParameter(raw)
+		newParams[p.name] = p
+		newOrder = append(newOrder, p)
+	}
+
+	for _, p := range newOrder {
+		oldParam, existed := oldParams[p.name]
+		if !existed {
+			if p.hasDefault {
+				continue // Optional parameter, callers are unaffected
+			}
+			changes = append(changes, BreakingChange{
+				Type:        BreakingRequiredParameter,
+				Symbol:      newSym,
+				NewValue:    p.name,
+				FilePath:    newSym.FilePath,
+				Line:        newSym.StartLine,
+				Severity:    "error",
+				Description: fmt.Sprintf("%s '%s' added required parameter '%s'", newSym.Kind, newSym.Name, p.name),
+				Suggestion:  "Consider giving the new parameter a default value",
+			})
+			continue
+		}
+
+		if oldParam.typ != p.typ && oldParam.typ != "" && p.typ != "" {
+			changes = append(changes, BreakingChange{
+				Type:        BreakingParameterChange,
+				Symbol:      newSym,
+				OldValue:    oldParam.typ,
+				NewValue:    p.typ,
+				FilePath:    newSym.FilePath,
+				Line:        newSym.StartLine,
+				Severity:    "error",
+				Description: fmt.Sprintf("%s '%s' parameter '%s' type changed from '%s' to '%s'", newSym.Kind, newSym.Name, p.name, oldParam.typ, p.typ),
+				Suggestion:  "Consider if this change is backward compatible",
+			})
+		}
+	}
+
+	for _, raw := range oldNamed {
+		p := parseNamedParameter(raw)
+		if _, exists := newParams[p.name]; exists {
+			continue
+		}
+		changes = append(changes, BreakingChange{
+			Type:        BreakingParameterChange,
+			Symbol:      newSym,
+			OldValue:    p.name,
+			FilePath:    newSym.FilePath,
+			Line:        newSym.StartLine,
+			Severity:    "warning",
+			Description: fmt.Sprintf("%s '%s' removed parameter '%s'", newSym.Kind, newSym.Name, p.name),
+			Suggestion:  "Verify callers don't pass the removed parameter by name",
+		})
+	}
+
+	return changes
+}
+
 // generateSummary creates a human-readable summary of the report
 func (d *BreakingChangeDetector) generateSummary(report *BreakingChangeReport) string {
 	if report.TotalChanges == 0 {
@@ -322,9 +502,10 @@ func (d *BreakingChangeDetector) generateSummary(report *BreakingChangeReport) s
 	return fmt.Sprintf("Found %d breaking changes: %s", report.TotalChanges, strings.Join(parts, ", "))
 }
 
-// FormatBreakingChangeReport generates a formatted report for PR comments
+// FormatBreakingChangeReport generates a formatted report for PR comments.
+// It keeps no shared state and is safe to call from multiple goroutines.
 func FormatBreakingChangeReport(report *BreakingChangeReport) string {
-	if !report.HasBreaking && report.WarningCount == 0 {
+	if report == nil || (!report.HasBreaking && report.WarningCount == 0) {
 		return ""
 	}
 
@@ -338,7 +519,7 @@ func FormatBreakingChangeReport(report *BreakingChangeReport) string {
 		sb.WriteString("### 🔴 Critical Breaking Changes\n\n")
 		for _, c := range report.Changes {
 			if c.Severity == "critical" {
-				sb.WriteString(d.formatChange(c))
+				sb.WriteString(formatBreakingChange(c))
 			}
 		}
 	}
@@ -347,7 +528,7 @@ func FormatBreakingChangeReport(report *BreakingChangeReport) string {
 		sb.WriteString("### 🟠 Error-Level Breaking Changes\n\n")
 		for _, c := range report.Changes {
 			if c.Severity == "error" {
-				sb.WriteString(d.formatChange(c))
+				sb.WriteString(formatBreakingChange(c))
 			}
 		}
 	}
@@ -356,7 +537,7 @@ func FormatBreakingChangeReport(report *BreakingChangeReport) string {
 		sb.WriteString("### 🟡 Warnings\n\n")
 		for _, c := range report.Changes {
 			if c.Severity == "warning" {
-				sb.WriteString(d.formatChange(c))
+				sb.WriteString(formatBreakingChange(c))
 			}
 		}
 	}
@@ -364,12 +545,8 @@ func FormatBreakingChangeReport(report *BreakingChangeReport) string {
 	return sb.String()
 }
 
-// formatChange helper variable to use in FormatBreakingChangeReport
-var d = &changeFormatter{}
-
-type changeFormatter struct{}
-
-func (cf *changeFormatter) formatChange(c BreakingChange) string {
+// formatBreakingChange formats a single breaking change as a markdown entry
+func formatBreakingChange(c BreakingChange) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("**%s** `%s` (line %d)\n", c.Type, c.Symbol.Name, c.Line))
 	sb.WriteString(fmt.Sprintf("- %s\n", c.Description))
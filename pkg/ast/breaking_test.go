@@ -2,6 +2,7 @@ package ast
 
 import (
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -49,6 +50,89 @@ func GetUser(id int) *User {
 	}
 }
 
+func TestDetectBreakingChangesInterfaceMethodRemoval(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `package store
+
+type Store interface {
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+type cache interface {
+	Evict(key string)
+}
+`
+
+	newCode := `package store
+
+type Store interface {
+	Get(key string) ([]byte, error)
+}
+
+type cache interface {
+}
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "store.go")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	if !report.HasBreaking || len(report.Changes) != 1 {
+		t.Fatalf("Expected one breaking change, got %+v", report.Changes)
+	}
+	c := report.Changes[0]
+	if c.Type != BreakingRemoval || c.Symbol.Name != "Delete" || c.Symbol.Parent != "Store" {
+		t.Errorf("Expected removal of Store.Delete, got %+v", c)
+	}
+	if c.Severity != "critical" {
+		t.Errorf("Expected critical severity for removed interface method, got %s", c.Severity)
+	}
+}
+
+func TestDetectBreakingChangesStructFieldChanges(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `package api
+
+type Options struct {
+	Timeout int
+	Retries int
+	debug   bool
+}
+`
+
+	newCode := `package api
+
+type Options struct {
+	Timeout string
+	verbose bool
+}
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "options.go")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	var removed, retyped bool
+	for _, c := range report.Changes {
+		switch {
+		case c.Type == BreakingRemoval && c.Symbol.Name == "Retries":
+			removed = true
+		case c.Type == BreakingTypeChange && c.Symbol.Name == "Timeout":
+			retyped = true
+		default:
+			t.Errorf("Unexpected change: %+v", c)
+		}
+	}
+	if !removed || !retyped {
+		t.Errorf("Expected Retries removal and Timeout type change, got %+v", report.Changes)
+	}
+}
+
 func TestDetectBreakingChangesUnexportedRemoval(t *testing.T) {
 	detector := NewBreakingChangeDetector()
 
@@ -190,6 +274,179 @@ func GetUser(id string) *User {
 	}
 }
 
+func TestDetectBreakingChangesReorderedPythonKwargs(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `def create_user(name: str, email: str, admin: bool = False):
+    pass
+`
+
+	newCode := `def create_user(email: str, name: str, admin: bool = False):
+    pass
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "users.py")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	if len(report.Changes) != 0 {
+		t.Errorf("Expected no changes for reordered keyword parameters, got %+v", report.Changes)
+	}
+
+	// Positional comparison should still flag the reorder when opted out
+	detector.KeywordArgLanguages = map[Language]bool{}
+	report, err = detector.DetectBreakingChanges(oldCode, newCode, "users.py")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+	if !report.HasBreaking {
+		t.Error("Expected positional comparison to flag reordered parameters")
+	}
+}
+
+func TestDetectBreakingChangesPythonNamedParameters(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `def create_user(name: str, legacy_id: int):
+    pass
+`
+
+	newCode := `def create_user(role: str, name: bytes, notify: bool = True):
+    pass
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "users.py")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	found := map[string]BreakingChange{}
+	for _, c := range report.Changes {
+		found[string(c.Type)+":"+c.OldValue+c.NewValue] = c
+	}
+
+	if _, ok := found[string(BreakingRequiredParameter)+":role"]; !ok {
+		t.Errorf("Expected new required parameter 'role' to be flagged, got %+v", report.Changes)
+	}
+	if _, ok := found[string(BreakingRequiredParameter)+":notify"]; ok {
+		t.Error("Did not expect optional parameter 'notify' to be flagged")
+	}
+	if _, ok := found[string(BreakingParameterChange)+":strbytes"]; !ok {
+		t.Errorf("Expected type change of 'name' to be flagged, got %+v", report.Changes)
+	}
+	if c, ok := found[string(BreakingParameterChange)+":legacy_id"]; !ok {
+		t.Errorf("Expected removed parameter 'legacy_id' to be flagged, got %+v", report.Changes)
+	} else if c.Severity != "warning" {
+		t.Errorf("Expected warning severity for removed parameter, got %s", c.Severity)
+	}
+}
+
+func TestDetectBreakingChangesPythonPositionalOnlyParameters(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `def move(src: str, dst: str, /, force: bool = False):
+    pass
+`
+
+	newCode := `def move(source: str, target: str, /, *, force: bool = False):
+    pass
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "files.py")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("Expected renamed positional-only parameters not to be flagged, got %+v", report.Changes)
+	}
+
+	newCode = `def move(src: bytes, dst: str, mode: int, /, force: bool = False):
+    pass
+`
+
+	report, err = detector.DetectBreakingChanges(oldCode, newCode, "files.py")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, c := range report.Changes {
+		found[string(c.Type)+":"+c.OldValue+c.NewValue] = true
+	}
+	if !found[string(BreakingRequiredParameter)+":mode"] {
+		t.Errorf("Expected new required positional parameter 'mode' to be flagged, got %+v", report.Changes)
+	}
+	if !found[string(BreakingParameterChange)+":strbytes"] {
+		t.Errorf("Expected type change of the first positional parameter to be flagged, got %+v", report.Changes)
+	}
+}
+
+func TestDetectBreakingChangesSharedTypeConstBlock(t *testing.T) {
+	detector := NewBreakingChangeDetector()
+
+	oldCode := `package main
+
+type Status string
+
+type Level int
+
+const (
+	Active, Inactive Status = "active", "inactive"
+	Low Level = iota
+	High
+)
+`
+
+	newCode := `package main
+
+type Status string
+
+type Level int
+
+const (
+	Active, Inactive = "active", "inactive"
+	Low int = iota
+	High
+)
+`
+
+	report, err := detector.DetectBreakingChanges(oldCode, newCode, "status.go")
+	if err != nil {
+		t.Fatalf("Failed to detect changes: %v", err)
+	}
+
+	typeChanges := make(map[string]BreakingChange)
+	for _, c := range report.Changes {
+		if c.Type == BreakingTypeChange {
+			if _, dup := typeChanges[c.Symbol.Name]; dup {
+				t.Errorf("Expected one type change for %s, got several", c.Symbol.Name)
+			}
+			typeChanges[c.Symbol.Name] = c
+		}
+	}
+
+	if len(typeChanges) != 4 {
+		t.Fatalf("Expected 4 per-identifier type changes, got %d: %+v", len(typeChanges), report.Changes)
+	}
+	for _, name := range []string{"Active", "Inactive"} {
+		c := typeChanges[name]
+		if c.OldValue != "Status" || c.NewValue != "" {
+			t.Errorf("Expected %s type removed from Status, got %q -> %q", name, c.OldValue, c.NewValue)
+		}
+	}
+	// High inherits its type from the previous spec in the block
+	for _, name := range []string{"Low", "High"} {
+		c := typeChanges[name]
+		if c.OldValue != "Level" || c.NewValue != "int" {
+			t.Errorf("Expected %s type changed from Level to int, got %q -> %q", name, c.OldValue, c.NewValue)
+		}
+	}
+	if !report.HasBreaking {
+		t.Error("Expected type changes to be breaking")
+	}
+}
+
 func TestDetectBreakingChangesReturnTypeChange(t *testing.T) {
 	detector := NewBreakingChangeDetector()
 
@@ -281,8 +538,8 @@ func CreateUser(name string) *User {
 	}
 }
 
-func TestFormatBreakingChangeReport(t *testing.T) {
-	report := &BreakingChangeReport{
+func sampleBreakingChangeReport() *BreakingChangeReport {
+	return &BreakingChangeReport{
 		FileName:      "user.go",
 		TotalChanges:  2,
 		CriticalCount: 1,
@@ -308,8 +565,10 @@ func TestFormatBreakingChangeReport(t *testing.T) {
 		HasBreaking: true,
 		Summary:     "Found 2 breaking changes: 1 critical, 1 error",
 	}
+}
 
-	formatted := FormatBreakingChangeReport(report)
+func TestFormatBreakingChangeReport(t *testing.T) {
+	formatted := FormatBreakingChangeReport(sampleBreakingChangeReport())
 
 	// Check for expected sections
 	if !strings.Contains(formatted, "Breaking Change Analysis") {
@@ -326,6 +585,34 @@ func TestFormatBreakingChangeReport(t *testing.T) {
 	}
 }
 
+func TestFormatBreakingChangeReportNil(t *testing.T) {
+	if got := FormatBreakingChangeReport(nil); got != "" {
+		t.Errorf("Expected empty output for nil report, got %q", got)
+	}
+}
+
+func TestFormatBreakingChangeReportConcurrent(t *testing.T) {
+	report := sampleBreakingChangeReport()
+	expected := FormatBreakingChangeReport(report)
+
+	var wg sync.WaitGroup
+	results := make([]string, 32)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = FormatBreakingChangeReport(report)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if got != expected {
+			t.Errorf("Goroutine %d produced different output:\n%s\nwant:\n%s", i, got, expected)
+		}
+	}
+}
+
 func TestIsBreaking(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,140 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// ModifiedSymbol pairs the old and new versions of a symbol that changed
+type ModifiedSymbol struct {
+	Old Symbol `json:"old"`
+	New Symbol `json:"new"`
+}
+
+// SymbolChanges describes which symbols were added, removed, or modified in a file.
+// It is the shared input for breaking change detection and impact analysis.
+type SymbolChanges struct {
+	FileName   string           `json:"file_name"`
+	OldSymbols []Symbol         `json:"old_symbols"`
+	NewSymbols []Symbol         `json:"new_symbols"`
+	Added      []Symbol         `json:"added"`
+	Removed    []Symbol         `json:"removed"`
+	Modified   []ModifiedSymbol `json:"modified"`
+
+	oldByKey map[string]Symbol
+	newByKey map[string]Symbol
+}
+
+// ChangedSymbols parses both versions of a file once and computes the symbol changes
+func ChangedSymbols(oldContent, newContent, filename string) (*SymbolChanges, error) {
+	return NewParser().ChangedSymbols(oldContent, newContent, filename)
+}
+
+// ChangedSymbols parses both versions of a file once and computes the symbol changes
+func (p *Parser) ChangedSymbols(oldContent, newContent, filename string) (*SymbolChanges, error) {
+	oldSymbols, err := p.ParseFile(filename, oldContent)
+	if err != nil {
+		oldSymbols = []Symbol{} // Might be a new file
+	}
+
+	newSymbols, err := p.ParseFile(filename, newContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new content: %w", err)
+	}
+
+	return CompareSymbols(oldSymbols, newSymbols, filename), nil
+}
+
+// CompareSymbols computes the symbol changes between two already parsed symbol lists.
+// Results follow source order so reports are stable between runs.
+func CompareSymbols(oldSymbols, newSymbols []Symbol, filename string) *SymbolChanges {
+	changes := &SymbolChanges{
+		FileName:   filename,
+		OldSymbols: oldSymbols,
+		NewSymbols: newSymbols,
+		Added:      []Symbol{},
+		Removed:    []Symbol{},
+		Modified:   []ModifiedSymbol{},
+		oldByKey:   buildSymbolMap(oldSymbols),
+		newByKey:   buildSymbolMap(newSymbols),
+	}
+
+	for _, oldSym := range oldSymbols {
+		newSym, exists := changes.newByKey[symbolKey(oldSym)]
+		if !exists {
+			changes.Removed = append(changes.Removed, oldSym)
+		} else if symbolModified(oldSym, newSym) {
+			changes.Modified = append(changes.Modified, ModifiedSymbol{Old: oldSym, New: newSym})
+		}
+	}
+
+	for _, newSym := range newSymbols {
+		if _, exists := changes.oldByKey[symbolKey(newSym)]; !exists {
+			changes.Added = append(changes.Added, newSym)
+		}
+	}
+
+	return changes
+}
+
+// HasChanges reports whether any symbol was added, removed, or modified
+func (c *SymbolChanges) HasChanges() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0 || len(c.Modified) > 0
+}
+
+// StaleDocs returns exported functions and methods whose signature changed while their
+// doc comment stayed the same. Undocumented symbols are not reported.
+func (c *SymbolChanges) StaleDocs() []ModifiedSymbol {
+	var stale []ModifiedSymbol
+	for _, mod := range c.Modified {
+		if mod.New.Kind != SymbolFunction && mod.New.Kind != SymbolMethod {
+			continue
+		}
+		if !mod.New.Exported || mod.New.Doc == "" {
+			continue
+		}
+		if mod.Old.Signature != mod.New.Signature && mod.Old.Doc == mod.New.Doc {
+			stale = append(stale, mod)
+		}
+	}
+	return stale
+}
+
+// symbolKey identifies a symbol by name, kind, and parent to distinguish overloaded methods
+func symbolKey(sym Symbol) string {
+	return fmt.Sprintf("%s:%s:%s", sym.Name, sym.Kind, sym.Parent)
+}
+
+// buildSymbolMap creates a map of symbols by their unique key
+func buildSymbolMap(symbols []Symbol) map[string]Symbol {
+	result := make(map[string]Symbol)
+	for _, sym := range symbols {
+		result[symbolKey(sym)] = sym
+	}
+	return result
+}
+
+// symbolModified checks if a symbol's signature, parameters, return type, or visibility changed.
+// Doc comment edits alone are not API changes and are ignored.
+func symbolModified(old, new Symbol) bool {
+	if old.Signature != new.Signature {
+		return true
+	}
+	if len(old.Parameters) != len(new.Parameters) {
+		return true
+	}
+	for i := range old.Parameters {
+		if old.Parameters[i] != new.Parameters[i] {
+			return true
+		}
+	}
+	if old.ReturnType != new.ReturnType {
+		return true
+	}
+	if old.ValueType != new.ValueType {
+		return true
+	}
+	if old.Exported != new.Exported {
+		return true
+	}
+	return false
+}
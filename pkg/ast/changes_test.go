@@ -0,0 +1,165 @@
+package ast
+
+import (
+	"testing"
+)
+
+func TestChangedSymbols(t *testing.T) {
+	oldCode := `package main
+
+func GetUser(id int) *User {
+	return nil
+}
+
+func DeleteUser(id int) error {
+	return nil
+}
+
+func ListUsers() []User {
+	return nil
+}
+`
+
+	newCode := `package main
+
+func GetUser(id int, includeDeleted bool) *User {
+	return nil
+}
+
+func ListUsers() []User {
+	return nil
+}
+
+func CreateUser(name string) *User {
+	return nil
+}
+`
+
+	changes, err := ChangedSymbols(oldCode, newCode, "user.go")
+	if err != nil {
+		t.Fatalf("Failed to compute changed symbols: %v", err)
+	}
+
+	if !changes.HasChanges() {
+		t.Fatal("Expected changes to be detected")
+	}
+
+	if len(changes.Removed) != 1 || changes.Removed[0].Name != "DeleteUser" {
+		t.Errorf("Expected DeleteUser to be removed, got %+v", changes.Removed)
+	}
+	if len(changes.Added) != 1 || changes.Added[0].Name != "CreateUser" {
+		t.Errorf("Expected CreateUser to be added, got %+v", changes.Added)
+	}
+	if len(changes.Modified) != 1 || changes.Modified[0].New.Name != "GetUser" {
+		t.Errorf("Expected GetUser to be modified, got %+v", changes.Modified)
+	}
+}
+
+func TestChangedSymbolsSharedByDetectors(t *testing.T) {
+	oldCode := `package main
+
+func DeleteUser(id int) error {
+	return nil
+}
+`
+
+	newCode := `package main
+`
+
+	changes, err := ChangedSymbols(oldCode, newCode, "user.go")
+	if err != nil {
+		t.Fatalf("Failed to compute changed symbols: %v", err)
+	}
+
+	report := NewBreakingChangeDetector().DetectFromChanges(changes)
+	if !report.HasBreaking {
+		t.Error("Expected breaking change for removed exported function")
+	}
+
+	impact := NewImpactAnalyzer().AnalyzeChanges(changes)
+	if len(impact.Impacts) != 1 || impact.Impacts[0].Severity != "critical" {
+		t.Errorf("Expected a single critical impact, got %+v", impact.Impacts)
+	}
+}
+
+func TestChangedSymbolsUnchanged(t *testing.T) {
+	code := `package main
+
+func GetUser(id int) *User {
+	return nil
+}
+`
+
+	changes, err := ChangedSymbols(code, code, "user.go")
+	if err != nil {
+		t.Fatalf("Failed to compute changed symbols: %v", err)
+	}
+
+	if changes.HasChanges() {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+func TestStaleDocs(t *testing.T) {
+	oldCode := `package main
+
+// GetUser returns the user with the given id.
+func GetUser(id int) *User {
+	return nil
+}
+
+// ListUsers returns all users.
+func ListUsers() []User {
+	return nil
+}
+
+func Undocumented(id int) {}
+
+// helper is unexported.
+func helper(a int) {}
+`
+
+	newCode := `package main
+
+// GetUser returns the user with the given id.
+func GetUser(id int, includeDeleted bool) *User {
+	return nil
+}
+
+// ListUsers returns all users, newest first.
+func ListUsers(limit int) []User {
+	return nil
+}
+
+func Undocumented(id string) {}
+
+// helper is unexported.
+func helper(a, b int) {}
+`
+
+	changes, err := ChangedSymbols(oldCode, newCode, "user.go")
+	if err != nil {
+		t.Fatalf("Failed to compute changed symbols: %v", err)
+	}
+
+	stale := changes.StaleDocs()
+	if len(stale) != 1 || stale[0].New.Name != "GetUser" {
+		t.Fatalf("Expected only GetUser to have a stale doc, got %+v", stale)
+	}
+	if stale[0].New.Doc != "GetUser returns the user with the given id.\n" {
+		t.Errorf("Unexpected doc text: %q", stale[0].New.Doc)
+	}
+}
+
+func TestChangedSymbolsIgnoresDocOnlyEdits(t *testing.T) {
+	oldCode := "package main\n\n// Run runs.\nfunc Run() {}\n"
+	newCode := "package main\n\n// Run runs the job.\nfunc Run() {}\n"
+
+	changes, err := ChangedSymbols(oldCode, newCode, "run.go")
+	if err != nil {
+		t.Fatalf("Failed to compute changed symbols: %v", err)
+	}
+	if changes.HasChanges() {
+		t.Errorf("Expected doc-only edit to be ignored, got %+v", changes.Modified)
+	}
+}
@@ -12,6 +12,8 @@ type ImpactAnalyzer struct {
 	symbolTable  *SymbolTable
 	dependencies map[string][]string // file -> files it depends on
 	dependents   map[string][]string // file -> files that depend on it
+
+	referencePatterns map[string]*regexp.Regexp // Word-boundary pattern per symbol name
 }
 
 // SymbolTable stores all symbols across the codebase
@@ -59,6 +61,8 @@ func NewImpactAnalyzer() *ImpactAnalyzer {
 		},
 		dependencies: make(map[string][]string),
 		dependents:   make(map[string][]string),
+
+		referencePatterns: make(map[string]*regexp.Regexp),
 	}
 }
 
@@ -94,9 +98,11 @@ func (a *ImpactAnalyzer) findReferences(filename string, content string) {
 
 		// Check for references to known symbols
 		for symbolName := range a.symbolTable.Symbols {
-			// Look for word boundaries around symbol name
-			pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(symbolName) + `\b`)
-			if pattern.MatchString(line) {
+			// Cheap substring check first, then look for word boundaries around symbol name
+			if !strings.Contains(line, symbolName) {
+				continue
+			}
+			if a.referencePattern(symbolName).MatchString(line) {
 				// Don't count definition as reference
 				isDefinition := false
 				for _, sym := range a.symbolTable.Symbols[symbolName] {
@@ -117,19 +123,30 @@ func (a *ImpactAnalyzer) findReferences(filename string, content string) {
 	}
 }
 
+// referencePattern returns the pattern matching symbolName as a whole word, compiling it
+// once per symbol
+func (a *ImpactAnalyzer) referencePattern(symbolName string) *regexp.Regexp {
+	pattern, ok := a.referencePatterns[symbolName]
+	if !ok {
+		pattern = regexp.MustCompile(`\b` + regexp.QuoteMeta(symbolName) + `\b`)
+		a.referencePatterns[symbolName] = pattern
+	}
+	return pattern
+}
+
 // AnalyzeImpact analyzes the impact of changes in a diff
 func (a *ImpactAnalyzer) AnalyzeImpact(oldContent, newContent, filename string) (*FileImpact, error) {
-	// Parse both versions
-	oldSymbols, err := a.parser.ParseFile(filename, oldContent)
+	changes, err := a.parser.ChangedSymbols(oldContent, newContent, filename)
 	if err != nil {
-		oldSymbols = []Symbol{} // Might be a new file
+		return nil, err
 	}
 
-	newSymbols, err := a.parser.ParseFile(filename, newContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse new content: %w", err)
-	}
+	return a.AnalyzeChanges(changes), nil
+}
 
+// AnalyzeChanges analyzes the impact of already computed symbol changes
+func (a *ImpactAnalyzer) AnalyzeChanges(changes *SymbolChanges) *FileImpact {
+	filename := changes.FileName
 	impact := &FileImpact{
 		FilePath:       filename,
 		ChangedSymbols: []Symbol{},
@@ -137,46 +154,20 @@ func (a *ImpactAnalyzer) AnalyzeImpact(oldContent, newContent, filename string)
 		AffectedFiles:  []string{},
 	}
 
-	// Build maps for comparison
-	oldSymbolMap := make(map[string]Symbol)
-	for _, sym := range oldSymbols {
-		key := fmt.Sprintf("%s:%s:%s", sym.Name, sym.Kind, sym.Parent)
-		oldSymbolMap[key] = sym
-	}
-
-	newSymbolMap := make(map[string]Symbol)
-	for _, sym := range newSymbols {
-		key := fmt.Sprintf("%s:%s:%s", sym.Name, sym.Kind, sym.Parent)
-		newSymbolMap[key] = sym
-	}
-
-	// Find changed symbols
+	// Removed and modified symbols first, added symbols are generally less impactful
 	changedSymbols := []Symbol{}
-
-	// Modified or removed symbols
-	for key, oldSym := range oldSymbolMap {
-		newSym, exists := newSymbolMap[key]
-		if !exists {
-			// Symbol was removed
-			changedSymbols = append(changedSymbols, oldSym)
-		} else if a.symbolChanged(oldSym, newSym) {
-			changedSymbols = append(changedSymbols, newSym)
-		}
-	}
-
-	// Added symbols (less impactful generally)
-	for key, newSym := range newSymbolMap {
-		if _, exists := oldSymbolMap[key]; !exists {
-			changedSymbols = append(changedSymbols, newSym)
-		}
+	changedSymbols = append(changedSymbols, changes.Removed...)
+	for _, modified := range changes.Modified {
+		changedSymbols = append(changedSymbols, modified.New)
 	}
+	changedSymbols = append(changedSymbols, changes.Added...)
 
 	impact.ChangedSymbols = changedSymbols
 
 	// Analyze impact for each changed symbol
 	affectedFilesSet := make(map[string]bool)
 	for _, sym := range changedSymbols {
-		symbolImpact := a.analyzeSymbolImpact(sym, oldSymbolMap, newSymbolMap)
+		symbolImpact := a.analyzeSymbolImpact(sym, changes.oldByKey, changes.newByKey)
 		impact.Impacts = append(impact.Impacts, symbolImpact)
 		impact.TotalReferences += len(symbolImpact.References)
 		for _, file := range symbolImpact.AffectedFiles {
@@ -194,33 +185,7 @@ func (a *ImpactAnalyzer) AnalyzeImpact(oldContent, newContent, filename string)
 	// Determine overall severity
 	impact.OverallSeverity = a.calculateOverallSeverity(impact)
 
-	return impact, nil
-}
-
-// symbolChanged checks if a symbol's signature has changed
-func (a *ImpactAnalyzer) symbolChanged(old, new Symbol) bool {
-	// Check signature changes
-	if old.Signature != new.Signature {
-		return true
-	}
-	// Check parameter changes
-	if len(old.Parameters) != len(new.Parameters) {
-		return true
-	}
-	for i := range old.Parameters {
-		if old.Parameters[i] != new.Parameters[i] {
-			return true
-		}
-	}
-	// Check return type changes
-	if old.ReturnType != new.ReturnType {
-		return true
-	}
-	// Check export status changes
-	if old.Exported != new.Exported {
-		return true
-	}
-	return false
+	return impact
 }
 
 // analyzeSymbolImpact analyzes the impact of changing a single symbol
@@ -248,7 +213,7 @@ func (a *ImpactAnalyzer) analyzeSymbolImpact(sym Symbol, oldMap, newMap map[stri
 	}
 
 	// Determine severity
-	key := fmt.Sprintf("%s:%s:%s", sym.Name, sym.Kind, sym.Parent)
+	key := symbolKey(sym)
 	oldSym, wasExisting := oldMap[key]
 	_, stillExists := newMap[key]
 
@@ -280,6 +245,10 @@ func (a *ImpactAnalyzer) analyzeSymbolImpact(sym Symbol, oldMap, newMap map[stri
 			changes = append(changes, "return type")
 			impact.Severity = "high"
 		}
+		if oldSym.ValueType != sym.ValueType {
+			changes = append(changes, "type")
+			impact.Severity = "high"
+		}
 		if oldSym.Exported != sym.Exported {
 			changes = append(changes, "visibility")
 			if !sym.Exported && oldSym.Exported {
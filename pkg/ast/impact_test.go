@@ -96,6 +96,11 @@ func CreateDefaultUser() *User {
 	if !foundHandlerRef {
 		t.Error("Expected reference from handler.go")
 	}
+
+	// Each symbol's pattern is compiled once, however many lines mention it
+	if pattern, ok := analyzer.referencePatterns["User"]; !ok || pattern != analyzer.referencePattern("User") {
+		t.Error("Expected the User pattern to be compiled once and reused")
+	}
 }
 
 func TestAnalyzeImpactRemovedSymbol(t *testing.T) {
@@ -19,6 +19,8 @@ const (
 	LangPython     Language = "python"
 	LangRust       Language = "rust"
 	LangJava       Language = "java"
+	LangCSharp     Language = "csharp"
+	LangKotlin     Language = "kotlin"
 	LangUnknown    Language = "unknown"
 )
 
@@ -32,7 +34,9 @@ type Symbol struct {
 	Exported   bool       `json:"exported"`
 	Parameters []string   `json:"parameters,omitempty"`
 	ReturnType string     `json:"return_type,omitempty"`
-	Parent     string     `json:"parent,omitempty"` // For methods: the receiver type
+	ValueType  string     `json:"value_type,omitempty"` // For constants/variables: the declared type
+	Parent     string     `json:"parent,omitempty"`     // For methods: the receiver type; for members: the enclosing type
+	Doc        string     `json:"doc,omitempty"`        // Preceding doc comment text (Go only)
 	FilePath   string     `json:"file_path"`
 }
 
@@ -79,6 +83,10 @@ func DetectLanguage(filename string) Language {
 		return LangRust
 	case ".java":
 		return LangJava
+	case ".cs":
+		return LangCSharp
+	case ".kt", ".kts":
+		return LangKotlin
 	default:
 		return LangUnknown
 	}
@@ -108,6 +116,10 @@ func (p *Parser) ParseFile(filename string, content string) ([]Symbol, error) {
 		return p.parseRust(filename, content)
 	case LangJava:
 		return p.parseJava(filename, content)
+	case LangCSharp:
+		return p.parseCSharp(filename, content)
+	case LangKotlin:
+		return p.parseKotlin(filename, content)
 	default:
 		return []Symbol{}, nil
 	}
@@ -167,37 +179,26 @@ func (p *Parser) extractGoFunction(fn *ast.FuncDecl, filename string) Symbol {
 		}
 	}
 
-	// Extract parameters
-	if fn.Type.Params != nil {
-		for _, param := range fn.Type.Params.List {
-			paramType := exprToString(param.Type)
-			for _, name := range param.Names {
-				sym.Parameters = append(sym.Parameters, name.Name+" "+paramType)
-			}
-			if len(param.Names) == 0 {
-				sym.Parameters = append(sym.Parameters, paramType)
-			}
-		}
-	}
-
-	// Extract return type
-	if fn.Type.Results != nil {
-		var returns []string
-		for _, result := range fn.Type.Results.List {
-			returns = append(returns, exprToString(result.Type))
-		}
-		sym.ReturnType = strings.Join(returns, ", ")
-	}
+	// Extract parameters and return type
+	sym.Parameters, sym.ReturnType = goFuncTypeParts(fn.Type)
 
 	// Build signature
 	sym.Signature = buildGoSignature(fn)
 
+	if fn.Doc != nil {
+		sym.Doc = fn.Doc.Text()
+	}
+
 	return sym
 }
 
 func (p *Parser) extractGoGenDecl(decl *ast.GenDecl, filename string) []Symbol {
 	var symbols []Symbol
 
+	// In const blocks a spec with neither type nor values repeats the previous spec's
+	// type and expression (e.g. iota enums), so the type carries over
+	var lastConstType string
+
 	for _, spec := range decl.Specs {
 		switch s := spec.(type) {
 		case *ast.TypeSpec:
@@ -213,27 +214,45 @@ func (p *Parser) extractGoGenDecl(decl *ast.GenDecl, filename string) []Symbol {
 				sym.EndLine = p.fset.Position(s.End()).Line
 			}
 
-			switch s.Type.(type) {
+			switch t := s.Type.(type) {
 			case *ast.StructType:
 				sym.Kind = SymbolStruct
+				symbols = append(symbols, sym)
+				symbols = append(symbols, p.extractGoStructFields(t, sym, filename)...)
 			case *ast.InterfaceType:
 				sym.Kind = SymbolInterface
+				symbols = append(symbols, sym)
+				symbols = append(symbols, p.extractGoInterfaceMethods(t, sym, filename)...)
 			default:
 				sym.Kind = SymbolType
+				symbols = append(symbols, sym)
 			}
-			symbols = append(symbols, sym)
 
 		case *ast.ValueSpec:
 			kind := SymbolVariable
 			if decl.Tok == token.CONST {
 				kind = SymbolConstant
 			}
+
+			// The declared type is shared by every name in the spec (A, B T = x, y)
+			valueType := ""
+			if s.Type != nil {
+				valueType = exprToString(s.Type)
+			}
+			if decl.Tok == token.CONST {
+				if s.Type == nil && len(s.Values) == 0 {
+					valueType = lastConstType
+				}
+				lastConstType = valueType
+			}
+
 			for _, name := range s.Names {
 				sym := Symbol{
-					Name:     name.Name,
-					Kind:     kind,
-					Exported: ast.IsExported(name.Name),
-					FilePath: filename,
+					Name:      name.Name,
+					Kind:      kind,
+					Exported:  ast.IsExported(name.Name),
+					ValueType: valueType,
+					FilePath:  filename,
 				}
 				if name.Pos().IsValid() {
 					sym.StartLine = p.fset.Position(name.Pos()).Line
@@ -247,6 +266,129 @@ func (p *Parser) extractGoGenDecl(decl *ast.GenDecl, filename string) []Symbol {
 	return symbols
 }
 
+// extractGoInterfaceMethods returns the methods of an interface as child symbols, so removing
+// one from an exported interface is reported like removing any other exported method.
+// Embedded interfaces are not expanded.
+func (p *Parser) extractGoInterfaceMethods(iface *ast.InterfaceType, parent Symbol, filename string) []Symbol {
+	var symbols []Symbol
+	if iface.Methods == nil {
+		return symbols
+	}
+
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue // Embedded interface or type constraint
+		}
+		params, returnType := goFuncTypeParts(fn)
+		for _, name := range field.Names {
+			sym := Symbol{
+				Name:       name.Name,
+				Kind:       SymbolMethod,
+				Exported:   parent.Exported && ast.IsExported(name.Name),
+				Parameters: params,
+				ReturnType: returnType,
+				Signature:  name.Name + goFuncTypeSignature(params, returnType, fn),
+				Parent:     parent.Name,
+				FilePath:   filename,
+			}
+			if name.Pos().IsValid() {
+				sym.StartLine = p.fset.Position(name.Pos()).Line
+				sym.EndLine = p.fset.Position(field.End()).Line
+			}
+			if field.Doc != nil {
+				sym.Doc = field.Doc.Text()
+			}
+			symbols = append(symbols, sym)
+		}
+	}
+
+	return symbols
+}
+
+// extractGoStructFields returns the exported fields of a struct as child symbols. Embedded
+// fields are named after their type, as in Go itself.
+func (p *Parser) extractGoStructFields(st *ast.StructType, parent Symbol, filename string) []Symbol {
+	var symbols []Symbol
+	if st.Fields == nil {
+		return symbols
+	}
+
+	for _, field := range st.Fields.List {
+		fieldType := exprToString(field.Type)
+		names := field.Names
+		if len(names) == 0 {
+			embedded := strings.TrimPrefix(fieldType, "*")
+			if idx := strings.LastIndex(embedded, "."); idx != -1 {
+				embedded = embedded[idx+1:]
+			}
+			names = []*ast.Ident{{Name: embedded, NamePos: field.Type.Pos()}}
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name.Name) {
+				continue
+			}
+			sym := Symbol{
+				Name:      name.Name,
+				Kind:      SymbolVariable,
+				Exported:  parent.Exported,
+				ValueType: fieldType,
+				Parent:    parent.Name,
+				FilePath:  filename,
+			}
+			if name.Pos().IsValid() {
+				sym.StartLine = p.fset.Position(name.Pos()).Line
+				sym.EndLine = p.fset.Position(field.End()).Line
+			}
+			if field.Doc != nil {
+				sym.Doc = field.Doc.Text()
+			}
+			symbols = append(symbols, sym)
+		}
+	}
+
+	return symbols
+}
+
+// goFuncTypeParts returns the parameters and joined result types of a function type
+func goFuncTypeParts(fn *ast.FuncType) (params []string, returnType string) {
+	if fn.Params != nil {
+		for _, param := range fn.Params.List {
+			paramType := exprToString(param.Type)
+			for _, name := range param.Names {
+				params = append(params, name.Name+" "+paramType)
+			}
+			if len(param.Names) == 0 {
+				params = append(params, paramType)
+			}
+		}
+	}
+
+	if fn.Results != nil {
+		var returns []string
+		for _, result := range fn.Results.List {
+			returns = append(returns, exprToString(result.Type))
+		}
+		returnType = strings.Join(returns, ", ")
+	}
+
+	return params, returnType
+}
+
+// goFuncTypeSignature formats "(params) results" for a function type, matching buildGoSignature
+func goFuncTypeSignature(params []string, returnType string, fn *ast.FuncType) string {
+	sig := "(" + strings.Join(params, ", ") + ")"
+	switch {
+	case fn.Results == nil || len(fn.Results.List) == 0:
+		return sig
+	case len(fn.Results.List) > 1:
+		return sig + " (" + returnType + ")"
+	default:
+		return sig + " " + returnType
+	}
+}
+
 func exprToString(expr ast.Expr) string {
 	switch e := expr.(type) {
 	case *ast.Ident:
@@ -322,15 +464,25 @@ func buildGoSignature(fn *ast.FuncDecl) string {
 
 // Regex-based parsers for other languages
 
+const (
+	tsDecoratorPrefix = `(?:@[\w.]+(?:\([^)]*\))?\s+)*`
+	tsExportPrefix    = `(?:export\s+(?:default\s+)?)?(?:declare\s+)?`
+
+	// ktModifierPrefix matches Kotlin annotations and modifiers before a declaration
+	ktModifierPrefix = `(?:@[\w.:]+(?:\([^)]*\))?\s+)*(?:(?:public|private|protected|internal|open|abstract|final|sealed|data|enum|annotation|inner|value|inline|override|suspend|operator|infix|tailrec|external|const|lateinit|expect|actual|fun)\s+)*`
+)
+
 var (
-	// TypeScript/JavaScript patterns
-	tsClassPattern     = regexp.MustCompile(`(?m)^(?:export\s+)?(?:abstract\s+)?class\s+(\w+)`)
-	tsFunctionPattern  = regexp.MustCompile(`(?m)^(?:export\s+)?(?:async\s+)?function\s+(\w+)\s*\(([^)]*)\)`)
+	// TypeScript/JavaScript patterns. Declarations may be "export", "export default" and/or
+	// "declare"; classes may also be preceded by decorators such as @Component({...}).
+	tsClassPattern     = regexp.MustCompile(`(?m)^` + tsDecoratorPrefix + tsExportPrefix + `(?:abstract\s+)?class\s+(\w+)`)
+	tsFunctionPattern  = regexp.MustCompile(`(?m)^` + tsExportPrefix + `(?:async\s+)?function(?:\s*\*\s*|\s+)(\w+)\s*(?:<[^>]*>)?\s*\(([^)]*)\)`)
 	tsMethodPattern    = regexp.MustCompile(`(?m)^\s+(?:async\s+)?(\w+)\s*\(([^)]*)\)\s*(?::\s*\w+)?\s*\{`)
-	tsInterfacePattern = regexp.MustCompile(`(?m)^(?:export\s+)?interface\s+(\w+)`)
-	tsTypePattern      = regexp.MustCompile(`(?m)^(?:export\s+)?type\s+(\w+)`)
-	tsConstPattern     = regexp.MustCompile(`(?m)^(?:export\s+)?const\s+(\w+)`)
-	tsArrowPattern     = regexp.MustCompile(`(?m)^(?:export\s+)?const\s+(\w+)\s*=\s*(?:async\s+)?\([^)]*\)\s*=>`)
+	tsInterfacePattern = regexp.MustCompile(`(?m)^` + tsExportPrefix + `interface\s+(\w+)`)
+	tsTypePattern      = regexp.MustCompile(`(?m)^` + tsExportPrefix + `type\s+(\w+)`)
+	tsConstPattern     = regexp.MustCompile(`(?m)^` + tsExportPrefix + `const\s+(\w+)`)
+	tsArrowPattern     = regexp.MustCompile(`(?m)^` + tsExportPrefix + `const\s+(\w+)\s*=\s*(?:async\s+)?\([^)]*\)\s*=>`)
+	tsDecoratorPattern = regexp.MustCompile(tsDecoratorPrefix)
 
 	// Python patterns
 	pyClassPattern    = regexp.MustCompile(`(?m)^class\s+(\w+)`)
@@ -351,6 +503,27 @@ var (
 	javaInterfacePattern = regexp.MustCompile(`(?m)^\s*(?:public\s+)?interface\s+(\w+)`)
 	// Method pattern excludes constructors (constructor has same name as class, no return type)
 	javaMethodPattern = regexp.MustCompile(`(?m)^\s+(?:public|private|protected)\s+(?:static\s+)?(?:final\s+)?(\w+(?:<[^>]*>)?)\s+(\w+)\s*\(([^)]*)\)`)
+
+	// C# patterns
+	csClassPattern     = regexp.MustCompile(`(?m)^\s*(?:(?:public|private|protected|internal|static|abstract|sealed|partial)\s+)*(?:record\s+)?class\s+(\w+)`)
+	csInterfacePattern = regexp.MustCompile(`(?m)^\s*(?:(?:public|private|protected|internal|partial)\s+)*interface\s+(\w+)`)
+	csStructPattern    = regexp.MustCompile(`(?m)^\s*(?:(?:public|private|protected|internal|readonly|ref|partial)\s+)*(?:record\s+)?struct\s+(\w+)`)
+	csEnumPattern      = regexp.MustCompile(`(?m)^\s*(?:(?:public|private|protected|internal)\s+)*enum\s+(\w+)`)
+	// Method pattern requires a modifier and a return type, so constructors only match with a
+	// modifier like "static"; those are skipped by name
+	csMethodPattern   = regexp.MustCompile(`(?m)^[ \t]+(?:(?:public|private|protected|internal|static|virtual|override|abstract|sealed|async|extern|unsafe|new|partial)\s+)+([\w.]+(?:<[^>]*>)?(?:\[\])?\??)\s+(\w+)\s*(?:<[^>]*>)?\s*\(([^)]*)\)`)
+	csPropertyPattern = regexp.MustCompile(`(?m)^[ \t]+(?:(?:public|private|protected|internal|static|virtual|override|abstract|sealed|new|required)\s+)+([\w.]+(?:<[^>]*>)?(?:\[\])?\??)\s+(\w+)[ \t]*(?:\{|=>)`)
+
+	// Kotlin patterns. "data class", "enum class" and "sealed class" match as classes, and
+	// "fun interface" as an interface; the "fun" modifier only appears before "interface".
+	ktClassPattern     = regexp.MustCompile(`(?m)^[ \t]*` + ktModifierPrefix + `class\s+(\w+)`)
+	ktObjectPattern    = regexp.MustCompile(`(?m)^[ \t]*` + ktModifierPrefix + `(?:companion\s+)?object\s+(\w+)`)
+	ktInterfacePattern = regexp.MustCompile(`(?m)^[ \t]*` + ktModifierPrefix + `interface\s+(\w+)`)
+	// Function pattern allows type parameters and extension receivers, e.g. "fun <T> List<T>.second()"
+	ktFunctionPattern = regexp.MustCompile(`(?m)^[ \t]*` + ktModifierPrefix + `fun\s+(?:<[^>]*>\s*)?(?:[\w.]+(?:<[^>]*>)?\??\.)?(\w+)\s*\(([^)]*)\)`)
+	// Properties are only collected at the top level, since members are usually implementation details
+	ktPropertyPattern = regexp.MustCompile(`(?m)^` + ktModifierPrefix + `(?:val|var)\s+(\w+)`)
+	ktPrivatePattern  = regexp.MustCompile(`\b(?:private|internal)\s`)
 )
 
 func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, error) {
@@ -361,13 +534,17 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 	for _, match := range tsClassPattern.FindAllStringSubmatchIndex(content, -1) {
 		if len(match) >= 4 {
 			name := content[match[2]:match[3]]
-			line := countLines(content[:match[0]])
+			if name == "extends" || name == "implements" {
+				continue // Anonymous "export default class extends Base"
+			}
+			// Decorators may sit on earlier lines, so the class starts at its name
+			line := countLines(content[:match[2]])
 			symbols = append(symbols, Symbol{
 				Name:      name,
 				Kind:      SymbolClass,
 				StartLine: line,
 				EndLine:   findBlockEnd(lines, line-1),
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -382,7 +559,7 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 				Name:      name,
 				Kind:      SymbolInterface,
 				StartLine: line,
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -397,7 +574,7 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 				Name:      name,
 				Kind:      SymbolFunction,
 				StartLine: line,
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -412,7 +589,7 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 				Name:      name,
 				Kind:      SymbolFunction,
 				StartLine: line,
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -427,7 +604,7 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 				Name:      name,
 				Kind:      SymbolType,
 				StartLine: line,
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -452,7 +629,7 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 				Name:      name,
 				Kind:      SymbolConstant,
 				StartLine: line,
-				Exported:  strings.Contains(content[match[0]:match[1]], "export"),
+				Exported:  tsIsExported(content[match[0]:match[1]], filename),
 				FilePath:  filename,
 			})
 		}
@@ -461,6 +638,16 @@ func (p *Parser) parseTypeScript(filename string, content string) ([]Symbol, err
 	return symbols, nil
 }
 
+// tsIsExported reports whether a TypeScript declaration is visible outside its file: it is
+// exported, or it is an ambient "declare" in a .d.ts file
+func tsIsExported(decl, filename string) bool {
+	decl = strings.TrimSpace(tsDecoratorPattern.ReplaceAllString(decl, ""))
+	if strings.HasPrefix(decl, "export") {
+		return true
+	}
+	return strings.HasSuffix(filename, ".d.ts") && strings.HasPrefix(decl, "declare")
+}
+
 func (p *Parser) parsePython(filename string, content string) ([]Symbol, error) {
 	var symbols []Symbol
 
@@ -487,11 +674,12 @@ func (p *Parser) parsePython(filename string, content string) ([]Symbol, error)
 			// Only include if at start of line (top-level)
 			if match[0] == 0 || content[match[0]-1] == '\n' {
 				symbols = append(symbols, Symbol{
-					Name:      name,
-					Kind:      SymbolFunction,
-					StartLine: line,
-					Exported:  !strings.HasPrefix(name, "_"),
-					FilePath:  filename,
+					Name:       name,
+					Kind:       SymbolFunction,
+					StartLine:  line,
+					Exported:   !strings.HasPrefix(name, "_"),
+					Parameters: splitParameters(content[match[4]:match[5]], "self", "cls", "*"),
+					FilePath:   filename,
 				})
 			}
 		}
@@ -647,8 +835,194 @@ func (p *Parser) parseJava(filename string, content string) ([]Symbol, error) {
 	return symbols, nil
 }
 
+// csTypeKeywords are declaration keywords the property pattern would otherwise read as a type
+var csTypeKeywords = map[string]bool{
+	"class": true, "interface": true, "struct": true, "enum": true, "record": true, "delegate": true, "event": true,
+}
+
+func (p *Parser) parseCSharp(filename string, content string) ([]Symbol, error) {
+	var symbols []Symbol
+	classNames := make(map[string]bool)
+
+	// Find type declarations
+	typePatterns := []struct {
+		pattern *regexp.Regexp
+		kind    SymbolKind
+	}{
+		{csClassPattern, SymbolClass},
+		{csInterfacePattern, SymbolInterface},
+		{csStructPattern, SymbolStruct},
+		{csEnumPattern, SymbolType},
+	}
+	for _, tp := range typePatterns {
+		for _, match := range tp.pattern.FindAllStringSubmatchIndex(content, -1) {
+			if len(match) >= 4 {
+				name := content[match[2]:match[3]]
+				line := countLines(content[:match[0]])
+				if tp.kind == SymbolClass || tp.kind == SymbolStruct {
+					classNames[name] = true
+				}
+				symbols = append(symbols, Symbol{
+					Name:      name,
+					Kind:      tp.kind,
+					StartLine: line,
+					Exported:  strings.Contains(content[match[0]:match[1]], "public"),
+					FilePath:  filename,
+				})
+			}
+		}
+	}
+
+	// Find methods (capture group 2 is the method name, group 1 is return type)
+	for _, match := range csMethodPattern.FindAllStringSubmatchIndex(content, -1) {
+		if len(match) >= 6 {
+			name := content[match[4]:match[5]]
+			if classNames[name] {
+				continue // Constructor
+			}
+			line := countLines(content[:match[0]])
+			symbols = append(symbols, Symbol{
+				Name:      name,
+				Kind:      SymbolMethod,
+				StartLine: line,
+				Exported:  strings.Contains(content[match[0]:match[1]], "public"),
+				FilePath:  filename,
+			})
+		}
+	}
+
+	// Find properties ("{ get; set; }" or expression-bodied "=>"), skipping nested type declarations
+	for _, match := range csPropertyPattern.FindAllStringSubmatchIndex(content, -1) {
+		if len(match) >= 6 {
+			if csTypeKeywords[content[match[2]:match[3]]] {
+				continue
+			}
+			name := content[match[4]:match[5]]
+			line := countLines(content[:match[0]])
+			symbols = append(symbols, Symbol{
+				Name:      name,
+				Kind:      SymbolVariable,
+				StartLine: line,
+				Exported:  strings.Contains(content[match[0]:match[1]], "public"),
+				FilePath:  filename,
+			})
+		}
+	}
+
+	return symbols, nil
+}
+
+func (p *Parser) parseKotlin(filename string, content string) ([]Symbol, error) {
+	var symbols []Symbol
+	lines := strings.Split(content, "\n")
+
+	// Find type declarations
+	typePatterns := []struct {
+		pattern *regexp.Regexp
+		kind    SymbolKind
+	}{
+		{ktClassPattern, SymbolClass},
+		{ktObjectPattern, SymbolClass},
+		{ktInterfacePattern, SymbolInterface},
+	}
+	for _, tp := range typePatterns {
+		for _, match := range tp.pattern.FindAllStringSubmatchIndex(content, -1) {
+			if len(match) >= 4 {
+				name := content[match[2]:match[3]]
+				line := countLines(content[:match[2]])
+				symbols = append(symbols, Symbol{
+					Name:      name,
+					Kind:      tp.kind,
+					StartLine: line,
+					EndLine:   findBlockEnd(lines, line-1),
+					Exported:  !ktPrivatePattern.MatchString(content[match[0]:match[2]]),
+					FilePath:  filename,
+				})
+			}
+		}
+	}
+
+	// Find functions; indented ones are members of a class or object
+	for _, match := range ktFunctionPattern.FindAllStringSubmatchIndex(content, -1) {
+		if len(match) >= 4 {
+			name := content[match[2]:match[3]]
+			line := countLines(content[:match[2]])
+			kind := SymbolFunction
+			if strings.HasPrefix(lines[line-1], " ") || strings.HasPrefix(lines[line-1], "\t") {
+				kind = SymbolMethod
+			}
+			symbols = append(symbols, Symbol{
+				Name:      name,
+				Kind:      kind,
+				StartLine: line,
+				Exported:  !ktPrivatePattern.MatchString(content[match[0]:match[2]]),
+				FilePath:  filename,
+			})
+		}
+	}
+
+	// Find top-level properties; "const val" declares a constant
+	for _, match := range ktPropertyPattern.FindAllStringSubmatchIndex(content, -1) {
+		if len(match) >= 4 {
+			name := content[match[2]:match[3]]
+			modifiers := content[match[0]:match[2]]
+			kind := SymbolVariable
+			if strings.Contains(modifiers, "const ") {
+				kind = SymbolConstant
+			}
+			symbols = append(symbols, Symbol{
+				Name:      name,
+				Kind:      kind,
+				StartLine: countLines(content[:match[2]]),
+				Exported:  !ktPrivatePattern.MatchString(modifiers),
+				FilePath:  filename,
+			})
+		}
+	}
+
+	return symbols, nil
+}
+
 // Helper functions
 
+// splitParameters splits a parameter list on top-level commas (ignoring commas nested
+// in brackets) and drops empty entries and any names listed in skip
+func splitParameters(params string, skip ...string) []string {
+	var result []string
+	depth := 0
+	start := 0
+
+	appendParam := func(raw string) {
+		param := strings.Join(strings.Fields(raw), " ")
+		if param == "" {
+			return
+		}
+		for _, s := range skip {
+			if param == s {
+				return
+			}
+		}
+		result = append(result, param)
+	}
+
+	for i, ch := range params {
+		switch ch {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				appendParam(params[start:i])
+				start = i + 1
+			}
+		}
+	}
+	appendParam(params[start:])
+
+	return result
+}
+
 func countLines(s string) int {
 	return strings.Count(s, "\n") + 1
 }
@@ -102,6 +102,75 @@ const MaxUsers = 100
 	}
 }
 
+func TestParseGoTypeMembers(t *testing.T) {
+	parser := NewParser()
+
+	goCode := `package store
+
+type Store interface {
+	io.Closer
+	// Get returns the value for key
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+type Options struct {
+	*Base
+	Timeout, Retries int
+	cache            map[string]string
+}
+
+type internalOptions struct {
+	Enabled bool
+}
+`
+
+	symbols, err := parser.ParseFile("store.go", goCode)
+	if err != nil {
+		t.Fatalf("Failed to parse Go file: %v", err)
+	}
+
+	members := make(map[string]Symbol)
+	for _, s := range symbols {
+		if s.Parent != "" {
+			members[s.Parent+"."+s.Name] = s
+		}
+	}
+
+	get, ok := members["Store.Get"]
+	if !ok {
+		t.Fatalf("Expected Store.Get method, got %+v", symbols)
+	}
+	if get.Kind != SymbolMethod || !get.Exported || get.ReturnType != "[]byte, error" || len(get.Parameters) != 2 {
+		t.Errorf("Unexpected Store.Get symbol: %+v", get)
+	}
+	if get.Signature != "Get(ctx context.Context, key string) ([]byte, error)" {
+		t.Errorf("Unexpected Store.Get signature: %s", get.Signature)
+	}
+	if get.Doc != "Get returns the value for key\n" {
+		t.Errorf("Expected Store.Get doc comment, got %q", get.Doc)
+	}
+
+	for _, name := range []string{"Options.Base", "Options.Timeout", "Options.Retries"} {
+		field, ok := members[name]
+		if !ok {
+			t.Errorf("Expected field %s", name)
+			continue
+		}
+		if field.Kind != SymbolVariable || !field.Exported {
+			t.Errorf("Unexpected field symbol %s: %+v", name, field)
+		}
+	}
+	if members["Options.Timeout"].ValueType != "int" {
+		t.Errorf("Expected Timeout type int, got %q", members["Options.Timeout"].ValueType)
+	}
+	if _, ok := members["Options.cache"]; ok {
+		t.Error("Unexported fields should not be parsed")
+	}
+	if enabled, ok := members["internalOptions.Enabled"]; !ok || enabled.Exported {
+		t.Errorf("Fields of an unexported struct should not be exported, got %+v", enabled)
+	}
+}
+
 func TestParseTypeScriptFile(t *testing.T) {
 	parser := NewParser()
 
@@ -184,6 +253,75 @@ const DEFAULT_USER: User = { id: 0, name: 'guest' };
 	}
 }
 
+func TestParseTypeScriptDecoratorsAndDefaults(t *testing.T) {
+	parser := NewParser()
+
+	tsCode := `import { Component } from '@angular/core';
+
+@Component({
+  selector: 'app-root',
+  templateUrl: './app.component.html',
+})
+export default class AppComponent {
+  title = 'app';
+}
+
+@Injectable() export class UserService {}
+
+@Internal
+class Helper {}
+
+export default async function bootstrap(): Promise<void> {}
+
+declare class LocalGlobal {}
+`
+
+	symbols, err := parser.ParseFile("app.component.ts", tsCode)
+	if err != nil {
+		t.Fatalf("Failed to parse TypeScript file: %v", err)
+	}
+	symbolMap := make(map[string]Symbol)
+	for _, s := range symbols {
+		symbolMap[s.Name] = s
+	}
+
+	tests := []struct {
+		name      string
+		kind      SymbolKind
+		exported  bool
+		startLine int
+	}{
+		{"AppComponent", SymbolClass, true, 7},
+		{"UserService", SymbolClass, true, 11},
+		{"Helper", SymbolClass, false, 14},
+		{"bootstrap", SymbolFunction, true, 16},
+		{"LocalGlobal", SymbolClass, false, 18},
+	}
+	for _, tt := range tests {
+		symbol, ok := symbolMap[tt.name]
+		if !ok {
+			t.Errorf("Expected to find %s", tt.name)
+			continue
+		}
+		if symbol.Kind != tt.kind || symbol.Exported != tt.exported || symbol.StartLine != tt.startLine {
+			t.Errorf("%s: got kind=%s exported=%t line=%d, want kind=%s exported=%t line=%d",
+				tt.name, symbol.Kind, symbol.Exported, symbol.StartLine, tt.kind, tt.exported, tt.startLine)
+		}
+	}
+	if _, ok := symbolMap["Component"]; ok {
+		t.Error("Decorator should not be parsed as a symbol")
+	}
+
+	// Ambient declarations in a .d.ts file are part of its public surface
+	symbols, err = parser.ParseFile("globals.d.ts", "declare class LocalGlobal {}\nexport declare function init(): void;\n")
+	if err != nil {
+		t.Fatalf("Failed to parse declaration file: %v", err)
+	}
+	if len(symbols) != 2 || !symbols[0].Exported || !symbols[1].Exported {
+		t.Errorf("Expected exported ambient declarations, got %+v", symbols)
+	}
+}
+
 func TestParsePythonFile(t *testing.T) {
 	parser := NewParser()
 
@@ -241,6 +379,9 @@ DEFAULT_NAME = "guest"
 		if createUser.Kind != SymbolFunction {
 			t.Errorf("Expected create_user to be a function, got %s", createUser.Kind)
 		}
+		if len(createUser.Parameters) != 2 || createUser.Parameters[0] != "id: int" || createUser.Parameters[1] != "name: str" {
+			t.Errorf("Expected create_user parameters [id: int, name: str], got %v", createUser.Parameters)
+		}
 	}
 
 	// Check async function
@@ -420,6 +561,203 @@ class InternalHelper {
 	}
 }
 
+func TestParseCSharpFile(t *testing.T) {
+	parser := NewParser()
+
+	csCode := `namespace Example;
+
+public class User
+{
+    private int id;
+
+    public User(int id, string name)
+    {
+        this.id = id;
+        Name = name;
+    }
+
+    public string Name { get; set; }
+
+    public string GetName()
+    {
+        return Name;
+    }
+
+    private void Reset()
+    {
+        Name = "";
+    }
+}
+
+public interface IUserService
+{
+    User GetUser(int id);
+}
+
+internal struct Point
+{
+    public int X { get; }
+}
+
+public enum Role
+{
+    Admin,
+    Member
+}
+
+internal class InternalHelper
+{
+    internal static void Helper() {}
+}
+`
+
+	symbols, err := parser.ParseFile("User.cs", csCode)
+	if err != nil {
+		t.Fatalf("Failed to parse C# file: %v", err)
+	}
+
+	symbolMap := make(map[string]Symbol)
+	for _, s := range symbols {
+		symbolMap[s.Name] = s
+	}
+
+	// Check class, and that the constructor is not reported as a method
+	if user, ok := symbolMap["User"]; !ok {
+		t.Error("Expected to find User class")
+	} else {
+		if user.Kind != SymbolClass {
+			t.Errorf("Expected User to be a class, got %s", user.Kind)
+		}
+		if !user.Exported {
+			t.Error("Expected User to be exported (public)")
+		}
+	}
+
+	// Check interface, struct and enum
+	expectedKinds := map[string]SymbolKind{
+		"IUserService": SymbolInterface,
+		"Point":        SymbolStruct,
+		"Role":         SymbolType,
+		"Name":         SymbolVariable,
+		"GetName":      SymbolMethod,
+		"Reset":        SymbolMethod,
+	}
+	for name, kind := range expectedKinds {
+		if s, ok := symbolMap[name]; !ok {
+			t.Errorf("Expected to find %s", name)
+		} else if s.Kind != kind {
+			t.Errorf("Expected %s to be a %s, got %s", name, kind, s.Kind)
+		}
+	}
+
+	// Check visibility
+	if symbolMap["Reset"].Exported {
+		t.Error("Expected private Reset to NOT be exported")
+	}
+	if !symbolMap["GetName"].Exported {
+		t.Error("Expected public GetName to be exported")
+	}
+	if helper, ok := symbolMap["InternalHelper"]; !ok {
+		t.Error("Expected to find InternalHelper class")
+	} else if helper.Exported {
+		t.Error("Expected InternalHelper to NOT be exported")
+	}
+
+	for _, s := range symbols {
+		if s.Name == "User" && s.Kind == SymbolMethod {
+			t.Error("Expected constructor to be skipped")
+		}
+	}
+}
+
+func TestParseKotlinFile(t *testing.T) {
+	parser := NewParser()
+
+	ktCode := `package com.example.users
+
+const val MAX_USERS = 100
+private val cache = mutableMapOf<Int, User>()
+
+data class User(val id: Int, val name: String)
+
+interface UserRepository {
+    fun find(id: Int): User?
+}
+
+class UserService(private val repository: UserRepository) {
+    fun getUser(id: Int): User? {
+        return cache[id] ?: repository.find(id)
+    }
+
+    private fun evict(id: Int) {
+        cache.remove(id)
+    }
+
+    companion object Factory {
+        fun create(repository: UserRepository) = UserService(repository)
+    }
+}
+
+internal object Registry
+
+@JvmStatic
+fun createUser(id: Int, name: String): User {
+    return User(id, name)
+}
+
+fun List<User>.names(): List<String> = map { it.name }
+`
+
+	symbols, err := parser.ParseFile("UserService.kt", ktCode)
+	if err != nil {
+		t.Fatalf("Failed to parse Kotlin file: %v", err)
+	}
+
+	symbolMap := make(map[string]Symbol)
+	for _, s := range symbols {
+		symbolMap[s.Name] = s
+	}
+
+	tests := []struct {
+		name     string
+		kind     SymbolKind
+		exported bool
+	}{
+		{"MAX_USERS", SymbolConstant, true},
+		{"cache", SymbolVariable, false},
+		{"User", SymbolClass, true},
+		{"UserRepository", SymbolInterface, true},
+		{"find", SymbolMethod, true},
+		{"UserService", SymbolClass, true},
+		{"getUser", SymbolMethod, true},
+		{"evict", SymbolMethod, false},
+		{"Factory", SymbolClass, true},
+		{"Registry", SymbolClass, false},
+		{"createUser", SymbolFunction, true},
+		{"names", SymbolFunction, true},
+	}
+	for _, tt := range tests {
+		symbol, ok := symbolMap[tt.name]
+		if !ok {
+			t.Errorf("Expected to find %s", tt.name)
+			continue
+		}
+		if symbol.Kind != tt.kind {
+			t.Errorf("Expected %s to be a %s, got %s", tt.name, tt.kind, symbol.Kind)
+		}
+		if symbol.Exported != tt.exported {
+			t.Errorf("Expected %s exported=%t, got %t", tt.name, tt.exported, symbol.Exported)
+		}
+	}
+
+	if _, ok := symbolMap["id"]; ok {
+		t.Error("Constructor and member properties should not be parsed as top-level symbols")
+	}
+	if createUser := symbolMap["createUser"]; createUser.StartLine != 29 {
+		t.Errorf("Expected createUser on line 29, got %d", createUser.StartLine)
+	}
+}
+
 func TestGetLanguageFromFilename(t *testing.T) {
 	parser := NewParser()
 
@@ -435,6 +773,9 @@ func TestGetLanguageFromFilename(t *testing.T) {
 		{"script.py", "python"},
 		{"lib.rs", "rust"},
 		{"Main.java", "java"},
+		{"Program.cs", "csharp"},
+		{"MainActivity.kt", "kotlin"},
+		{"build.gradle.kts", "kotlin"},
 		{"README.md", ""},
 		{"config.yaml", ""},
 	}
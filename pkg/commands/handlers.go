@@ -41,12 +41,21 @@ type CommandContext struct {
 	CommentBody         string // The original comment from user
 	FilePath            string // File path if this is a review comment
 	FileLine            int    // Line number if this is a review comment
+	StartLine           int    // First line of a multi-line review comment, 0 for a single line
 	CodeContext         string // Surrounding code context
 	OriginalIssue       string // The original bot comment that user is replying to
 	Session             *state.Session
 	ConversationHistory []ConversationMessage // Previous messages in this thread
 }
 
+// requestContext returns the context for LLM requests made while handling the command
+func (c *CommandContext) requestContext() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
+}
+
 // CommandResult contains the result of executing a command
 type CommandResult struct {
 	Response      string
@@ -54,7 +63,13 @@ type CommandResult struct {
 	DismissIssue  bool
 	DismissedHash string
 	DismissReason string
+	ResolveIssue  bool
+	ResolvedHash  string
 	TriggerReview bool
+	ReviewFile    string // Changed file to review on its own, replying with the findings
+
+	DismissedFuzzyHash string // Matches the dismissed issue after edits nearby shift its lines
+	ResolvedFuzzyHash  string // Matches the resolved issue after edits nearby shift its lines
 }
 
 // Handle executes a command and returns the response
@@ -66,12 +81,16 @@ func (h *Handler) Handle(cmd Command, ctx *CommandContext) (*CommandResult, erro
 		return h.handleSuggestFix(cmd, ctx)
 	case CommandIgnore:
 		return h.handleIgnore(cmd, ctx)
+	case CommandResolve:
+		return h.handleResolve(cmd, ctx)
 	case CommandRegenerate:
 		return h.handleRegenerate(cmd, ctx)
 	case CommandHelp:
 		return h.handleHelp(cmd, ctx)
 	case CommandSummarize:
 		return h.handleSummarize(cmd, ctx)
+	case CommandReviewFile:
+		return h.handleReviewFile(cmd, ctx)
 	case CommandUnknown:
 		return h.handleUnknown(cmd, ctx)
 	default:
@@ -82,7 +101,7 @@ func (h *Handler) Handle(cmd Command, ctx *CommandContext) (*CommandResult, erro
 func (h *Handler) handleExplain(cmd Command, ctx *CommandContext) (*CommandResult, error) {
 	prompt := h.buildExplainPrompt(cmd, ctx)
 
-	response, err := h.AIClient.GenerateResponse(prompt)
+	response, err := h.AIClient.GenerateResponse(ctx.requestContext(), prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate explanation: %w", err)
 	}
@@ -96,7 +115,7 @@ func (h *Handler) handleExplain(cmd Command, ctx *CommandContext) (*CommandResul
 func (h *Handler) handleSuggestFix(cmd Command, ctx *CommandContext) (*CommandResult, error) {
 	prompt := h.buildSuggestFixPrompt(cmd, ctx)
 
-	response, err := h.AIClient.GenerateResponse(prompt)
+	response, err := h.AIClient.GenerateResponse(ctx.requestContext(), prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate fix suggestion: %w", err)
 	}
@@ -108,10 +127,13 @@ func (h *Handler) handleSuggestFix(cmd Command, ctx *CommandContext) (*CommandRe
 }
 
 func (h *Handler) handleIgnore(cmd Command, ctx *CommandContext) (*CommandResult, error) {
-	// Calculate the hash of the issue being dismissed
-	var hash string
+	// Calculate the hash of the issue being dismissed, matching the one the review filters on
+	var hash, fuzzyHash string
 	if ctx.FilePath != "" && ctx.FileLine > 0 && ctx.OriginalIssue != "" {
-		hash = state.ComputeCommentHash(ctx.FilePath, ctx.FileLine, ctx.FileLine, ctx.OriginalIssue)
+		start, end := ctx.issueLines()
+		content := IssueContent(ctx.OriginalIssue)
+		hash = state.ComputeCommentHash(ctx.FilePath, start, end, content)
+		fuzzyHash = state.ComputeFuzzyHash(ctx.FilePath, content)
 	}
 
 	reason := cmd.Args
@@ -127,6 +149,51 @@ func (h *Handler) handleIgnore(cmd Command, ctx *CommandContext) (*CommandResult
 		DismissIssue:  true,
 		DismissedHash: hash,
 		DismissReason: reason,
+
+		DismissedFuzzyHash: fuzzyHash,
+	}, nil
+}
+
+// IssueContent returns the text of a posted review comment without its bold header and
+// suggestion block, which is the comment content the review hashes
+func IssueContent(body string) string {
+	body = strings.TrimSpace(body)
+	if strings.HasPrefix(body, "**") {
+		if header, content, ok := strings.Cut(body, "\n\n"); ok && strings.HasSuffix(header, "**") {
+			body = content
+		}
+	}
+	if i := strings.Index(body, "\n\n```suggestion\n"); i >= 0 {
+		body = body[:i]
+	}
+	return strings.TrimSpace(body)
+}
+
+// issueLines returns the line range of the review comment, as the review hashed it
+func (c *CommandContext) issueLines() (start, end int) {
+	if c.StartLine > 0 {
+		return c.StartLine, c.FileLine
+	}
+	return c.FileLine, c.FileLine
+}
+
+func (h *Handler) handleResolve(_ Command, ctx *CommandContext) (*CommandResult, error) {
+	// Calculate the hash of the issue being resolved, matching the one the review filters on
+	var hash, fuzzyHash string
+	if ctx.FilePath != "" && ctx.FileLine > 0 && ctx.OriginalIssue != "" {
+		start, end := ctx.issueLines()
+		content := IssueContent(ctx.OriginalIssue)
+		hash = state.ComputeCommentHash(ctx.FilePath, start, end, content)
+		fuzzyHash = state.ComputeFuzzyHash(ctx.FilePath, content)
+	}
+
+	return &CommandResult{
+		Response:      "Thanks! I've marked this issue as resolved and won't flag it again in future reviews.",
+		UpdateSession: true,
+		ResolveIssue:  true,
+		ResolvedHash:  hash,
+
+		ResolvedFuzzyHash: fuzzyHash,
 	}, nil
 }
 
@@ -140,6 +207,14 @@ func (h *Handler) handleRegenerate(cmd Command, ctx *CommandContext) (*CommandRe
 	}, nil
 }
 
+// handleReviewFile asks for one file to be reviewed. The caller has the PR's diff, so it
+// checks the file is changed, runs the review and replies with the findings.
+func (h *Handler) handleReviewFile(cmd Command, _ *CommandContext) (*CommandResult, error) {
+	return &CommandResult{
+		ReviewFile: cmd.Args,
+	}, nil
+}
+
 func (h *Handler) handleHelp(_ Command, _ *CommandContext) (*CommandResult, error) {
 	return &CommandResult{
 		Response: GetHelpText(),
@@ -149,7 +224,7 @@ func (h *Handler) handleHelp(_ Command, _ *CommandContext) (*CommandResult, erro
 func (h *Handler) handleSummarize(cmd Command, ctx *CommandContext) (*CommandResult, error) {
 	prompt := h.buildSummarizePrompt(cmd, ctx)
 
-	response, err := h.AIClient.GenerateResponse(prompt)
+	response, err := h.AIClient.GenerateResponse(ctx.requestContext(), prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
@@ -164,7 +239,7 @@ func (h *Handler) handleUnknown(cmd Command, ctx *CommandContext) (*CommandResul
 	// Try to be helpful with unknown commands
 	prompt := h.buildConversationalPrompt(cmd, ctx)
 
-	response, err := h.AIClient.GenerateResponse(prompt)
+	response, err := h.AIClient.GenerateResponse(ctx.requestContext(), prompt)
 	if err != nil {
 		// Fallback to help message
 		return &CommandResult{
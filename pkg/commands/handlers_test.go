@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+func TestHandleResolve(t *testing.T) {
+	handler := NewHandler(nil, nil)
+	ctx := &CommandContext{
+		FilePath:      "main.go",
+		FileLine:      12,
+		StartLine:     10,
+		OriginalIssue: "**🟡 Nil check**\n\nPossible nil dereference\n\n```suggestion\nif u != nil {\n```",
+	}
+
+	result, err := handler.Handle(Command{Type: CommandResolve}, ctx)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	// The review hashes the comment's content over its full line range
+	want := state.ComputeCommentHash("main.go", 10, 12, "Possible nil dereference")
+	if !result.ResolveIssue || result.ResolvedHash != want {
+		t.Errorf("Expected issue %s to be resolved, got %+v", want, result)
+	}
+	if fuzzy := state.ComputeFuzzyHash("main.go", "Possible nil dereference"); result.ResolvedFuzzyHash != fuzzy {
+		t.Errorf("Expected fuzzy hash %s of the comment content, got %+v", fuzzy, result)
+	}
+	if result.DismissIssue || !result.UpdateSession || result.Response == "" {
+		t.Errorf("Expected a session update with a reply and no dismissal, got %+v", result)
+	}
+}
+
+func TestHandleIgnore_Hashes(t *testing.T) {
+	ctx := &CommandContext{
+		FilePath:      "main.go",
+		FileLine:      12,
+		StartLine:     10,
+		OriginalIssue: "**🟡 Nil check**\n\nPossible nil dereference\n\n```suggestion\nif u != nil {\n```",
+	}
+
+	result, err := NewHandler(nil, nil).Handle(Command{Type: CommandIgnore}, ctx)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	// The review hashes the comment's content, without the header and suggestion it was posted
+	// with, over its full line range
+	if want := state.ComputeCommentHash("main.go", 10, 12, "Possible nil dereference"); result.DismissedHash != want {
+		t.Errorf("Expected hash %s of the comment content, got %+v", want, result)
+	}
+	if want := state.ComputeFuzzyHash("main.go", "Possible nil dereference"); result.DismissedFuzzyHash != want {
+		t.Errorf("Expected fuzzy hash %s of the comment content, got %+v", want, result)
+	}
+}
+
+func TestHandleResolve_WithoutReviewComment(t *testing.T) {
+	result, err := NewHandler(nil, nil).Handle(Command{Type: CommandResolve}, &CommandContext{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if result.ResolvedHash != "" {
+		t.Errorf("Expected no hash outside a review comment thread, got %q", result.ResolvedHash)
+	}
+}
+
+func TestHandleReviewFile(t *testing.T) {
+	cmds := NewParser("manque").Parse("@manque review pkg/api/client.go", 1, "", 0)
+	if len(cmds) != 1 {
+		t.Fatalf("Expected one command, got %+v", cmds)
+	}
+
+	result, err := NewHandler(nil, nil).Handle(cmds[0], &CommandContext{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if result.ReviewFile != "pkg/api/client.go" || result.TriggerReview {
+		t.Errorf("Expected a review of pkg/api/client.go only, got %+v", result)
+	}
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -12,9 +14,11 @@ const (
 	CommandExplain    CommandType = "explain"
 	CommandSuggestFix CommandType = "suggest_fix"
 	CommandIgnore     CommandType = "ignore"
+	CommandResolve    CommandType = "resolve"
 	CommandRegenerate CommandType = "regenerate"
 	CommandHelp       CommandType = "help"
 	CommandSummarize  CommandType = "summarize"
+	CommandReviewFile CommandType = "review_file"
 	CommandUnknown    CommandType = "unknown"
 )
 
@@ -34,21 +38,47 @@ type Parser struct {
 	aliases []string
 }
 
-// NewParser creates a new command parser
-func NewParser(botName string) *Parser {
+// NewParser creates a new command parser. Extra aliases, such as a renamed bot's handle,
+// are added to the defaults; the "@" prefix is optional.
+func NewParser(botName string, extraAliases ...string) *Parser {
 	// Support common variations
-	aliases := []string{
-		"@" + botName,
-		"@" + strings.ToLower(botName),
-		"@manque",
-		"@manque-ai",
+	candidates := append([]string{botName, "manque", "manque-ai"}, extraAliases...)
+
+	seen := make(map[string]bool)
+	var aliases []string
+	for _, alias := range candidates {
+		alias = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(alias), "@"))
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, "@"+alias)
 	}
+
+	// Longest first, so "@manque-ai" is matched before "@manque"
+	sort.SliceStable(aliases, func(i, j int) bool { return len(aliases[i]) > len(aliases[j]) })
+
 	return &Parser{
 		botName: botName,
 		aliases: aliases,
 	}
 }
 
+// isHandleChar reports whether c can be part of a GitHub handle
+func isHandleChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// mentionsAt reports whether text has the whole handle alias at index i, so "@manque"
+// doesn't match "@manque-bot" and "me@manque.dev" isn't a mention
+func mentionsAt(text, alias string, i int) bool {
+	end := i + len(alias)
+	if !strings.HasPrefix(text[i:], alias) {
+		return false
+	}
+	return (i == 0 || !isHandleChar(text[i-1])) && (end == len(text) || !isHandleChar(text[end]))
+}
+
 // Parse extracts commands from a comment body
 func (p *Parser) Parse(body string, commentID int64, file string, lineNum int) []Command {
 	var cmds []Command
@@ -64,8 +94,9 @@ func (p *Parser) Parse(body string, commentID int64, file string, lineNum int) [
 		}
 
 		// Check if line starts with any of our aliases
+		lineLower := strings.ToLower(trimmedLine)
 		for _, alias := range p.aliases {
-			if strings.HasPrefix(strings.ToLower(trimmedLine), strings.ToLower(alias)) {
+			if mentionsAt(lineLower, alias, 0) {
 				// Extract the command part after the alias
 				cmdPart := strings.TrimSpace(trimmedLine[len(alias):])
 				cmd := p.parseCommand(cmdPart, commentID, file, lineNum)
@@ -107,7 +138,19 @@ func (p *Parser) parseCommand(text string, commentID int64, file string, line in
 		cmd.Type = CommandSuggestFix
 	case "ignore", "dismiss", "skip":
 		cmd.Type = CommandIgnore
-	case "regenerate", "rereview", "review", "re-review":
+	case "resolve", "resolved", "done", "fixed":
+		cmd.Type = CommandResolve
+	case "review", "check":
+		// A path narrows the review to one file; "review" alone reviews the whole PR again
+		if path := filePathArg(args); path != "" {
+			cmd.Type = CommandReviewFile
+			cmd.Args = path
+		} else if cmdWord == "review" {
+			cmd.Type = CommandRegenerate
+		} else {
+			cmd.Type = p.inferCommandType(text)
+		}
+	case "regenerate", "rereview", "re-review":
 		cmd.Type = CommandRegenerate
 	case "help", "?":
 		cmd.Type = CommandHelp
@@ -121,6 +164,19 @@ func (p *Parser) parseCommand(text string, commentID int64, file string, line in
 	return cmd
 }
 
+// filePathArg returns args as a file path, without surrounding backticks or quotes, or ""
+// when it doesn't look like one: a single word with a directory or an extension
+func filePathArg(args string) string {
+	path := strings.Trim(args, "`\"'")
+	if path == "" || strings.ContainsAny(path, " \t") {
+		return ""
+	}
+	if !strings.Contains(path, "/") && (filepath.Ext(path) == "" || filepath.Ext(path) == ".") {
+		return ""
+	}
+	return path
+}
+
 // inferCommandType tries to infer command type from natural language
 func (p *Parser) inferCommandType(text string) CommandType {
 	text = strings.ToLower(text)
@@ -137,6 +193,19 @@ func (p *Parser) inferCommandType(text string) CommandType {
 		}
 	}
 
+	// Resolution patterns, checked before fix patterns since "fixed" contains "fix". A
+	// question such as "how should this be fixed?" asks for help rather than resolving.
+	resolvePatterns := []string{
+		"fixed", "resolved", "addressed", "this is done",
+	}
+	if !strings.Contains(text, "?") {
+		for _, pattern := range resolvePatterns {
+			if strings.Contains(text, pattern) {
+				return CommandResolve
+			}
+		}
+	}
+
 	// Fix patterns
 	fixPatterns := []string{
 		"fix", "suggest", "how to fix", "how can i fix",
@@ -190,8 +259,15 @@ func ParseMention(body string) []string {
 func (p *Parser) IsBotMentioned(body string) bool {
 	bodyLower := strings.ToLower(body)
 	for _, alias := range p.aliases {
-		if strings.Contains(bodyLower, strings.ToLower(alias)) {
-			return true
+		for i := 0; ; {
+			j := strings.Index(bodyLower[i:], alias)
+			if j < 0 {
+				break
+			}
+			if mentionsAt(bodyLower, alias, i+j) {
+				return true
+			}
+			i += j + 1
 		}
 	}
 	return false
@@ -208,7 +284,9 @@ You can interact with me using the following commands:
 | ` + "`@manque explain`" + ` | Explain the code or issue in detail |
 | ` + "`@manque suggest fix`" + ` | Get a suggested fix for this issue |
 | ` + "`@manque ignore`" + ` | Dismiss this issue (won't be flagged again) |
+| ` + "`@manque resolve`" + ` | Mark this issue as fixed (won't be flagged again) |
 | ` + "`@manque regenerate`" + ` | Re-run the review for this PR |
+| ` + "`@manque review <path>`" + ` | Review one changed file again, e.g. after addressing its comments |
 | ` + "`@manque summarize`" + ` | Get a summary of the changes |
 | ` + "`@manque help`" + ` | Show this help message |
 
@@ -17,8 +17,8 @@ func TestParserIsBotMentioned(t *testing.T) {
 		{"@MANQUE ignore", true},
 		{"No mention here", false},
 		{"manque without @", false},
-		// Note: email addresses will match due to simple substring check
-		// This is acceptable as we parse for actual commands after
+		{"Mail me@manque.dev", false},
+		{"@manque-bot is another bot", false},
 	}
 
 	for _, tt := range tests {
@@ -44,8 +44,16 @@ func TestParserParse(t *testing.T) {
 		{"@manque fix this issue", CommandSuggestFix, "this issue"}, // "fix" maps to suggest_fix
 		{"@manque ignore false positive", CommandIgnore, "false positive"},
 		{"@manque dismiss", CommandIgnore, ""},
+		{"@manque resolve", CommandResolve, ""},
+		{"@manque done", CommandResolve, ""},
+		{"@manque fixed in abc123", CommandResolve, "in abc123"},
 		{"@manque regenerate", CommandRegenerate, ""},
 		{"@manque rereview", CommandRegenerate, ""},
+		{"@manque review", CommandRegenerate, ""},
+		{"@manque review again", CommandRegenerate, "again"},
+		{"@manque review pkg/api/client.go", CommandReviewFile, "pkg/api/client.go"},
+		{"@manque check `main.go`", CommandReviewFile, "main.go"},
+		{"@manque review build/Dockerfile", CommandReviewFile, "build/Dockerfile"},
 		{"@manque help", CommandHelp, ""},
 		{"@manque ?", CommandHelp, ""},
 		{"@manque summarize", CommandSummarize, ""},
@@ -111,6 +119,10 @@ func TestParserNaturalLanguage(t *testing.T) {
 		{"@manque please recommend a fix", CommandSuggestFix},
 		{"@manque this is a false positive", CommandIgnore},
 		{"@manque not an issue in our codebase", CommandIgnore},
+		{"@manque I fixed this in the last commit", CommandResolve},
+		{"@manque this has been addressed", CommandResolve},
+		{"@manque how should this be fixed?", CommandSuggestFix},
+		{"@manque has this been addressed?", CommandUnknown},
 		{"@manque give me an overview", CommandSummarize},
 	}
 
@@ -160,6 +172,7 @@ func TestGetHelpText(t *testing.T) {
 		"@manque explain",
 		"@manque suggest",
 		"@manque ignore",
+		"@manque resolve",
 		"@manque regenerate",
 		"@manque help",
 	}
@@ -183,3 +196,32 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestParserCustomAliases(t *testing.T) {
+	parser := NewParser("manque", "@acme-reviewer", "AcmeBot", " ")
+
+	tests := []struct {
+		body     string
+		expected bool
+	}{
+		{"@acme-reviewer explain", true},
+		{"Hey @ACMEBOT, can you help?", true},
+		{"@manque help", true}, // Defaults are kept
+		{"acme-reviewer explain", false},
+		{"@acme-reviewers explain", false},
+	}
+	for _, tt := range tests {
+		if result := parser.IsBotMentioned(tt.body); result != tt.expected {
+			t.Errorf("IsBotMentioned(%q) = %v, want %v", tt.body, result, tt.expected)
+		}
+	}
+
+	cmds := parser.Parse("@acme-reviewer explain this function", 1, "", 0)
+	if len(cmds) != 1 || cmds[0].Type != CommandExplain || cmds[0].Args != "this function" {
+		t.Errorf("Expected explain command from custom alias, got %+v", cmds)
+	}
+	cmds = parser.Parse("@manque-ai summarize", 1, "", 0)
+	if len(cmds) != 1 || cmds[0].Type != CommandSummarize {
+		t.Errorf("Expected @manque-ai to match as a whole alias, got %+v", cmds)
+	}
+}
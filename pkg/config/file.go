@@ -1,8 +1,15 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,23 +18,29 @@ import (
 type FileConfig struct {
 	Version int `yaml:"version"`
 
-	Review ReviewConfig `yaml:"review"`
-	Ignore []string     `yaml:"ignore"`
-	Rules  []PathRule   `yaml:"rules"`
+	Review     ReviewConfig `yaml:"review"`
+	Include    []string     `yaml:"include,omitempty"` // Only review matching files; applied before ignore
+	Ignore     []string     `yaml:"ignore"`
+	Rules      []PathRule   `yaml:"rules"`
+	BotAliases []string     `yaml:"bot_aliases,omitempty"` // Extra handles for bot commands, e.g. "@acme-reviewer"
+
+	setKeys map[string]bool // Dotted paths of the keys present in the file
 }
 
 // ReviewConfig contains review-specific settings
 type ReviewConfig struct {
-	AutoApproveThreshold int  `yaml:"auto_approve_threshold"`
-	BlockOnCritical      bool `yaml:"block_on_critical"`
+	AutoApproveThreshold int               `yaml:"auto_approve_threshold"`
+	BlockOnCritical      bool              `yaml:"block_on_critical"`
+	LabelTones           map[string]string `yaml:"label_tones,omitempty"` // Tone per comment label
 }
 
 // PathRule defines rules for specific file paths
 type PathRule struct {
 	Path             string `yaml:"path"`
-	SeverityOverride string `yaml:"severity_override,omitempty"` // "suggestion", "warning", "critical"
+	SeverityOverride string `yaml:"severity_override,omitempty"` // "strict", "relaxed" or "off"
 	ExtraRules       string `yaml:"extra_rules,omitempty"`       // Additional rules as text
 	Ignore           bool   `yaml:"ignore,omitempty"`            // Ignore files matching this path
+	SkipTestCheck    bool   `yaml:"skip_test_check,omitempty"`   // Exempt from the REQUIRE_TESTS check
 }
 
 // DefaultConfig returns the default configuration
@@ -51,21 +64,226 @@ func DefaultConfig() *FileConfig {
 	}
 }
 
-// LoadFromFile loads configuration from a YAML file
+// LoadFromFile loads configuration from a YAML file. Unknown keys are skipped and values of
+// the wrong type keep their defaults; ValidateFile reports both.
 func LoadFromFile(path string) (*FileConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return Parse(data)
+}
+
+// ValidateFile checks a YAML config file for unknown keys and values of the wrong type,
+// returning a *ValidationError listing them
+func ValidateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
 
+	err = Validate(data)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Path = path
+	}
+	return err
+}
+
+// Parse decodes YAML configuration over the defaults. Unknown keys are skipped and values of
+// the wrong type keep their defaults, so one typo doesn't drop the rest of the file; only
+// malformed YAML is an error.
+func Parse(data []byte) (*FileConfig, error) {
 	config := DefaultConfig()
+	var problems []Problem
 	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, err
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+		problems = describeProblems(data, typeErr.Errors)
 	}
 
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err == nil {
+		config.setKeys = make(map[string]bool)
+		collectKeys(&root, "", config.setKeys)
+		// Mistyped keys keep their defaults, so they don't count as set
+		for _, problem := range problems {
+			delete(config.setKeys, problem.Key)
+		}
+	}
 	return config, nil
 }
 
+// Validate decodes YAML configuration strictly, returning a *ValidationError listing its
+// unknown keys and values of the wrong type
+func Validate(data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(DefaultConfig()); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil // Empty file
+		}
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return &ValidationError{Problems: describeProblems(data, typeErr.Errors)}
+		}
+		return err
+	}
+	return nil
+}
+
+// IsSet reports whether a key, given as a dotted path like "review.block_on_critical", was
+// present in the parsed file rather than taken from the defaults
+func (c *FileConfig) IsSet(key string) bool {
+	return c.setKeys[key]
+}
+
+// collectKeys records the dotted path of every mapping key in the document
+func collectKeys(node *yaml.Node, path string, keys map[string]bool) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collectKeys(child, path, keys)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := node.Content[i].Value
+			if path != "" {
+				keyPath = path + "." + keyPath
+			}
+			keys[keyPath] = true
+			collectKeys(node.Content[i+1], keyPath, keys)
+		}
+	}
+}
+
+// Problem is an unknown or mistyped key in a config file
+type Problem struct {
+	Key     string // Dotted path to the key, e.g. "rules[0].severity_override"
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Key == "" {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Key, p.Message)
+}
+
+// ValidationError lists every problem found decoding a config file
+type ValidationError struct {
+	Path     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = problem.String()
+	}
+	prefix := "invalid config"
+	if e.Path != "" {
+		prefix = "invalid config " + e.Path
+	}
+	return prefix + ": " + strings.Join(problems, "; ")
+}
+
+var (
+	unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type`)
+	wrongTypePattern    = regexp.MustCompile("^line (\\d+): cannot unmarshal !!(\\w+)( `.*`)? into (.+)$")
+)
+
+// describeProblems turns yaml.v3's decoding errors, which only carry a line, into problems
+// naming the offending key
+func describeProblems(data []byte, errs []string) []Problem {
+	var root yaml.Node
+	_ = yaml.Unmarshal(data, &root)
+	keys := make(map[int]string)
+	indexKeys(&root, "", keys)
+
+	problems := make([]Problem, 0, len(errs))
+	for _, msg := range errs {
+		if m := unknownFieldPattern.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			problems = append(problems, Problem{Key: keys[line], Line: line, Message: "unknown field"})
+			continue
+		}
+		if m := wrongTypePattern.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			problems = append(problems, Problem{
+				Key:     keys[line],
+				Line:    line,
+				Message: fmt.Sprintf("expected %s, got %s%s", goTypeName(m[4]), yamlTagName(m[2]), m[3]),
+			})
+			continue
+		}
+		line := 0
+		if _, err := fmt.Sscanf(msg, "line %d:", &line); err == nil {
+			msg = strings.TrimSpace(strings.SplitN(msg, ":", 2)[1])
+		}
+		problems = append(problems, Problem{Key: keys[line], Line: line, Message: msg})
+	}
+	return problems
+}
+
+// indexKeys maps each line of the document to the path of the deepest key or list item on it
+func indexKeys(node *yaml.Node, path string, keys map[int]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			indexKeys(child, path, keys)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			keys[key.Line] = keyPath
+			keys[value.Line] = keyPath
+			indexKeys(value, keyPath, keys)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			keys[item.Line] = itemPath
+			indexKeys(item, itemPath, keys)
+		}
+	}
+}
+
+// yamlTagName names a YAML tag, e.g. "str", in the terms used in problems
+func yamlTagName(tag string) string {
+	switch tag {
+	case "str":
+		return "string"
+	case "map":
+		return "mapping"
+	case "seq":
+		return "list"
+	case "float":
+		return "number"
+	default:
+		return tag
+	}
+}
+
+// goTypeName names the Go type a value was decoded into, e.g. "[]string", in YAML terms
+func goTypeName(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return "list"
+	case strings.HasPrefix(typ, "map["), strings.HasPrefix(typ, "config."):
+		return "mapping"
+	default:
+		return typ
+	}
+}
+
 // FindConfigFile searches for a .manque.yml file in the current directory and parent directories
 func FindConfigFile(startDir string) (string, error) {
 	configNames := []string{".manque.yml", ".manque.yaml", "manque.yml", "manque.yaml"}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +39,8 @@ version: 1
 review:
   auto_approve_threshold: 85
   block_on_critical: false
+include:
+  - "services/api/**"
 ignore:
   - "*.test.js"
   - "**/__mocks__/**"
@@ -45,6 +50,8 @@ rules:
   - path: "src/api/**"
     extra_rules: |
       - All endpoints must have OpenAPI docs
+bot_aliases:
+  - "@acme-reviewer"
 `
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to write test config: %v", err)
@@ -63,6 +70,10 @@ rules:
 		t.Error("Expected block_on_critical to be false")
 	}
 
+	if len(config.Include) != 1 || config.Include[0] != "services/api/**" {
+		t.Errorf("Expected include pattern services/api/**, got %v", config.Include)
+	}
+
 	if len(config.Ignore) != 2 {
 		t.Errorf("Expected 2 ignore patterns, got %d", len(config.Ignore))
 	}
@@ -70,6 +81,124 @@ rules:
 	if len(config.Rules) != 2 {
 		t.Errorf("Expected 2 rules, got %d", len(config.Rules))
 	}
+
+	if len(config.BotAliases) != 1 || config.BotAliases[0] != "@acme-reviewer" {
+		t.Errorf("Expected bot alias @acme-reviewer, got %v", config.BotAliases)
+	}
+}
+
+func TestValidateFile_UnknownField(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".manque.yml")
+	configContent := `version: 1
+review:
+  auto_aprove_threshold: 85
+rules:
+  - path: "src/**"
+    severity: strict
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	err := ValidateFile(configPath)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	if validationErr.Path != configPath {
+		t.Errorf("Expected path %s, got %s", configPath, validationErr.Path)
+	}
+
+	expected := []Problem{
+		{Key: "review.auto_aprove_threshold", Line: 3, Message: "unknown field"},
+		{Key: "rules[0].severity", Line: 6, Message: "unknown field"},
+	}
+	if !reflect.DeepEqual(validationErr.Problems, expected) {
+		t.Errorf("Expected problems %+v, got %+v", expected, validationErr.Problems)
+	}
+}
+
+func TestValidateFile_WrongType(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".manque.yml")
+	configContent := `review:
+  auto_approve_threshold: high
+  block_on_critical: yes please
+ignore: "*.lock"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	err := ValidateFile(configPath)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	expected := []Problem{
+		{Key: "review.auto_approve_threshold", Line: 2, Message: "expected int, got string `high`"},
+		{Key: "review.block_on_critical", Line: 3, Message: "expected bool, got string `yes please`"},
+		{Key: "ignore", Line: 4, Message: "expected list, got string `*.lock`"},
+	}
+	if !reflect.DeepEqual(validationErr.Problems, expected) {
+		t.Errorf("Expected problems %+v, got %+v", expected, validationErr.Problems)
+	}
+	if !strings.Contains(err.Error(), "line 2: review.auto_approve_threshold: expected int") {
+		t.Errorf("Expected the error to point at the key, got %q", err.Error())
+	}
+}
+
+func TestParse_SkipsInvalidKeys(t *testing.T) {
+	config, err := Parse([]byte(`review:
+  auto_aprove_threshold: 85
+  block_on_critical: yes please
+ignore: ["*.snap"]
+bot_aliases: ["@acme-reviewer"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(config.Ignore) != 1 || config.Ignore[0] != "*.snap" || len(config.BotAliases) != 1 {
+		t.Errorf("Expected the valid keys to be applied, got ignore %v and aliases %v", config.Ignore, config.BotAliases)
+	}
+	if !config.Review.BlockOnCritical || config.IsSet("review.block_on_critical") {
+		t.Error("Expected the mistyped key to keep its default and not count as set")
+	}
+	if config.Review.AutoApproveThreshold != 90 {
+		t.Errorf("Expected the misspelled key to be skipped, got threshold %d", config.Review.AutoApproveThreshold)
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	if err := Validate([]byte("version: 1\nreview:\n  block_on_critical: false\n")); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+	if err := Validate(nil); err != nil {
+		t.Errorf("Expected an empty config to be valid, got %v", err)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	config, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if config.Review.AutoApproveThreshold != 90 {
+		t.Errorf("Expected defaults for an empty file, got %+v", config.Review)
+	}
+}
+
+func TestParse_IsSet(t *testing.T) {
+	config, err := Parse([]byte("review:\n  block_on_critical: false\nignore: []\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !config.IsSet("review.block_on_critical") || !config.IsSet("ignore") {
+		t.Error("Expected keys in the file to be reported as set")
+	}
+	if config.IsSet("review.auto_approve_threshold") || config.IsSet("include") {
+		t.Error("Expected keys left to the defaults to be reported as unset")
+	}
 }
 
 func TestFindConfigFile(t *testing.T) {
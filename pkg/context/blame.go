@@ -1,6 +1,7 @@
 package context
 
 import (
+	stdcontext "context"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -37,14 +38,48 @@ var (
 
 // GetBlameInfo runs git blame and extracts information about a file
 func GetBlameInfo(filename string, startLine, endLine int) (*BlameInfo, error) {
-	// Run git blame for the specific line range
-	args := []string{"blame", "-l", "--date=iso"}
+	return GetBlameInfoInDir(stdcontext.Background(), "", filename, startLine, endLine)
+}
+
+// GetBlameInfoInDir runs git blame from dir (the current directory if empty). git is killed
+// when ctx is done, since blaming a huge file can take minutes.
+func GetBlameInfoInDir(ctx stdcontext.Context, dir, filename string, startLine, endLine int) (*BlameInfo, error) {
+	var ranges []LineRange
 	if startLine > 0 && endLine > 0 {
-		args = append(args, fmt.Sprintf("-L%d,%d", startLine, endLine))
+		ranges = append(ranges, LineRange{Start: startLine, End: endLine})
+	}
+	return GetBlameInfoForRanges(ctx, dir, filename, ranges)
+}
+
+// LineRange is an inclusive range of line numbers
+type LineRange struct {
+	Start, End int
+}
+
+// ChangedLineRanges merges sorted line numbers into ranges of consecutive lines
+func ChangedLineRanges(lines []int) []LineRange {
+	var ranges []LineRange
+	for _, line := range lines {
+		if n := len(ranges); n > 0 && line <= ranges[n-1].End+1 {
+			ranges[n-1].End = max(ranges[n-1].End, line)
+			continue
+		}
+		ranges = append(ranges, LineRange{Start: line, End: line})
+	}
+	return ranges
+}
+
+// GetBlameInfoForRanges blames only the given line ranges of a file, the whole file if there
+// are none, with a single git blame call
+func GetBlameInfoForRanges(ctx stdcontext.Context, dir, filename string, ranges []LineRange) (*BlameInfo, error) {
+	args := []string{"blame", "-l", "--date=iso"}
+	for _, r := range ranges {
+		args = append(args, fmt.Sprintf("-L%d,%d", r.Start, r.End))
 	}
 	args = append(args, "--", filename)
 
-	cmd := exec.Command("git", args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git blame failed: %w", err)
@@ -101,15 +136,17 @@ func parseBlameOutput(filename, output string) (*BlameInfo, error) {
 
 // GetFileBlameContext gets blame context for a file diff
 func GetFileBlameContext(filename string, changedLines []int) string {
+	return GetFileBlameContextInDir(stdcontext.Background(), "", filename, changedLines)
+}
+
+// GetFileBlameContextInDir gets blame context for a file diff, running git from dir. It is
+// empty when git fails or ctx is done first.
+func GetFileBlameContextInDir(ctx stdcontext.Context, dir, filename string, changedLines []int) string {
 	if len(changedLines) == 0 {
 		return ""
 	}
 
-	// Get blame for the first and last changed line
-	startLine := changedLines[0]
-	endLine := changedLines[len(changedLines)-1]
-
-	info, err := GetBlameInfo(filename, startLine, endLine)
+	info, err := GetBlameInfoForRanges(ctx, dir, filename, ChangedLineRanges(changedLines))
 	if err != nil {
 		return ""
 	}
@@ -130,6 +167,15 @@ func GetFileBlameContext(filename string, changedLines []int) string {
 	return context.String()
 }
 
+// IsGitCheckout reports whether dir (the current directory if empty) is inside a git work
+// tree, so blame isn't attempted where it can only fail
+func IsGitCheckout(ctx stdcontext.Context, dir string) bool {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--is-inside-work-tree")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
 // FormatBlameContext formats blame information for inclusion in the prompt
 func FormatBlameContext(files map[string]string) string {
 	if len(files) == 0 {
@@ -163,9 +209,9 @@ func GetChangedLineNumbers(lines []struct {
 }
 
 // GetLogForFile gets recent commit history for a file
-func GetLogForFile(filename string, limit int) ([]string, error) {
+func GetLogForFile(ctx stdcontext.Context, filename string, limit int) ([]string, error) {
 	args := []string{"log", "--oneline", fmt.Sprintf("-n%d", limit), "--", filename}
-	cmd := exec.Command("git", args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -176,11 +222,11 @@ func GetLogForFile(filename string, limit int) ([]string, error) {
 }
 
 // GetFileStability determines if a file is stable (not frequently changed)
-func GetFileStability(filename string) (stable bool, commitCount int, err error) {
+func GetFileStability(ctx stdcontext.Context, filename string) (stable bool, commitCount int, err error) {
 	// Count commits in the last 30 days
 	since := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
 	args := []string{"log", "--oneline", fmt.Sprintf("--since=%s", since), "--", filename}
-	cmd := exec.Command("git", args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return false, 0, err
@@ -0,0 +1,31 @@
+package context
+
+import (
+	stdcontext "context"
+	"reflect"
+	"testing"
+)
+
+func TestChangedLineRanges(t *testing.T) {
+	tests := []struct {
+		lines []int
+		want  []LineRange
+	}{
+		{nil, nil},
+		{[]int{5}, []LineRange{{5, 5}}},
+		{[]int{3, 4, 5, 10, 11, 40}, []LineRange{{3, 5}, {10, 11}, {40, 40}}},
+		{[]int{7, 7, 8}, []LineRange{{7, 8}}},
+	}
+
+	for _, tt := range tests {
+		if got := ChangedLineRanges(tt.lines); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ChangedLineRanges(%v) = %v, want %v", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestIsGitCheckout_NotARepo(t *testing.T) {
+	if IsGitCheckout(stdcontext.Background(), t.TempDir()) {
+		t.Error("Expected an empty temp dir not to be a git checkout")
+	}
+}
@@ -30,6 +30,17 @@ type FetchedFile struct {
 type Fetcher struct {
 	RootDir  string
 	Resolver *Resolver
+
+	// PathPrefix is the location of RootDir within the repository, used to translate
+	// repo-relative diff paths when running from a monorepo subdirectory
+	PathPrefix string
+
+	// Depth is how many import hops to follow from the changed files. 1 fetches only
+	// direct imports, 2 also fetches the imports of those files, and so on.
+	Depth int
+	// MaxFiles and MaxBytes bound the total referenced context across all depths
+	MaxFiles int
+	MaxBytes int
 }
 
 // NewFetcher creates a new context fetcher
@@ -37,52 +48,74 @@ func NewFetcher(rootDir string) *Fetcher {
 	return &Fetcher{
 		RootDir:  rootDir,
 		Resolver: NewResolver(rootDir),
+		Depth:    1,
+		MaxFiles: MaxFilesToFetch,
+		MaxBytes: MaxTotalContextSize,
 	}
 }
 
-// FetchReferencedFiles extracts imports from changed files and fetches their content
+// pendingFile is a file whose imports are still to be followed
+type pendingFile struct {
+	localPath string
+	content   string
+}
+
+// FetchReferencedFiles extracts imports from changed files and fetches their content,
+// following imports of fetched files breadth-first up to the configured depth. Each file
+// is fetched at most once, so import cycles terminate.
 func (f *Fetcher) FetchReferencedFiles(files []diff.FileDiff) []FetchedFile {
 	var fetched []FetchedFile
 	seen := make(map[string]bool)
 	totalSize := 0
 
+	// Start from the new content of the changed files
+	var level []pendingFile
 	for _, file := range files {
-		if len(fetched) >= MaxFilesToFetch {
-			break
-		}
-
-		// Get the new content from the diff
 		content := f.extractNewContent(file)
 		if content == "" {
 			continue
 		}
+		level = append(level, pendingFile{localPath: ToLocalPath(f.PathPrefix, file.Filename), content: content})
+	}
 
-		// Extract imports from the changed file
-		imports := f.Resolver.ExtractImports(file.Filename, content)
-
-		for _, imp := range imports {
-			if seen[imp.ResolvedPath] || imp.ResolvedPath == "" {
-				continue
-			}
-			if len(fetched) >= MaxFilesToFetch {
-				break
-			}
+	for depth := 1; depth <= f.Depth && len(level) > 0; depth++ {
+		var next []pendingFile
 
-			// Fetch the file content
-			fetchedFile, err := f.fetchFile(imp.ResolvedPath, imp.Language)
-			if err != nil {
-				continue
+		for _, file := range level {
+			if len(fetched) >= f.MaxFiles {
+				return fetched
 			}
 
-			// Check size limits
-			if totalSize+fetchedFile.Size > MaxTotalContextSize {
-				continue
+			// Extract imports, resolving relative to the local checkout
+			imports := f.Resolver.ExtractImports(file.localPath, file.content)
+
+			for _, imp := range imports {
+				if seen[imp.ResolvedPath] || imp.ResolvedPath == "" {
+					continue
+				}
+				if len(fetched) >= f.MaxFiles {
+					break
+				}
+
+				// Fetch the file content
+				fetchedFile, err := f.fetchFile(imp.ResolvedPath, imp.Language)
+				if err != nil {
+					continue
+				}
+
+				// Check size limits
+				if totalSize+fetchedFile.Size > f.MaxBytes {
+					continue
+				}
+
+				seen[imp.ResolvedPath] = true
+				fetched = append(fetched, *fetchedFile)
+				totalSize += fetchedFile.Size
+				next = append(next, pendingFile{localPath: imp.ResolvedPath, content: fetchedFile.Content})
 			}
-
-			seen[imp.ResolvedPath] = true
-			fetched = append(fetched, *fetchedFile)
-			totalSize += fetchedFile.Size
 		}
+
+		level = next
 	}
 
 	return fetched
@@ -104,6 +137,15 @@ func (f *Fetcher) extractNewContent(file diff.FileDiff) string {
 	return builder.String()
 }
 
+// ReadFile reads a changed file from the local checkout by its repo-relative path
+func (f *Fetcher) ReadFile(repoPath string) (string, error) {
+	fetched, err := f.fetchFile(ToLocalPath(f.PathPrefix, repoPath), "")
+	if err != nil {
+		return "", err
+	}
+	return fetched.Content, nil
+}
+
 // fetchFile reads a file and returns its content
 func (f *Fetcher) fetchFile(relPath, language string) (*FetchedFile, error) {
 	fullPath := filepath.Join(f.RootDir, relPath)
@@ -123,7 +165,7 @@ func (f *Fetcher) fetchFile(relPath, language string) (*FetchedFile, error) {
 	}
 
 	return &FetchedFile{
-		Path:     relPath,
+		Path:     ToRepoPath(f.PathPrefix, relPath),
 		Content:  string(content),
 		Language: language,
 		Size:     len(content),
@@ -0,0 +1,127 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// writeFiles creates files under dir from a map of relative path to content
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// changedFile builds a file diff whose new content is a single added line
+func changedFile(filename, content string) diff.FileDiff {
+	return diff.FileDiff{
+		Filename: filename,
+		Hunks: []diff.Hunk{{
+			Lines: []diff.Line{{Type: diff.LineAdded, Content: content, NewNum: 1}},
+		}},
+	}
+}
+
+func fetchedPaths(files []FetchedFile) map[string]bool {
+	paths := make(map[string]bool)
+	for _, file := range files {
+		paths[file.Path] = true
+	}
+	return paths
+}
+
+func TestFetchReferencedFiles_Depth(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"app.js": "import { b } from './b'",
+		"b.js":   "import { c } from './c'",
+		"c.js":   "import { d } from './d'",
+		"d.js":   "export const d = 1",
+	})
+	changed := []diff.FileDiff{changedFile("app.js", "import { b } from './b'")}
+
+	tests := []struct {
+		depth    int
+		expected []string
+	}{
+		{0, nil},
+		{1, []string{"b.js"}},
+		{2, []string{"b.js", "c.js"}},
+		{5, []string{"b.js", "c.js", "d.js"}},
+	}
+
+	for _, tt := range tests {
+		fetcher := NewFetcher(tmpDir)
+		fetcher.Depth = tt.depth
+
+		fetched := fetcher.FetchReferencedFiles(changed)
+		if len(fetched) != len(tt.expected) {
+			t.Errorf("depth %d: expected %d files, got %d", tt.depth, len(tt.expected), len(fetched))
+			continue
+		}
+		paths := fetchedPaths(fetched)
+		for _, path := range tt.expected {
+			if !paths[path] {
+				t.Errorf("depth %d: expected %s to be fetched", tt.depth, path)
+			}
+		}
+	}
+}
+
+func TestFetchReferencedFiles_Cycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a.js": "import { b } from './b'",
+		"b.js": "import { a } from './a'",
+	})
+
+	fetcher := NewFetcher(tmpDir)
+	fetcher.Depth = 10
+
+	fetched := fetcher.FetchReferencedFiles([]diff.FileDiff{changedFile("a.js", "import { b } from './b'")})
+	if len(fetched) != 2 {
+		t.Fatalf("expected each file in the cycle once, got %d files", len(fetched))
+	}
+}
+
+func TestFetchReferencedFiles_Budget(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"b.js": "import { c } from './c'\nimport { d } from './d'",
+		"c.js": "export const c = 1",
+		"d.js": "export const d = 1",
+	})
+	changed := []diff.FileDiff{changedFile("app.js", "import { b } from './b'")}
+
+	fetcher := NewFetcher(tmpDir)
+	fetcher.Depth = 2
+	fetcher.MaxFiles = 2
+	if fetched := fetcher.FetchReferencedFiles(changed); len(fetched) != 2 {
+		t.Errorf("expected file budget to cap at 2, got %d", len(fetched))
+	}
+
+	fetcher = NewFetcher(tmpDir)
+	fetcher.Depth = 2
+	fetcher.MaxBytes = 70
+	fetched := fetcher.FetchReferencedFiles(changed)
+	total := 0
+	for _, file := range fetched {
+		total += file.Size
+	}
+	if total > 70 {
+		t.Errorf("expected byte budget of 70, got %d bytes", total)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("expected b.js and one of its imports within the byte budget, got %d files", len(fetched))
+	}
+}
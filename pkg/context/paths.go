@@ -0,0 +1,40 @@
+package context
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ToLocalPath translates a repo-relative diff path into a path relative to the local
+// checkout, given the checkout's location within the repository (e.g. "services/api").
+// Paths outside the prefix are returned with ".." segments so a full checkout still resolves.
+func ToLocalPath(pathPrefix, repoPath string) string {
+	prefix := normalizePrefix(pathPrefix)
+	if prefix == "" {
+		return repoPath
+	}
+
+	rel, err := filepath.Rel(prefix, filepath.FromSlash(repoPath))
+	if err != nil {
+		return repoPath
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ToRepoPath translates a path relative to the local checkout back into a repo-relative path
+func ToRepoPath(pathPrefix, localPath string) string {
+	prefix := normalizePrefix(pathPrefix)
+	if prefix == "" {
+		return localPath
+	}
+	return filepath.ToSlash(filepath.Join(prefix, filepath.FromSlash(localPath)))
+}
+
+// normalizePrefix cleans a path prefix, treating "", "." and "/" as the repository root
+func normalizePrefix(pathPrefix string) string {
+	prefix := strings.Trim(filepath.ToSlash(pathPrefix), "/")
+	if prefix == "" || prefix == "." {
+		return ""
+	}
+	return filepath.Clean(filepath.FromSlash(prefix))
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestToLocalPath(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		repoPath string
+		expected string
+	}{
+		{"", "services/api/main.go", "services/api/main.go"},
+		{".", "main.go", "main.go"},
+		{"services/api", "services/api/main.go", "main.go"},
+		{"services/api/", "services/api/handlers/user.go", "handlers/user.go"},
+		{"/services/api", "services/web/app.ts", "../web/app.ts"},
+	}
+
+	for _, tt := range tests {
+		result := ToLocalPath(tt.prefix, tt.repoPath)
+		if result != tt.expected {
+			t.Errorf("ToLocalPath(%q, %q) = %q, want %q", tt.prefix, tt.repoPath, result, tt.expected)
+		}
+	}
+}
+
+func TestToRepoPath(t *testing.T) {
+	tests := []struct {
+		prefix    string
+		localPath string
+		expected  string
+	}{
+		{"", "main.go", "main.go"},
+		{"services/api", "main.go", "services/api/main.go"},
+		{"services/api", "../web/app.ts", "services/web/app.ts"},
+	}
+
+	for _, tt := range tests {
+		result := ToRepoPath(tt.prefix, tt.localPath)
+		if result != tt.expected {
+			t.Errorf("ToRepoPath(%q, %q) = %q, want %q", tt.prefix, tt.localPath, result, tt.expected)
+		}
+	}
+}
+
+func TestFetchReferencedFilesWithPathPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The local checkout is the "services/api" subdirectory of the repository
+	if err := os.WriteFile(filepath.Join(tmpDir, "utils.py"), []byte("def helper():\n    pass\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := NewFetcher(tmpDir)
+	fetcher.PathPrefix = "services/api"
+
+	files := []diff.FileDiff{{
+		Filename: "services/api/app.py",
+		Hunks: []diff.Hunk{{
+			Lines: []diff.Line{{Type: diff.LineAdded, Content: "import utils"}},
+		}},
+	}}
+
+	fetched := fetcher.FetchReferencedFiles(files)
+	if len(fetched) != 1 {
+		t.Fatalf("Expected 1 referenced file, got %d", len(fetched))
+	}
+	if fetched[0].Path != "services/api/utils.py" {
+		t.Errorf("Expected repo-relative path services/api/utils.py, got %s", fetched[0].Path)
+	}
+}
@@ -1,11 +1,14 @@
 package context
 
 import (
-	"bufio"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // ImportInfo represents an import statement found in a file
@@ -19,6 +22,9 @@ type ImportInfo struct {
 // Resolver resolves imports from code files to their local paths
 type Resolver struct {
 	RootDir string
+
+	moduleOnce sync.Once
+	modulePath string // Go module path from go.mod at RootDir, read on first use
 }
 
 // NewResolver creates a new import resolver
@@ -28,10 +34,6 @@ func NewResolver(rootDir string) *Resolver {
 
 // Regular expressions for different import patterns
 var (
-	// Go: import "path" or import ( "path" )
-	goImportRegex  = regexp.MustCompile(`(?:import\s+(?:"([^"]+)"|\(\s*(?:[^)]*?"([^"]+)"[^)]*?)*\s*\)))`)
-	goSingleImport = regexp.MustCompile(`"([^"]+)"`)
-
 	// JavaScript/TypeScript: import ... from 'path' or require('path')
 	jsImportRegex = regexp.MustCompile(`(?:import\s+.*?\s+from\s+['"]([^'"]+)['"]|require\s*\(\s*['"]([^'"]+)['"]\s*\))`)
 
@@ -61,45 +63,30 @@ func (r *Resolver) ExtractImports(filename, content string) []ImportInfo {
 	return imports
 }
 
-// extractGoImports extracts imports from Go code
+// extractGoImports extracts imports from Go code. The import specs are read with go/parser,
+// so grouped, aliased, dot and blank imports are all found.
 func (r *Resolver) extractGoImports(filename, content string) []ImportInfo {
 	var imports []ImportInfo
 
-	// Find all quoted strings in import statements
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	inImportBlock := false
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	// A file with syntax errors still yields the import specs parsed before them
+	file, _ := parser.ParseFile(token.NewFileSet(), filename, content, parser.ImportsOnly)
+	if file == nil {
+		return nil
+	}
 
-		if strings.HasPrefix(line, "import (") {
-			inImportBlock = true
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
 			continue
 		}
-		if inImportBlock && line == ")" {
-			inImportBlock = false
-			continue
-		}
-
-		if inImportBlock || strings.HasPrefix(line, "import ") {
-			matches := goSingleImport.FindAllStringSubmatch(line, -1)
-			for _, match := range matches {
-				if len(match) > 1 && match[1] != "" {
-					importPath := match[1]
-					// Only resolve local imports (relative or same module)
-					if isLocalImport(importPath) {
-						resolved := r.resolveGoImport(filename, importPath)
-						if resolved != "" {
-							imports = append(imports, ImportInfo{
-								Source:       filename,
-								ImportPath:   importPath,
-								ResolvedPath: resolved,
-								Language:     "go",
-							})
-						}
-					}
-				}
-			}
+		// A package is several files, each its own import entry
+		for _, resolved := range r.resolveGoImport(importPath) {
+			imports = append(imports, ImportInfo{
+				Source:       filename,
+				ImportPath:   importPath,
+				ResolvedPath: resolved,
+				Language:     "go",
+			})
 		}
 	}
 
@@ -198,25 +185,61 @@ func (r *Resolver) extractRustImports(filename, content string) []ImportInfo {
 	return imports
 }
 
-// resolveGoImport resolves a Go import path to a local file
-func (r *Resolver) resolveGoImport(sourceFile, importPath string) string {
-	// For Go, we need to check if the import is from the same module
-	// This is a simplified resolver - full resolution would require go.mod parsing
+// resolveGoImport resolves a Go import path to the non-test files of its package, relative
+// to RootDir. Imports under the module declared in go.mod map directly to a directory. Without
+// a go.mod, local-looking imports fall back to matching their last two path segments.
+func (r *Resolver) resolveGoImport(importPath string) []string {
+	var dir string
+	if module := r.goModulePath(); module != "" {
+		switch {
+		case importPath == module:
+			dir = "."
+		case strings.HasPrefix(importPath, module+"/"):
+			dir = strings.TrimPrefix(importPath, module+"/")
+		default:
+			return nil // Standard library or a dependency
+		}
+	} else if isLocalImport(importPath) {
+		parts := strings.Split(importPath, "/")
+		dir = strings.Join(parts[len(parts)-2:], "/")
+	} else {
+		return nil
+	}
 
-	// Check if it's a relative-looking path within the module
-	parts := strings.Split(importPath, "/")
-	if len(parts) > 0 {
-		// Try to find it relative to root
-		possiblePath := filepath.Join(r.RootDir, strings.Join(parts[len(parts)-2:], "/"))
-		if info, err := os.Stat(possiblePath); err == nil && info.IsDir() {
-			// Look for .go files
-			files, _ := filepath.Glob(filepath.Join(possiblePath, "*.go"))
-			if len(files) > 0 {
-				return files[0] // Return first .go file
-			}
+	matches, _ := filepath.Glob(filepath.Join(r.RootDir, filepath.FromSlash(dir), "*.go"))
+	var files []string
+	for _, match := range matches {
+		if strings.HasSuffix(match, "_test.go") {
+			continue
 		}
+		files = append(files, filepath.Join(filepath.FromSlash(dir), filepath.Base(match)))
 	}
+	return files
+}
 
+// goModulePath returns the module path declared in go.mod at RootDir, or "" if there is none
+func (r *Resolver) goModulePath() string {
+	r.moduleOnce.Do(func() {
+		data, err := os.ReadFile(filepath.Join(r.RootDir, "go.mod"))
+		if err != nil {
+			return
+		}
+		r.modulePath = parseModulePath(string(data))
+	})
+	return r.modulePath
+}
+
+// parseModulePath reads the module directive from go.mod content
+func parseModulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
 	return ""
 }
 
@@ -3,6 +3,7 @@ package context
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -54,6 +55,128 @@ func main() {
 	}
 }
 
+func TestExtractGoImports_Forms(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"internal/store", "internal/auth", "pkg/util"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, dir, "file.go"), []byte("package x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolver := NewResolver(tmpDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"single-line import", `package main
+
+import "example.com/app/internal/store"
+`, []string{"example.com/app/internal/store"}},
+		{"grouped block opening on the first import", `package main
+
+import ("fmt"
+	"example.com/app/internal/store")
+`, []string{"example.com/app/internal/store"}},
+		{"aliased, dot and blank imports", `package main
+
+import (
+	"os"
+
+	db "example.com/app/internal/store"
+	. "example.com/app/pkg/util"
+	_ "example.com/app/internal/auth"
+)
+`, []string{"example.com/app/internal/store", "example.com/app/pkg/util", "example.com/app/internal/auth"}},
+		{"several import declarations", `package main
+
+import "example.com/app/internal/store"
+import auth "example.com/app/internal/auth"
+`, []string{"example.com/app/internal/store", "example.com/app/internal/auth"}},
+		{"syntax error after imports", `package main
+
+import "example.com/app/pkg/util"
+
+func main() {
+`, []string{"example.com/app/pkg/util"}},
+		{"import path in a string is ignored", `package main
+
+var s = "import \"example.com/app/internal/store\""
+`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, imp := range resolver.extractGoImports("main.go", tt.content) {
+				got = append(got, imp.ImportPath)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("extractGoImports() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveGoImport_ModulePath(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                           "// Example module\nmodule example.com/app // trailing comment\n\ngo 1.21\n",
+		"internal/store/store.go":          "package store\n",
+		"pkg/internal/store/store.go":      "package store\n",
+		"pkg/internal/store/cache.go":      "package store\n",
+		"pkg/internal/store/cache_test.go": "package store\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolver := NewResolver(tmpDir)
+
+	content := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/pkg/internal/store"
+	"github.com/spf13/cobra"
+)
+`
+	var got []string
+	for _, imp := range resolver.extractGoImports("main.go", content) {
+		got = append(got, filepath.ToSlash(imp.ResolvedPath))
+	}
+	// The last-two-segments heuristic picked internal/store/store.go here
+	expected := []string{"pkg/internal/store/cache.go", "pkg/internal/store/store.go"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Resolved %v, want %v", got, expected)
+	}
+}
+
+func TestParseModulePath(t *testing.T) {
+	tests := []struct {
+		gomod    string
+		expected string
+	}{
+		{"module example.com/app\n\ngo 1.21\n", "example.com/app"},
+		{"// comment\nmodule \"example.com/quoted\"\n", "example.com/quoted"},
+		{"go 1.21\n", ""},
+	}
+	for _, tt := range tests {
+		if got := parseModulePath(tt.gomod); got != tt.expected {
+			t.Errorf("parseModulePath(%q) = %q, want %q", tt.gomod, got, tt.expected)
+		}
+	}
+}
+
 func TestExtractJSImports(t *testing.T) {
 	tmpDir := t.TempDir()
 	resolver := NewResolver(tmpDir)
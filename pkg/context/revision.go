@@ -0,0 +1,21 @@
+package context
+
+import (
+	stdcontext "context"
+	"fmt"
+	"os/exec"
+)
+
+// GetFileAtRevision returns a file's content at a git revision, running git from dir
+// (the current directory if empty). The path is relative to the repository root. git is
+// killed when ctx is done.
+func GetFileAtRevision(ctx stdcontext.Context, dir, rev, path string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", fmt.Sprintf("%s:%s", rev, path))
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git show %s:%s failed: %w", rev, path, err)
+	}
+
+	return string(output), nil
+}
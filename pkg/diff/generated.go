@@ -0,0 +1,49 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// generatedBannerLines is how many lines from the top of a file are searched for a
+// generated-code banner
+const generatedBannerLines = 20
+
+// generatedBanner matches the Go convention ("// Code generated ... DO NOT EDIT.") and the
+// @generated and <auto-generated> markers used by other code generators
+var generatedBanner = regexp.MustCompile(`^\s*(?://|#|/?\*|--|<!--)?\s*(?:Code generated .* DO NOT EDIT\.?|@generated\b|<auto-generated)`)
+
+// generatedSuffixes are file name endings produced by common code generators
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_grpc.pb.go", ".pb.cc", ".pb.h", "_pb2.py", "_pb2_grpc.py",
+	"_generated.go", ".generated.go", ".g.dart", ".designer.cs",
+}
+
+// IsGenerated reports whether a file was produced by a code generator, judging by its name or
+// by a generated-code banner in the first lines of the new file. Vendored directories are
+// handled separately by the config's vendor settings.
+func IsGenerated(file FileDiff) bool {
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(file.Filename, suffix) {
+			return true
+		}
+	}
+
+	for _, hunk := range file.Hunks {
+		if hunk.NewStart > generatedBannerLines {
+			break
+		}
+		for _, line := range hunk.Lines {
+			if line.Type == LineRemoved {
+				continue
+			}
+			if line.NewNum > generatedBannerLines {
+				break
+			}
+			if generatedBanner.MatchString(line.Content) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package diff
+
+import "testing"
+
+func TestIsGenerated_Banner(t *testing.T) {
+	tests := []struct {
+		name     string
+		diffText string
+		expected bool
+	}{
+		{"go banner", `diff --git a/api/types.go b/api/types.go
+--- /dev/null
++++ b/api/types.go
+@@ -0,0 +1,3 @@
++// Code generated by protoc-gen-go. DO NOT EDIT.
++
++package api
+`, true},
+		{"banner in context after license", `diff --git a/mocks/store.go b/mocks/store.go
+--- a/mocks/store.go
++++ b/mocks/store.go
+@@ -1,4 +1,4 @@
+ // Copyright 2024 Acme
+ // Code generated by MockGen. DO NOT EDIT.
+-// Source: store.go
++// Source: internal/store.go
+ package mocks
+`, true},
+		{"@generated marker", `diff --git a/schema.ts b/schema.ts
+--- /dev/null
++++ b/schema.ts
+@@ -0,0 +1,2 @@
++/* @generated */
++export type Query = {};
+`, true},
+		{"banner mentioned in code", `diff --git a/gen.go b/gen.go
+--- /dev/null
++++ b/gen.go
+@@ -0,0 +1,3 @@
++package gen
++
++const header = "// Code generated by gen. DO NOT EDIT."
+`, false},
+		{"banner far below the top", `diff --git a/notes.go b/notes.go
+--- a/notes.go
++++ b/notes.go
+@@ -40,1 +40,2 @@
+ package notes
++// Code generated by hand. DO NOT EDIT.
+`, false},
+		{"regular file", `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+ package main
+-func main() {}
++func main() { run() }
+`, false},
+	}
+
+	for _, tt := range tests {
+		files, err := ParseGitDiff(tt.diffText)
+		if err != nil || len(files) != 1 {
+			t.Fatalf("%s: failed to parse diff: %v", tt.name, err)
+		}
+		if got := IsGenerated(files[0]); got != tt.expected {
+			t.Errorf("%s: IsGenerated = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestIsGenerated_FileName(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected bool
+	}{
+		{"api/v1/service.pb.go", true},
+		{"api/v1/service_grpc.pb.go", true},
+		{"proto/service_pb2.py", true},
+		{"lib/models/user.g.dart", true},
+		{"internal/zz_generated.go", true},
+		{"internal/generator.go", false},
+		{"cmd/pb.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsGenerated(FileDiff{Filename: tt.filename}); got != tt.expected {
+			t.Errorf("IsGenerated(%q) = %v, want %v", tt.filename, got, tt.expected)
+		}
+	}
+}
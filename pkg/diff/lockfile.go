@@ -0,0 +1,26 @@
+package diff
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// lockfileNames are dependency lockfiles whose checksums add noise to reviews and look like
+// random secrets
+var lockfileNames = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Pipfile.lock":      true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+// IsLockfile checks if a file is a dependency lockfile
+func IsLockfile(filename string) bool {
+	base := filepath.Base(filename)
+	return lockfileNames[base] || strings.HasSuffix(base, ".lock")
+}
@@ -0,0 +1,16 @@
+package diff
+
+import "testing"
+
+func TestIsLockfile(t *testing.T) {
+	for _, name := range []string{"go.sum", "web/package-lock.json", "pnpm-lock.yaml", "deps/mix.lock"} {
+		if !IsLockfile(name) {
+			t.Errorf("Expected %s to be a lockfile", name)
+		}
+	}
+	for _, name := range []string{"main.go", "go.mod", "docs/lock.md"} {
+		if IsLockfile(name) {
+			t.Errorf("Expected %s not to be a lockfile", name)
+		}
+	}
+}
@@ -9,10 +9,20 @@ import (
 )
 
 type FileDiff struct {
-	Filename   string
-	OldContent string
-	NewContent string
-	Hunks      []Hunk
+	Filename    string
+	OldFilename string // Path before a rename, empty when the file kept its path
+	OldContent  string
+	NewContent  string
+	Hunks       []Hunk
+}
+
+// PreviousFilename returns the file's path before the change, which differs from Filename
+// for renamed files
+func (f FileDiff) PreviousFilename() string {
+	if f.OldFilename != "" {
+		return f.OldFilename
+	}
+	return f.Filename
 }
 
 type Hunk struct {
@@ -61,14 +71,30 @@ func ParseGitDiff(diffText string) ([]FileDiff, error) {
 				files = append(files, *currentFile)
 			}
 
+			// Comments attach to the 'b/' path, which is the file's path after the change
 			currentFile = &FileDiff{
-				Filename: match[1], // Use the 'a/' path
+				Filename: match[2],
 				Hunks:    []Hunk{},
 			}
+			if match[1] != match[2] {
+				currentFile.OldFilename = match[1]
+			}
 			currentHunk = nil
 			continue
 		}
 
+		// Rename headers name both paths unambiguously, even when they contain " b/"
+		if currentFile != nil && currentHunk == nil {
+			if oldName, ok := strings.CutPrefix(line, "rename from "); ok {
+				currentFile.OldFilename = oldName
+				continue
+			}
+			if newName, ok := strings.CutPrefix(line, "rename to "); ok {
+				currentFile.Filename = newName
+				continue
+			}
+		}
+
 		// Check for hunk header
 		if match := hunkHeaderRegex.FindStringSubmatch(line); match != nil {
 			if currentHunk != nil {
@@ -141,6 +167,22 @@ func ParseGitDiff(diffText string) ([]FileDiff, error) {
 	return files, scanner.Err()
 }
 
+// FileSection returns the part of a git diff that changes filename, its path after the
+// change, or "" when the diff doesn't touch it
+func FileSection(diffText, filename string) string {
+	var section strings.Builder
+	inFile := false
+	for _, line := range strings.SplitAfter(diffText, "\n") {
+		if match := fileHeaderRegex.FindStringSubmatch(strings.TrimRight(line, "\n")); match != nil {
+			inFile = match[2] == filename
+		}
+		if inFile {
+			section.WriteString(line)
+		}
+	}
+	return section.String()
+}
+
 func calculateLineNumbers(hunk *Hunk) {
 	oldLineNum := hunk.OldStart
 	newLineNum := hunk.NewStart
@@ -164,12 +206,34 @@ func calculateLineNumbers(hunk *Hunk) {
 	}
 }
 
+// FormatOptions are optional additions to the diff sent to the LLM
+type FormatOptions struct {
+	// WordDiff follows each added line that replaces a removed one with a word diff against
+	// it, so the model sees exactly which tokens changed. It makes the prompt larger.
+	WordDiff bool
+}
+
+// wordDiffLegend explains the word diff annotations once per formatted diff
+const wordDiffLegend = "Lines replacing a removed line are followed by a word diff marking removed words as [-old-] and added words as {+new+}.\n\n"
+
 // FormatForLLM formats the diff in the specific format expected by the LLM
 func FormatForLLM(files []FileDiff) string {
+	return FormatForLLMWithOptions(files, FormatOptions{})
+}
+
+// FormatForLLMWithOptions is FormatForLLM with optional annotations
+func FormatForLLMWithOptions(files []FileDiff, opts FormatOptions) string {
 	var result strings.Builder
+	if opts.WordDiff {
+		result.WriteString(wordDiffLegend)
+	}
 
 	for _, file := range files {
-		result.WriteString(fmt.Sprintf("## File: '%s'\n", file.Filename))
+		if file.OldFilename != "" {
+			result.WriteString(fmt.Sprintf("## File: '%s' (renamed from '%s')\n", file.Filename, file.OldFilename))
+		} else {
+			result.WriteString(fmt.Sprintf("## File: '%s'\n", file.Filename))
+		}
 
 		for _, hunk := range file.Hunks {
 			// Write hunk header
@@ -178,10 +242,17 @@ func FormatForLLM(files []FileDiff) string {
 
 			// Generate new hunk section
 			result.WriteString("__new hunk__\n")
+			var replaced map[int]string
+			if opts.WordDiff {
+				replaced = replacedLines(hunk.Lines)
+			}
 			newLineNum := hunk.NewStart
-			for _, line := range hunk.Lines {
+			for i, line := range hunk.Lines {
 				if line.Type == LineAdded {
 					result.WriteString(fmt.Sprintf("%d +%s\n", newLineNum, line.Content))
+					if old, ok := replaced[i]; ok {
+						result.WriteString(fmt.Sprintf("   word diff: %s\n", WordDiff(old, line.Content)))
+					}
 					newLineNum++
 				} else if line.Type == LineContext {
 					result.WriteString(fmt.Sprintf("%d  %s\n", newLineNum, line.Content))
@@ -204,3 +275,26 @@ func FormatForLLM(files []FileDiff) string {
 
 	return result.String()
 }
+
+// replacedLines pairs each run of removed lines with the run of added lines right after it,
+// line by line, returning the removed content by the added line's index in lines
+func replacedLines(lines []Line) map[int]string {
+	replaced := make(map[int]string)
+	for i := 0; i < len(lines); {
+		if lines[i].Type != LineRemoved {
+			i++
+			continue
+		}
+		removedStart := i
+		for i < len(lines) && lines[i].Type == LineRemoved {
+			i++
+		}
+		removedEnd := i
+		for k := 0; i < len(lines) && lines[i].Type == LineAdded; i, k = i+1, k+1 {
+			if removedStart+k < removedEnd {
+				replaced[i] = lines[removedStart+k].Content
+			}
+		}
+	}
+	return replaced
+}
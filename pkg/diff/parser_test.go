@@ -147,6 +147,59 @@ index 1234567..0000000
 	}
 }
 
+func TestParseGitDiff_RenamedFile(t *testing.T) {
+	diffText := `diff --git a/pkg/old.go b/pkg/new.go
+similarity index 90%
+rename from pkg/old.go
+rename to pkg/new.go
+index 1234567..89abcde 100644
+--- a/pkg/old.go
++++ b/pkg/new.go
+@@ -1,2 +1,2 @@
+ package pkg
+-func Old() {}
++func New() {}
+diff --git a/docs/a b.md b/docs/c b.md
+similarity index 100%
+rename from docs/a b.md
+rename to docs/c b.md
+`
+
+	files, err := ParseGitDiff(diffText)
+	if err != nil {
+		t.Fatalf("ParseGitDiff returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+
+	renamed := files[0]
+	if renamed.Filename != "pkg/new.go" || renamed.OldFilename != "pkg/old.go" {
+		t.Errorf("Expected pkg/old.go renamed to pkg/new.go, got %q from %q", renamed.Filename, renamed.OldFilename)
+	}
+	if renamed.PreviousFilename() != "pkg/old.go" {
+		t.Errorf("Expected the previous filename to be the old path, got %s", renamed.PreviousFilename())
+	}
+	if len(renamed.Hunks) != 1 || renamed.Hunks[0].Lines[2].NewNum != 2 {
+		t.Errorf("Expected the modification to be parsed, got %+v", renamed.Hunks)
+	}
+
+	// Pure renames have no hunks, and the rename headers disambiguate paths with spaces
+	pure := files[1]
+	if pure.Filename != "docs/c b.md" || pure.OldFilename != "docs/a b.md" || len(pure.Hunks) != 0 {
+		t.Errorf("Expected a pure rename of docs/a b.md to docs/c b.md, got %+v", pure)
+	}
+
+	if formatted := FormatForLLM(files[:1]); !strings.Contains(formatted, "## File: 'pkg/new.go' (renamed from 'pkg/old.go')") {
+		t.Errorf("Expected the rename in the LLM format, got %q", formatted)
+	}
+
+	unchanged := FileDiff{Filename: "main.go"}
+	if unchanged.PreviousFilename() != "main.go" {
+		t.Errorf("Expected the previous filename of a file that kept its path to be its path, got %s", unchanged.PreviousFilename())
+	}
+}
+
 func TestParseGitDiff_BinaryFile(t *testing.T) {
 	// Binary files should be parsed (even if they have no hunks)
 	diffText := `diff --git a/image.png b/image.png
@@ -174,6 +227,22 @@ Binary files /dev/null and b/image.png differ
 	}
 }
 
+func TestFileSection(t *testing.T) {
+	first := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n"
+	second := "diff --git a/old.go b/pkg/new.go\nrename from old.go\nrename to pkg/new.go\n"
+	diffText := first + second
+
+	if got := FileSection(diffText, "main.go"); got != first {
+		t.Errorf("Expected the main.go section, got %q", got)
+	}
+	if got := FileSection(diffText, "pkg/new.go"); got != second {
+		t.Errorf("Expected the renamed file's section, got %q", got)
+	}
+	if got := FileSection(diffText, "old.go"); got != "" {
+		t.Errorf("Expected no section for a path the change moved away from, got %q", got)
+	}
+}
+
 func TestCalculateLineNumbers(t *testing.T) {
 	hunk := &Hunk{
 		OldStart: 10,
@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxLCSCells bounds the table used to diff the changed middle of two files. Beyond it the
+// whole middle is reported as replaced, which is still a valid, if coarse, diff.
+const maxLCSCells = 4_000_000
+
+// op is one line of an edit script: ' ' kept, '-' removed or '+' added
+type op struct {
+	kind byte
+	text string
+}
+
+// splitLines splits content into lines without their newlines; empty content has none
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// editScript returns the line edits turning oldLines into newLines. Common leading and
+// trailing lines are matched directly, so only the changed middle needs the LCS table.
+func editScript(oldLines, newLines []string) []op {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for _, line := range oldLines[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	ops = append(ops, diffMiddle(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle diffs two line slices with a longest common subsequence table
+func diffMiddle(a, b []string) []op {
+	var ops []op
+	if len(a)*len(b) > maxLCSCells {
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// hunkStart is the line number git shows for a hunk range: its first line, or the line
+// before it when the range is empty
+func hunkStart(before, count int) int {
+	if count == 0 {
+		return before
+	}
+	return before + 1
+}
+
+// Unified builds a git-style unified diff of a file between two versions, with contextLines
+// unchanged lines around each change. An empty oldContent is shown as a new file. It returns
+// "" when the versions are identical.
+func Unified(filename, oldContent, newContent string, contextLines int) string {
+	ops := editScript(splitLines(oldContent), splitLines(newContent))
+
+	var changes []int
+	for i, o := range ops {
+		if o.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", filename, filename))
+	if oldContent == "" {
+		builder.WriteString("new file mode 100644\n--- /dev/null\n")
+	} else {
+		builder.WriteString(fmt.Sprintf("--- a/%s\n", filename))
+	}
+	builder.WriteString(fmt.Sprintf("+++ b/%s\n", filename))
+
+	// oldLine and newLine count the lines of each version before ops[i]
+	oldLine, newLine, i := 0, 0, 0
+	for c := 0; c < len(changes); {
+		start := max(changes[c]-contextLines, i)
+		// Extend the hunk while the next change is close enough for the contexts to touch
+		end := changes[c]
+		for c < len(changes) && changes[c] <= end+2*contextLines {
+			end = changes[c]
+			c++
+		}
+		end = min(end+contextLines, len(ops)-1)
+
+		for ; i < start; i++ {
+			oldLine++
+			newLine++
+		}
+
+		var body strings.Builder
+		oldCount, newCount := 0, 0
+		for _, o := range ops[start : end+1] {
+			body.WriteByte(o.kind)
+			body.WriteString(o.text)
+			body.WriteByte('\n')
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
+			hunkStart(oldLine, oldCount), oldCount, hunkStart(newLine, newCount), newCount))
+		builder.WriteString(body.String())
+
+		oldLine += oldCount
+		newLine += newCount
+		i = end + 1
+	}
+
+	return builder.String()
+}
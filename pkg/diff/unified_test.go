@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified_NewFile(t *testing.T) {
+	got := Unified("main.go", "", "package main\n\nfunc main() {}\n", 3)
+	expected := `diff --git a/main.go b/main.go
+new file mode 100644
+--- /dev/null
++++ b/main.go
+@@ -0,0 +1,3 @@
++package main
++
++func main() {}
+`
+	if got != expected {
+		t.Errorf("Unified() =\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestUnified_Identical(t *testing.T) {
+	content := "a\nb\nc\n"
+	if got := Unified("f.txt", content, content, 3); got != "" {
+		t.Errorf("Unified() of identical content = %q, expected empty", got)
+	}
+}
+
+func TestUnified_ModifiedParsesWithLineNumbers(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+	}
+	newLines := append([]string{}, oldLines...)
+	// Modify line 2 and insert a line after line 15
+	newLines[1] = "line 2 changed"
+	newLines = append(newLines[:15], append([]string{"inserted"}, newLines[15:]...)...)
+	oldContent := strings.Join(oldLines, "\n") + "\n"
+	newContent := strings.Join(newLines, "\n") + "\n"
+
+	files, err := ParseGitDiff(Unified("pkg/f.go", oldContent, newContent, 2))
+	if err != nil {
+		t.Fatalf("ParseGitDiff() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Filename != "pkg/f.go" {
+		t.Fatalf("Expected one file pkg/f.go, got %+v", files)
+	}
+	hunks := files[0].Hunks
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks for distant changes, got %d", len(hunks))
+	}
+
+	first := hunks[0]
+	if first.OldStart != 1 || first.OldCount != 4 || first.NewStart != 1 || first.NewCount != 4 {
+		t.Errorf("First hunk range = -%d,%d +%d,%d, expected -1,4 +1,4", first.OldStart, first.OldCount, first.NewStart, first.NewCount)
+	}
+
+	added := map[string]int{}
+	for _, hunk := range hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == LineAdded {
+				added[line.Content] = line.NewNum
+			}
+		}
+	}
+	if added["line 2 changed"] != 2 {
+		t.Errorf("Modified line at new line %d, expected 2", added["line 2 changed"])
+	}
+	if added["inserted"] != 16 {
+		t.Errorf("Inserted line at new line %d, expected 16", added["inserted"])
+	}
+}
+
+func TestUnified_MergesNearbyChanges(t *testing.T) {
+	oldContent := "a\nb\nc\nd\ne\n"
+	newContent := "A\nb\nc\nd\nE\n"
+
+	files, err := ParseGitDiff(Unified("f.txt", oldContent, newContent, 3))
+	if err != nil {
+		t.Fatalf("ParseGitDiff() error = %v", err)
+	}
+	if len(files) != 1 || len(files[0].Hunks) != 1 {
+		t.Fatalf("Expected changes within context to share one hunk, got %+v", files)
+	}
+	if hunk := files[0].Hunks[0]; hunk.OldCount != 5 || hunk.NewCount != 5 {
+		t.Errorf("Hunk counts = %d,%d, expected 5,5", hunk.OldCount, hunk.NewCount)
+	}
+}
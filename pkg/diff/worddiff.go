@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxWordDiffCells bounds the LCS table of a word diff; longer line pairs are shown as a
+// whole-line replacement
+const maxWordDiffCells = 250000
+
+// WordDiff returns new annotated with the words changed from old, in git's plain word diff
+// style: removed words as [-old-] and added words as {+new+}. Words are runs of letters,
+// digits and underscores; whitespace runs and punctuation are tokens of their own.
+func WordDiff(old, new string) string {
+	oldTokens, newTokens := splitWords(old), splitWords(new)
+	if len(oldTokens)*len(newTokens) > maxWordDiffCells {
+		return formatWordChange(old, new)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of oldTokens[i:] and newTokens[j:]
+	lcs := make([][]int, len(oldTokens)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newTokens)+1)
+	}
+	for i := len(oldTokens) - 1; i >= 0; i-- {
+		for j := len(newTokens) - 1; j >= 0; j-- {
+			if oldTokens[i] == newTokens[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result, removed, added strings.Builder
+	flush := func() {
+		result.WriteString(formatWordChange(removed.String(), added.String()))
+		removed.Reset()
+		added.Reset()
+	}
+	i, j := 0, 0
+	for i < len(oldTokens) || j < len(newTokens) {
+		switch {
+		case i < len(oldTokens) && j < len(newTokens) && oldTokens[i] == newTokens[j]:
+			flush()
+			result.WriteString(oldTokens[i])
+			i++
+			j++
+		case j == len(newTokens) || (i < len(oldTokens) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed.WriteString(oldTokens[i])
+			i++
+		default:
+			added.WriteString(newTokens[j])
+			j++
+		}
+	}
+	flush()
+	return result.String()
+}
+
+// formatWordChange marks a replaced span, either side of which may be empty
+func formatWordChange(removed, added string) string {
+	var change string
+	if removed != "" {
+		change += "[-" + removed + "-]"
+	}
+	if added != "" {
+		change += "{+" + added + "+}"
+	}
+	return change
+}
+
+// splitWords splits a line into word, whitespace and punctuation tokens
+func splitWords(line string) []string {
+	var tokens []string
+	runes := []rune(line)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		switch {
+		case isWordRune(runes[start]):
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+		case unicode.IsSpace(runes[start]):
+			for end < len(runes) && unicode.IsSpace(runes[end]) {
+				end++
+			}
+		}
+		tokens = append(tokens, string(runes[start:end]))
+		start = end
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
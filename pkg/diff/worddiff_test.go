@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{"unchanged", "return nil", "return nil", "return nil"},
+		{"replaced word", "timeout := 30", "timeout := 60", "timeout := [-30-]{+60+}"},
+		{"added argument", "fetch(url)", "fetch(url, opts)", "fetch(url{+, opts+})"},
+		{"removed word", "if err != nil && retry {", "if err != nil {", "if err != nil [-&& retry -]{"},
+		{"renamed identifier", "userID := req.UserID", "accountID := req.AccountID", "[-userID-]{+accountID+} := req.[-UserID-]{+AccountID+}"},
+		{"from empty", "", "x := 1", "{+x := 1+}"},
+		{"to empty", "x := 1", "", "[-x := 1-]"},
+		{"indentation", "\tfoo()", "\t\tfoo()", "[-\t-]{+\t\t+}foo()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordDiff(tt.old, tt.new); got != tt.want {
+				t.Errorf("WordDiff(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatForLLMWithOptions_WordDiff(t *testing.T) {
+	files, err := ParseGitDiff(`diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@
+ package main
+-var timeout = 30
+-var retries = 3
++var timeout = 60
++var retries = 5
++var verbose = true
+ func main() {}
+`)
+	if err != nil {
+		t.Fatalf("ParseGitDiff failed: %v", err)
+	}
+
+	plain := FormatForLLM(files)
+	if strings.Contains(plain, "word diff") {
+		t.Errorf("Expected no word diff by default, got:\n%s", plain)
+	}
+
+	output := FormatForLLMWithOptions(files, FormatOptions{WordDiff: true})
+	for _, want := range []string{
+		"2 +var timeout = 60\n   word diff: var timeout = [-30-]{+60+}\n",
+		"3 +var retries = 5\n   word diff: var retries = [-3-]{+5+}\n",
+		"4 +var verbose = true\n5  func main() {}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
 )
 
 // MaxPracticesSize is the maximum size in bytes for combined practices content.
@@ -74,7 +76,14 @@ var patterns = []discoveryPattern{
 
 // Discover scans a repository for practices and guidelines files.
 // It returns a RepoPractices struct with all discovered content.
+// Vendored dependency directories are skipped.
 func Discover(repoPath string) (*RepoPractices, error) {
+	return DiscoverExcluding(repoPath, internal.DefaultVendorDirs)
+}
+
+// DiscoverExcluding scans a repository for practices and guidelines files,
+// skipping any directory whose name is in excludedDirs.
+func DiscoverExcluding(repoPath string, excludedDirs []string) (*RepoPractices, error) {
 	practices := &RepoPractices{
 		Sources: make(map[string]string),
 	}
@@ -94,7 +103,7 @@ func Discover(repoPath string) (*RepoPractices, error) {
 
 			if info.IsDir() && pattern.IsDir {
 				// Scan directory for matching files
-				if err := scanDirectory(practices, fullPath, pattern.Name, pattern.Extensions); err != nil {
+				if err := scanDirectory(practices, fullPath, pattern.Name, pattern.Extensions, excludedDirs); err != nil {
 					// Log but don't fail on individual directory errors
 					continue
 				}
@@ -118,13 +127,17 @@ func Discover(repoPath string) (*RepoPractices, error) {
 	return practices, nil
 }
 
-// scanDirectory recursively scans a directory for files matching the given extensions
-func scanDirectory(practices *RepoPractices, dirPath, patternName string, extensions []string) error {
+// scanDirectory recursively scans a directory for files matching the given extensions,
+// without descending into excluded directories
+func scanDirectory(practices *RepoPractices, dirPath, patternName string, extensions, excludedDirs []string) error {
 	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip files we can't access
 		}
 		if info.IsDir() {
+			if path != dirPath && isExcludedDir(info.Name(), excludedDirs) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -154,6 +167,16 @@ func scanDirectory(practices *RepoPractices, dirPath, patternName string, extens
 	})
 }
 
+// isExcludedDir checks if a directory name is in the excluded list
+func isExcludedDir(name string, excludedDirs []string) bool {
+	for _, dir := range excludedDirs {
+		if name == dir {
+			return true
+		}
+	}
+	return false
+}
+
 // readFileWithLimit reads a file up to a reasonable size limit
 func readFileWithLimit(path string) (string, error) {
 	info, err := os.Stat(path)
@@ -210,6 +210,34 @@ func TestDiscover_IgnoresNonMatchingExtensions(t *testing.T) {
 	}
 }
 
+func TestDiscover_SkipsVendoredDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	claudeDir := filepath.Join(tmpDir, ".claude")
+	vendoredDir := filepath.Join(claudeDir, "node_modules", "pkg")
+	if err := os.MkdirAll(vendoredDir, 0755); err != nil {
+		t.Fatalf("Failed to create vendored dir: %v", err)
+	}
+	os.WriteFile(filepath.Join(claudeDir, "rules.md"), []byte("Prefer small functions"), 0644)
+	os.WriteFile(filepath.Join(vendoredDir, "README.md"), []byte("Dependency docs"), 0644)
+
+	practices, err := Discover(tmpDir)
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if len(practices.Sources) != 1 || containsString(practices.Combined, "Dependency docs") {
+		t.Errorf("Expected vendored files to be skipped, got sources %v", practices.Sources)
+	}
+
+	practices, err = DiscoverExcluding(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("DiscoverExcluding returned error: %v", err)
+	}
+	if len(practices.Sources) != 2 {
+		t.Errorf("Expected vendored files when exclusion is disabled, got sources %v", practices.Sources)
+	}
+}
+
 func containsString(haystack, needle string) bool {
 	return len(haystack) >= len(needle) && (haystack == needle || len(needle) == 0 ||
 		(len(haystack) > 0 && containsSubstring(haystack, needle)))
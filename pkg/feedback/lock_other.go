@@ -0,0 +1,15 @@
+//go:build !unix
+
+package feedback
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; appends of a single write are still
+// unlikely to interleave
+func lockFile(file *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
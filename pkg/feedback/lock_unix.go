@@ -0,0 +1,21 @@
+//go:build unix
+
+package feedback
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an advisory lock on the file, blocking until it is available
+func lockFile(file *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	return syscall.Flock(int(file.Fd()), how)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
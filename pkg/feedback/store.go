@@ -0,0 +1,107 @@
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	storeDir  = ".manque-ai"
+	storeFile = "feedback.jsonl"
+)
+
+// FileStore persists feedback entries as JSON lines so learnings accumulate across PRs.
+// Reads and writes take a file lock, so parallel runs sharing the file don't corrupt it.
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore creates a store backed by the given file
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// DefaultStorePath returns ~/.manque-ai/feedback.jsonl
+func DefaultStorePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, storeDir, storeFile), nil
+}
+
+// Append writes entries to the end of the store
+func (s *FileStore) Append(entries []FeedbackEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal feedback entry: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open feedback store: %w", err)
+	}
+	defer file.Close()
+
+	if err := lockFile(file, true); err != nil {
+		return fmt.Errorf("failed to lock feedback store: %w", err)
+	}
+	defer unlockFile(file)
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write feedback store: %w", err)
+	}
+	return nil
+}
+
+// LoadAll reads every entry recorded for a repository, across all of its PRs. An empty
+// repository returns entries for all repositories. A missing store yields no entries, and
+// malformed lines, such as one cut short by a crash, are skipped.
+func (s *FileStore) LoadAll(repository string) ([]FeedbackEntry, error) {
+	file, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feedback store: %w", err)
+	}
+	defer file.Close()
+
+	if err := lockFile(file, false); err != nil {
+		return nil, fmt.Errorf("failed to lock feedback store: %w", err)
+	}
+	defer unlockFile(file)
+
+	var entries []FeedbackEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry FeedbackEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if repository == "" || entry.Repository == repository {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feedback store: %w", err)
+	}
+
+	return entries, nil
+}
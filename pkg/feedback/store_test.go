@@ -0,0 +1,146 @@
+package feedback
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileStoreAccumulatesAcrossPRs(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "nested", "feedback.jsonl"))
+
+	first := NewTracker("owner/repo", 1)
+	first.Store = store
+	first.RecordAcceptance("hash1", "a.go", 10, "bug", false)
+	first.RecordDismissal("hash2", "a.go", 20, "style", "noise")
+	if err := first.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	// A second flush without new entries must not duplicate them
+	if err := first.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	other := NewTracker("owner/other", 5)
+	other.Store = store
+	other.RecordDismissal("hash3", "b.go", 1, "style", "noise")
+	if err := other.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	second := NewTracker("owner/repo", 2)
+	second.Store = store
+	second.RecordDismissal("hash4", "c.go", 5, "style", "noise")
+	if err := second.LoadFromStore("owner/repo"); err != nil {
+		t.Fatalf("LoadFromStore failed: %v", err)
+	}
+	if len(second.Entries) != 3 {
+		t.Fatalf("Expected 3 entries for owner/repo, got %d: %+v", len(second.Entries), second.Entries)
+	}
+
+	stats := second.GetStats()
+	if style := stats.ByIssueType["style"]; style.Total != 2 || style.Dismissed != 2 {
+		t.Errorf("Expected repo-wide style stats across PRs, got %+v", style)
+	}
+
+	all, err := store.LoadAll("")
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected 3 stored entries, got %d", len(all))
+	}
+}
+
+func TestTrackerLoadFromStoreSkipsKnownEntries(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "feedback.jsonl"))
+
+	tracker := NewTracker("owner/repo", 1)
+	tracker.Store = store
+	tracker.RecordAcceptance("hash1", "a.go", 10, "bug", false)
+	if err := tracker.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	// The same entries also come back from the PR body on the next run
+	next := NewTracker("owner/repo", 1)
+	next.Store = store
+	next.LoadFromBody(CreateFeedbackMarker(tracker.Entries))
+	if err := next.LoadFromStore("owner/repo"); err != nil {
+		t.Fatalf("LoadFromStore failed: %v", err)
+	}
+	if len(next.Entries) != 1 {
+		t.Errorf("Expected duplicate entry to be skipped, got %d entries", len(next.Entries))
+	}
+}
+
+func TestFileStoreMissingAndMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feedback.jsonl")
+	store := NewFileStore(path)
+
+	entries, err := store.LoadAll("owner/repo")
+	if err != nil || entries != nil {
+		t.Fatalf("Expected no entries for a missing store, got %v, %v", entries, err)
+	}
+
+	content := `{"comment_hash":"hash1","type":"accepted","repository":"owner/repo"}` + "\n" + `{"comment_hash":"trunc`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = store.LoadAll("owner/repo")
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].CommentHash != "hash1" {
+		t.Errorf("Expected malformed line to be skipped, got %+v", entries)
+	}
+}
+
+func TestFileStoreConcurrentAppends(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "feedback.jsonl"))
+
+	const writers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(pr int) {
+			defer wg.Done()
+			tracker := NewTracker("owner/repo", pr)
+			tracker.Store = store
+			for j := 0; j < 20; j++ {
+				tracker.RecordAcceptance(fmt.Sprintf("hash%d", j), "a.go", j, "bug", false)
+			}
+			if err := tracker.Flush(); err != nil {
+				t.Errorf("Flush failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := store.LoadAll("owner/repo")
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(entries) != writers*20 {
+		t.Errorf("Expected %d entries, got %d", writers*20, len(entries))
+	}
+}
+
+func TestGetLearningsOverTime(t *testing.T) {
+	tracker := NewTracker("owner/repo", 1)
+	tracker.Entries = []FeedbackEntry{
+		{Type: FeedbackDismissed, IssueType: "style", RecordedAt: time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{Type: FeedbackAccepted, IssueType: "style", RecordedAt: time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	learnings := tracker.GetLearnings()
+	for _, want := range []string{"**Over Time:**", "- 2026-08: 0.0% acceptance (1 total)", "- 2026-09: 100.0% acceptance (1 total)"} {
+		if !strings.Contains(learnings, want) {
+			t.Errorf("Expected learnings to contain %q, got:\n%s", want, learnings)
+		}
+	}
+}
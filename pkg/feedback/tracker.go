@@ -3,8 +3,11 @@ package feedback
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/igcodinap/manque-ai/pkg/markers"
 )
 
 // FeedbackType represents the type of feedback
@@ -32,6 +35,7 @@ type FeedbackEntry struct {
 	IsCritical  bool         `json:"is_critical"`
 	RecordedAt  time.Time    `json:"recorded_at"`
 	UserComment string       `json:"user_comment,omitempty"` // Optional user explanation
+	Reactor     string       `json:"reactor,omitempty"`      // Login of the user who reacted, for reactions
 }
 
 // FeedbackStats represents aggregated feedback statistics
@@ -44,6 +48,7 @@ type FeedbackStats struct {
 	AcceptanceRate   float64               `json:"acceptance_rate"`
 	ByIssueType      map[string]IssueStats `json:"by_issue_type"`
 	ByRepository     map[string]RepoStats  `json:"by_repository"`
+	ByMonth          map[string]IssueStats `json:"by_month"` // Keyed by "2006-01" of the recorded time
 	CommonDismissals []string              `json:"common_dismissals"`
 	UpdatedAt        time.Time             `json:"updated_at"`
 }
@@ -64,14 +69,22 @@ type RepoStats struct {
 	AcceptanceRate float64 `json:"acceptance_rate"`
 }
 
-// FeedbackMarker is the HTML comment marker for storing feedback in PR body
-const FeedbackMarker = "<!-- manque-feedback:"
+// legacyFeedbackMarker stored feedback before markers were namespaced
+const legacyFeedbackMarker = "<!-- manque-feedback:"
+
+// FeedbackMarker returns the HTML comment marker for storing feedback in PR body
+func FeedbackMarker() string {
+	return markers.Data("feedback")
+}
 
 // Tracker manages feedback collection and storage
 type Tracker struct {
 	Repository string
 	PRNumber   int
 	Entries    []FeedbackEntry
+	Store      *FileStore // Persists entries across PRs; nil keeps them in the PR body only
+
+	unflushed []FeedbackEntry // Recorded since the last Flush
 }
 
 // NewTracker creates a new feedback tracker
@@ -89,6 +102,48 @@ func (t *Tracker) RecordFeedback(entry FeedbackEntry) {
 	entry.PRNumber = t.PRNumber
 	entry.RecordedAt = time.Now()
 	t.Entries = append(t.Entries, entry)
+	t.unflushed = append(t.unflushed, entry)
+}
+
+// Flush appends the entries recorded since the last flush to the store
+func (t *Tracker) Flush() error {
+	if t.Store == nil || len(t.unflushed) == 0 {
+		return nil
+	}
+	if err := t.Store.Append(t.unflushed); err != nil {
+		return err
+	}
+	t.unflushed = nil
+	return nil
+}
+
+// LoadFromStore adds the entries recorded for a repository across all PRs, skipping those
+// already loaded, e.g. from this PR's body
+func (t *Tracker) LoadFromStore(repository string) error {
+	if t.Store == nil {
+		return nil
+	}
+	entries, err := t.Store.LoadAll(repository)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(t.Entries))
+	for _, entry := range t.Entries {
+		seen[entry.key()] = true
+	}
+	for _, entry := range entries {
+		if !seen[entry.key()] {
+			seen[entry.key()] = true
+			t.Entries = append(t.Entries, entry)
+		}
+	}
+	return nil
+}
+
+// key identifies an entry across the PR body and the store
+func (e FeedbackEntry) key() string {
+	return fmt.Sprintf("%s:%d:%s:%s:%d", e.Repository, e.PRNumber, e.CommentHash, e.Type, e.RecordedAt.UnixNano())
 }
 
 // RecordAcceptance records that a suggestion was accepted
@@ -126,24 +181,44 @@ func (t *Tracker) RecordResolution(commentHash, filePath string, line int, issue
 	})
 }
 
-// RecordReaction records a thumbs up/down reaction
-func (t *Tracker) RecordReaction(commentHash string, isPositive bool) {
-	feedbackType := FeedbackThumbsUp
-	if !isPositive {
-		feedbackType = FeedbackThumbsDown
-	}
+// RecordReaction records a thumbs up/down reaction by reactor
+func (t *Tracker) RecordReaction(commentHash, filePath string, line int, reactor string, isPositive bool) {
 	t.RecordFeedback(FeedbackEntry{
 		CommentHash: commentHash,
-		Type:        feedbackType,
+		Type:        reactionType(isPositive),
+		FilePath:    filePath,
+		Line:        line,
+		Reactor:     reactor,
 	})
 }
 
+// HasReaction reports whether reactor's thumbs up/down on a comment of this PR was already
+// recorded, so collecting reactions again doesn't count them twice
+func (t *Tracker) HasReaction(commentHash, reactor string, isPositive bool) bool {
+	feedbackType := reactionType(isPositive)
+	for _, entry := range t.Entries {
+		if entry.Repository == t.Repository && entry.PRNumber == t.PRNumber &&
+			entry.CommentHash == commentHash && entry.Reactor == reactor && entry.Type == feedbackType {
+			return true
+		}
+	}
+	return false
+}
+
+func reactionType(isPositive bool) FeedbackType {
+	if isPositive {
+		return FeedbackThumbsUp
+	}
+	return FeedbackThumbsDown
+}
+
 // GetStats computes statistics from recorded feedback
 func (t *Tracker) GetStats() *FeedbackStats {
 	stats := &FeedbackStats{
 		TotalComments:    len(t.Entries),
 		ByIssueType:      make(map[string]IssueStats),
 		ByRepository:     make(map[string]RepoStats),
+		ByMonth:          make(map[string]IssueStats),
 		CommonDismissals: []string{},
 		UpdatedAt:        time.Now(),
 	}
@@ -188,6 +263,19 @@ func (t *Tracker) GetStats() *FeedbackStats {
 			}
 			stats.ByRepository[entry.Repository] = repoStats
 		}
+
+		// Track by month, so acceptance can be followed over time
+		if !entry.RecordedAt.IsZero() {
+			month := entry.RecordedAt.Format("2006-01")
+			monthStats := stats.ByMonth[month]
+			monthStats.Total++
+			if entry.Type == FeedbackAccepted || entry.Type == FeedbackResolved {
+				monthStats.Accepted++
+			} else if entry.Type == FeedbackDismissed {
+				monthStats.Dismissed++
+			}
+			stats.ByMonth[month] = monthStats
+		}
 	}
 
 	// Calculate acceptance rates
@@ -202,6 +290,13 @@ func (t *Tracker) GetStats() *FeedbackStats {
 		}
 	}
 
+	for month, monthStats := range stats.ByMonth {
+		if monthStats.Total > 0 {
+			monthStats.AcceptanceRate = float64(monthStats.Accepted) / float64(monthStats.Total)
+			stats.ByMonth[month] = monthStats
+		}
+	}
+
 	for repo, repoStats := range stats.ByRepository {
 		if repoStats.Total > 0 {
 			repoStats.AcceptanceRate = float64(repoStats.Accepted) / float64(repoStats.Total)
@@ -221,12 +316,12 @@ func (t *Tracker) GetStats() *FeedbackStats {
 
 // ExtractFeedbackFromBody extracts feedback data from a PR body
 func ExtractFeedbackFromBody(body string) []FeedbackEntry {
-	startIdx := strings.Index(body, FeedbackMarker)
+	startIdx, marker := markers.Index(body, FeedbackMarker(), legacyFeedbackMarker)
 	if startIdx == -1 {
 		return nil
 	}
 
-	jsonStart := startIdx + len(FeedbackMarker)
+	jsonStart := startIdx + len(marker)
 	endIdx := strings.Index(body[jsonStart:], "-->")
 	if endIdx == -1 {
 		return nil
@@ -248,12 +343,12 @@ func CreateFeedbackMarker(entries []FeedbackEntry) string {
 	if err != nil {
 		return ""
 	}
-	return fmt.Sprintf("%s%s-->", FeedbackMarker, string(data))
+	return fmt.Sprintf("%s%s-->", FeedbackMarker(), string(data))
 }
 
 // StripFeedbackMarker removes the feedback marker from a PR body
 func StripFeedbackMarker(body string) string {
-	startIdx := strings.Index(body, FeedbackMarker)
+	startIdx, _ := markers.Index(body, FeedbackMarker(), legacyFeedbackMarker)
 	if startIdx == -1 {
 		return body
 	}
@@ -301,6 +396,22 @@ func (t *Tracker) GetLearnings() string {
 		sb.WriteString("\n")
 	}
 
+	if len(stats.ByMonth) > 1 {
+		months := make([]string, 0, len(stats.ByMonth))
+		for month := range stats.ByMonth {
+			months = append(months, month)
+		}
+		sort.Strings(months)
+
+		sb.WriteString("**Over Time:**\n")
+		for _, month := range months {
+			monthStats := stats.ByMonth[month]
+			sb.WriteString(fmt.Sprintf("- %s: %.1f%% acceptance (%d total)\n",
+				month, monthStats.AcceptanceRate*100, monthStats.Total))
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(stats.CommonDismissals) > 0 {
 		sb.WriteString("**Common Dismissal Reasons:**\n")
 		for _, reason := range stats.CommonDismissals {
@@ -11,8 +11,8 @@ func TestTrackerRecordFeedback(t *testing.T) {
 	tracker.RecordAcceptance("hash1", "file.go", 10, "bug", true)
 	tracker.RecordDismissal("hash2", "file.go", 20, "style", "false positive")
 	tracker.RecordResolution("hash3", "file.go", 30, "security")
-	tracker.RecordReaction("hash4", true)
-	tracker.RecordReaction("hash5", false)
+	tracker.RecordReaction("hash4", "file.go", 40, "alice", true)
+	tracker.RecordReaction("hash5", "file.go", 50, "bob", false)
 
 	if len(tracker.Entries) != 5 {
 		t.Errorf("Expected 5 entries, got %d", len(tracker.Entries))
@@ -29,6 +29,28 @@ func TestTrackerRecordFeedback(t *testing.T) {
 	}
 }
 
+func TestTrackerHasReaction(t *testing.T) {
+	tracker := NewTracker("owner/repo", 123)
+	tracker.RecordReaction("hash1", "file.go", 10, "alice", true)
+
+	if !tracker.HasReaction("hash1", "alice", true) {
+		t.Error("Expected alice's thumbs up to be recorded")
+	}
+	if tracker.HasReaction("hash1", "alice", false) {
+		t.Error("Expected no thumbs down from alice")
+	}
+	if tracker.HasReaction("hash1", "bob", true) {
+		t.Error("Expected no reaction from bob")
+	}
+
+	// The same reaction on another PR is a different entry
+	other := NewTracker("owner/repo", 124)
+	other.Entries = tracker.Entries
+	if other.HasReaction("hash1", "alice", true) {
+		t.Error("Expected reactions to be scoped to the PR")
+	}
+}
+
 func TestTrackerGetStats(t *testing.T) {
 	tracker := NewTracker("owner/repo", 123)
 
@@ -98,7 +120,7 @@ func TestFeedbackMarker(t *testing.T) {
 		t.Error("Expected non-empty marker")
 	}
 
-	if !strings.HasPrefix(marker, FeedbackMarker) {
+	if !strings.HasPrefix(marker, FeedbackMarker()) {
 		t.Error("Marker should start with FeedbackMarker")
 	}
 
@@ -161,7 +183,7 @@ func TestStripFeedbackMarker(t *testing.T) {
 
 	stripped := StripFeedbackMarker(body)
 
-	if strings.Contains(stripped, FeedbackMarker) {
+	if strings.Contains(stripped, FeedbackMarker()) {
 		t.Error("Marker should be stripped")
 	}
 
@@ -4,18 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v60/github"
 	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/markers"
 	"golang.org/x/oauth2"
 )
 
 type Client struct {
-	client *github.Client
-	ctx    context.Context
+	client      *github.Client
+	ctx         context.Context
+	graphqlURL  string
+	rateLimiter *rateLimitTransport
+
+	// Existing review comments by location per PR, so each PR's comments are listed once
+	// until we post to it
+	commentCacheMu sync.Mutex
+	commentCache   map[commentCacheKey]map[string]*ExistingComment
+}
+
+// commentCacheKey identifies a PR in the existing comments cache
+type commentCacheKey struct {
+	owner, repo string
+	number      int
 }
 
 type PRInfo struct {
@@ -24,8 +40,11 @@ type PRInfo struct {
 	Description string
 	Repository  string
 	Owner       string
+	Author      string // Login of the PR author
 	Diff        string
 	HeadSHA     string
+	BaseSHA     string
+	Draft       bool
 }
 
 type GitHubEvent struct {
@@ -33,9 +52,16 @@ type GitHubEvent struct {
 		Number int    `json:"number"`
 		Title  string `json:"title"`
 		Body   string `json:"body"`
-		Head   struct {
+		Draft  bool   `json:"draft"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head struct {
 			SHA string `json:"sha"`
 		} `json:"head"`
+		Base struct {
+			SHA string `json:"sha"`
+		} `json:"base"`
 	} `json:"pull_request"`
 	Repository struct {
 		FullName string `json:"full_name"`
@@ -52,6 +78,8 @@ func NewClient(token, apiURL string) *Client {
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	rateLimiter := newRateLimitTransport(tc.Transport)
+	tc.Transport = rateLimiter
 
 	var client *github.Client
 	if apiURL != "" && apiURL != "https://api.github.com" {
@@ -62,12 +90,28 @@ func NewClient(token, apiURL string) *Client {
 	}
 
 	return &Client{
-		client: client,
-		ctx:    ctx,
+		client:      client,
+		ctx:         ctx,
+		graphqlURL:  graphQLURLFromAPIURL(apiURL),
+		rateLimiter: rateLimiter,
 	}
 }
 
 func (c *Client) GetPRFromEvent(eventPath string) (*PRInfo, error) {
+	event, err := ParseEventFile(eventPath)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := event.Repository.Owner.Login
+	repo := event.Repository.Name
+	prNumber := event.PullRequest.Number
+
+	return c.GetPR(owner, repo, prNumber)
+}
+
+// ParseEventFile reads and parses a GitHub Actions event payload
+func ParseEventFile(eventPath string) (*GitHubEvent, error) {
 	data, err := os.ReadFile(eventPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read GitHub event file: %w", err)
@@ -78,23 +122,46 @@ func (c *Client) GetPRFromEvent(eventPath string) (*PRInfo, error) {
 		return nil, fmt.Errorf("failed to parse GitHub event: %w", err)
 	}
 
+	return &event, nil
+}
+
+// PRInfoFromEvent builds PR info from an event payload and a diff without calling the API
+func PRInfoFromEvent(event *GitHubEvent, diff string) *PRInfo {
 	owner := event.Repository.Owner.Login
-	repo := event.Repository.Name
-	prNumber := event.PullRequest.Number
+	repository := event.Repository.FullName
+	if repository == "" {
+		repository = fmt.Sprintf("%s/%s", owner, event.Repository.Name)
+	}
 
-	return c.GetPR(owner, repo, prNumber)
+	return &PRInfo{
+		Number:      event.PullRequest.Number,
+		Title:       event.PullRequest.Title,
+		Description: event.PullRequest.Body,
+		Repository:  repository,
+		Owner:       owner,
+		Author:      event.PullRequest.User.Login,
+		Diff:        diff,
+		HeadSHA:     event.PullRequest.Head.SHA,
+		BaseSHA:     event.PullRequest.Base.SHA,
+		Draft:       event.PullRequest.Draft,
+	}
 }
 
 func (c *Client) GetPR(owner, repo string, number int) (*PRInfo, error) {
 	pr, _, err := c.client.PullRequests.Get(c.ctx, owner, repo, number)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get PR: %w", err)
+		return nil, fmt.Errorf("failed to get PR: %w", wrapSSOError(err))
 	}
 
-	// Get the diff
+	// Get the diff. GitHub refuses to render very large diffs, so that failure leaves the
+	// diff empty for the caller to reconstruct locally instead of aborting the review.
 	diff, err := c.getPRDiff(owner, repo, number)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get PR diff: %w", err)
+		if !isDiffTooLarge(err) {
+			return nil, err
+		}
+		internal.Logger.Warn("PR diff unavailable from GitHub API", "pr", number, "error", err)
+		diff = ""
 	}
 
 	return &PRInfo{
@@ -103,11 +170,23 @@ func (c *Client) GetPR(owner, repo string, number int) (*PRInfo, error) {
 		Description: pr.GetBody(),
 		Repository:  fmt.Sprintf("%s/%s", owner, repo),
 		Owner:       owner,
+		Author:      pr.GetUser().GetLogin(),
 		Diff:        diff,
 		HeadSHA:     pr.GetHead().GetSHA(),
+		BaseSHA:     pr.GetBase().GetSHA(),
+		Draft:       pr.GetDraft(),
 	}, nil
 }
 
+// GetPRBody fetches the current description of a PR, without its diff
+func (c *Client) GetPRBody(owner, repo string, number int) (string, error) {
+	pr, _, err := c.client.PullRequests.Get(c.ctx, owner, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("failed to get PR: %w", wrapSSOError(err))
+	}
+	return pr.GetBody(), nil
+}
+
 func (c *Client) GetPRFromURL(url string) (*PRInfo, error) {
 	// Parse GitHub PR URL: https://github.com/owner/repo/pull/123
 	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
@@ -130,7 +209,20 @@ func (c *Client) getPRDiff(owner, repo string, number int) (string, error) {
 		Type: github.Diff,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get PR diff: %w", err)
+		return "", fmt.Errorf("failed to get PR diff: %w", wrapSSOError(err))
+	}
+
+	return diff, nil
+}
+
+// CompareCommits fetches the diff between two commits from the compare API, so it works
+// without a local checkout of either commit
+func (c *Client) CompareCommits(owner, repo, base, head string) (string, error) {
+	diff, _, err := c.client.Repositories.CompareCommitsRaw(c.ctx, owner, repo, base, head, github.RawOptions{
+		Type: github.Diff,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compare %s...%s: %w", base, head, wrapSSOError(err))
 	}
 
 	return diff, nil
@@ -147,18 +239,31 @@ func (c *Client) UpdatePR(owner, repo string, number int, title, body *string) e
 
 	_, _, err := c.client.PullRequests.Edit(c.ctx, owner, repo, number, update)
 	if err != nil {
-		return fmt.Errorf("failed to update PR: %w", err)
+		return fmt.Errorf("failed to update PR: %w", wrapSSOError(err))
 	}
 
 	return nil
 }
 
-// BotCommentMarker is used to identify comments created by this bot
-const BotCommentMarker = "<!-- manque-ai-bot -->"
+// BotCommentMarker returns the marker used to identify comments created by this bot
+func BotCommentMarker() string {
+	return markers.Tag("bot")
+}
+
+// BreakingChangeMarker returns the marker identifying the sticky breaking change report comment
+func BreakingChangeMarker() string {
+	return markers.Tag("breaking-changes")
+}
+
+// StateCommentMarker returns the marker identifying the comment that stores review state
+// when it is kept out of the PR body
+func StateCommentMarker() string {
+	return markers.Tag("review-state")
+}
 
 func (c *Client) CreateComment(owner, repo string, number int, body string) error {
 	// Add marker to identify bot comments
-	markedBody := BotCommentMarker + "\n" + body
+	markedBody := BotCommentMarker() + "\n" + body
 	comment := &github.IssueComment{
 		Body: &markedBody,
 	}
@@ -173,17 +278,35 @@ func (c *Client) CreateComment(owner, repo string, number int, body string) erro
 
 // FindBotComment finds an existing comment created by this bot
 func (c *Client) FindBotComment(owner, repo string, number int) (*github.IssueComment, error) {
-	comments, _, err := c.client.Issues.ListComments(c.ctx, owner, repo, number, &github.IssueListCommentsOptions{
+	return c.findCommentWithMarker(owner, repo, number, BotCommentMarker())
+}
+
+// FindStateComment finds the comment storing review state, like FindBotComment does for the
+// main bot comment
+func (c *Client) FindStateComment(owner, repo string, number int) (*github.IssueComment, error) {
+	return c.findCommentWithMarker(owner, repo, number, StateCommentMarker())
+}
+
+// findCommentWithMarker finds an existing comment whose body starts with the given marker
+func (c *Client) findCommentWithMarker(owner, repo string, number int, marker string) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list comments: %w", err)
 	}
 
-	for _, comment := range comments {
-		if comment.Body != nil && strings.HasPrefix(*comment.Body, BotCommentMarker) {
-			return comment, nil
+	for {
+		comments, resp, err := c.client.Issues.ListComments(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		for _, comment := range comments {
+			if comment.Body != nil && strings.HasPrefix(*comment.Body, marker) {
+				return comment, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 
 	return nil, nil
@@ -191,12 +314,18 @@ func (c *Client) FindBotComment(owner, repo string, number int) (*github.IssueCo
 
 // CreateOrUpdateComment creates a new comment or updates an existing bot comment
 func (c *Client) CreateOrUpdateComment(owner, repo string, number int, body string) error {
-	existingComment, err := c.FindBotComment(owner, repo, number)
+	return c.CreateOrUpdateMarkedComment(owner, repo, number, BotCommentMarker(), body)
+}
+
+// CreateOrUpdateMarkedComment creates a comment identified by marker, or updates it in place
+// if one already exists, so separate sticky comments don't overwrite each other
+func (c *Client) CreateOrUpdateMarkedComment(owner, repo string, number int, marker, body string) error {
+	existingComment, err := c.findCommentWithMarker(owner, repo, number, marker)
 	if err != nil {
 		return err
 	}
 
-	markedBody := BotCommentMarker + "\n" + body
+	markedBody := marker + "\n" + body
 
 	if existingComment != nil {
 		// Update existing comment
@@ -220,6 +349,22 @@ func (c *Client) CreateOrUpdateComment(owner, repo string, number int, body stri
 	return nil
 }
 
+// UpdateMarkedComment updates the comment identified by marker in place. Unlike
+// CreateOrUpdateMarkedComment it creates nothing when no such comment exists.
+func (c *Client) UpdateMarkedComment(owner, repo string, number int, marker, body string) error {
+	existingComment, err := c.findCommentWithMarker(owner, repo, number, marker)
+	if err != nil || existingComment == nil {
+		return err
+	}
+
+	markedBody := marker + "\n" + body
+	existingComment.Body = &markedBody
+	if _, _, err := c.client.Issues.EditComment(c.ctx, owner, repo, *existingComment.ID, existingComment); err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
+
 // ListReviewComments lists all review comments on a pull request
 func (c *Client) ListReviewComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
 	opts := &github.PullRequestListCommentsOptions{
@@ -244,27 +389,47 @@ func (c *Client) ListReviewComments(owner, repo string, number int) ([]*github.P
 
 // ExistingComment represents a comment that already exists on a PR
 type ExistingComment struct {
-	ID        int64
-	Path      string
-	StartLine int
-	EndLine   int
-	Body      string
-	IsBot     bool // True if created by manque-ai
+	ID         int64
+	Path       string
+	StartLine  int
+	EndLine    int
+	Body       string
+	LatestBody string // Body of the most recent reply in the thread, or Body if there is none
+	IsBot      bool   // True if created by manque-ai
 }
 
-// GetExistingCommentsByLocation returns existing comments indexed by file:line
+// GetExistingCommentsByLocation returns existing thread root comments indexed by file:line.
+// The result is cached until InvalidateCommentCache is called for the PR and must not be
+// modified.
 func (c *Client) GetExistingCommentsByLocation(owner, repo string, number int) (map[string]*ExistingComment, error) {
+	key := commentCacheKey{owner: owner, repo: repo, number: number}
+	c.commentCacheMu.Lock()
+	cached, ok := c.commentCache[key]
+	c.commentCacheMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
 	comments, err := c.ListReviewComments(owner, repo, number)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make(map[string]*ExistingComment)
+	byID := make(map[int64]*ExistingComment)
 	for _, comment := range comments {
 		if comment.Path == nil {
 			continue
 		}
 
+		// Replies are listed after their root, so the last one seen is the latest
+		if comment.InReplyTo != nil {
+			if root, ok := byID[*comment.InReplyTo]; ok {
+				root.LatestBody = comment.GetBody()
+			}
+			continue
+		}
+
 		startLine := 0
 		endLine := 0
 		if comment.StartLine != nil {
@@ -275,24 +440,46 @@ func (c *Client) GetExistingCommentsByLocation(owner, repo string, number int) (
 		}
 
 		// Create location key
-		key := fmt.Sprintf("%s:%d:%d", *comment.Path, startLine, endLine)
+		locationKey := fmt.Sprintf("%s:%d:%d", *comment.Path, startLine, endLine)
 
 		// Check if this is a bot comment
-		isBot := comment.Body != nil && strings.Contains(*comment.Body, BotCommentMarker)
-
-		result[key] = &ExistingComment{
-			ID:        comment.GetID(),
-			Path:      *comment.Path,
-			StartLine: startLine,
-			EndLine:   endLine,
-			Body:      comment.GetBody(),
-			IsBot:     isBot,
+		isBot := comment.Body != nil && strings.Contains(*comment.Body, BotCommentMarker())
+
+		existing := &ExistingComment{
+			ID:         comment.GetID(),
+			Path:       *comment.Path,
+			StartLine:  startLine,
+			EndLine:    endLine,
+			Body:       comment.GetBody(),
+			LatestBody: comment.GetBody(),
+			IsBot:      isBot,
 		}
+		result[locationKey] = existing
+		byID[existing.ID] = existing
+	}
+
+	c.commentCacheMu.Lock()
+	if c.commentCache == nil {
+		c.commentCache = make(map[commentCacheKey]map[string]*ExistingComment)
 	}
+	c.commentCache[key] = result
+	c.commentCacheMu.Unlock()
 
 	return result, nil
 }
 
+// InvalidateCommentCache drops the cached existing comments of PR number, in any repository,
+// so the next lookup lists them again. Call it after posting comments to the PR.
+func (c *Client) InvalidateCommentCache(number int) {
+	c.commentCacheMu.Lock()
+	defer c.commentCacheMu.Unlock()
+	for key := range c.commentCache {
+		if key.number == number {
+			delete(c.commentCache, key)
+		}
+	}
+}
+
 // ConversationMessage represents a single message in a conversation thread
 type ConversationMessage struct {
 	Author    string
@@ -302,8 +489,21 @@ type ConversationMessage struct {
 	ID        int64
 }
 
-// GetCommentThread gets the conversation thread for a review comment
+// GetCommentThread gets the conversation thread for a review comment.
+// It prefers a single GraphQL query and falls back to reconstructing the thread from REST review comments.
 func (c *Client) GetCommentThread(owner, repo string, number int, commentID int64) ([]ConversationMessage, error) {
+	thread, err := c.FindReviewThread(owner, repo, number, commentID)
+	if err != nil {
+		internal.Logger.Debug("GraphQL thread lookup failed, falling back to REST", "error", err)
+	} else if thread != nil {
+		return thread.Comments, nil
+	}
+
+	return c.getCommentThreadREST(owner, repo, number, commentID)
+}
+
+// getCommentThreadREST reconstructs a review comment thread by listing all review comments
+func (c *Client) getCommentThreadREST(owner, repo string, number int, commentID int64) ([]ConversationMessage, error) {
 	comments, err := c.ListReviewComments(owner, repo, number)
 	if err != nil {
 		return nil, err
@@ -338,7 +538,7 @@ func (c *Client) GetCommentThread(owner, repo string, number int, commentID int6
 			thread = append(thread, ConversationMessage{
 				Author:    comment.GetUser().GetLogin(),
 				Body:      comment.GetBody(),
-				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker),
+				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker()),
 				CreatedAt: comment.GetCreatedAt().String(),
 				ID:        comment.GetID(),
 			})
@@ -348,7 +548,7 @@ func (c *Client) GetCommentThread(owner, repo string, number int, commentID int6
 			thread = append(thread, ConversationMessage{
 				Author:    comment.GetUser().GetLogin(),
 				Body:      comment.GetBody(),
-				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker),
+				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker()),
 				CreatedAt: comment.GetCreatedAt().String(),
 				ID:        comment.GetID(),
 			})
@@ -375,7 +575,7 @@ func (c *Client) GetIssueCommentThread(owner, repo string, number int) ([]Conver
 			thread = append(thread, ConversationMessage{
 				Author:    comment.GetUser().GetLogin(),
 				Body:      comment.GetBody(),
-				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker),
+				IsBot:     strings.Contains(comment.GetBody(), BotCommentMarker()),
 				CreatedAt: comment.GetCreatedAt().String(),
 				ID:        comment.GetID(),
 			})
@@ -390,19 +590,31 @@ func (c *Client) GetIssueCommentThread(owner, repo string, number int) ([]Conver
 	return thread, nil
 }
 
-// ReplyToComment adds a reply to an existing review comment
+// ReplyToComment adds a reply to an existing review comment thread. If commentID turns out
+// to be an issue comment, which has no threads, the reply is posted on the conversation instead.
 func (c *Client) ReplyToComment(owner, repo string, number int, commentID int64, body string) error {
-	comment := &github.PullRequestComment{
-		Body: &body,
+	_, resp, err := c.client.PullRequests.CreateCommentInReplyTo(c.ctx, owner, repo, number, body, commentID)
+	if err == nil {
+		return nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to reply to comment: %w", err)
 	}
 
-	_, _, err := c.client.PullRequests.CreateCommentInReplyTo(c.ctx, owner, repo, number, body, commentID)
-	if err != nil {
+	issueComment, _, getErr := c.client.Issues.GetComment(c.ctx, owner, repo, commentID)
+	if getErr != nil {
 		return fmt.Errorf("failed to reply to comment: %w", err)
 	}
-	_ = comment // unused but keeping for potential future use
+	return c.ReplyToIssueComment(owner, repo, number, issueComment.GetUser().GetLogin(), body)
+}
 
-	return nil
+// ReplyToIssueComment answers a comment on the PR conversation, mentioning its author
+// so the reply is linked to the question
+func (c *Client) ReplyToIssueComment(owner, repo string, number int, author, body string) error {
+	if author != "" {
+		body = fmt.Sprintf("@%s %s", author, body)
+	}
+	return c.CreateComment(owner, repo, number, body)
 }
 
 // UpdateComment updates an existing review comment
@@ -419,11 +631,40 @@ func (c *Client) UpdateComment(owner, repo string, commentID int64, body string)
 	return nil
 }
 
+// DefaultReplyPrefix introduces threaded replies posted on re-review
+const DefaultReplyPrefix = "**Update on re-review:**"
+
 // CreateReviewOptions configures the review creation behavior
 type CreateReviewOptions struct {
-	IsIncremental bool // If true, reply to existing comments instead of creating new ones
+	IsIncremental bool   // If true, reply to existing comments instead of creating new ones
+	ReplyPrefix   string // Prepended to threaded replies; empty posts the comment as is
+}
+
+// replyBody builds a threaded reply from a comment body
+func (o CreateReviewOptions) replyBody(body string) string {
+	if o.ReplyPrefix == "" {
+		return body
+	}
+	return fmt.Sprintf("%s\n\n%s", o.ReplyPrefix, body)
+}
+
+// repeatsThread reports whether body matches the root comment or the latest reply,
+// with or without the reply prefix, so a persisting issue isn't re-posted on every push
+func (o CreateReviewOptions) repeatsThread(existing *ExistingComment, body string) bool {
+	body = strings.TrimSpace(body)
+	latest := strings.TrimSpace(existing.LatestBody)
+	if strings.TrimSpace(existing.Body) == body || latest == body {
+		return true
+	}
+	if o.ReplyPrefix != "" {
+		latest = strings.TrimSpace(strings.TrimPrefix(latest, o.ReplyPrefix))
+	}
+	return latest == body
 }
 
+// approvalBody is submitted with approvals that have no body of their own
+const approvalBody = "✅ No issues found."
+
 func (c *Client) CreateReview(owner, repo string, number int, comments []*github.DraftReviewComment, body *string, action string) error {
 	return c.CreateReviewWithOptions(owner, repo, number, comments, body, action, CreateReviewOptions{})
 }
@@ -463,8 +704,8 @@ func (c *Client) CreateReviewWithOptions(owner, repo string, number int, comment
 		existing, hasExisting := existingByLocation[locationKey]
 
 		if hasExisting {
-			// Check if the content is the same (exact duplicate)
-			if strings.TrimSpace(existing.Body) == strings.TrimSpace(*comment.Body) {
+			// Check if the content is the same as the comment or its latest reply
+			if opts.repeatsThread(existing, *comment.Body) {
 				skippedDuplicates++
 				internal.Logger.Debug("Skipping duplicate comment", "path", *comment.Path, "line", endLine)
 				continue
@@ -472,8 +713,7 @@ func (c *Client) CreateReviewWithOptions(owner, repo string, number int, comment
 
 			// For incremental reviews, reply to existing comment instead of creating new
 			if opts.IsIncremental && existing.IsBot {
-				replyBody := fmt.Sprintf("**Update on re-review:**\n\n%s", *comment.Body)
-				if err := c.ReplyToComment(owner, repo, number, existing.ID, replyBody); err != nil {
+				if err := c.ReplyToComment(owner, repo, number, existing.ID, opts.replyBody(*comment.Body)); err != nil {
 					internal.Logger.Warn("Failed to reply to comment, will create new", "error", err)
 					newComments = append(newComments, comment)
 				} else {
@@ -488,22 +728,37 @@ func (c *Client) CreateReviewWithOptions(owner, repo string, number int, comment
 		newComments = append(newComments, comment)
 	}
 
+	// Replies change the threads, so later lookups list them again
+	if threadedReplies > 0 {
+		c.InvalidateCommentCache(number)
+	}
+
 	internal.Logger.Debug("Comment processing complete",
 		"new_comments", len(newComments),
 		"skipped_duplicates", skippedDuplicates,
 		"threaded_replies", threadedReplies)
 
-	// 3. If nothing new to post, just return (or post body if it's new)
-	if len(newComments) == 0 && (body == nil || *body == "") {
-		internal.Logger.Debug("No new comments to post, returning early")
-		return nil
-	}
-
 	// Use provided action or default to COMMENT
 	event := action
 	if event == "" {
 		event = "COMMENT"
 	}
+
+	// 3. If nothing new to post, just return (or post body if it's new). Threaded replies don't
+	// change the PR's review status, so an approval or change request is still submitted.
+	if len(newComments) == 0 && (body == nil || *body == "") {
+		if event == "COMMENT" {
+			internal.Logger.Debug("No new comments to post, returning early")
+			return nil
+		}
+		// GitHub requires a body for REQUEST_CHANGES reviews, and an approval without one
+		// doesn't say why the PR was approved
+		summary := fmt.Sprintf("Updated review: %d issue(s) followed up in their existing threads.", threadedReplies)
+		if event == "APPROVE" && threadedReplies == 0 {
+			summary = approvalBody
+		}
+		body = &summary
+	}
 	review := &github.PullRequestReviewRequest{
 		Body:     body,
 		Event:    &event,
@@ -512,6 +767,7 @@ func (c *Client) CreateReviewWithOptions(owner, repo string, number int, comment
 
 	internal.Logger.Debug("Posting review to GitHub", "comment_count", len(newComments))
 	_, _, err = c.client.PullRequests.CreateReview(c.ctx, owner, repo, number, review)
+	c.InvalidateCommentCache(number)
 	if err != nil {
 		return fmt.Errorf("failed to create review: %w", err)
 	}
@@ -1,33 +1,41 @@
 package github
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/igcodinap/manque-ai/internal"
 )
 
 func TestBotCommentMarker(t *testing.T) {
 	// Verify the marker is an HTML comment (hidden in GitHub UI)
-	if !strings.HasPrefix(BotCommentMarker, "<!--") {
-		t.Errorf("Bot comment marker should be an HTML comment, got: %s", BotCommentMarker)
+	if !strings.HasPrefix(BotCommentMarker(), "<!--") {
+		t.Errorf("Bot comment marker should be an HTML comment, got: %s", BotCommentMarker())
 	}
-	if !strings.HasSuffix(BotCommentMarker, "-->") {
-		t.Errorf("Bot comment marker should end with -->, got: %s", BotCommentMarker)
+	if !strings.HasSuffix(BotCommentMarker(), "-->") {
+		t.Errorf("Bot comment marker should end with -->, got: %s", BotCommentMarker())
 	}
 }
 
 func TestBotCommentMarker_Identifiable(t *testing.T) {
 	// The marker should contain identifiable text
-	if !strings.Contains(BotCommentMarker, "manque-ai") {
+	if !strings.Contains(BotCommentMarker(), "manque-ai") {
 		t.Errorf("Bot comment marker should contain 'manque-ai' for identification")
 	}
 }
 
 func TestMarkedCommentFormat(t *testing.T) {
 	body := "Test comment body"
-	markedBody := BotCommentMarker + "\n" + body
+	markedBody := BotCommentMarker() + "\n" + body
 
 	// Should start with marker
-	if !strings.HasPrefix(markedBody, BotCommentMarker) {
+	if !strings.HasPrefix(markedBody, BotCommentMarker()) {
 		t.Errorf("Marked body should start with bot marker")
 	}
 
@@ -76,6 +84,16 @@ func TestGitHubEvent_Parsing(t *testing.T) {
 	}
 }
 
+func TestPRInfoFromEvent_Draft(t *testing.T) {
+	var event GitHubEvent
+	if err := json.Unmarshal([]byte(`{"pull_request":{"number":3,"draft":true},"repository":{"full_name":"owner/repo"}}`), &event); err != nil {
+		t.Fatalf("Failed to parse event: %v", err)
+	}
+	if info := PRInfoFromEvent(&event, ""); !info.Draft {
+		t.Error("Expected the PR to be a draft")
+	}
+}
+
 // TestNewClient_StandardGitHub tests client creation for standard GitHub
 func TestNewClient_StandardGitHub(t *testing.T) {
 	client := NewClient("test-token", "https://api.github.com")
@@ -97,3 +115,375 @@ func TestNewClient_EmptyAPIURL(t *testing.T) {
 		t.Error("Expected non-nil client for empty API URL")
 	}
 }
+
+// threadServer serves one bot thread on main.go:5 with a reply, and records posted replies
+func threadServer(t *testing.T, replies *[]string) *Client {
+	t.Helper()
+	return threadReviewServer(t, replies, nil)
+}
+
+// threadReviewServer is threadServer that also records submitted reviews
+func threadReviewServer(t *testing.T, replies *[]string, reviews *[]github.PullRequestReviewRequest) *Client {
+	t.Helper()
+	internal.InitLogger(false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls/1/comments"):
+			w.Write([]byte(`[
+				{"id":10,"path":"main.go","line":5,"body":"<!-- manque-ai-bot -->\nOld issue"},
+				{"id":11,"path":"main.go","line":5,"in_reply_to_id":10,"body":"**Update on re-review:**\n\n<!-- manque-ai-bot -->\nSame issue"}
+			]`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls/1/comments"):
+			body, _ := io.ReadAll(r.Body)
+			var reply struct {
+				Body      string `json:"body"`
+				InReplyTo int64  `json:"in_reply_to"`
+			}
+			json.Unmarshal(body, &reply)
+			if reply.InReplyTo != 10 {
+				t.Errorf("Expected reply to thread root 10, got %d", reply.InReplyTo)
+			}
+			*replies = append(*replies, reply.Body)
+			w.Write([]byte(`{"id":12}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls/1/reviews") && reviews != nil:
+			var review github.PullRequestReviewRequest
+			json.NewDecoder(r.Body).Decode(&review)
+			*reviews = append(*reviews, review)
+			w.Write([]byte(`{"id":30}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("token", server.URL)
+}
+
+func TestGetExistingCommentsByLocation_Cached(t *testing.T) {
+	internal.InitLogger(false)
+	lists := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lists[r.URL.Path]++
+		w.Write([]byte(`[{"id":10,"path":"main.go","line":5,"body":"Old issue"}]`))
+	}))
+	defer server.Close()
+	client := NewClient("token", server.URL)
+
+	for i := 0; i < 2; i++ {
+		existing, err := client.GetExistingCommentsByLocation("owner", "repo", 1)
+		if err != nil {
+			t.Fatalf("GetExistingCommentsByLocation failed: %v", err)
+		}
+		if existing["main.go:0:5"] == nil {
+			t.Fatalf("Expected the comment on main.go:5, got %+v", existing)
+		}
+	}
+	if got := lists["/api/v3/repos/owner/repo/pulls/1/comments"]; got != 1 {
+		t.Errorf("Expected the comments to be listed once across two lookups, got %d (%v)", got, lists)
+	}
+
+	// Other PRs are cached separately
+	if _, err := client.GetExistingCommentsByLocation("owner", "repo", 2); err != nil {
+		t.Fatalf("GetExistingCommentsByLocation failed: %v", err)
+	}
+	if got := lists["/api/v3/repos/owner/repo/pulls/2/comments"]; got != 1 {
+		t.Errorf("Expected PR 2 to be listed on its own, got %d", got)
+	}
+
+	client.InvalidateCommentCache(1)
+	client.GetExistingCommentsByLocation("owner", "repo", 1)
+	client.GetExistingCommentsByLocation("owner", "repo", 2)
+	if lists["/api/v3/repos/owner/repo/pulls/1/comments"] != 2 || lists["/api/v3/repos/owner/repo/pulls/2/comments"] != 1 {
+		t.Errorf("Expected only PR 1 to be listed again after invalidating it, got %v", lists)
+	}
+}
+
+func TestFindStateComment_Paginates(t *testing.T) {
+	internal.InitLogger(false)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"id":7,"body":` + strconv.Quote(StateCommentMarker()+"\nstate") + `}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+server.URL+r.URL.Path+`?page=2>; rel="next"`)
+		w.Write([]byte(`[{"id":1,"body":"LGTM"}]`))
+	}))
+	defer server.Close()
+
+	comment, err := NewClient("token", server.URL).FindStateComment("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FindStateComment failed: %v", err)
+	}
+	if comment == nil || comment.GetID() != 7 {
+		t.Errorf("Expected the marked comment on the second page, got %+v", comment)
+	}
+}
+
+func TestGetPR_DiffErrors(t *testing.T) {
+	internal.InitLogger(false)
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		expectErr bool
+	}{
+		{"too large", http.StatusNotAcceptable, `{"message":"Sorry, the diff exceeded the maximum number of files (300)."}`, false},
+		{"too_large code", http.StatusUnprocessableEntity, `{"message":"Validation Failed","errors":[{"resource":"PullRequest","code":"too_large"}]}`, false},
+		{"server error", http.StatusInternalServerError, `{"message":"Server Error"}`, true},
+		{"unauthorized", http.StatusUnauthorized, `{"message":"Bad credentials"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.Header.Get("Accept"), "diff") {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+					return
+				}
+				w.Write([]byte(`{"number":1,"title":"Add feature"}`))
+			}))
+			defer server.Close()
+
+			prInfo, err := NewClient("token", server.URL).GetPR("owner", "repo", 1)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected the diff error to be returned, got %+v", prInfo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected an oversized diff to be left empty, got %v", err)
+			}
+			if prInfo.Diff != "" || prInfo.Title != "Add feature" {
+				t.Errorf("Expected the PR without a diff, got %+v", prInfo)
+			}
+		})
+	}
+}
+
+func TestCreateReviewWithOptions_SkipsRepeatedReply(t *testing.T) {
+	var replies []string
+	client := threadServer(t, &replies)
+
+	comments := []*github.DraftReviewComment{{
+		Path: github.String("main.go"),
+		Line: github.Int(5),
+		Body: github.String(BotCommentMarker() + "\nSame issue"),
+	}}
+	opts := CreateReviewOptions{IsIncremental: true, ReplyPrefix: DefaultReplyPrefix}
+	if err := client.CreateReviewWithOptions("owner", "repo", 1, comments, nil, "COMMENT", opts); err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+	if len(replies) != 0 {
+		t.Errorf("Expected no reply when it repeats the latest one, got %q", replies)
+	}
+}
+
+func TestCreateReviewWithOptions_ReplyPrefix(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{DefaultReplyPrefix, "**Update on re-review:**\n\n" + BotCommentMarker() + "\nNew issue"},
+		{"", BotCommentMarker() + "\nNew issue"},
+	}
+
+	for _, tt := range tests {
+		var replies []string
+		client := threadServer(t, &replies)
+
+		comments := []*github.DraftReviewComment{{
+			Path: github.String("main.go"),
+			Line: github.Int(5),
+			Body: github.String(BotCommentMarker() + "\nNew issue"),
+		}}
+		opts := CreateReviewOptions{IsIncremental: true, ReplyPrefix: tt.prefix}
+		if err := client.CreateReviewWithOptions("owner", "repo", 1, comments, nil, "COMMENT", opts); err != nil {
+			t.Fatalf("CreateReviewWithOptions failed: %v", err)
+		}
+		if len(replies) != 1 || replies[0] != tt.expected {
+			t.Errorf("Prefix %q: expected reply %q, got %q", tt.prefix, tt.expected, replies)
+		}
+	}
+}
+
+func TestCreateReviewWithOptions_IncrementalRequestChanges(t *testing.T) {
+	var replies []string
+	var reviews []github.PullRequestReviewRequest
+	client := threadReviewServer(t, &replies, &reviews)
+
+	threaded := &github.DraftReviewComment{
+		Path: github.String("main.go"),
+		Line: github.Int(5),
+		Body: github.String(BotCommentMarker() + "\nStill unchecked"),
+	}
+	critical := &github.DraftReviewComment{
+		Path: github.String("main.go"),
+		Line: github.Int(12),
+		Body: github.String(BotCommentMarker() + "\n🔴 SQL injection"),
+	}
+	opts := CreateReviewOptions{IsIncremental: true}
+	err := client.CreateReviewWithOptions("owner", "repo", 1, []*github.DraftReviewComment{threaded, critical},
+		github.String("## 🚫 Code Review Summary"), "REQUEST_CHANGES", opts)
+	if err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+
+	if len(replies) != 1 {
+		t.Errorf("Expected the existing thread to get a reply, got %q", replies)
+	}
+	if len(reviews) != 1 || reviews[0].GetEvent() != "REQUEST_CHANGES" {
+		t.Fatalf("Expected a REQUEST_CHANGES review, got %+v", reviews)
+	}
+	if len(reviews[0].Comments) != 1 || reviews[0].Comments[0].GetLine() != 12 {
+		t.Errorf("Expected only the new critical comment in the review, got %+v", reviews[0].Comments)
+	}
+}
+
+func TestCreateReviewWithOptions_ApproveWithoutComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     *string
+		expected string
+	}{
+		{"with body", github.String("## ✅ Code Review Summary"), "## ✅ Code Review Summary"},
+		{"without body", nil, approvalBody},
+	}
+
+	for _, tt := range tests {
+		var replies []string
+		var reviews []github.PullRequestReviewRequest
+		client := threadReviewServer(t, &replies, &reviews)
+
+		if err := client.CreateReviewWithOptions("owner", "repo", 1, nil, tt.body, "APPROVE", CreateReviewOptions{}); err != nil {
+			t.Fatalf("%s: CreateReviewWithOptions failed: %v", tt.name, err)
+		}
+		if len(reviews) != 1 || reviews[0].GetEvent() != "APPROVE" {
+			t.Fatalf("%s: expected an APPROVE review, got %+v", tt.name, reviews)
+		}
+		if reviews[0].GetBody() != tt.expected {
+			t.Errorf("%s: expected body %q, got %q", tt.name, tt.expected, reviews[0].GetBody())
+		}
+		if len(reviews[0].Comments) != 0 {
+			t.Errorf("%s: expected no inline comments, got %+v", tt.name, reviews[0].Comments)
+		}
+	}
+}
+
+func TestCreateReviewWithOptions_IncrementalAllThreaded(t *testing.T) {
+	var replies []string
+	var reviews []github.PullRequestReviewRequest
+	client := threadReviewServer(t, &replies, &reviews)
+
+	comments := []*github.DraftReviewComment{{
+		Path: github.String("main.go"),
+		Line: github.Int(5),
+		Body: github.String(BotCommentMarker() + "\n🔴 Now critical"),
+	}}
+	opts := CreateReviewOptions{IsIncremental: true}
+	if err := client.CreateReviewWithOptions("owner", "repo", 1, comments, nil, "REQUEST_CHANGES", opts); err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+
+	// The reply alone would leave the PR status unchanged
+	if len(reviews) != 1 || reviews[0].GetEvent() != "REQUEST_CHANGES" || reviews[0].GetBody() == "" {
+		t.Fatalf("Expected a REQUEST_CHANGES review with a body, got %+v", reviews)
+	}
+	if len(reviews[0].Comments) != 0 {
+		t.Errorf("Expected no inline comments in the review, got %+v", reviews[0].Comments)
+	}
+
+	reviews = nil
+	if err := client.CreateReviewWithOptions("owner", "repo", 1, comments, nil, "COMMENT", opts); err != nil {
+		t.Fatalf("CreateReviewWithOptions failed: %v", err)
+	}
+	if len(reviews) != 0 {
+		t.Errorf("Expected no empty COMMENT review, got %+v", reviews)
+	}
+}
+
+// replyServer records replies posted to review threads and to the conversation. Comment 20
+// is an issue comment, so the review reply API returns 404 for it.
+func replyServer(t *testing.T, threadReplies, issueComments *[]string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Body      string `json:"body"`
+			InReplyTo int64  `json:"in_reply_to"`
+		}
+		json.Unmarshal(body, &req)
+
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls/1/comments"):
+			if req.InReplyTo == 20 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"Not Found"}`))
+				return
+			}
+			*threadReplies = append(*threadReplies, req.Body)
+			w.Write([]byte(`{"id":11}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/issues/comments/20"):
+			w.Write([]byte(`{"id":20,"body":"@manque explain","user":{"login":"alice"}}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/issues/1/comments"):
+			*issueComments = append(*issueComments, req.Body)
+			w.Write([]byte(`{"id":21}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("token", server.URL)
+}
+
+func TestReplyToComment_ReviewComment(t *testing.T) {
+	var threadReplies, issueComments []string
+	client := replyServer(t, &threadReplies, &issueComments)
+
+	if err := client.ReplyToComment("owner", "repo", 1, 10, "Answer"); err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if len(threadReplies) != 1 || threadReplies[0] != "Answer" || len(issueComments) != 0 {
+		t.Errorf("Expected a thread reply only, got replies %q and comments %q", threadReplies, issueComments)
+	}
+}
+
+func TestReplyToComment_IssueComment(t *testing.T) {
+	var threadReplies, issueComments []string
+	client := replyServer(t, &threadReplies, &issueComments)
+
+	if err := client.ReplyToComment("owner", "repo", 1, 20, "Answer"); err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if len(threadReplies) != 0 || len(issueComments) != 1 {
+		t.Fatalf("Expected a conversation comment only, got replies %q and comments %q", threadReplies, issueComments)
+	}
+	if issueComments[0] != BotCommentMarker()+"\n@alice Answer" {
+		t.Errorf("Expected the reply to mention the author, got %q", issueComments[0])
+	}
+}
+
+func TestCompareCommits(t *testing.T) {
+	const diff = "diff --git a/main.go b/main.go\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/repos/acme/repo/compare/abc123...def456") {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if accept := r.Header.Get("Accept"); !strings.Contains(accept, "diff") {
+			t.Errorf("Expected a diff media type, got %q", accept)
+		}
+		w.Write([]byte(diff))
+	}))
+	defer server.Close()
+
+	got, err := NewClient("token", server.URL).CompareCommits("acme", "repo", "abc123", "def456")
+	if err != nil {
+		t.Fatalf("CompareCommits failed: %v", err)
+	}
+	if got != diff {
+		t.Errorf("Expected the compare diff, got %q", got)
+	}
+}
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeownersLocations are the paths GitHub reads CODEOWNERS from, in order of precedence
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersRule maps a path pattern to its owners
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Codeowners holds the ownership rules of a repository
+type Codeowners struct {
+	rules []codeownersRule
+}
+
+// LoadCodeowners reads the CODEOWNERS file of a checkout, returning nil if there is none
+func LoadCodeowners(dir string) (*Codeowners, error) {
+	for _, location := range codeownersLocations {
+		data, err := os.ReadFile(filepath.Join(dir, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return ParseCodeowners(string(data)), nil
+	}
+	return nil, nil
+}
+
+// ParseCodeowners parses CODEOWNERS content. Patterns follow gitignore rules, as on GitHub.
+func ParseCodeowners(content string) *Codeowners {
+	codeowners := &Codeowners{}
+
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, err := regexp.Compile(codeownersPatternToRegex(fields[0]))
+		if err != nil {
+			continue
+		}
+		codeowners.rules = append(codeowners.rules, codeownersRule{pattern: pattern, owners: fields[1:]})
+	}
+
+	return codeowners
+}
+
+// Owners returns the owners of a repo-relative path. The last matching rule wins, and a
+// matching rule without owners leaves the path unowned.
+func (c *Codeowners) Owners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// codeownersPatternToRegex converts a gitignore-style pattern to a regular expression
+func codeownersPatternToRegex(pattern string) string {
+	// A slash at the start or in the middle anchors the pattern to the repository root
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var builder strings.Builder
+	builder.WriteString("^")
+	if !anchored {
+		builder.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			builder.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+
+	// A pattern matching a directory also matches everything inside it
+	builder.WriteString("(?:/.*)?$")
+	return builder.String()
+}
+
+// IsTeamMember checks if a user is an active member of an organization team
+func (c *Client) IsTeamMember(org, team, user string) (bool, error) {
+	membership, resp, err := c.client.Teams.GetTeamMembershipBySlug(c.ctx, org, team, user)
+	if resp != nil && resp.StatusCode == 404 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get membership of %s in %s/%s: %w", user, org, team, wrapSSOError(err))
+	}
+	return membership.GetState() == "active", nil
+}
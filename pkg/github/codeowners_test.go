@@ -0,0 +1,64 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeownersOwners(t *testing.T) {
+	codeowners := ParseCodeowners(`# Default owners
+*                   @org/platform
+*.js                @org/frontend   # Inline comment
+/docs/              @writer
+services/payments/  @org/payments @alice
+**/migrations/*.sql @org/dba
+/generated/
+`)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"main.go", "@org/platform"},
+		{"web/app.js", "@org/frontend"},
+		{"docs/guide.md", "@writer"},
+		{"api/docs/guide.md", "@org/platform"},
+		{"services/payments/charge.go", "@org/payments @alice"},
+		{"services/payments/internal/refund.go", "@org/payments @alice"},
+		{"db/migrations/001_init.sql", "@org/dba"},
+		{"migrations/001_init.sql", "@org/dba"},
+		{"generated/types.go", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(codeowners.Owners(tt.path), " "); got != tt.expected {
+			t.Errorf("Owners(%s) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadCodeowners(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	codeowners, err := LoadCodeowners(tmpDir)
+	if err != nil || codeowners != nil {
+		t.Fatalf("Expected nil without a CODEOWNERS file, got %v, %v", codeowners, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".github", "CODEOWNERS"), []byte("* @bob\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	codeowners, err = LoadCodeowners(tmpDir)
+	if err != nil || codeowners == nil {
+		t.Fatalf("Expected CODEOWNERS to load, got %v", err)
+	}
+	if owners := codeowners.Owners("main.go"); len(owners) != 1 || owners[0] != "@bob" {
+		t.Errorf("Expected @bob to own main.go, got %v", owners)
+	}
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// ssoHeader is set by GitHub when a token needs SAML SSO authorization for an organization
+const ssoHeader = "X-GitHub-SSO"
+
+// SSOAuthorizationError is returned when the token is not authorized for an SSO-protected organization
+type SSOAuthorizationError struct {
+	AuthorizationURL string // URL where the user can authorize the token, if GitHub provided one
+	Err              error
+}
+
+func (e *SSOAuthorizationError) Error() string {
+	msg := "GitHub token is not authorized for this organization's SAML SSO. " +
+		"Authorize the token for the organization (GitHub Settings > Developer settings > Personal access tokens > Configure SSO)"
+	if e.AuthorizationURL != "" {
+		msg += " or visit " + e.AuthorizationURL
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *SSOAuthorizationError) Unwrap() error {
+	return e.Err
+}
+
+// ssoAuthorizationURL extracts the authorization URL from an X-GitHub-SSO header
+// like "required; url=https://github.com/orgs/acme/sso?authorization_request=..."
+func ssoAuthorizationURL(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		if url, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return url
+		}
+	}
+	return ""
+}
+
+// checkSSOResponse returns an SSOAuthorizationError if the response is a 403 requiring SSO authorization
+func checkSSOResponse(resp *http.Response, err error) error {
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		return nil
+	}
+
+	header := resp.Header.Get(ssoHeader)
+	if !strings.HasPrefix(header, "required") {
+		return nil
+	}
+
+	return &SSOAuthorizationError{
+		AuthorizationURL: ssoAuthorizationURL(header),
+		Err:              err,
+	}
+}
+
+// wrapSSOError converts go-github errors caused by missing SSO authorization into an
+// actionable SSOAuthorizationError, leaving other errors unchanged
+func wrapSSOError(err error) error {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) {
+		if ssoErr := checkSSOResponse(errResp.Response, err); ssoErr != nil {
+			return ssoErr
+		}
+	}
+	return err
+}
+
+// isDiffTooLarge reports whether GitHub refused to render a diff because of its size, which
+// it answers with a 406 or a too_large error
+func isDiffTooLarge(err error) bool {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return false
+	}
+	if errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotAcceptable {
+		return true
+	}
+	for _, e := range errResp.Errors {
+		if e.Code == "too_large" {
+			return true
+		}
+	}
+	return false
+}
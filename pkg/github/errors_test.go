@@ -0,0 +1,58 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSOAuthorizationURL(t *testing.T) {
+	header := "required; url=https://github.com/orgs/acme/sso?authorization_request=abc"
+	if got := ssoAuthorizationURL(header); got != "https://github.com/orgs/acme/sso?authorization_request=abc" {
+		t.Errorf("Unexpected URL: %s", got)
+	}
+	if got := ssoAuthorizationURL("required"); got != "" {
+		t.Errorf("Expected empty URL, got %s", got)
+	}
+}
+
+func TestGetPR_SSORequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/acme/sso?authorization_request=abc")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource protected by organization SAML enforcement."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", server.URL)
+	_, err := client.GetPR("acme", "repo", 1)
+
+	var ssoErr *SSOAuthorizationError
+	if !errors.As(err, &ssoErr) {
+		t.Fatalf("Expected SSOAuthorizationError, got %v", err)
+	}
+	if ssoErr.AuthorizationURL != "https://github.com/orgs/acme/sso?authorization_request=abc" {
+		t.Errorf("Unexpected authorization URL: %s", ssoErr.AuthorizationURL)
+	}
+	if !strings.Contains(err.Error(), "Authorize the token") {
+		t.Errorf("Expected actionable message, got %s", err.Error())
+	}
+}
+
+func TestGetPR_ForbiddenWithoutSSO(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Forbidden"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", server.URL)
+	_, err := client.GetPR("acme", "repo", 1)
+
+	var ssoErr *SSOAuthorizationError
+	if err == nil || errors.As(err, &ssoErr) {
+		t.Errorf("Expected a plain error for 403 without SSO header, got %v", err)
+	}
+}
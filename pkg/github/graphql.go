@@ -0,0 +1,250 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ReviewThread represents a pull request review thread fetched via GraphQL
+type ReviewThread struct {
+	ID         string // GraphQL node ID, used for resolve/unresolve mutations
+	IsResolved bool
+	IsOutdated bool
+	Path       string
+	Line       int
+	StartLine  int
+	Comments   []ConversationMessage
+}
+
+// ContainsComment reports whether the thread contains the comment with the given REST ID
+func (t *ReviewThread) ContainsComment(commentID int64) bool {
+	for _, comment := range t.Comments {
+		if comment.ID == commentID {
+			return true
+		}
+	}
+	return false
+}
+
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          startLine
+          comments(first: 100) {
+            nodes {
+              databaseId
+              body
+              createdAt
+              author { login }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const resolveReviewThreadMutation = `mutation($threadId: ID!) {
+  resolveReviewThread(input: {threadId: $threadId}) {
+    thread { id isResolved }
+  }
+}`
+
+type reviewThreadsResponse struct {
+	Repository struct {
+		PullRequest struct {
+			ReviewThreads struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					ID         string `json:"id"`
+					IsResolved bool   `json:"isResolved"`
+					IsOutdated bool   `json:"isOutdated"`
+					Path       string `json:"path"`
+					Line       *int   `json:"line"`
+					StartLine  *int   `json:"startLine"`
+					Comments   struct {
+						Nodes []struct {
+							DatabaseID int64  `json:"databaseId"`
+							Body       string `json:"body"`
+							CreatedAt  string `json:"createdAt"`
+							Author     *struct {
+								Login string `json:"login"`
+							} `json:"author"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"nodes"`
+			} `json:"reviewThreads"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+// graphQLURLFromAPIURL derives the GraphQL endpoint from the REST API URL
+func graphQLURLFromAPIURL(apiURL string) string {
+	if apiURL == "" || apiURL == "https://api.github.com" {
+		return "https://api.github.com/graphql"
+	}
+
+	// GitHub Enterprise: https://host/api/v3 -> https://host/api/graphql
+	base := strings.TrimSuffix(apiURL, "/")
+	base = strings.TrimSuffix(base, "/api/v3")
+	return base + "/api/graphql"
+}
+
+// graphQL executes a GraphQL query and decodes the "data" field into out
+func (c *Client) graphQL(query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.graphqlURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("GraphQL request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read GraphQL response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("GraphQL request failed with status %d: %s", resp.StatusCode, string(body))
+		if ssoErr := checkSSOResponse(resp, err); ssoErr != nil {
+			return ssoErr
+		}
+		return err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	if len(envelope.Errors) > 0 {
+		var messages []string
+		for _, e := range envelope.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL error: %s", strings.Join(messages, "; "))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %w", err)
+	}
+
+	return nil
+}
+
+// GetReviewThreads fetches all review threads of a PR, including resolution state, in as few requests as possible
+func (c *Client) GetReviewThreads(owner, repo string, number int) ([]ReviewThread, error) {
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"repo":   repo,
+		"number": number,
+	}
+
+	var threads []ReviewThread
+	for {
+		var data reviewThreadsResponse
+		if err := c.graphQL(reviewThreadsQuery, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to fetch review threads: %w", err)
+		}
+
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, node := range page.Nodes {
+			thread := ReviewThread{
+				ID:         node.ID,
+				IsResolved: node.IsResolved,
+				IsOutdated: node.IsOutdated,
+				Path:       node.Path,
+			}
+			if node.Line != nil {
+				thread.Line = *node.Line
+			}
+			if node.StartLine != nil {
+				thread.StartLine = *node.StartLine
+			}
+			for _, comment := range node.Comments.Nodes {
+				author := ""
+				if comment.Author != nil {
+					author = comment.Author.Login
+				}
+				thread.Comments = append(thread.Comments, ConversationMessage{
+					Author:    author,
+					Body:      comment.Body,
+					IsBot:     strings.Contains(comment.Body, BotCommentMarker()),
+					CreatedAt: comment.CreatedAt,
+					ID:        comment.DatabaseID,
+				})
+			}
+			threads = append(threads, thread)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+
+	return threads, nil
+}
+
+// FindReviewThread returns the review thread containing the given comment, or nil if none does
+func (c *Client) FindReviewThread(owner, repo string, number int, commentID int64) (*ReviewThread, error) {
+	threads, err := c.GetReviewThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range threads {
+		if threads[i].ContainsComment(commentID) {
+			return &threads[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ResolveReviewThread marks a review thread as resolved
+func (c *Client) ResolveReviewThread(threadID string) error {
+	variables := map[string]interface{}{
+		"threadId": threadID,
+	}
+	if err := c.graphQL(resolveReviewThreadMutation, variables, nil); err != nil {
+		return fmt.Errorf("failed to resolve review thread: %w", err)
+	}
+	return nil
+}
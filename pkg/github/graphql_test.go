@@ -0,0 +1,109 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLURLFromAPIURL(t *testing.T) {
+	tests := []struct {
+		apiURL   string
+		expected string
+	}{
+		{"", "https://api.github.com/graphql"},
+		{"https://api.github.com", "https://api.github.com/graphql"},
+		{"https://ghe.example.com/api/v3", "https://ghe.example.com/api/graphql"},
+		{"https://ghe.example.com/api/v3/", "https://ghe.example.com/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		if got := graphQLURLFromAPIURL(tt.apiURL); got != tt.expected {
+			t.Errorf("graphQLURLFromAPIURL(%q) = %q, want %q", tt.apiURL, got, tt.expected)
+		}
+	}
+}
+
+func TestGetReviewThreads(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		if !strings.Contains(req.Query, "reviewThreads") {
+			t.Errorf("expected reviewThreads query, got %s", req.Query)
+		}
+
+		// Serve two pages to exercise pagination
+		if req.Variables["cursor"] == nil {
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"id":"T1","isResolved":true,"isOutdated":false,"path":"main.go","line":12,"startLine":10,
+					"comments":{"nodes":[
+						{"databaseId":101,"body":"<!-- manque-ai-bot -->\nissue","createdAt":"2024-01-01T00:00:00Z","author":{"login":"bot"}},
+						{"databaseId":102,"body":"fixed","createdAt":"2024-01-02T00:00:00Z","author":{"login":"alice"}}
+					]}}]}}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[{"id":"T2","isResolved":false,"isOutdated":true,"path":"util.go","line":null,"startLine":null,
+				"comments":{"nodes":[{"databaseId":201,"body":"question","createdAt":"2024-01-03T00:00:00Z","author":null}]}}]}}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", "")
+	client.graphqlURL = server.URL
+
+	threads, err := client.GetReviewThreads("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("GetReviewThreads returned error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 paginated requests, got %d", requests)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("Expected 2 threads, got %d", len(threads))
+	}
+
+	first := threads[0]
+	if !first.IsResolved || first.Path != "main.go" || first.Line != 12 || first.StartLine != 10 {
+		t.Errorf("Unexpected first thread: %+v", first)
+	}
+	if len(first.Comments) != 2 || !first.Comments[0].IsBot || first.Comments[1].Author != "alice" {
+		t.Errorf("Unexpected first thread comments: %+v", first.Comments)
+	}
+	if !first.ContainsComment(102) || first.ContainsComment(201) {
+		t.Error("ContainsComment returned unexpected result")
+	}
+
+	second := threads[1]
+	if second.IsResolved || !second.IsOutdated || second.Line != 0 {
+		t.Errorf("Unexpected second thread: %+v", second)
+	}
+}
+
+func TestGetReviewThreads_GraphQLError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"Could not resolve to a Repository"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", "")
+	client.graphqlURL = server.URL
+
+	_, err := client.GetReviewThreads("owner", "missing", 1)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("Expected GraphQL error to be surfaced, got %v", err)
+	}
+}
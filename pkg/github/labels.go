@@ -0,0 +1,53 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// ListLabels returns the names of the labels on a PR
+func (c *Client) ListLabels(owner, repo string, number int) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var names []string
+	for {
+		labels, resp, err := c.client.Issues.ListLabelsByIssue(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list labels: %w", wrapSSOError(err))
+		}
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
+
+// AddLabels adds labels to a PR, creating labels the repository doesn't have yet
+func (c *Client) AddLabels(owner, repo string, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if _, _, err := c.client.Issues.AddLabelsToIssue(c.ctx, owner, repo, number, labels); err != nil {
+		return fmt.Errorf("failed to add labels: %w", wrapSSOError(err))
+	}
+	return nil
+}
+
+// RemoveLabel removes a label from a PR. A label that is already gone is not an error.
+func (c *Client) RemoveLabel(owner, repo string, number int, label string) error {
+	resp, err := c.client.Issues.RemoveLabelForIssue(c.ctx, owner, repo, number, label)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to remove label %s: %w", label, wrapSSOError(err))
+	}
+	return nil
+}
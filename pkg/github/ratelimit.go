@@ -0,0 +1,181 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+const (
+	// maxRateLimitRetries is how many times a request rejected by a rate limit is retried
+	maxRateLimitRetries = 3
+	// secondaryLimitWait is GitHub's advised wait after a secondary rate limit response
+	// without Retry-After
+	secondaryLimitWait = time.Minute
+	// maxRateLimitWait caps a single wait, so a run fails instead of stalling until an
+	// hourly reset
+	maxRateLimitWait = 2 * time.Minute
+	// lowRateLimitRemaining is the remaining budget below which requests are spaced out
+	// until the reset
+	lowRateLimitRemaining = 50
+)
+
+// RateLimit is the primary rate limit budget reported by the last GitHub API response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimitTransport spaces out requests when the rate limit budget runs low and retries
+// requests rejected by secondary rate limits, which busy orgs hit when paginating comments
+type rateLimitTransport struct {
+	base  http.RoundTripper
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+
+	mu    sync.Mutex
+	last  RateLimit
+	known bool
+}
+
+func newRateLimitTransport(base http.RoundTripper) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, sleep: sleepContext, now: time.Now}
+}
+
+// sleepContext waits for d, returning early with the context's error if it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := t.throttleWait(); wait > 0 {
+		internal.Logger.Debug("GitHub rate limit budget low, waiting", "wait", wait)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp)
+
+		wait, limited := t.retryWait(resp)
+		if !limited || attempt >= maxRateLimitRetries || wait > maxRateLimitWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		internal.Logger.Warn("GitHub rate limit hit, retrying", "wait", wait, "attempt", attempt+1)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// record keeps the rate limit budget reported by a response
+func (t *rateLimitTransport) record(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.known = true
+}
+
+// rateLimit returns the last reported budget, and whether any response reported one
+func (t *rateLimitTransport) rateLimit() (RateLimit, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last, t.known
+}
+
+// throttleWait spreads the remaining budget over the time left until the reset once it
+// runs low, so pagination slows down instead of hitting the limit
+func (t *rateLimitTransport) throttleWait() time.Duration {
+	limit, ok := t.rateLimit()
+	if !ok || limit.Remaining >= lowRateLimitRemaining {
+		return 0
+	}
+	untilReset := limit.Reset.Sub(t.now())
+	if untilReset <= 0 {
+		return 0
+	}
+	return min(untilReset/time.Duration(limit.Remaining+1), maxRateLimitWait)
+}
+
+// retryWait reports whether a response was rejected by a rate limit and how long to wait
+// before retrying: Retry-After when set, the primary limit's reset when exhausted, or
+// GitHub's advised minute for secondary limits
+func (t *rateLimitTransport) retryWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if limit, ok := t.rateLimit(); ok {
+			return max(limit.Reset.Sub(t.now()), 0), true
+		}
+	}
+	if isSecondaryLimit(resp) {
+		return secondaryLimitWait, true
+	}
+	return 0, false
+}
+
+// isSecondaryLimit checks the error message of a 403 response for a secondary rate limit.
+// The body is restored so the caller can still read it.
+func isSecondaryLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// RateLimit returns the primary rate limit budget reported by the last GitHub API
+// response, and false before any response reported one
+func (c *Client) RateLimit() (RateLimit, bool) {
+	if c.rateLimiter == nil {
+		return RateLimit{}, false
+	}
+	return c.rateLimiter.rateLimit()
+}
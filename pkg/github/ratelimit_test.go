@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igcodinap/manque-ai/internal"
+)
+
+// stubTransport returns the queued responses in order and counts requests
+type stubTransport struct {
+	responses []*http.Response
+	requests  int
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := s.responses[s.requests]
+	s.requests++
+	return resp, nil
+}
+
+func stubResponse(status int, headers map[string]string, body string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func newTestRateLimitTransport(base http.RoundTripper, now time.Time) (*rateLimitTransport, *[]time.Duration) {
+	var slept []time.Duration
+	transport := newRateLimitTransport(base)
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return transport, &slept
+}
+
+func TestRateLimitTransport_RetriesSecondaryLimit(t *testing.T) {
+	internal.InitLogger(false)
+
+	now := time.Unix(1_700_000_000, 0)
+	base := &stubTransport{responses: []*http.Response{
+		stubResponse(http.StatusForbidden, map[string]string{"Retry-After": "30"},
+			`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`),
+		stubResponse(http.StatusOK, map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "4321",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+		}, `[]`),
+	}}
+	transport, slept := newTestRateLimitTransport(base, now)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/repo/pulls/1/comments", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the retry to succeed, got status %d", resp.StatusCode)
+	}
+	if base.requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", base.requests)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 30*time.Second {
+		t.Errorf("Expected one 30s wait from Retry-After, got %v", *slept)
+	}
+
+	client := &Client{rateLimiter: transport}
+	limit, ok := client.RateLimit()
+	if !ok {
+		t.Fatal("Expected the rate limit to be known")
+	}
+	if limit.Remaining != 4321 || limit.Limit != 5000 || !limit.Reset.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected rate limit: %+v", limit)
+	}
+}
+
+func TestRateLimitTransport_SecondaryLimitWithoutRetryAfter(t *testing.T) {
+	internal.InitLogger(false)
+
+	base := &stubTransport{responses: []*http.Response{
+		stubResponse(http.StatusForbidden, nil, `{"message":"You have exceeded a secondary rate limit."}`),
+		stubResponse(http.StatusOK, nil, `[]`),
+	}}
+	transport, slept := newTestRateLimitTransport(base, time.Now())
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/repo/issues/1/comments", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != secondaryLimitWait {
+		t.Errorf("Expected GitHub's advised wait, got %v", *slept)
+	}
+}
+
+func TestRateLimitTransport_DoesNotRetryOtherForbidden(t *testing.T) {
+	base := &stubTransport{responses: []*http.Response{
+		stubResponse(http.StatusForbidden, nil, `{"message":"Resource not accessible by integration"}`),
+	}}
+	transport, slept := newTestRateLimitTransport(base, time.Now())
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/repo", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "Resource not accessible") {
+		t.Errorf("Expected the response body to stay readable, got %q", body)
+	}
+	if base.requests != 1 || len(*slept) != 0 {
+		t.Errorf("Expected no retry, got %d requests and waits %v", base.requests, *slept)
+	}
+}
+
+func TestRateLimitTransport_ThrottlesWhenBudgetLow(t *testing.T) {
+	internal.InitLogger(false)
+
+	now := time.Unix(1_700_000_000, 0)
+	base := &stubTransport{responses: []*http.Response{
+		stubResponse(http.StatusOK, map[string]string{
+			"X-RateLimit-Remaining": "9",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(100*time.Second).Unix(), 10),
+		}, `[]`),
+		stubResponse(http.StatusOK, nil, `[]`),
+	}}
+	transport, slept := newTestRateLimitTransport(base, now)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/repo/pulls/1/comments?page="+strconv.Itoa(i+1), nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The second page waits for its share of the time left: 100s over 10 requests
+	if len(*slept) != 1 || (*slept)[0] != 10*time.Second {
+		t.Errorf("Expected a 10s wait before the next page, got %v", *slept)
+	}
+}
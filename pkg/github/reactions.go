@@ -0,0 +1,42 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Reaction is an emoji reaction on a review comment
+type Reaction struct {
+	ID      int64
+	User    string
+	IsBot   bool   // True if a bot or app reacted
+	Content string // "+1", "-1", "laugh", "confused", "heart", "hooray", "rocket" or "eyes"
+}
+
+// GetCommentReactions lists the reactions on a PR review comment
+func (c *Client) GetCommentReactions(owner, repo string, commentID int64) ([]Reaction, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var reactions []Reaction
+	for {
+		page, resp, err := c.client.Reactions.ListPullRequestCommentReactions(c.ctx, owner, repo, commentID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comment reactions: %w", wrapSSOError(err))
+		}
+		for _, reaction := range page {
+			reactions = append(reactions, Reaction{
+				ID:      reaction.GetID(),
+				User:    reaction.GetUser().GetLogin(),
+				IsBot:   reaction.GetUser().GetType() == "Bot",
+				Content: reaction.GetContent(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return reactions, nil
+}
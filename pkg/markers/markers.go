@@ -0,0 +1,72 @@
+// Package markers names the HTML comments the bot uses to find its own comments and the data
+// it stores in PR descriptions. Every marker is prefixed with a namespace, so several bot
+// instances on one repository (e.g. a staging bot) don't overwrite each other's comments.
+package markers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultNamespace is the marker namespace used when MARKER_NAMESPACE is not set
+const DefaultNamespace = "manque-ai"
+
+var namespace = DefaultNamespace
+
+// namespaceRegex keeps namespaces safe inside HTML comment markers
+var namespaceRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidNamespace reports whether ns can be used as a marker namespace
+func ValidNamespace(ns string) bool {
+	return namespaceRegex.MatchString(ns)
+}
+
+// SetNamespace sets the marker namespace for the process. Empty restores the default.
+// A namespace that could break out of the HTML comment is rejected.
+func SetNamespace(ns string) error {
+	ns = strings.TrimSpace(ns)
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+	if !ValidNamespace(ns) {
+		return fmt.Errorf("invalid marker namespace %q: must contain only letters, digits, '.', '_' and '-'", ns)
+	}
+	namespace = ns
+	return nil
+}
+
+// Namespace returns the current marker namespace
+func Namespace() string {
+	return namespace
+}
+
+// Tag returns a complete marker, e.g. "<!-- manque-ai-bot -->" for name "bot"
+func Tag(name string) string {
+	return "<!-- " + namespace + "-" + name + " -->"
+}
+
+// Data returns the opening of a marker whose data runs up to the closing "-->",
+// e.g. "<!-- manque-ai-session:" for name "session"
+func Data(name string) string {
+	return "<!-- " + namespace + "-" + name + ":"
+}
+
+// Index returns the position of marker in s and the marker found there, or -1 and "".
+// With the default namespace the legacy markers, written before markers were namespaced,
+// are matched too so existing PRs keep their state. Other namespaces ignore them, since
+// they belong to the default instance.
+func Index(s, marker string, legacy ...string) (int, string) {
+	if idx := strings.Index(s, marker); idx != -1 {
+		return idx, marker
+	}
+	if namespace != DefaultNamespace {
+		return -1, ""
+	}
+	for _, old := range legacy {
+		if idx := strings.Index(s, old); idx != -1 {
+			return idx, old
+		}
+	}
+	return -1, ""
+}
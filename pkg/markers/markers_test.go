@@ -0,0 +1,44 @@
+package markers
+
+import "testing"
+
+func TestTagAndData(t *testing.T) {
+	if got := Tag("bot"); got != "<!-- manque-ai-bot -->" {
+		t.Errorf("Unexpected default tag %q", got)
+	}
+
+	SetNamespace("staging-bot")
+	t.Cleanup(func() { SetNamespace("") })
+
+	if got := Tag("bot"); got != "<!-- staging-bot-bot -->" {
+		t.Errorf("Unexpected namespaced tag %q", got)
+	}
+	if got := Data("session"); got != "<!-- staging-bot-session:" {
+		t.Errorf("Unexpected namespaced data marker %q", got)
+	}
+}
+
+func TestSetNamespace_RejectsInvalid(t *testing.T) {
+	t.Cleanup(func() { SetNamespace("") })
+	if err := SetNamespace("bot -->"); err == nil {
+		t.Error("Expected a namespace that closes the comment to be rejected")
+	}
+	if Namespace() != DefaultNamespace {
+		t.Errorf("Expected a rejected namespace to leave the current one, got %q", Namespace())
+	}
+}
+
+func TestIndex_LegacyOnlyForDefaultNamespace(t *testing.T) {
+	body := "Description\n<!-- manque-session:{}-->"
+
+	if idx, marker := Index(body, Data("session"), "<!-- manque-session:"); idx != 12 || marker != "<!-- manque-session:" {
+		t.Errorf("Expected the legacy marker to match, got %d %q", idx, marker)
+	}
+
+	SetNamespace("staging")
+	t.Cleanup(func() { SetNamespace("") })
+
+	if idx, _ := Index(body, Data("session"), "<!-- manque-session:"); idx != -1 {
+		t.Errorf("Expected a custom namespace to ignore legacy markers, got %d", idx)
+	}
+}
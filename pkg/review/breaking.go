@@ -0,0 +1,103 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// Breaking change report destinations
+const (
+	// BreakingOutputOff disables breaking change detection
+	BreakingOutputOff = "off"
+	// BreakingOutputBody appends the report to the AI section of the PR body (default)
+	BreakingOutputBody = "body"
+	// BreakingOutputComment posts the report as its own sticky PR comment
+	BreakingOutputComment = "comment"
+	// BreakingOutputReview includes the report in the review summary
+	BreakingOutputReview = "review"
+)
+
+// FileLoader loads a file's content at a revision
+type FileLoader func(rev, path string) (string, error)
+
+// DetectBreakingChanges compares each changed file between two revisions and returns
+// the reports that contain breaking changes or warnings
+func DetectBreakingChanges(files []diff.FileDiff, load FileLoader, baseRev, headRev string) []*ast.BreakingChangeReport {
+	detector := ast.NewBreakingChangeDetector()
+	var reports []*ast.BreakingChangeReport
+
+	for _, file := range files {
+		if ast.DetectLanguage(file.Filename) == ast.LangUnknown {
+			continue
+		}
+
+		newContent, err := load(headRev, file.Filename)
+		if err != nil {
+			// Deleted files or revisions missing from the checkout
+			internal.Logger.Debug("Skipping breaking change detection", "file", file.Filename, "error", err)
+			continue
+		}
+		oldContent, err := load(baseRev, file.PreviousFilename())
+		if err != nil {
+			oldContent = "" // New file
+		}
+
+		report, err := detector.DetectBreakingChanges(oldContent, newContent, file.Filename)
+		if err != nil {
+			internal.Logger.Debug("Breaking change detection failed", "file", file.Filename, "error", err)
+			continue
+		}
+		if report.HasBreaking || report.WarningCount > 0 {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+// FormatBreakingChanges combines per-file breaking change reports into one markdown section
+func FormatBreakingChanges(reports []*ast.BreakingChangeReport) string {
+	var sections []string
+	for _, report := range reports {
+		if formatted := ast.FormatBreakingChangeReport(report); formatted != "" {
+			sections = append(sections, strings.TrimSpace(formatted))
+		}
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// BreakingChangeComments turns critical breaking changes in Go files into inline comments at
+// the symbol's line. Removals are left to the report, since the symbol has no line in the
+// new file, and other languages are parsed too loosely to comment on their exports.
+func BreakingChangeComments(reports []*ast.BreakingChangeReport) []ai.Comment {
+	var comments []ai.Comment
+	for _, report := range reports {
+		if ast.DetectLanguage(report.FileName) != ast.LangGo {
+			continue
+		}
+		for _, change := range report.Changes {
+			if change.Severity != "critical" || change.Type == ast.BreakingRemoval {
+				continue
+			}
+			content := change.Description + "."
+			if change.Suggestion != "" {
+				content += " " + change.Suggestion + "."
+			}
+			comments = append(comments, ai.Comment{
+				File:      report.FileName,
+				StartLine: change.Line,
+				EndLine:   change.Line,
+				Header:    fmt.Sprintf("🔴 Breaking change to %s", change.Symbol.Name),
+				Content:   content,
+				Label:     "bug",
+				Critical:  true,
+			})
+		}
+	}
+	return comments
+}
@@ -0,0 +1,94 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestDetectBreakingChanges(t *testing.T) {
+	internal.InitLogger(false)
+
+	revisions := map[string]map[string]string{
+		"base": {
+			"api.go":  "package api\n\nfunc GetUser(id int) error {\n\treturn nil\n}\n",
+			"util.go": "package api\n\nfunc helper() {}\n",
+		},
+		"head": {
+			"api.go":  "package api\n\nfunc GetUser(id int, force bool) error {\n\treturn nil\n}\n",
+			"util.go": "package api\n\nfunc helper() {}\n",
+			"new.go":  "package api\n\nfunc Added() {}\n",
+		},
+	}
+	load := func(rev, path string) (string, error) {
+		content, ok := revisions[rev][path]
+		if !ok {
+			return "", fmt.Errorf("%s not found at %s", path, rev)
+		}
+		return content, nil
+	}
+
+	files := []diff.FileDiff{
+		{Filename: "api.go"},
+		{Filename: "util.go"},
+		{Filename: "new.go"},
+		{Filename: "deleted.go"},
+		{Filename: "README.md"},
+	}
+
+	reports := DetectBreakingChanges(files, load, "base", "head")
+	if len(reports) != 1 || reports[0].FileName != "api.go" {
+		t.Fatalf("Expected a single report for api.go, got %+v", reports)
+	}
+
+	formatted := FormatBreakingChanges(reports)
+	if !strings.Contains(formatted, "GetUser") {
+		t.Errorf("Expected formatted report to mention GetUser, got %q", formatted)
+	}
+}
+
+func TestFormatBreakingChangesEmpty(t *testing.T) {
+	if got := FormatBreakingChanges(nil); got != "" {
+		t.Errorf("Expected empty report, got %q", got)
+	}
+}
+
+func TestBreakingChanges_RemovedAndUnexportedFuncs(t *testing.T) {
+	internal.InitLogger(false)
+
+	revisions := map[string]map[string]string{
+		"base": {
+			"api.go": "package api\n\nfunc Delete(id int) error {\n\treturn nil\n}\n\nfunc Fetch() {}\n",
+			"api.py": "def Delete(id):\n    pass\n\ndef Fetch():\n    pass\n",
+		},
+		"head": {
+			"api.go": "package api\n\nfunc fetch() {}\n",
+			"api.py": "def fetch():\n    pass\n",
+		},
+	}
+	load := func(rev, path string) (string, error) {
+		content, ok := revisions[rev][path]
+		if !ok {
+			return "", fmt.Errorf("%s not found at %s", path, rev)
+		}
+		return content, nil
+	}
+
+	reports := DetectBreakingChanges([]diff.FileDiff{{Filename: "api.go"}, {Filename: "api.py"}}, load, "base", "head")
+	formatted := FormatBreakingChanges(reports)
+	if !strings.Contains(formatted, "Critical Breaking Changes") || !strings.Contains(formatted, "Exported function 'Delete' was removed") {
+		t.Errorf("Expected a critical section for the removed func, got %q", formatted)
+	}
+
+	comments := BreakingChangeComments(reports)
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 inline comment for the Go visibility change, got %d: %+v", len(comments), comments)
+	}
+	c := comments[0]
+	if c.File != "api.go" || c.StartLine != 3 || !c.Critical || !strings.HasPrefix(c.Header, "🔴") {
+		t.Errorf("Unexpected comment: %+v", c)
+	}
+}
@@ -0,0 +1,89 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// budgetPriority orders file categories for the diff size budget: code first, then what
+// most often hides bugs, with documentation last
+var budgetPriority = map[FileCategory]int{
+	CategorySource:    0,
+	CategoryTest:      1,
+	CategoryConfig:    2,
+	CategoryInfra:     3,
+	CategoryMigration: 4,
+	CategoryDocs:      5,
+}
+
+// limitDiffSize keeps the highest-priority files whose formatted diffs fit in maxBytes:
+// source files before other categories and smaller files first, ties broken by name so
+// the selection is deterministic. Kept files stay in their diff order. maxBytes <= 0 keeps
+// every file.
+func limitDiffSize(files []diff.FileDiff, maxBytes int) (kept []diff.FileDiff, skipped []string) {
+	if maxBytes <= 0 {
+		return files, nil
+	}
+
+	sizes := make([]int, len(files))
+	total := 0
+	for i, file := range files {
+		sizes[i] = len(diff.FormatForLLM([]diff.FileDiff{file}))
+		total += sizes[i]
+	}
+	if total <= maxBytes {
+		return files, nil
+	}
+
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		fa, fb := files[order[a]], files[order[b]]
+		if pa, pb := budgetPriority[CategorizeFile(fa.Filename)], budgetPriority[CategorizeFile(fb.Filename)]; pa != pb {
+			return pa < pb
+		}
+		if sizes[order[a]] != sizes[order[b]] {
+			return sizes[order[a]] < sizes[order[b]]
+		}
+		return fa.Filename < fb.Filename
+	})
+
+	keep := make([]bool, len(files))
+	used := 0
+	for _, i := range order {
+		if used+sizes[i] <= maxBytes {
+			keep[i] = true
+			used += sizes[i]
+		}
+	}
+
+	for i, file := range files {
+		if keep[i] {
+			kept = append(kept, file)
+		} else {
+			skipped = append(skipped, file.Filename)
+		}
+	}
+	sort.Strings(skipped)
+	return kept, skipped
+}
+
+// FormatSkippedFiles notes the files left out of a review by MAX_TOTAL_DIFF_BYTES, or
+// returns "" when there are none
+func FormatSkippedFiles(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("⚠️ %d file(s) were not reviewed because the diff exceeds the size limit (MAX_TOTAL_DIFF_BYTES):\n", len(files)))
+	for _, file := range files {
+		builder.WriteString(fmt.Sprintf("- `%s`\n", file))
+	}
+	return builder.String()
+}
@@ -0,0 +1,94 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// linesFile builds a file diff adding n lines
+func linesFile(name string, n int) diff.FileDiff {
+	file := diff.FileDiff{Filename: name}
+	hunk := diff.Hunk{NewStart: 1, NewCount: n}
+	for i := 1; i <= n; i++ {
+		hunk.Lines = append(hunk.Lines, diff.Line{Type: diff.LineAdded, Content: strings.Repeat("x", 40), NewNum: i})
+	}
+	file.Hunks = []diff.Hunk{hunk}
+	return file
+}
+
+func TestLimitDiffSize(t *testing.T) {
+	files := []diff.FileDiff{
+		linesFile("db/migrations/001_init.sql", 400),
+		linesFile("docs/guide.md", 5),
+		linesFile("pkg/api/handler.go", 20),
+		linesFile("pkg/api/handler_test.go", 30),
+		linesFile("pkg/store/big.go", 200),
+		linesFile("config/app.yaml", 10),
+		linesFile("pkg/api/router.go", 20),
+	}
+	size := func(names ...string) int {
+		total := 0
+		for _, file := range files {
+			for _, name := range names {
+				if file.Filename == name {
+					total += len(diff.FormatForLLM([]diff.FileDiff{file}))
+				}
+			}
+		}
+		return total
+	}
+
+	// Room for the small source files, the test and the config, but not the big source file
+	budget := size("pkg/api/handler.go", "pkg/api/router.go", "pkg/api/handler_test.go", "config/app.yaml") + 100
+	kept, skipped := limitDiffSize(files, budget)
+
+	var keptNames []string
+	for _, file := range kept {
+		keptNames = append(keptNames, file.Filename)
+	}
+	wantKept := []string{"pkg/api/handler.go", "pkg/api/handler_test.go", "config/app.yaml", "pkg/api/router.go"}
+	if !reflect.DeepEqual(keptNames, wantKept) {
+		t.Errorf("Expected kept files %v in diff order, got %v", wantKept, keptNames)
+	}
+	wantSkipped := []string{"db/migrations/001_init.sql", "docs/guide.md", "pkg/store/big.go"}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("Expected skipped files %v, got %v", wantSkipped, skipped)
+	}
+
+	// Same input, same selection
+	if again, _ := limitDiffSize(files, budget); !reflect.DeepEqual(again, kept) {
+		t.Error("Expected a deterministic selection")
+	}
+
+	if kept, skipped := limitDiffSize(files, 0); len(kept) != len(files) || skipped != nil {
+		t.Errorf("Expected no limit at 0, got %d kept and %v skipped", len(kept), skipped)
+	}
+}
+
+func TestEngine_SkipsFilesOverDiffBudget(t *testing.T) {
+	internal.InitLogger(false)
+	client := &MockAIClient{
+		Summary: &ai.PRSummary{Description: "Summary"},
+		Review:  &ai.ReviewResult{Review: ai.ReviewSummary{Score: 90}},
+	}
+	engine := &Engine{AIClient: client, Config: &internal.Config{MaxTotalDiffBytes: 70000}}
+
+	diffContent := largeFileDiff("main.go") + largeFileDiff("generated/schema.sql") + largeFileDiff("docs/api.md")
+	_, review, err := engine.Review(diffContent)
+	if err != nil {
+		t.Fatalf("Review returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(review.SkippedFiles, []string{"docs/api.md", "generated/schema.sql"}) {
+		t.Errorf("Expected the non-source files skipped, got %v", review.SkippedFiles)
+	}
+	note := FormatOutput(&ai.PRSummary{}, review)
+	if !strings.Contains(note, "2 file(s) were not reviewed") || !strings.Contains(note, "- `generated/schema.sql`") {
+		t.Errorf("Expected a skip note listing the files, got:\n%s", note)
+	}
+}
@@ -0,0 +1,95 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// FileCategory is the kind of file a change touches, used to focus the review prompt
+type FileCategory string
+
+const (
+	CategorySource    FileCategory = "source"
+	CategoryTest      FileCategory = "test"
+	CategoryConfig    FileCategory = "config"
+	CategoryMigration FileCategory = "migration"
+	CategoryInfra     FileCategory = "infra"
+	CategoryDocs      FileCategory = "docs"
+)
+
+// categoryHints tell the LLM what matters most when a chunk is mostly one kind of file.
+// Source files need no hint, the review prompt is written for them.
+var categoryHints = map[FileCategory]string{
+	CategoryTest:      "These are mostly tests; focus on missing assertions and edge cases, flakiness from timing or shared state, and tests that cannot fail.",
+	CategoryConfig:    "These are mostly configuration files; focus on invalid or risky values, committed secrets, environment-specific settings and changed defaults.",
+	CategoryMigration: "These are database migrations; focus on irreversible operations, data loss, missing indexes, long locks on large tables and missing rollbacks.",
+	CategoryInfra:     "These are infrastructure files; focus on public exposure, overly broad permissions, resources that would be destroyed or replaced, and cost.",
+	CategoryDocs:      "These are mostly documentation; focus on accuracy against the code, broken examples and unclear instructions rather than prose style.",
+}
+
+var (
+	migrationDirs = []string{"migrations", "migration", "migrate", "alembic", "flyway", "liquibase"}
+	infraDirs     = []string{"terraform", "infra", "infrastructure", "k8s", "kubernetes", "helm", "charts", "deploy", "deployment", "ansible"}
+	testDirs      = []string{"test", "tests", "spec", "specs", "__tests__", "testdata", "e2e"}
+	docsDirs      = []string{"docs", "doc", "documentation"}
+
+	infraExtensions  = map[string]bool{".tf": true, ".tfvars": true, ".hcl": true, ".nomad": true}
+	docsExtensions   = map[string]bool{".md": true, ".markdown": true, ".rst": true, ".adoc": true}
+	configExtensions = map[string]bool{
+		".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true, ".cfg": true,
+		".conf": true, ".properties": true, ".env": true, ".xml": true,
+	}
+	infraFiles = map[string]bool{"dockerfile": true, "docker-compose.yml": true, "docker-compose.yaml": true, "jenkinsfile": true, "vagrantfile": true}
+)
+
+// CategorizeFile classifies a file by its extension and path conventions
+func CategorizeFile(filename string) FileCategory {
+	path := strings.ToLower(filepath.ToSlash(filename))
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+
+	switch {
+	case inDir(path, migrationDirs) && !docsExtensions[ext]:
+		return CategoryMigration
+	case isTestFile(filename) || inDir(path, testDirs):
+		return CategoryTest
+	case infraExtensions[ext] || infraFiles[base] || strings.HasPrefix(base, "dockerfile.") ||
+		strings.HasPrefix(path, ".github/workflows/") || inDir(path, infraDirs):
+		return CategoryInfra
+	case docsExtensions[ext] || inDir(path, docsDirs):
+		return CategoryDocs
+	case configExtensions[ext] || strings.HasPrefix(base, ".env"):
+		return CategoryConfig
+	}
+	return CategorySource
+}
+
+// inDir reports whether any directory of a slash-separated path is one of dirs
+func inDir(path string, dirs []string) bool {
+	parts := strings.Split(path, "/")
+	for _, part := range parts[:len(parts)-1] {
+		for _, dir := range dirs {
+			if part == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// chunkCategoryHint returns the hint for the category of more than half of a chunk's files,
+// or "" when no category dominates or it has no hint
+func chunkCategoryHint(chunk []diff.FileDiff) string {
+	counts := make(map[FileCategory]int)
+	for _, file := range chunk {
+		counts[CategorizeFile(file.Filename)]++
+	}
+	for category, count := range counts {
+		if count*2 > len(chunk) {
+			return categoryHints[category]
+		}
+	}
+	return ""
+}
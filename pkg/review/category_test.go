@@ -0,0 +1,68 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestCategorizeFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected FileCategory
+	}{
+		{"pkg/review/engine.go", CategorySource},
+		{"src/components/Button.tsx", CategorySource},
+		{"pkg/review/engine_test.go", CategoryTest},
+		{"src/components/Button.test.tsx", CategoryTest},
+		{"tests/test_api.py", CategoryTest},
+		{"src/test/java/com/acme/AppTest.java", CategoryTest},
+		{"db/migrations/0042_add_orders.sql", CategoryMigration},
+		{"db/migrate/20240101_create_users.rb", CategoryMigration},
+		{"alembic/versions/abc123_add_index.py", CategoryMigration},
+		{"db/migrations/README.md", CategoryDocs},
+		{"infra/main.tf", CategoryInfra},
+		{"modules/vpc/variables.tfvars", CategoryInfra},
+		{"Dockerfile", CategoryInfra},
+		{"build/Dockerfile.dev", CategoryInfra},
+		{".github/workflows/ci.yml", CategoryInfra},
+		{"k8s/deployment.yaml", CategoryInfra},
+		{"README.md", CategoryDocs},
+		{"docs/guide/setup.rst", CategoryDocs},
+		{"config/settings.yaml", CategoryConfig},
+		{"package.json", CategoryConfig},
+		{".env.example", CategoryConfig},
+		{"pyproject.toml", CategoryConfig},
+	}
+
+	for _, tt := range tests {
+		if got := CategorizeFile(tt.filename); got != tt.expected {
+			t.Errorf("CategorizeFile(%q) = %s, want %s", tt.filename, got, tt.expected)
+		}
+	}
+}
+
+func TestChunkCategoryHint(t *testing.T) {
+	migrations := []diff.FileDiff{
+		{Filename: "db/migrations/0001_init.sql"},
+		{Filename: "db/migrations/0002_orders.sql"},
+		{Filename: "pkg/orders/store.go"},
+	}
+	if hint := chunkCategoryHint(migrations); !strings.Contains(hint, "database migrations") {
+		t.Errorf("Expected the migration hint, got %q", hint)
+	}
+
+	mixed := []diff.FileDiff{
+		{Filename: "db/migrations/0001_init.sql"},
+		{Filename: "pkg/orders/store.go"},
+	}
+	if hint := chunkCategoryHint(mixed); hint != "" {
+		t.Errorf("Expected no hint when no category dominates, got %q", hint)
+	}
+
+	source := []diff.FileDiff{{Filename: "main.go"}, {Filename: "server.go"}}
+	if hint := chunkCategoryHint(source); hint != "" {
+		t.Errorf("Expected no hint for source files, got %q", hint)
+	}
+}
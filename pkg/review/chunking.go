@@ -0,0 +1,118 @@
+package review
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// Chunk strategies for packing files into LLM requests
+const (
+	// ChunkStrategySize packs files purely by size (default)
+	ChunkStrategySize = "size"
+	// ChunkStrategyByDir keeps files from the same directory in the same chunk
+	ChunkStrategyByDir = "by-dir"
+	// ChunkStrategyByLang keeps files of the same language in the same chunk
+	ChunkStrategyByLang = "by-lang"
+)
+
+// lockfileGroup is the group key for lockfiles, which are always isolated from source files
+const lockfileGroup = "\x00lockfiles"
+
+// languageGroup groups files by detected language, falling back to the extension
+func languageGroup(file diff.FileDiff) string {
+	if lang := ast.DetectLanguage(file.Filename); lang != ast.LangUnknown {
+		return string(lang)
+	}
+	return strings.ToLower(filepath.Ext(file.Filename))
+}
+
+// packBySizeWithoutLockfiles packs files by size alone, except that lockfiles are
+// packed on their own after the source chunks
+func (e *Engine) packBySizeWithoutLockfiles(files []diff.FileDiff) [][]diff.FileDiff {
+	var sources, lockfiles []diff.FileDiff
+	for _, file := range files {
+		if diff.IsLockfile(file.Filename) {
+			lockfiles = append(lockfiles, file)
+		} else {
+			sources = append(sources, file)
+		}
+	}
+	return append(e.packBySize(sources), e.packBySize(lockfiles)...)
+}
+
+// packByGroup packs files so each group stays together in one chunk when it fits.
+// Small groups share chunks; groups over the chunk token budget are split by size.
+// Lockfiles are always packed on their own.
+func (e *Engine) packByGroup(files []diff.FileDiff, groupKey func(diff.FileDiff) string) [][]diff.FileDiff {
+	maxTokens := e.maxChunkTokens()
+
+	type fileGroup struct {
+		key   string
+		files []diff.FileDiff
+		size  int
+	}
+
+	groupsByKey := make(map[string]*fileGroup)
+	var groups []*fileGroup
+	for _, file := range files {
+		key := groupKey(file)
+		if diff.IsLockfile(file.Filename) {
+			key = lockfileGroup
+		}
+
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &fileGroup{key: key}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.files = append(group.files, file)
+		group.size += e.fileTokens(file)
+	}
+
+	// Largest groups first for better packing, ties broken by key for stable output
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].size != groups[j].size {
+			return groups[i].size > groups[j].size
+		}
+		return groups[i].key < groups[j].key
+	})
+
+	var chunks [][]diff.FileDiff
+	var lockfileChunks [][]diff.FileDiff
+	var currentChunk []diff.FileDiff
+	currentSize := 0
+
+	for _, group := range groups {
+		if group.key == lockfileGroup {
+			lockfileChunks = e.packBySize(group.files)
+			continue
+		}
+
+		// Oversized groups can't stay together, split them by size
+		if group.size > maxTokens {
+			chunks = append(chunks, e.packBySize(group.files)...)
+			continue
+		}
+
+		if currentSize+group.size > maxTokens && len(currentChunk) > 0 {
+			chunks = append(chunks, currentChunk)
+			currentChunk = nil
+			currentSize = 0
+		}
+
+		currentChunk = append(currentChunk, group.files...)
+		currentSize += group.size
+	}
+
+	if len(currentChunk) > 0 {
+		chunks = append(chunks, currentChunk)
+	}
+
+	// Lockfiles go last so the first chunk (used for the summary) holds source changes
+	return append(chunks, lockfileChunks...)
+}
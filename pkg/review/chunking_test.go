@@ -0,0 +1,125 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// sizedFile creates a file diff whose formatted size is roughly n characters
+func sizedFile(name string, n int) diff.FileDiff {
+	return diff.FileDiff{
+		Filename: name,
+		Hunks: []diff.Hunk{{
+			Lines: []diff.Line{{Type: diff.LineAdded, Content: strings.Repeat("x", n), NewNum: 1}},
+		}},
+	}
+}
+
+// chunkIndex returns the index of the chunk containing the file, or -1
+func chunkIndex(chunks [][]diff.FileDiff, filename string) int {
+	for i, chunk := range chunks {
+		for _, f := range chunk {
+			if f.Filename == filename {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func TestCreateFileChunks_SizeStrategy(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{ChunkStrategy: ChunkStrategySize}}
+	maxChunkChars := engine.maxChunkTokens() * 4
+
+	files := []diff.FileDiff{
+		sizedFile("a/one.go", maxChunkChars/2),
+		sizedFile("b/two.go", maxChunkChars/2),
+		sizedFile("a/three.go", maxChunkChars/4),
+		sizedFile("package-lock.json", 100),
+	}
+
+	chunks := engine.createFileChunks(files)
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	// Size packing doesn't care about directories
+	if chunkIndex(chunks, "a/one.go") == chunkIndex(chunks, "a/three.go") {
+		t.Error("Expected size strategy to pack by size, not directory")
+	}
+
+	// The lockfile would fit beside the source files, but is still kept apart
+	lockChunk := chunkIndex(chunks, "package-lock.json")
+	if lockChunk != len(chunks)-1 || len(chunks[lockChunk]) != 1 {
+		t.Error("Expected lockfile isolated in the last chunk")
+	}
+}
+
+func TestCreateFileChunks_ByDirStrategy(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{ChunkStrategy: ChunkStrategyByDir}}
+	maxChunkChars := engine.maxChunkTokens() * 4
+
+	files := []diff.FileDiff{
+		sizedFile("a/one.go", maxChunkChars/2),
+		sizedFile("b/two.go", maxChunkChars/2),
+		sizedFile("a/three.go", maxChunkChars/4),
+		sizedFile("go.sum", 100),
+	}
+
+	chunks := engine.createFileChunks(files)
+	if chunkIndex(chunks, "a/one.go") != chunkIndex(chunks, "a/three.go") {
+		t.Error("Expected files in the same directory to share a chunk")
+	}
+	if chunkIndex(chunks, "a/one.go") == chunkIndex(chunks, "b/two.go") {
+		t.Error("Expected directories that don't fit together to be split")
+	}
+
+	lockChunk := chunkIndex(chunks, "go.sum")
+	if lockChunk != len(chunks)-1 || len(chunks[lockChunk]) != 1 {
+		t.Error("Expected lockfile isolated in the last chunk")
+	}
+}
+
+func TestCreateFileChunks_ByLangStrategy(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{ChunkStrategy: ChunkStrategyByLang}}
+	maxChunkChars := engine.maxChunkTokens() * 4
+
+	files := []diff.FileDiff{
+		sizedFile("api/handler.go", maxChunkChars/2),
+		sizedFile("web/app.ts", maxChunkChars/2),
+		sizedFile("web/view.tsx", maxChunkChars/4),
+		sizedFile("pkg/util.go", maxChunkChars/4),
+	}
+
+	chunks := engine.createFileChunks(files)
+	if chunkIndex(chunks, "api/handler.go") != chunkIndex(chunks, "pkg/util.go") {
+		t.Error("Expected Go files to share a chunk")
+	}
+	if chunkIndex(chunks, "web/app.ts") != chunkIndex(chunks, "web/view.tsx") {
+		t.Error("Expected TypeScript files to share a chunk")
+	}
+	if chunkIndex(chunks, "api/handler.go") == chunkIndex(chunks, "web/app.ts") {
+		t.Error("Expected languages that don't fit together to be split")
+	}
+}
+
+func TestCreateFileChunks_OversizedGroupIsSplit(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{ChunkStrategy: ChunkStrategyByDir}}
+	maxChunkChars := engine.maxChunkTokens() * 4
+
+	files := []diff.FileDiff{
+		sizedFile("a/one.go", maxChunkChars*2/3),
+		sizedFile("a/two.go", maxChunkChars*2/3),
+	}
+
+	chunks := engine.createFileChunks(files)
+	if len(chunks) != 2 {
+		t.Errorf("Expected oversized directory split into 2 chunks, got %d", len(chunks))
+	}
+}
@@ -0,0 +1,142 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// testedExtensions are source file types with a test file naming convention we can map to
+var testedExtensions = map[string]bool{
+	".go":   true,
+	".js":   true,
+	".jsx":  true,
+	".ts":   true,
+	".tsx":  true,
+	".py":   true,
+	".java": true,
+}
+
+// isTestFile checks if a file follows a common test file naming convention
+func isTestFile(filename string) bool {
+	base := filepath.Base(filename)
+	return strings.Contains(filename, "_test.go") ||
+		strings.Contains(filename, ".test.") ||
+		strings.Contains(filename, ".spec.") ||
+		strings.Contains(filename, "__tests__") ||
+		(strings.HasSuffix(base, ".py") && (strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py"))) ||
+		strings.HasSuffix(base, "Test.java")
+}
+
+// testSubject returns the source file stem a test file covers, e.g. "foo" for
+// foo_test.go, foo.test.ts, foo.spec.js, test_foo.py, and FooTest.java -> "Foo"
+func testSubject(testFile string) string {
+	stem := strings.TrimSuffix(filepath.Base(testFile), filepath.Ext(testFile))
+	stem = strings.TrimSuffix(stem, ".test")
+	stem = strings.TrimSuffix(stem, ".spec")
+	stem = strings.TrimSuffix(stem, "_test")
+	stem = strings.TrimPrefix(stem, "test_")
+	if strings.HasSuffix(testFile, "Test.java") {
+		stem = strings.TrimSuffix(stem, "Test")
+	}
+	return stem
+}
+
+// TestCoverageGaps returns the changed source files, with added lines, whose test file isn't
+// changed in the same diff: foo.go needs foo_test.go, Bar.java BarTest.java, and baz.ts
+// baz.test.ts or baz.spec.ts. Matching is by file stem in any directory, so it's a heuristic.
+func TestCoverageGaps(files []diff.FileDiff) []string {
+	var gaps []string
+	for _, file := range untestedFiles(files) {
+		gaps = append(gaps, file.Filename)
+	}
+	return gaps
+}
+
+// untestedFiles returns the files reported by TestCoverageGaps
+func untestedFiles(files []diff.FileDiff) []diff.FileDiff {
+	testedStems := make(map[string]bool)
+	for _, file := range files {
+		if isTestFile(file.Filename) {
+			testedStems[testSubject(file.Filename)] = true
+		}
+	}
+
+	var untested []diff.FileDiff
+	for _, file := range files {
+		if isTestFile(file.Filename) || !testedExtensions[filepath.Ext(file.Filename)] {
+			continue
+		}
+		if firstAddedLine(file) == 0 {
+			continue // Deletions only, nothing new to test
+		}
+
+		stem := strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+		if !testedStems[stem] {
+			untested = append(untested, file)
+		}
+	}
+	return untested
+}
+
+// untestedChanges returns TestCoverageGaps without files exempt from the test check
+func (e *Engine) untestedChanges(files []diff.FileDiff) []diff.FileDiff {
+	var untested []diff.FileDiff
+	for _, file := range untestedFiles(files) {
+		if e.Config != nil && e.Config.IsTestCheckExempt(file.Filename) {
+			internal.Logger.Debug("File exempt from test check", "file", file.Filename)
+			continue
+		}
+		untested = append(untested, file)
+	}
+	return untested
+}
+
+// detectMissingTests emits a warning listing changed source files with no matching test file
+// in the same diff
+func (e *Engine) detectMissingTests(files []diff.FileDiff) []ai.Comment {
+	untested := e.untestedChanges(files)
+	if len(untested) == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, file := range untested {
+		names = append(names, file.Filename)
+	}
+
+	line := firstAddedLine(untested[0])
+	return []ai.Comment{{
+		File:      untested[0].Filename,
+		StartLine: line,
+		EndLine:   line,
+		Header:    "🟡 Changed files without tests",
+		Content:   fmt.Sprintf("The following source files were changed but no corresponding test file was updated:%s\n\nPlease add or update tests, or exempt these paths with `skip_test_check` in `.manque.yml`.", FormatFileList(names)),
+		Label:     "testing",
+	}}
+}
+
+// FormatFileList renders file paths as a Markdown list, each on a new line
+func FormatFileList(files []string) string {
+	var builder strings.Builder
+	for _, file := range files {
+		builder.WriteString(fmt.Sprintf("\n- `%s`", file))
+	}
+	return builder.String()
+}
+
+// firstAddedLine returns the new line number of the first added line in a file diff, or 0
+func firstAddedLine(file diff.FileDiff) int {
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == diff.LineAdded {
+				return line.NewNum
+			}
+		}
+	}
+	return 0
+}
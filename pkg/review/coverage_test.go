@@ -0,0 +1,114 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func addedFile(name string) diff.FileDiff {
+	return diff.FileDiff{
+		Filename: name,
+		Hunks: []diff.Hunk{{
+			Lines: []diff.Line{{Type: diff.LineAdded, Content: "x", NewNum: 10}},
+		}},
+	}
+}
+
+func TestDetectMissingTests(t *testing.T) {
+	internal.InitLogger(false)
+
+	engine := &Engine{Config: &internal.Config{RequireTests: true}}
+	files := []diff.FileDiff{
+		addedFile("pkg/user/user.go"),
+		addedFile("pkg/user/user_test.go"),
+		addedFile("pkg/order/order.go"),
+		addedFile("web/src/cart.ts"),
+		addedFile("web/src/cart.spec.ts"),
+		addedFile("scripts/sync.py"),
+		addedFile("README.md"),
+	}
+
+	comments := engine.detectMissingTests(files)
+	if len(comments) != 1 {
+		t.Fatalf("Expected a single warning comment, got %d", len(comments))
+	}
+
+	c := comments[0]
+	if c.File != "pkg/order/order.go" || c.StartLine != 10 {
+		t.Errorf("Expected comment anchored on first untested file, got %s:%d", c.File, c.StartLine)
+	}
+	if !strings.Contains(c.Content, "pkg/order/order.go") || !strings.Contains(c.Content, "scripts/sync.py") {
+		t.Errorf("Expected untested files listed, got %s", c.Content)
+	}
+	if strings.Contains(c.Content, "user.go") || strings.Contains(c.Content, "cart.ts") || strings.Contains(c.Content, "README.md") {
+		t.Errorf("Did not expect tested or non-source files listed, got %s", c.Content)
+	}
+}
+
+func TestDetectMissingTests_Exemptions(t *testing.T) {
+	internal.InitLogger(false)
+
+	engine := &Engine{Config: &internal.Config{
+		RequireTests: true,
+		PathRules: []internal.PathRule{
+			{Path: "cmd/*", SkipTestCheck: true},
+		},
+	}}
+
+	comments := engine.detectMissingTests([]diff.FileDiff{addedFile("cmd/root.go")})
+	if len(comments) != 0 {
+		t.Errorf("Expected exempt file to be skipped, got %+v", comments)
+	}
+}
+
+func TestTestCoverageGaps(t *testing.T) {
+	files := []diff.FileDiff{
+		addedFile("pkg/user/user.go"),
+		addedFile("pkg/user/user_test.go"),
+		addedFile("pkg/order/order.go"),
+		addedFile("src/main/java/Bar.java"),
+		addedFile("src/test/java/BarTest.java"),
+		addedFile("src/main/java/Baz.java"),
+		addedFile("web/src/baz.ts"),
+		addedFile("web/src/baz.test.ts"),
+		addedFile("web/src/cart.tsx"),
+		addedFile("web/src/list.js"),
+		addedFile("web/src/list.spec.js"),
+		addedFile("app/models.py"),
+		addedFile("tests/test_models.py"),
+		addedFile("app/views.py"),
+		addedFile("README.md"),
+		{Filename: "pkg/legacy/old.go"}, // Deletions only
+	}
+
+	got := TestCoverageGaps(files)
+	want := []string{"pkg/order/order.go", "src/main/java/Baz.java", "web/src/cart.tsx", "app/views.py"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TestCoverageGaps() = %v, want %v", got, want)
+	}
+
+	if gaps := TestCoverageGaps([]diff.FileDiff{addedFile("a.go"), addedFile("a_test.go")}); len(gaps) != 0 {
+		t.Errorf("Expected no gaps when every file's test changed, got %v", gaps)
+	}
+}
+
+func TestTestSubject(t *testing.T) {
+	tests := map[string]string{
+		"pkg/foo_test.go":      "foo",
+		"src/foo.test.ts":      "foo",
+		"src/foo.spec.js":      "foo",
+		"tests/test_foo.py":    "foo",
+		"src/FooTest.java":     "Foo",
+		"src/__tests__/foo.ts": "foo",
+	}
+
+	for file, expected := range tests {
+		if got := testSubject(file); got != expected {
+			t.Errorf("testSubject(%s) = %s, want %s", file, got, expected)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// directiveRegex matches "manque:disable=style,nitpick" and "manque:enable=style" in a comment
+var directiveRegex = regexp.MustCompile(`manque:(disable|enable)=([A-Za-z0-9_-]+(?:\s*,\s*[A-Za-z0-9_-]+)*)`)
+
+// directiveCommentPrefixes are line comment starters for the languages we review
+var directiveCommentPrefixes = []string{"//", "#", "--", "/*", "*", "<!--", ";"}
+
+// fileDirectives records which comment labels are disabled for a file
+type fileDirectives struct {
+	all      bool            // Every label is disabled, except those in enabled
+	disabled map[string]bool // Labels disabled individually
+	enabled  map[string]bool // Labels re-enabled after "disable=all"
+}
+
+// parseDirectives reads manque:disable/enable directives from comment lines, applied in order
+// so later lines can re-enable labels. It returns nil if the file has no directives.
+func parseDirectives(content string) *fileDirectives {
+	var directives *fileDirectives
+
+	for _, line := range strings.Split(content, "\n") {
+		if !isCommentLine(line) {
+			continue
+		}
+		for _, match := range directiveRegex.FindAllStringSubmatch(line, -1) {
+			if directives == nil {
+				directives = &fileDirectives{disabled: make(map[string]bool), enabled: make(map[string]bool)}
+			}
+			for _, label := range strings.Split(match[2], ",") {
+				label = strings.ToLower(strings.TrimSpace(label))
+				if label == "" {
+					continue
+				}
+				if match[1] == "disable" {
+					directives.disable(label)
+				} else {
+					directives.enable(label)
+				}
+			}
+		}
+	}
+
+	return directives
+}
+
+func (d *fileDirectives) disable(label string) {
+	if label == "all" {
+		d.all = true
+		d.enabled = make(map[string]bool)
+		return
+	}
+	d.disabled[label] = true
+	delete(d.enabled, label)
+}
+
+func (d *fileDirectives) enable(label string) {
+	if label == "all" {
+		d.all = false
+		d.disabled = make(map[string]bool)
+		return
+	}
+	delete(d.disabled, label)
+	if d.all {
+		d.enabled[label] = true
+	}
+}
+
+// Suppresses reports whether comments with the given label are disabled
+func (d *fileDirectives) Suppresses(label string) bool {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if d.all {
+		return !d.enabled[label]
+	}
+	return d.disabled[label]
+}
+
+// isCommentLine checks if a line starts with a comment in any supported language
+func isCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range directiveCommentPrefixes {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newFileContent returns the added and unchanged lines of a file diff
+func newFileContent(file diff.FileDiff) string {
+	var builder strings.Builder
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != diff.LineRemoved {
+				builder.WriteString(line.Content)
+				builder.WriteString("\n")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// applyFileDirectives drops comments whose label is disabled by an in-file directive.
+// The full file is read from the local checkout when available, since directives at the
+// top of the file are often outside the diff.
+func (e *Engine) applyFileDirectives(files []diff.FileDiff, comments []ai.Comment) []ai.Comment {
+	directivesByFile := make(map[string]*fileDirectives)
+	for _, file := range files {
+		content := ""
+		if e.ContextFetcher != nil {
+			content, _ = e.ContextFetcher.ReadFile(file.Filename)
+		}
+		if content == "" {
+			content = newFileContent(file)
+		}
+		if directives := parseDirectives(content); directives != nil {
+			directivesByFile[file.Filename] = directives
+		}
+	}
+	if len(directivesByFile) == 0 {
+		return comments
+	}
+
+	var kept []ai.Comment
+	for _, comment := range comments {
+		if directives, ok := directivesByFile[comment.File]; ok && directives.Suppresses(comment.Label) {
+			internal.Logger.Debug("Suppressing comment disabled by file directive", "file", comment.File, "label", comment.Label)
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
@@ -0,0 +1,94 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/context"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestParseDirectives(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		suppressed []string
+		allowed    []string
+	}{
+		{
+			name:       "go line comment",
+			content:    "// manque:disable=style,Nitpick\npackage main\n",
+			suppressed: []string{"style", "nitpick"},
+			allowed:    []string{"bug", "security"},
+		},
+		{
+			name:       "python re-enable",
+			content:    "# manque:disable=style,performance\n# manque:enable=performance\nimport os\n",
+			suppressed: []string{"style"},
+			allowed:    []string{"performance"},
+		},
+		{
+			name:       "disable all except security",
+			content:    "<!-- manque:disable=all -->\n<!-- manque:enable=security -->\n# Title\n",
+			suppressed: []string{"bug", "style"},
+			allowed:    []string{"security"},
+		},
+		{
+			name:    "directive outside a comment is ignored",
+			content: "const help = \"manque:disable=bug\"\n",
+			allowed: []string{"bug"},
+		},
+	}
+
+	for _, tt := range tests {
+		directives := parseDirectives(tt.content)
+		if len(tt.suppressed) == 0 {
+			if directives != nil && directives.Suppresses(tt.allowed[0]) {
+				t.Errorf("%s: expected no directives, got %+v", tt.name, directives)
+			}
+			continue
+		}
+		if directives == nil {
+			t.Fatalf("%s: expected directives to be parsed", tt.name)
+		}
+		for _, label := range tt.suppressed {
+			if !directives.Suppresses(label) {
+				t.Errorf("%s: expected %q to be suppressed", tt.name, label)
+			}
+		}
+		for _, label := range tt.allowed {
+			if directives.Suppresses(label) {
+				t.Errorf("%s: expected %q to be allowed", tt.name, label)
+			}
+		}
+	}
+}
+
+func TestApplyFileDirectives(t *testing.T) {
+	internal.InitLogger(false)
+	dir := t.TempDir()
+	// The directive sits at the top of the file, outside the diff hunk
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("// manque:disable=style\npackage main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	files := []diff.FileDiff{
+		{Filename: "main.go", Hunks: []diff.Hunk{{Lines: []diff.Line{{Type: diff.LineAdded, Content: "func main() {}", NewNum: 4}}}}},
+		{Filename: "util.py", Hunks: []diff.Hunk{{Lines: []diff.Line{{Type: diff.LineAdded, Content: "# manque:disable=bug", NewNum: 1}}}}},
+	}
+	comments := []ai.Comment{
+		{File: "main.go", Label: "style", Header: "Rename"},
+		{File: "main.go", Label: "bug", Header: "Nil check"},
+		{File: "util.py", Label: "bug", Header: "Off by one"},
+		{File: "other.go", Label: "style", Header: "Unrelated"},
+	}
+
+	engine := &Engine{ContextFetcher: context.NewFetcher(dir)}
+	kept := engine.applyFileDirectives(files, comments)
+	if len(kept) != 2 || kept[0].Header != "Nil check" || kept[1].Header != "Unrelated" {
+		t.Errorf("Expected disabled labels to be dropped per file, got %+v", kept)
+	}
+}
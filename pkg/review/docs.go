@@ -0,0 +1,52 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// DetectStaleDocs flags exported Go functions whose signature changed between two revisions
+// while their doc comment was left untouched
+func DetectStaleDocs(files []diff.FileDiff, load FileLoader, baseRev, headRev string) []ai.Comment {
+	var comments []ai.Comment
+
+	for _, file := range files {
+		if ast.DetectLanguage(file.Filename) != ast.LangGo || isTestFile(file.Filename) {
+			continue
+		}
+
+		newContent, err := load(headRev, file.Filename)
+		if err != nil {
+			continue // Deleted file
+		}
+		oldContent, err := load(baseRev, file.PreviousFilename())
+		if err != nil {
+			continue // New file, nothing to compare against
+		}
+
+		changes, err := ast.ChangedSymbols(oldContent, newContent, file.Filename)
+		if err != nil {
+			internal.Logger.Debug("Stale doc detection failed", "file", file.Filename, "error", err)
+			continue
+		}
+
+		for _, mod := range changes.StaleDocs() {
+			comments = append(comments, ai.Comment{
+				File:      file.Filename,
+				StartLine: mod.New.StartLine,
+				EndLine:   mod.New.StartLine,
+				Header:    fmt.Sprintf("🟡 Update the doc comment for %s", mod.New.Name),
+				Content: fmt.Sprintf("The signature of `%s` changed from `%s` to `%s`, but its doc comment was not updated. "+
+					"Check that it still describes the parameters and return values.",
+					mod.New.Name, mod.Old.Signature, mod.New.Signature),
+				Label: "maintainability",
+			})
+		}
+	}
+
+	return comments
+}
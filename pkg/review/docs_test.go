@@ -0,0 +1,42 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestDetectStaleDocs(t *testing.T) {
+	revisions := map[string]map[string]string{
+		"base": {
+			"user.go": "package user\n\n// Get returns a user by id.\nfunc Get(id int) error {\n\treturn nil\n}\n",
+		},
+		"head": {
+			"user.go": "package user\n\n// Get returns a user by id.\nfunc Get(id int, force bool) error {\n\treturn nil\n}\n",
+			"new.go":  "package user\n\n// New creates a user.\nfunc New() {}\n",
+		},
+	}
+	load := func(rev, path string) (string, error) {
+		content, ok := revisions[rev][path]
+		if !ok {
+			return "", fmt.Errorf("%s not found at %s", path, rev)
+		}
+		return content, nil
+	}
+
+	files := []diff.FileDiff{{Filename: "user.go"}, {Filename: "new.go"}, {Filename: "README.md"}}
+	comments := DetectStaleDocs(files, load, "base", "head")
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 stale doc comment, got %d: %+v", len(comments), comments)
+	}
+
+	c := comments[0]
+	if c.File != "user.go" || c.StartLine != 4 || c.Label != "maintainability" {
+		t.Errorf("Unexpected comment: %+v", c)
+	}
+	if !strings.Contains(c.Content, "func Get(id int, force bool) error") {
+		t.Errorf("Expected new signature in comment, got %q", c.Content)
+	}
+}
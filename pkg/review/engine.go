@@ -1,8 +1,10 @@
 package review
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -10,51 +12,189 @@ import (
 	"github.com/igcodinap/manque-ai/pkg/ai"
 	"github.com/igcodinap/manque-ai/pkg/context"
 	"github.com/igcodinap/manque-ai/pkg/diff"
-)
-
-const (
-	// MaxChunkSize is the maximum size of a diff chunk in characters
-	MaxChunkSize = 80000
-	// MinChunkSize is the minimum useful chunk size
-	MinChunkSize = 10000
+	"github.com/igcodinap/manque-ai/pkg/secrets"
+	"github.com/igcodinap/manque-ai/pkg/state"
 )
 
 type Engine struct {
 	AIClient       ai.Client
 	Config         *internal.Config
 	ContextFetcher *context.Fetcher
+	Baseline       *state.Baseline // Known issues filtered out of the results, if any
+	Hooks          []Hook          // Run before and after the LLM review
+	TokenEstimator TokenEstimator  // Sizes diff chunks; DefaultTokenEstimator if nil
+
+	// NoCheckout is set when the repository isn't checked out locally, as in the webhook
+	// server, so blame and other checks reading git or the working tree are skipped
+	NoCheckout bool
 }
 
-func NewEngine(config *internal.Config) (*Engine, error) {
+// newLLMCache returns the response cache configured by LLM_CACHE, or nil when it is disabled
+func newLLMCache(config *internal.Config) ai.CacheBackend {
+	if !config.LLMCache {
+		return nil
+	}
+	dir := config.LLMCacheDir
+	if dir == "" {
+		var err error
+		if dir, err = ai.DefaultCacheDir(); err != nil {
+			internal.Logger.Warn("LLM cache disabled", "error", err)
+			return nil
+		}
+	}
+	return ai.NewFileCache(dir, config.LLMCacheTTL)
+}
+
+// NewAIClient creates the LLM client for config, with the review language, the repository's
+// prompt templates and the response cache applied
+func NewAIClient(config *internal.Config) (ai.Client, error) {
+	if _, ok := ai.ReviewLanguageName(config.ReviewLanguage); config.ReviewLanguage != "" && !ok {
+		internal.Logger.Warn("Unsupported review language, reviewing in English",
+			"language", config.ReviewLanguage, "supported", ai.SupportedReviewLanguages())
+	}
+
+	// A broken template would fail every review, so the built-in prompts are kept instead
+	overrides, err := ai.LoadPromptOverrides(internal.FileConfigDir(config))
+	if err != nil {
+		internal.Logger.Warn("Ignoring prompt templates, using the built-in prompts", "error", err)
+		overrides = nil
+	} else if overrides != nil {
+		internal.Logger.Info("Using prompt templates from the repository", "dir", ai.PromptOverridesDir)
+	}
+
 	aiClient, err := ai.NewClient(ai.Config{
-		Provider: config.LLMProvider,
-		APIKey:   config.LLMAPIKey,
-		Model:    config.LLMModel,
-		BaseURL:  config.LLMBaseURL,
+		Provider:    config.LLMProvider,
+		APIKey:      config.LLMAPIKey,
+		Model:       config.LLMModel,
+		BaseURL:     config.LLMBaseURL,
+		LabelTones:  config.LabelTones,
+		Temperature: config.LLMTemperature,
+		MaxTokens:   config.LLMMaxTokens,
+
+		ReviewLanguage:  config.ReviewLanguage,
+		PromptOverrides: overrides,
+
+		MaxRetries:     config.LLMMaxRetries,
+		RetryBaseDelay: config.LLMRetryBaseDelay,
+
+		DisableJSONMode: !config.LLMJSONMode,
+
+		Cache: newLLMCache(config),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize AI client: %w", err)
 	}
+	return aiClient, nil
+}
+
+func NewEngine(config *internal.Config) (*Engine, error) {
+	aiClient, err := NewAIClient(config)
+	if err != nil {
+		return nil, err
+	}
 
-	// Initialize context fetcher with current working directory
+	// Initialize context fetcher with the configured work dir, or the current working directory
 	var ctxFetcher *context.Fetcher
-	if cwd, err := os.Getwd(); err == nil {
+	if config.WorkDir != "" {
+		ctxFetcher = context.NewFetcher(config.WorkDir)
+	} else if cwd, err := os.Getwd(); err == nil {
 		ctxFetcher = context.NewFetcher(cwd)
 	}
+	if ctxFetcher != nil {
+		ctxFetcher.PathPrefix = config.PathPrefix
+		ctxFetcher.Depth = config.ContextDepth
+		if config.ContextMaxFiles > 0 {
+			ctxFetcher.MaxFiles = config.ContextMaxFiles
+		}
+		if config.ContextMaxBytes > 0 {
+			ctxFetcher.MaxBytes = config.ContextMaxBytes
+		}
+	}
 
 	return &Engine{
 		AIClient:       aiClient,
 		Config:         config,
 		ContextFetcher: ctxFetcher,
+		Baseline:       loadBaseline(config),
 	}, nil
 }
 
+// loadBaseline reads the configured baseline file, returning nil if there is none
+func loadBaseline(config *internal.Config) *state.Baseline {
+	if config.BaselineFile == "" {
+		return nil
+	}
+
+	path := BaselinePath(config)
+	baseline, err := state.LoadBaseline(path)
+	if err != nil {
+		internal.Logger.Warn("Ignoring unreadable baseline", "path", path, "error", err)
+		return nil
+	}
+	if len(baseline.Entries) == 0 {
+		return nil
+	}
+
+	internal.Logger.Info("Loaded baseline of known issues", "path", path, "entries", len(baseline.Entries))
+	return baseline
+}
+
+// BaselinePath resolves the configured baseline file against the work dir
+func BaselinePath(config *internal.Config) string {
+	path := config.BaselineFile
+	if path == "" {
+		path = state.DefaultBaselineFile
+	}
+	if filepath.IsAbs(path) || config.WorkDir == "" {
+		return path
+	}
+	return filepath.Join(config.WorkDir, path)
+}
+
+// BaselineHash identifies a comment in the baseline
+func BaselineHash(comment ai.Comment) string {
+	return state.BaselineHash(comment.File, comment.Label, comment.Content)
+}
+
+// filterBaseline drops comments for issues recorded in the baseline
+func (e *Engine) filterBaseline(comments []ai.Comment) []ai.Comment {
+	if e.Baseline == nil {
+		return comments
+	}
+
+	var kept []ai.Comment
+	for _, comment := range comments {
+		if e.Baseline.Contains(BaselineHash(comment)) {
+			internal.Logger.Debug("Suppressing baselined issue", "file", comment.File, "header", comment.Header)
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	if suppressed := len(comments) - len(kept); suppressed > 0 {
+		internal.Logger.Info(fmt.Sprintf("Suppressed %d known issue(s) from the baseline", suppressed))
+	}
+	return kept
+}
+
 func (e *Engine) Review(diffContent string) (*ai.PRSummary, *ai.ReviewResult, error) {
-	return e.ReviewWithContext("Local Changes", "Review of local changes", diffContent)
+	return e.ReviewStream(diffContent, nil)
+}
+
+// ReviewStream is Review with each chunk's comments passed to onChunk as they arrive
+func (e *Engine) ReviewStream(diffContent string, onChunk func(chunkIndex int, comments []ai.Comment)) (*ai.PRSummary, *ai.ReviewResult, error) {
+	return e.ReviewWithContextStream("Local Changes", "Review of local changes", diffContent, onChunk)
 }
 
 // ReviewWithContext allows passing specific title/description (used by GitHub action)
 func (e *Engine) ReviewWithContext(title, description, diffContent string) (*ai.PRSummary, *ai.ReviewResult, error) {
+	return e.ReviewWithContextStream(title, description, diffContent, nil)
+}
+
+// ReviewWithContextStream reviews like ReviewWithContext, calling onChunk with the comments of
+// each chunk as soon as it is reviewed, so large PRs show progress. chunkIndex is 0-based and
+// failed chunks are skipped. Streamed comments are already filtered by file directives and the
+// baseline; deterministic findings and post-review hooks only show up in the final result.
+func (e *Engine) ReviewWithContextStream(title, description, diffContent string, onChunk func(chunkIndex int, comments []ai.Comment)) (*ai.PRSummary, *ai.ReviewResult, error) {
 	files, err := diff.ParseGitDiff(diffContent)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse diff: %w", err)
@@ -67,79 +207,133 @@ func (e *Engine) ReviewWithContext(title, description, diffContent string) (*ai.
 		return &ai.PRSummary{Description: "No reviewable files"}, &ai.ReviewResult{}, nil
 	}
 
+	// Oversized diffs are reviewed up to MAX_TOTAL_DIFF_BYTES, most important files first
+	var skippedFiles []string
+	if e.Config != nil {
+		filteredFiles, skippedFiles = limitDiffSize(filteredFiles, e.Config.MaxTotalDiffBytes)
+		if len(skippedFiles) > 0 {
+			internal.Logger.Warn("Diff exceeds MAX_TOTAL_DIFF_BYTES, skipping lower-priority files",
+				"max_bytes", e.Config.MaxTotalDiffBytes, "skipped", len(skippedFiles))
+		}
+		if len(filteredFiles) == 0 {
+			return &ai.PRSummary{Description: "No reviewable files"}, &ai.ReviewResult{SkippedFiles: skippedFiles}, nil
+		}
+	}
+
+	// Secrets must be masked before any part of the diff reaches the LLM
+	var secretComments []ai.Comment
+	if e.Config != nil && e.Config.RedactSecrets {
+		filteredFiles, secretComments = redactSecrets(filteredFiles)
+	}
+
+	hookComments := e.runPreReviewHooks(filteredFiles)
+
 	// Create chunks based on file sizes
 	chunks := e.createFileChunks(filteredFiles)
 	internal.Logger.Info(fmt.Sprintf("Processing %d files in %d chunk(s)", len(filteredFiles), len(chunks)))
 
-	// Generate summary using the first chunk (or full diff if small enough)
-	summaryDiff := diff.FormatForLLM(chunks[0])
-	if len(chunks) > 1 {
-		// For summary, use a condensed version of all files
-		summaryDiff = e.createSummaryDiff(filteredFiles)
-	}
+	combinedRules := e.getCombinedRules()
 
-	internal.Logger.Info("Generating PR summary...")
-	summary, err := e.AIClient.GeneratePRSummary(title, description, summaryDiff)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate PR summary: %w", err)
+	// Small PRs get the summary and review from a single request when the client supports it
+	summary, singleCallReview := e.reviewInOneCall(title, description, chunks, combinedRules)
+
+	if summary == nil {
+		// Generate summary using the first chunk (or full diff if small enough)
+		summaryDiff := diff.FormatForLLM(chunks[0])
+		if len(chunks) > 1 {
+			// For summary, use a condensed version of all files
+			summaryDiff = e.createSummaryDiff(filteredFiles)
+		}
+
+		internal.Logger.Info("Generating PR summary...")
+		ctx, cancel := e.Config.OperationContext()
+		summary, err = e.AIClient.GeneratePRSummary(ctx, title, description, summaryDiff)
+		cancel()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate PR summary: %w", err)
+		}
 	}
 
-	// Generate code review for each chunk and aggregate comments
-	combinedRules := e.getCombinedRules()
+	// Generate code review for each chunk and aggregate comments. Scores are averaged over the
+	// chunks that were reviewed, so a failed chunk doesn't drag the aggregate down.
 	var allComments []ai.Comment
-	var totalScore, totalEffort int
+	var totalScore, totalEffort, reviewedChunks int
+	tokensIn, tokensOut := summary.TokensIn, summary.TokensOut
+	// Files shared between chunks, e.g. as referenced context, can yield the same finding twice
+	seen := make(map[string]bool)
+	// Chunks whose response wasn't valid JSON; past MAX_PARSE_FAILURES the model is assumed
+	// unable to follow the format and the remaining chunks aren't sent
+	parseFailures := 0
 
 	for i, chunk := range chunks {
-		chunkDiff := diff.FormatForLLM(chunk)
-
-		// Fetch referenced files for context expansion
-		var contextSection string
-		if e.ContextFetcher != nil {
-			referencedFiles := e.ContextFetcher.FetchReferencedFiles(chunk)
-			if len(referencedFiles) > 0 {
-				contextSection = context.FormatForLLM(referencedFiles)
-				internal.Logger.Debug(fmt.Sprintf("Added %d referenced files to context", len(referencedFiles)))
+		review := singleCallReview
+		if review == nil {
+			fullContext := e.chunkContext(chunk)
+
+			internal.Logger.Info(fmt.Sprintf("Generating code review for chunk %d/%d (%d files, %d chars)...",
+				i+1, len(chunks), len(chunk), len(fullContext)))
+
+			// Each chunk has its own deadline, so a slow chunk is skipped like a failed one
+			ctx, cancel := e.Config.OperationContext()
+			if combinedRules != "" {
+				review, err = e.AIClient.GenerateCodeReviewWithStyleGuide(ctx, title, description, fullContext, combinedRules)
+			} else {
+				review, err = e.AIClient.GenerateCodeReview(ctx, title, description, fullContext)
+			}
+			cancel()
+			if err != nil {
+				internal.Logger.Warn(fmt.Sprintf("Failed to review chunk %d: %v", i+1, err))
+				if errors.Is(err, ai.ErrInvalidJSON) {
+					parseFailures++
+					if e.Config != nil && e.Config.MaxParseFailures > 0 && parseFailures > e.Config.MaxParseFailures {
+						return nil, nil, fmt.Errorf("aborting review: %d chunk(s) returned invalid JSON, more than MAX_PARSE_FAILURES (%d): %w",
+							parseFailures, e.Config.MaxParseFailures, err)
+					}
+				}
+				continue
 			}
 		}
 
-		// Add git blame context for code history
-		blameContext := e.getBlameContext(chunk)
-		if blameContext != "" {
-			contextSection += blameContext
+		comments := dropSeenComments(seen, e.finalizeComments(filteredFiles, e.normalizeLabels(review.Comments)))
+		if onChunk != nil {
+			onChunk(i, comments)
 		}
 
-		// Combine diff with context
-		fullContext := chunkDiff
-		if contextSection != "" {
-			fullContext = chunkDiff + "\n" + contextSection
-		}
+		allComments = append(allComments, comments...)
+		totalScore += review.Review.Score
+		totalEffort += review.Review.EstimatedEffort
+		tokensIn += review.TokensIn
+		tokensOut += review.TokensOut
+		reviewedChunks++
+	}
 
-		internal.Logger.Info(fmt.Sprintf("Generating code review for chunk %d/%d (%d files, %d chars)...",
-			i+1, len(chunks), len(chunk), len(fullContext)))
+	if reviewedChunks == 0 {
+		return nil, nil, fmt.Errorf("failed to review all %d chunk(s)", len(chunks))
+	}
+	if failed := len(chunks) - reviewedChunks; failed > 0 {
+		internal.Logger.Warn(fmt.Sprintf("Reviewed %d/%d chunks, %d failed", reviewedChunks, len(chunks), failed))
+	}
 
-		var review *ai.ReviewResult
-		if combinedRules != "" {
-			review, err = e.AIClient.GenerateCodeReviewWithStyleGuide(title, description, fullContext, combinedRules)
-		} else {
-			review, err = e.AIClient.GenerateCodeReview(title, description, fullContext)
-		}
-		if err != nil {
-			internal.Logger.Warn(fmt.Sprintf("Failed to review chunk %d: %v", i+1, err))
-			continue
-		}
+	var extraComments []ai.Comment
+	if e.Config != nil && e.Config.ReviewMarkdownCode {
+		extraComments = e.normalizeLabels(e.reviewMarkdownCodeBlocks(title, description, filteredFiles))
+	}
 
-		allComments = append(allComments, review.Comments...)
-		totalScore += review.Review.Score
-		totalEffort += review.Review.EstimatedEffort
+	// Add deterministic findings that don't rely on the LLM
+	extraComments = append(extraComments, secretComments...)
+	extraComments = append(extraComments, hookComments...)
+	extraComments = append(extraComments, detectDuplicateLines(filteredFiles)...)
+	if e.Config != nil && e.Config.CheckErrorStrings {
+		extraComments = append(extraComments, detectErrorStringStyle(filteredFiles)...)
+	}
+	if e.Config != nil && e.Config.RequireTests {
+		extraComments = append(extraComments, e.detectMissingTests(filteredFiles)...)
 	}
+	allComments = append(allComments, dropSeenComments(seen, e.finalizeComments(filteredFiles, extraComments))...)
 
 	// Aggregate results
-	avgScore := totalScore
-	avgEffort := totalEffort
-	if len(chunks) > 0 {
-		avgScore = totalScore / len(chunks)
-		avgEffort = totalEffort / len(chunks)
-	}
+	avgScore := totalScore / reviewedChunks
+	avgEffort := totalEffort / reviewedChunks
 
 	aggregatedReview := &ai.ReviewResult{
 		Review: ai.ReviewSummary{
@@ -148,35 +342,214 @@ func (e *Engine) ReviewWithContext(title, description, diffContent string) (*ai.
 			HasRelevantTests: e.hasTestFiles(filteredFiles),
 			SecurityConcerns: e.aggregateSecurityConcerns(allComments),
 		},
-		Comments: allComments,
+		Comments:     allComments,
+		Chunks:       len(chunks),
+		FailedChunks: len(chunks) - reviewedChunks,
+		SkippedFiles: skippedFiles,
+		TokensIn:     tokensIn,
+		TokensOut:    tokensOut,
 	}
+	// With REQUIRE_TESTS the untested files are already listed in a warning comment
+	if e.Config == nil || !e.Config.RequireTests {
+		for _, file := range e.untestedChanges(filteredFiles) {
+			aggregatedReview.UntestedFiles = append(aggregatedReview.UntestedFiles, file.Filename)
+		}
+	}
+
+	e.runPostReviewHooks(aggregatedReview)
 
 	return summary, aggregatedReview, nil
 }
 
-// filterIgnoredFiles removes files that match ignore patterns
+// finalizeComments drops comments disabled by in-file directives or found in the baseline,
+// then applies severity overrides. Each comment must go through it exactly once, since
+// overrides shift severities relative to the current one.
+func (e *Engine) finalizeComments(files []diff.FileDiff, comments []ai.Comment) []ai.Comment {
+	if len(comments) == 0 {
+		return comments
+	}
+	comments = e.applyFileDirectives(files, comments)
+	comments = e.filterLowConfidence(comments)
+	comments = e.filterBaseline(comments)
+	// Applied after the baseline, whose entries are keyed on the original headers
+	return e.applySeverityOverrides(comments)
+}
+
+// FinalizeComments filters and adjusts comments found outside the engine, such as breaking
+// changes, like the engine's own: in-file directives, MIN_CONFIDENCE, the baseline and
+// severity overrides apply. diffContent holds the files the comments are on.
+func (e *Engine) FinalizeComments(diffContent string, comments []ai.Comment) []ai.Comment {
+	if len(comments) == 0 {
+		return comments
+	}
+	files, err := diff.ParseGitDiff(diffContent)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse diff for comment filtering", "error", err)
+	}
+	return e.finalizeComments(files, e.normalizeLabels(comments))
+}
+
+// filterLowConfidence drops comments the LLM is less confident about than MIN_CONFIDENCE
+func (e *Engine) filterLowConfidence(comments []ai.Comment) []ai.Comment {
+	if e.Config == nil || e.Config.MinConfidence <= 0 {
+		return comments
+	}
+
+	var kept []ai.Comment
+	for _, comment := range comments {
+		if comment.ConfidenceScore() < e.Config.MinConfidence {
+			internal.Logger.Debug("Dropping low confidence comment", "file", comment.File, "line", comment.StartLine, "confidence", comment.ConfidenceScore())
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+// dropSeenComments removes comments whose hash is already in seen, keeping the first
+// occurrence, and records the hashes of the comments it keeps
+func dropSeenComments(seen map[string]bool, comments []ai.Comment) []ai.Comment {
+	var kept []ai.Comment
+	for _, comment := range comments {
+		hash := state.ComputeCommentHash(comment.File, comment.StartLine, comment.EndLine, comment.Content)
+		if seen[hash] {
+			internal.Logger.Debug("Dropping duplicate comment from another chunk", "file", comment.File, "line", comment.StartLine)
+			continue
+		}
+		seen[hash] = true
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+// filterIgnoredFiles drops the files the review skips, see FilterReviewedFiles
 func (e *Engine) filterIgnoredFiles(files []diff.FileDiff) []diff.FileDiff {
-	if e.Config == nil {
+	return FilterReviewedFiles(e.Config, files)
+}
+
+// FilterReviewedFiles keeps files that match the include patterns, if any, and then removes
+// those that match ignore patterns or, with SKIP_GENERATED, were produced by a code generator.
+// Checks run outside the engine use it to skip the same files as the review.
+func FilterReviewedFiles(config *internal.Config, files []diff.FileDiff) []diff.FileDiff {
+	if config == nil {
 		return files
 	}
 
 	var filtered []diff.FileDiff
 	for _, file := range files {
-		if !e.Config.ShouldIgnoreFile(file.Filename) {
-			filtered = append(filtered, file)
-		} else {
+		switch {
+		case !config.ShouldIncludeFile(file.Filename):
+			internal.Logger.Debug("Skipping file not matching include patterns", "file", file.Filename)
+		case config.ShouldIgnoreFile(file.Filename):
 			internal.Logger.Debug("Ignoring file", "file", file.Filename)
+		case config.SkipGenerated && diff.IsGenerated(file):
+			internal.Logger.Debug("Skipping generated file", "file", file.Filename)
+		default:
+			filtered = append(filtered, file)
 		}
 	}
 	return filtered
 }
 
-// createFileChunks groups files into chunks that fit within the size limit
+// chunkContext formats a chunk's diff together with referenced files and blame context,
+// led by a review focus hint when most files in the chunk are of one category. Secrets in
+// the added context are redacted like those in the diff.
+func (e *Engine) chunkContext(chunk []diff.FileDiff) string {
+	chunkDiff := diff.FormatForLLMWithOptions(chunk, diff.FormatOptions{WordDiff: e.Config != nil && e.Config.WordDiff})
+	if hint := chunkCategoryHint(chunk); hint != "" {
+		chunkDiff = "Review focus: " + hint + "\n\n" + chunkDiff
+	}
+
+	// Fetch referenced files for context expansion
+	var contextSection string
+	if e.ContextFetcher != nil {
+		referencedFiles := e.ContextFetcher.FetchReferencedFiles(chunk)
+		if len(referencedFiles) > 0 {
+			contextSection = context.FormatForLLM(referencedFiles)
+			internal.Logger.Debug(fmt.Sprintf("Added %d referenced files to context", len(referencedFiles)))
+		}
+	}
+
+	// Add git blame context for code history
+	blameContext := e.getBlameContext(chunk)
+	if blameContext != "" {
+		contextSection += blameContext
+	}
+
+	// The diff was redacted up front; referenced files and blame are read from disk here
+	if contextSection != "" && e.Config != nil && e.Config.RedactSecrets {
+		contextSection = secrets.RedactText(contextSection)
+	}
+
+	// Combine diff with context
+	if contextSection != "" {
+		return chunkDiff + "\n" + contextSection
+	}
+	return chunkDiff
+}
+
+// reviewInOneCall requests the summary and review together for PRs that fit in one chunk
+// below the configured size. It returns nils when not applicable or on failure, so the
+// caller falls back to separate requests.
+func (e *Engine) reviewInOneCall(title, description string, chunks [][]diff.FileDiff, rules string) (*ai.PRSummary, *ai.ReviewResult) {
+	if e.Config == nil || e.Config.SingleCallMaxSize <= 0 || len(chunks) != 1 {
+		return nil, nil
+	}
+	client, ok := e.AIClient.(ai.CombinedReviewer)
+	if !ok {
+		return nil, nil
+	}
+
+	// Check the bare diff first to skip fetching context for PRs that are clearly too large
+	if len(diff.FormatForLLM(chunks[0])) > e.Config.SingleCallMaxSize {
+		return nil, nil
+	}
+	fullContext := e.chunkContext(chunks[0])
+	if len(fullContext) > e.Config.SingleCallMaxSize {
+		return nil, nil
+	}
+
+	internal.Logger.Info(fmt.Sprintf("Generating PR summary and code review in one request (%d files, %d chars)...",
+		len(chunks[0]), len(fullContext)))
+	ctx, cancel := e.Config.OperationContext()
+	defer cancel()
+	summary, review, err := client.GenerateCombinedReview(ctx, title, description, fullContext, rules)
+	if err != nil {
+		internal.Logger.Warn("Combined review failed, falling back to separate requests", "error", err)
+		return nil, nil
+	}
+	return summary, review
+}
+
+// createFileChunks groups files into chunks that fit within the model's token budget,
+// using the configured chunk strategy
 func (e *Engine) createFileChunks(files []diff.FileDiff) [][]diff.FileDiff {
 	if len(files) == 0 {
 		return nil
 	}
 
+	strategy := ChunkStrategySize
+	if e.Config != nil && e.Config.ChunkStrategy != "" {
+		strategy = e.Config.ChunkStrategy
+	}
+
+	switch strategy {
+	case ChunkStrategyByDir:
+		return e.packByGroup(files, func(f diff.FileDiff) string { return filepath.Dir(f.Filename) })
+	case ChunkStrategyByLang:
+		return e.packByGroup(files, languageGroup)
+	default:
+		return e.packBySizeWithoutLockfiles(files)
+	}
+}
+
+// packBySize packs files into chunks by estimated tokens alone, largest first
+func (e *Engine) packBySize(files []diff.FileDiff) [][]diff.FileDiff {
+	if len(files) == 0 {
+		return nil
+	}
+	maxTokens := e.maxChunkTokens()
+
 	// Calculate size for each file
 	type fileWithSize struct {
 		file diff.FileDiff
@@ -184,8 +557,7 @@ func (e *Engine) createFileChunks(files []diff.FileDiff) [][]diff.FileDiff {
 	}
 	filesWithSizes := make([]fileWithSize, len(files))
 	for i, file := range files {
-		formatted := diff.FormatForLLM([]diff.FileDiff{file})
-		filesWithSizes[i] = fileWithSize{file: file, size: len(formatted)}
+		filesWithSizes[i] = fileWithSize{file: file, size: e.fileTokens(file)}
 	}
 
 	// Sort by size (largest first) for better packing
@@ -200,19 +572,19 @@ func (e *Engine) createFileChunks(files []diff.FileDiff) [][]diff.FileDiff {
 
 	for _, fws := range filesWithSizes {
 		// If this single file is too large, it gets its own chunk
-		if fws.size > MaxChunkSize {
+		if fws.size > maxTokens {
 			if len(currentChunk) > 0 {
 				chunks = append(chunks, currentChunk)
 				currentChunk = nil
 				currentSize = 0
 			}
 			chunks = append(chunks, []diff.FileDiff{fws.file})
-			internal.Logger.Warn(fmt.Sprintf("File %s is very large (%d chars), reviewing separately", fws.file.Filename, fws.size))
+			internal.Logger.Warn(fmt.Sprintf("File %s is very large (~%d tokens), reviewing separately", fws.file.Filename, fws.size))
 			continue
 		}
 
 		// If adding this file exceeds limit, start new chunk
-		if currentSize+fws.size > MaxChunkSize && len(currentChunk) > 0 {
+		if currentSize+fws.size > maxTokens && len(currentChunk) > 0 {
 			chunks = append(chunks, currentChunk)
 			currentChunk = nil
 			currentSize = 0
@@ -254,7 +626,7 @@ func (e *Engine) createSummaryDiff(files []diff.FileDiff) string {
 	// Add first file's diff as example if space permits
 	if len(files) > 0 {
 		firstDiff := diff.FormatForLLM([]diff.FileDiff{files[0]})
-		if len(firstDiff) < MaxChunkSize/2 {
+		if e.tokenEstimator().EstimateTokens(firstDiff) < e.maxChunkTokens()/2 {
 			builder.WriteString("\n# First File Details\n")
 			builder.WriteString(firstDiff)
 		}
@@ -266,21 +638,34 @@ func (e *Engine) createSummaryDiff(files []diff.FileDiff) string {
 // hasTestFiles checks if any of the files are test files
 func (e *Engine) hasTestFiles(files []diff.FileDiff) bool {
 	for _, file := range files {
-		if strings.Contains(file.Filename, "_test.go") ||
-			strings.Contains(file.Filename, ".test.") ||
-			strings.Contains(file.Filename, ".spec.") ||
-			strings.Contains(file.Filename, "__tests__") {
+		if isTestFile(file.Filename) {
 			return true
 		}
 	}
 	return false
 }
 
+// normalizeLabels lowercases comment labels so they match configured label tones,
+// and logs labels the model used that have no tone configured
+func (e *Engine) normalizeLabels(comments []ai.Comment) []ai.Comment {
+	for i := range comments {
+		comments[i].Label = strings.ToLower(strings.TrimSpace(comments[i].Label))
+
+		if e.Config == nil || len(e.Config.LabelTones) == 0 {
+			continue
+		}
+		if _, ok := e.Config.LabelTones[comments[i].Label]; !ok {
+			internal.Logger.Debug("Comment label has no configured tone", "label", comments[i].Label, "file", comments[i].File)
+		}
+	}
+	return comments
+}
+
 // aggregateSecurityConcerns combines security-related comments
 func (e *Engine) aggregateSecurityConcerns(comments []ai.Comment) string {
 	var concerns []string
 	for _, comment := range comments {
-		if comment.Label == "security" || comment.Critical {
+		if comment.Category() == ai.CategorySecurity || comment.Critical {
 			concerns = append(concerns, comment.Header)
 		}
 	}
@@ -290,8 +675,20 @@ func (e *Engine) aggregateSecurityConcerns(comments []ai.Comment) string {
 	return fmt.Sprintf("%d security concern(s): %s", len(concerns), strings.Join(concerns, "; "))
 }
 
-// getBlameContext gets git blame context for files in a chunk
+// getBlameContext gets git blame context for files in a chunk when INCLUDE_BLAME is set and
+// the work dir is a git checkout, which it often isn't in the Action
 func (e *Engine) getBlameContext(files []diff.FileDiff) string {
+	if e.Config == nil || !e.Config.IncludeBlame || e.NoCheckout {
+		return ""
+	}
+	checkCtx, cancel := e.Config.OperationContext()
+	isCheckout := context.IsGitCheckout(checkCtx, e.Config.WorkDir)
+	cancel()
+	if !isCheckout {
+		internal.Logger.Debug("Skipping blame context outside a git checkout", "dir", e.Config.WorkDir)
+		return ""
+	}
+
 	blameContexts := make(map[string]string)
 
 	for _, file := range files {
@@ -306,7 +703,10 @@ func (e *Engine) getBlameContext(files []diff.FileDiff) string {
 		}
 
 		if len(changedLines) > 0 {
-			blameCtx := context.GetFileBlameContext(file.Filename, changedLines)
+			localPath := context.ToLocalPath(e.Config.PathPrefix, file.Filename)
+			ctx, cancel := e.Config.OperationContext()
+			blameCtx := context.GetFileBlameContextInDir(ctx, e.Config.WorkDir, localPath, changedLines)
+			cancel()
 			if blameCtx != "" {
 				blameContexts[file.Filename] = blameCtx
 			}
@@ -338,6 +738,16 @@ func (e *Engine) getCombinedRules() string {
 // FormatOutput generates the standard markdown report
 func FormatOutput(summary *ai.PRSummary, review *ai.ReviewResult) string {
 	var builder strings.Builder
+	builder.WriteString(FormatSummary(summary, review))
+	for _, comment := range review.Comments {
+		builder.WriteString(FormatComment(comment))
+	}
+	return builder.String()
+}
+
+// FormatSummary generates the top of the report, before the comments
+func FormatSummary(summary *ai.PRSummary, review *ai.ReviewResult) string {
+	var builder strings.Builder
 
 	// We can still print the summary at the top if desired, or skip it to match the requested "structure" exactly.
 	// The user request shows file-based comments.
@@ -346,69 +756,80 @@ func FormatOutput(summary *ai.PRSummary, review *ai.ReviewResult) string {
 	builder.WriteString("🪶 **Executive Summary**\n")
 	builder.WriteString(summary.Description + "\n\n")
 
+	if review.FailedChunks > 0 {
+		builder.WriteString(fmt.Sprintf("⚠️ Reviewed %d/%d chunks; %d failed and may hide issues.\n\n",
+			review.Chunks-review.FailedChunks, review.Chunks, review.FailedChunks))
+	}
+	if skipped := FormatSkippedFiles(review.SkippedFiles); skipped != "" {
+		builder.WriteString(skipped + "\n")
+	}
+
 	if len(review.Comments) == 0 {
 		builder.WriteString("No issues found! 🎉\n")
-		return builder.String()
 	}
+	return builder.String()
+}
 
-	for _, comment := range review.Comments {
-		// Determine Type
-		issueType := comment.Label
-		if issueType == "" {
-			issueType = "potential_issue"
-		}
-		if comment.Critical {
-			issueType = "critical_issue"
-		}
+// FormatComment generates the report section for a single comment
+func FormatComment(comment ai.Comment) string {
+	var builder strings.Builder
 
-		builder.WriteString("============================================================================\n")
-		builder.WriteString(fmt.Sprintf("File: %s\n", comment.File))
-		if comment.EndLine > 0 && comment.EndLine > comment.StartLine {
-			builder.WriteString(fmt.Sprintf("Line: %d to %d\n", comment.StartLine, comment.EndLine))
-		} else if comment.StartLine > 0 {
-			builder.WriteString(fmt.Sprintf("Line: %d\n", comment.StartLine))
-		} else {
-			builder.WriteString("Line: (unknown)\n")
-		}
-		builder.WriteString(fmt.Sprintf("Type: %s\n\n", issueType))
+	// Determine Type
+	issueType := comment.Label
+	if issueType == "" {
+		issueType = "potential_issue"
+	}
+	if comment.Critical {
+		issueType = "critical_issue"
+	}
 
-		// Clean up Header (remove emoji if present for the "Comment" section?)
-		// The user example had "Comment:\nRemove duplicate line.\n\nLine 105..."
-		// Our Header is usually short. Content is longer.
-		// Let's combine them or just use Header as title.
+	builder.WriteString("============================================================================\n")
+	builder.WriteString(fmt.Sprintf("File: %s\n", comment.File))
+	if comment.EndLine > 0 && comment.EndLine > comment.StartLine {
+		builder.WriteString(fmt.Sprintf("Line: %d to %d\n", comment.StartLine, comment.EndLine))
+	} else if comment.StartLine > 0 {
+		builder.WriteString(fmt.Sprintf("Line: %d\n", comment.StartLine))
+	} else {
+		builder.WriteString("Line: (unknown)\n")
+	}
+	builder.WriteString(fmt.Sprintf("Type: %s\n\n", issueType))
 
-		builder.WriteString("Comment:\n")
-		if comment.Header != "" {
-			builder.WriteString(comment.Header + "\n\n")
-		}
-		builder.WriteString(comment.Content + "\n\n")
+	// Clean up Header (remove emoji if present for the "Comment" section?)
+	// The user example had "Comment:\nRemove duplicate line.\n\nLine 105..."
+	// Our Header is usually short. Content is longer.
+	// Let's combine them or just use Header as title.
 
-		if comment.HighlightedCode != "" {
-			builder.WriteString("Current Code:\n```\n")
-			builder.WriteString(comment.HighlightedCode)
-			builder.WriteString("\n```\n\n")
-		}
+	builder.WriteString("Comment:\n")
+	if comment.Header != "" {
+		builder.WriteString(comment.Header + "\n\n")
+	}
+	builder.WriteString(comment.Content + "\n\n")
 
-		if comment.SuggestedCode != "" {
-			builder.WriteString("Suggested Fix:\n```suggestion\n")
-			builder.WriteString(comment.SuggestedCode)
-			if !strings.HasSuffix(comment.SuggestedCode, "\n") {
-				builder.WriteString("\n")
-			}
-			builder.WriteString("```\n\n")
+	if comment.HighlightedCode != "" {
+		builder.WriteString("Current Code:\n```\n")
+		builder.WriteString(comment.HighlightedCode)
+		builder.WriteString("\n```\n\n")
+	}
+
+	if comment.SuggestedCode != "" {
+		builder.WriteString("Suggested Fix:\n```suggestion\n")
+		builder.WriteString(comment.SuggestedCode)
+		if !strings.HasSuffix(comment.SuggestedCode, "\n") {
+			builder.WriteString("\n")
 		}
+		builder.WriteString("```\n\n")
+	}
 
-		builder.WriteString("Prompt for AI Agent:\n")
-		// Construct the agent prompt
-		// "In @<file> around lines <start> - <end>, <content/instruction>"
-		agentPrompt := fmt.Sprintf("In @%s around lines %d - %d, %s",
-			comment.File,
-			comment.StartLine,
-			comment.EndLine,
-			strings.ReplaceAll(comment.Content, "\n", " "))
+	builder.WriteString("Prompt for AI Agent:\n")
+	// Construct the agent prompt
+	// "In @<file> around lines <start> - <end>, <content/instruction>"
+	agentPrompt := fmt.Sprintf("In @%s around lines %d - %d, %s",
+		comment.File,
+		comment.StartLine,
+		comment.EndLine,
+		strings.ReplaceAll(comment.Content, "\n", " "))
 
-		builder.WriteString(agentPrompt + "\n\n")
-	}
+	builder.WriteString(agentPrompt + "\n\n")
 
 	return builder.String()
 }
@@ -0,0 +1,48 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// Hook extends a review with custom behavior, such as extra linters, external scanners
+// or notifications, without changing the engine
+type Hook interface {
+	// PreReview runs before the LLM review and returns extra comments for the changed files.
+	// Its comments go through the same directive and baseline filters as built-in findings.
+	PreReview(files []diff.FileDiff) ([]ai.Comment, error)
+	// PostReview runs after the results are aggregated, for side effects
+	PostReview(result *ai.ReviewResult) error
+}
+
+// RegisterHook adds a hook to run around every review, in registration order
+func (e *Engine) RegisterHook(hook Hook) {
+	e.Hooks = append(e.Hooks, hook)
+}
+
+// runPreReviewHooks collects comments from every hook. A failing hook is logged and
+// skipped so it can't block the review.
+func (e *Engine) runPreReviewHooks(files []diff.FileDiff) []ai.Comment {
+	var comments []ai.Comment
+	for i, hook := range e.Hooks {
+		hookComments, err := hook.PreReview(files)
+		if err != nil {
+			internal.Logger.Warn(fmt.Sprintf("Pre-review hook %d failed: %v", i+1, err))
+			continue
+		}
+		comments = append(comments, hookComments...)
+	}
+	return comments
+}
+
+// runPostReviewHooks passes the final result to every hook, logging failures
+func (e *Engine) runPostReviewHooks(result *ai.ReviewResult) {
+	for i, hook := range e.Hooks {
+		if err := hook.PostReview(result); err != nil {
+			internal.Logger.Warn(fmt.Sprintf("Post-review hook %d failed: %v", i+1, err))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+	"github.com/igcodinap/manque-ai/pkg/state"
+)
+
+// recordingHook returns fixed comments and records what it was called with
+type recordingHook struct {
+	comments []ai.Comment
+	preErr   error
+	files    []string
+	result   *ai.ReviewResult
+}
+
+func (h *recordingHook) PreReview(files []diff.FileDiff) ([]ai.Comment, error) {
+	for _, file := range files {
+		h.files = append(h.files, file.Filename)
+	}
+	return h.comments, h.preErr
+}
+
+func (h *recordingHook) PostReview(result *ai.ReviewResult) error {
+	h.result = result
+	return fmt.Errorf("notification failed")
+}
+
+func TestEngine_Hooks(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{
+		AIClient: &MockAIClient{Summary: &ai.PRSummary{Description: "Summary"}, Review: &ai.ReviewResult{}},
+		Config:   &internal.Config{},
+	}
+
+	scanner := &recordingHook{comments: []ai.Comment{
+		{File: "test.txt", StartLine: 1, EndLine: 1, Header: "🔴 Leaked secret", Label: "security", Content: "API key committed."},
+		{File: "test.txt", StartLine: 1, EndLine: 1, Header: "💅 Known nit", Label: "style", Content: "Trailing space."},
+	}}
+	failing := &recordingHook{comments: []ai.Comment{{File: "test.txt", Header: "ignored"}}, preErr: fmt.Errorf("scanner offline")}
+	engine.RegisterHook(scanner)
+	engine.RegisterHook(failing)
+
+	engine.Baseline = state.NewBaseline()
+	engine.Baseline.Add("test.txt", "style", "💅 Known nit", "Trailing space.")
+
+	_, review, err := engine.Review(smallDiff)
+	if err != nil {
+		t.Fatalf("Review returned error: %v", err)
+	}
+
+	if len(scanner.files) != 1 || scanner.files[0] != "test.txt" {
+		t.Errorf("Expected pre-review hook to see test.txt, got %v", scanner.files)
+	}
+	if len(review.Comments) != 1 || review.Comments[0].Header != "🔴 Leaked secret" {
+		t.Errorf("Expected only the non-baselined hook comment, got %+v", review.Comments)
+	}
+	if !strings.Contains(review.Review.SecurityConcerns, "Leaked secret") {
+		t.Error("Expected hook comments to count towards security concerns")
+	}
+	if scanner.result != review || failing.result != review {
+		t.Error("Expected every post-review hook to receive the final result")
+	}
+}
@@ -0,0 +1,206 @@
+package review
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/context"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// Bounds on indexing the checkout for impact analysis, so large repositories stay fast
+const (
+	maxImpactIndexFiles    = 2000
+	maxImpactIndexFileSize = 256 * 1024
+)
+
+// ImpactAnalysis is the impact of a PR on the rest of the repository
+type ImpactAnalysis struct {
+	Impacts  []*ast.FileImpact // Changed files whose symbols are referenced by other files
+	Comments []ai.Comment      // Warnings for files with high or critical impact
+}
+
+// AnalyzeImpact is the engine step finding files in the checkout that reference symbols
+// changed in the diff, with ANALYZE_IMPACT set. Files the review skips are left out. The
+// analysis is empty when disabled, without a checkout or when the revisions are unavailable.
+func (e *Engine) AnalyzeImpact(diffContent, baseRev, headRev string) *ImpactAnalysis {
+	if e.Config == nil || !e.Config.AnalyzeImpact || e.NoCheckout || baseRev == "" || headRev == "" {
+		return &ImpactAnalysis{}
+	}
+
+	files, err := diff.ParseGitDiff(diffContent)
+	if err != nil {
+		internal.Logger.Warn("Failed to parse diff for impact analysis", "error", err)
+		return &ImpactAnalysis{}
+	}
+
+	root := e.Config.WorkDir
+	if root == "" {
+		root = "."
+	}
+	load := func(rev, path string) (string, error) {
+		ctx, cancel := e.Config.OperationContext()
+		defer cancel()
+		return context.GetFileAtRevision(ctx, e.Config.WorkDir, rev, path)
+	}
+	analysis := AnalyzeImpact(e.filterIgnoredFiles(files), load, baseRev, headRev, root, e.Config.PathPrefix, e.Config.ShouldIgnoreFile)
+	if len(analysis.Impacts) > 0 {
+		internal.Logger.Info("Changes affect other files", "files", len(analysis.Impacts))
+	}
+	return analysis
+}
+
+// AnalyzeImpact indexes the source files in the checkout at root and reports which other
+// files reference the symbols changed in each file. Changed files are indexed at baseRev
+// first, so references to removed symbols are found too. pathPrefix is the location of
+// root within the repository, and skip filters out ignored repository paths.
+func AnalyzeImpact(files []diff.FileDiff, load FileLoader, baseRev, headRev, root, pathPrefix string, skip func(path string) bool) *ImpactAnalysis {
+	analyzer := ast.NewImpactAnalyzer()
+
+	type changedFile struct {
+		filename, oldContent, newContent string
+	}
+	var changed []changedFile
+	changedPaths := make(map[string]bool)
+
+	for _, file := range files {
+		if ast.DetectLanguage(file.Filename) == ast.LangUnknown {
+			continue
+		}
+		// Missing revisions are new or deleted files
+		oldContent, _ := load(baseRev, file.PreviousFilename())
+		newContent, _ := load(headRev, file.Filename)
+		if oldContent == "" && newContent == "" {
+			continue
+		}
+		if oldContent != "" {
+			if err := analyzer.IndexFile(file.Filename, oldContent); err != nil {
+				internal.Logger.Debug("Failed to index changed file", "file", file.Filename, "error", err)
+			}
+		}
+		changed = append(changed, changedFile{file.Filename, oldContent, newContent})
+		changedPaths[file.Filename] = true
+	}
+	if len(changed) == 0 {
+		return &ImpactAnalysis{}
+	}
+
+	indexRepository(analyzer, root, pathPrefix, func(path string) bool {
+		return changedPaths[path] || skip(path)
+	})
+
+	analysis := &ImpactAnalysis{}
+	for _, file := range changed {
+		changes, err := ast.ChangedSymbols(file.oldContent, file.newContent, file.filename)
+		if err != nil {
+			internal.Logger.Debug("Impact analysis failed", "file", file.filename, "error", err)
+			continue
+		}
+		impact := analyzer.AnalyzeChanges(changes)
+		if len(impact.AffectedFiles) == 0 {
+			continue
+		}
+		sort.Strings(impact.AffectedFiles)
+		analysis.Impacts = append(analysis.Impacts, impact)
+		if comment, ok := impactComment(impact, changes); ok {
+			analysis.Comments = append(analysis.Comments, comment)
+		}
+	}
+
+	return analysis
+}
+
+// indexRepository adds the source files under root to the analyzer, stopping once
+// maxImpactIndexFiles have been indexed. Hidden directories and skipped paths are not
+// descended into.
+func indexRepository(analyzer *ast.ImpactAnalyzer, root, pathPrefix string, skip func(path string) bool) {
+	indexed := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		repoPath := context.ToRepoPath(pathPrefix, filepath.ToSlash(rel))
+
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") || skip(repoPath+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ast.DetectLanguage(repoPath) == ast.LangUnknown || skip(repoPath) {
+			return nil
+		}
+		if info, err := entry.Info(); err != nil || info.Size() > maxImpactIndexFileSize {
+			return nil
+		}
+
+		if indexed >= maxImpactIndexFiles {
+			internal.Logger.Debug("Impact analysis index limit reached", "files", indexed)
+			return filepath.SkipAll
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if err := analyzer.IndexFile(repoPath, string(content)); err != nil {
+			internal.Logger.Debug("Failed to index file", "file", repoPath, "error", err)
+			return nil
+		}
+		indexed++
+		return nil
+	})
+	if err != nil {
+		internal.Logger.Warn("Failed to index repository for impact analysis", "error", err)
+	}
+}
+
+// impactComment warns about a file whose changes have high or critical impact. It is placed
+// on the first such symbol still in the file; removals have no line and go to the review body.
+func impactComment(impact *ast.FileImpact, changes *ast.SymbolChanges) (ai.Comment, bool) {
+	if impact.OverallSeverity != "high" && impact.OverallSeverity != "critical" {
+		return ai.Comment{}, false
+	}
+
+	removed := make(map[string]bool)
+	for _, sym := range changes.Removed {
+		removed[sym.Name] = true
+	}
+	line := 0
+	for _, imp := range impact.Impacts {
+		if (imp.Severity == "high" || imp.Severity == "critical") && len(imp.AffectedFiles) > 0 && !removed[imp.ChangedSymbol.Name] {
+			line = imp.ChangedSymbol.StartLine
+			break
+		}
+	}
+
+	return ai.Comment{
+		File:      impact.FilePath,
+		StartLine: line,
+		EndLine:   line,
+		Header:    fmt.Sprintf("🟡 Changes affect %d other file(s)", len(impact.AffectedFiles)),
+		Content: fmt.Sprintf("Symbols changed in this file are referenced from `%s`. "+
+			"Check that those callers still work with the new code.",
+			strings.Join(impact.AffectedFiles, "`, `")),
+		Label: "maintainability",
+	}, true
+}
+
+// FormatImpact combines per-file impact reports into one markdown section
+func FormatImpact(impacts []*ast.FileImpact) string {
+	var sections []string
+	for _, impact := range impacts {
+		sections = append(sections, strings.TrimSpace(ast.FormatImpactReport(impact)))
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,84 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestAnalyzeImpact_RemovedSymbolReferencedElsewhere(t *testing.T) {
+	internal.InitLogger(false)
+
+	root := t.TempDir()
+	checkout := map[string]string{
+		"api/user.go":          "package api\n\nfunc Update() {}\n",
+		"handlers/user.go":     "package handlers\n\nfunc Handle() {\n\tapi.Delete(1)\n}\n",
+		"vendor/lib/delete.go": "package lib\n\nfunc run() {\n\tapi.Delete(2)\n}\n",
+		"README.md":            "Call Delete to remove a user.\n",
+	}
+	for path, content := range checkout {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	revisions := map[string]map[string]string{
+		"base": {"api/user.go": "package api\n\nfunc Delete(id int) error {\n\treturn nil\n}\n\nfunc Update() {}\n"},
+		"head": {"api/user.go": checkout["api/user.go"]},
+	}
+	load := func(rev, path string) (string, error) {
+		content, ok := revisions[rev][path]
+		if !ok {
+			return "", fmt.Errorf("%s not found at %s", path, rev)
+		}
+		return content, nil
+	}
+	skip := (&internal.Config{ExcludeVendorDirs: true, VendorDirs: []string{"vendor"}}).ShouldIgnoreFile
+
+	analysis := AnalyzeImpact([]diff.FileDiff{{Filename: "api/user.go"}}, load, "base", "head", root, "", skip)
+	if len(analysis.Impacts) != 1 {
+		t.Fatalf("Expected 1 file impact, got %d", len(analysis.Impacts))
+	}
+	impact := analysis.Impacts[0]
+	if len(impact.AffectedFiles) != 1 || impact.AffectedFiles[0] != "handlers/user.go" {
+		t.Errorf("Expected only handlers/user.go to be affected, got %v", impact.AffectedFiles)
+	}
+
+	report := FormatImpact(analysis.Impacts)
+	if !strings.Contains(report, "### Affected Files\n- handlers/user.go") {
+		t.Errorf("Expected affected files in the report, got %q", report)
+	}
+
+	if len(analysis.Comments) != 1 {
+		t.Fatalf("Expected 1 warning comment, got %d", len(analysis.Comments))
+	}
+	c := analysis.Comments[0]
+	if c.File != "api/user.go" || c.StartLine != 0 || !strings.Contains(c.Content, "handlers/user.go") {
+		t.Errorf("Unexpected comment for a removed symbol: %+v", c)
+	}
+}
+
+func TestAnalyzeImpact_NoReferences(t *testing.T) {
+	internal.InitLogger(false)
+
+	load := func(rev, path string) (string, error) {
+		if rev == "base" {
+			return "package api\n\nfunc Delete() {}\n", nil
+		}
+		return "package api\n", nil
+	}
+
+	analysis := AnalyzeImpact([]diff.FileDiff{{Filename: "api.go"}}, load, "base", "head", t.TempDir(), "", func(string) bool { return false })
+	if len(analysis.Impacts) != 0 || len(analysis.Comments) != 0 {
+		t.Errorf("Expected no impact without references, got %+v", analysis)
+	}
+}
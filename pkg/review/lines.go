@@ -0,0 +1,67 @@
+package review
+
+import (
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// hunkRange is the span of new-file lines covered by a hunk
+type hunkRange struct {
+	start, end int
+}
+
+// commentableRanges returns, per file, the new-file line ranges GitHub accepts inline
+// comments on: the added and context lines of each hunk
+func commentableRanges(files []diff.FileDiff) map[string][]hunkRange {
+	ranges := make(map[string][]hunkRange)
+	for _, file := range files {
+		for _, hunk := range file.Hunks {
+			r := hunkRange{}
+			for _, line := range hunk.Lines {
+				if line.Type == diff.LineRemoved {
+					continue
+				}
+				if r.start == 0 {
+					r.start = line.NewNum
+				}
+				r.end = line.NewNum
+			}
+			if r.start > 0 {
+				ranges[file.Filename] = append(ranges[file.Filename], r)
+			}
+		}
+	}
+	return ranges
+}
+
+// SplitByDiffLines separates comments that can be posted inline from those whose lines are
+// not in the diff, which GitHub rejects. A comment is kept when its last line is in a hunk;
+// a start line before that hunk is moved to its first line, since a multi-line comment
+// cannot span hunks.
+func SplitByDiffLines(files []diff.FileDiff, comments []ai.Comment) (inline, outside []ai.Comment) {
+	ranges := commentableRanges(files)
+
+	for _, comment := range comments {
+		line := comment.EndLine
+		if line < comment.StartLine {
+			line = comment.StartLine
+		}
+
+		kept := false
+		for _, r := range ranges[comment.File] {
+			if line < r.start || line > r.end {
+				continue
+			}
+			comment.EndLine = line
+			comment.StartLine = max(comment.StartLine, r.start)
+			inline = append(inline, comment)
+			kept = true
+			break
+		}
+		if !kept {
+			outside = append(outside, comment)
+		}
+	}
+
+	return inline, outside
+}
@@ -0,0 +1,92 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+const linesDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,4 +10,5 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	fmt.Println(a, b)
+ 	return
+@@ -40,3 +41,3 @@ func helper() {
+ 	x := 1
+-	y := 2
++	y := 5
+ 	return x + y
+`
+
+func TestSplitByDiffLines(t *testing.T) {
+	files, err := diff.ParseGitDiff(linesDiff)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	comments := []ai.Comment{
+		{File: "main.go", StartLine: 11, EndLine: 12, Header: "added lines"},
+		{File: "main.go", StartLine: 14, EndLine: 14, Header: "context line"},
+		{File: "main.go", StartLine: 42, Header: "single line without end"},
+		{File: "main.go", StartLine: 12, EndLine: 42, Header: "spans hunks"},
+		{File: "main.go", StartLine: 30, EndLine: 30, Header: "between hunks"},
+		{File: "main.go", StartLine: 100, EndLine: 105, Header: "past the end"},
+		{File: "other.go", StartLine: 11, EndLine: 11, Header: "file not in diff"},
+		{File: "main.go", Header: "no line"},
+	}
+
+	inline, outside := SplitByDiffLines(files, comments)
+
+	wantInline := []struct {
+		header     string
+		start, end int
+	}{
+		{"added lines", 11, 12},
+		{"context line", 14, 14},
+		{"single line without end", 42, 42},
+		{"spans hunks", 41, 42},
+	}
+	if len(inline) != len(wantInline) {
+		t.Fatalf("Expected %d inline comments, got %d: %+v", len(wantInline), len(inline), inline)
+	}
+	for i, want := range wantInline {
+		got := inline[i]
+		if got.Header != want.header || got.StartLine != want.start || got.EndLine != want.end {
+			t.Errorf("Expected %q at %d-%d, got %q at %d-%d", want.header, want.start, want.end, got.Header, got.StartLine, got.EndLine)
+		}
+	}
+
+	wantOutside := []string{"between hunks", "past the end", "file not in diff", "no line"}
+	if len(outside) != len(wantOutside) {
+		t.Fatalf("Expected %d comments outside the diff, got %d: %+v", len(wantOutside), len(outside), outside)
+	}
+	for i, header := range wantOutside {
+		if outside[i].Header != header {
+			t.Errorf("Expected outside comment %q, got %q", header, outside[i].Header)
+		}
+	}
+}
+
+func TestSplitByDiffLines_DeletedLinesOnly(t *testing.T) {
+	files, err := diff.ParseGitDiff(`diff --git a/old.go b/old.go
+--- a/old.go
++++ b/old.go
+@@ -5,1 +4,0 @@
+-	unused := 1
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	inline, outside := SplitByDiffLines(files, []ai.Comment{{File: "old.go", StartLine: 5, EndLine: 5}})
+	if len(inline) != 0 || len(outside) != 1 {
+		t.Errorf("Expected a comment on a hunk with no new lines to be outside the diff, got %d inline", len(inline))
+	}
+}
@@ -0,0 +1,167 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// trivialRepeatedLines are tokens that legitimately repeat on consecutive lines
+var trivialRepeatedLines = map[string]bool{
+	"else": true,
+	"end":  true,
+	"fi":   true,
+	"done": true,
+	"pass": true,
+}
+
+// detectDuplicateLines flags consecutive identical added lines within a hunk.
+// This is a deterministic check that doesn't depend on the LLM noticing the duplicate.
+func detectDuplicateLines(files []diff.FileDiff) []ai.Comment {
+	var comments []ai.Comment
+
+	for _, file := range files {
+		for _, hunk := range file.Hunks {
+			lines := hunk.Lines
+			for i := 0; i < len(lines)-1; i++ {
+				first := lines[i]
+				if first.Type != diff.LineAdded || isTrivialLine(first.Content) {
+					continue
+				}
+
+				// Extend over the whole run of identical added lines
+				last := i
+				for last+1 < len(lines) && lines[last+1].Type == diff.LineAdded && lines[last+1].Content == first.Content {
+					last++
+				}
+				if last == i {
+					continue
+				}
+
+				var highlighted []string
+				for _, line := range lines[i : last+1] {
+					highlighted = append(highlighted, line.Content)
+				}
+
+				content := fmt.Sprintf("Line %d is a duplicate of line %d.", lines[i+1].NewNum, first.NewNum)
+				if last > i+1 {
+					content = fmt.Sprintf("Lines %d-%d duplicate line %d.", lines[i+1].NewNum, lines[last].NewNum, first.NewNum)
+				}
+
+				comments = append(comments, ai.Comment{
+					File:            file.Filename,
+					StartLine:       first.NewNum,
+					EndLine:         lines[last].NewNum,
+					Header:          "🟡 Remove duplicate line",
+					Content:         content + " Repeated statements are usually a copy-paste mistake and can cause side effects to run twice.",
+					Label:           "bug",
+					HighlightedCode: strings.Join(highlighted, "\n"),
+					SuggestedCode:   first.Content,
+				})
+
+				i = last
+			}
+		}
+	}
+
+	return comments
+}
+
+// isTrivialLine reports whether a line is blank, punctuation only (e.g. "}"), or a common repeated keyword
+func isTrivialLine(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" || trivialRepeatedLines[trimmed] {
+		return true
+	}
+
+	for _, r := range trimmed {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// errorStringRegex matches the message literal of errors.New("...") and fmt.Errorf("...")
+var errorStringRegex = regexp.MustCompile(`\b(?:errors\.New|fmt\.Errorf)\(\s*"((?:[^"\\]|\\.)*)"`)
+
+// detectErrorStringStyle flags added Go error strings that start with a capital letter or end
+// with punctuation, following the Go convention (staticcheck ST1005) that error strings are
+// lowercase and unpunctuated since they are usually wrapped in other messages.
+func detectErrorStringStyle(files []diff.FileDiff) []ai.Comment {
+	var comments []ai.Comment
+
+	for _, file := range files {
+		if ast.DetectLanguage(file.Filename) != ast.LangGo {
+			continue
+		}
+
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				if line.Type != diff.LineAdded {
+					continue
+				}
+
+				suggested := line.Content
+				var problems []string
+				for _, match := range errorStringRegex.FindAllStringSubmatch(line.Content, -1) {
+					message := match[1]
+					fixed, issues := fixErrorString(message)
+					if len(issues) == 0 {
+						continue
+					}
+					problems = append(problems, issues...)
+					suggested = strings.Replace(suggested, `"`+message+`"`, `"`+fixed+`"`, 1)
+				}
+				if len(problems) == 0 {
+					continue
+				}
+
+				comments = append(comments, ai.Comment{
+					File:      file.Filename,
+					StartLine: line.NewNum,
+					EndLine:   line.NewNum,
+					Header:    "💅 Error string style",
+					Content: fmt.Sprintf("Go error strings should not %s, since they are often wrapped in other messages.",
+						strings.Join(problems, " or ")),
+					Label:           "style",
+					HighlightedCode: line.Content,
+					SuggestedCode:   suggested,
+				})
+			}
+		}
+	}
+
+	return comments
+}
+
+// fixErrorString lowercases a leading capital and strips trailing punctuation from an error
+// message, returning the fixed message and the problems found. Messages starting with an
+// acronym or identifier such as "HTTP" or "ID" are left capitalized.
+func fixErrorString(message string) (string, []string) {
+	var issues []string
+	fixed := message
+
+	first, size := utf8.DecodeRuneInString(fixed)
+	if unicode.IsUpper(first) {
+		second, _ := utf8.DecodeRuneInString(fixed[size:])
+		if unicode.IsLower(second) {
+			fixed = string(unicode.ToLower(first)) + fixed[size:]
+			issues = append(issues, "start with a capital letter")
+		}
+	}
+
+	trimmed := strings.TrimRight(fixed, ".!?")
+	if trimmed != fixed && trimmed != "" {
+		fixed = trimmed
+		issues = append(issues, "end with punctuation")
+	}
+
+	return fixed, issues
+}
@@ -0,0 +1,165 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestDetectDuplicateLines_PaymentExample(t *testing.T) {
+	// Mirrors the duplicate line from the --mock payment example
+	diffText := `diff --git a/internal/payments/service/integration_test.go b/internal/payments/service/integration_test.go
+index 123..456 100644
+--- a/internal/payments/service/integration_test.go
++++ b/internal/payments/service/integration_test.go
+@@ -100,5 +100,8 @@ func (r *memoryRepo) Save(p Payment) (Payment, error) {
+ 	r.mu.Lock()
+ 	defer r.mu.Unlock()
+ 
++	r.payments[p.ID] = p
++	r.payments[p.ID] = p
++	return p, nil
+ }
+`
+
+	files, err := diff.ParseGitDiff(diffText)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	comments := detectDuplicateLines(files)
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 duplicate line comment, got %d", len(comments))
+	}
+
+	c := comments[0]
+	if c.File != "internal/payments/service/integration_test.go" {
+		t.Errorf("Unexpected file: %s", c.File)
+	}
+	if c.StartLine != 103 || c.EndLine != 104 {
+		t.Errorf("Expected lines 103-104, got %d-%d", c.StartLine, c.EndLine)
+	}
+	if c.Label != "bug" {
+		t.Errorf("Expected bug label, got %s", c.Label)
+	}
+	if c.SuggestedCode != "\tr.payments[p.ID] = p" {
+		t.Errorf("Expected suggestion keeping a single line, got %q", c.SuggestedCode)
+	}
+}
+
+func TestDetectDuplicateLines_IgnoresTrivialLines(t *testing.T) {
+	diffText := `diff --git a/main.go b/main.go
+index 123..456 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,8 @@
+ func main() {
++	if ok {
++		run()
++	}
++	}
++
++
+ }
+`
+
+	files, err := diff.ParseGitDiff(diffText)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	if comments := detectDuplicateLines(files); len(comments) != 0 {
+		t.Errorf("Expected no comments for braces and blank lines, got %+v", comments)
+	}
+}
+
+func TestDetectDuplicateLines_RequiresAddedLines(t *testing.T) {
+	diffText := `diff --git a/main.go b/main.go
+index 123..456 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ 	counter++
++	counter++
+ 	return counter
+`
+
+	files, err := diff.ParseGitDiff(diffText)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	if comments := detectDuplicateLines(files); len(comments) != 0 {
+		t.Errorf("Expected no comments when only one of the lines was added, got %+v", comments)
+	}
+}
+
+func TestDetectErrorStringStyle(t *testing.T) {
+	diffText := `diff --git a/pkg/store/store.go b/pkg/store/store.go
+index 123..456 100644
+--- a/pkg/store/store.go
++++ b/pkg/store/store.go
+@@ -10,3 +10,7 @@ func Open(path string) error {
+ 	if path == "" {
+ 		return errors.New("Path is required.")
+ 	}
++	if err := check(path); err != nil {
++		return fmt.Errorf("Failed to open %s: %w", path, err)
++	}
++	return errors.New("HTTP store is not supported")
+ }
+`
+
+	files, err := diff.ParseGitDiff(diffText)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	comments := detectErrorStringStyle(files)
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment for the added capitalized error, got %d", len(comments))
+	}
+
+	c := comments[0]
+	if c.StartLine != 14 || c.Label != "style" {
+		t.Errorf("Expected style comment on line 14, got line %d label %s", c.StartLine, c.Label)
+	}
+	if c.SuggestedCode != "\t\treturn fmt.Errorf(\"failed to open %s: %w\", path, err)" {
+		t.Errorf("Unexpected suggestion: %q", c.SuggestedCode)
+	}
+}
+
+func TestDetectErrorStringStyle_SkipsOtherLanguages(t *testing.T) {
+	files := []diff.FileDiff{{
+		Filename: "app.js",
+		Hunks: []diff.Hunk{{Lines: []diff.Line{
+			{Type: diff.LineAdded, Content: `throw errors.New("Bad input.")`, NewNum: 1},
+		}}},
+	}}
+
+	if comments := detectErrorStringStyle(files); len(comments) != 0 {
+		t.Errorf("Expected no comments for non-Go files, got %d", len(comments))
+	}
+}
+
+func TestFixErrorString(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+		issues   int
+	}{
+		{"failed to connect", "failed to connect", 0},
+		{"Failed to connect", "failed to connect", 1},
+		{"failed to connect.", "failed to connect", 1},
+		{"Something went wrong!", "something went wrong", 2},
+		{"ID must be set", "ID must be set", 0},
+		{"...", "...", 0},
+	}
+
+	for _, tt := range tests {
+		fixed, issues := fixErrorString(tt.message)
+		if fixed != tt.expected || len(issues) != tt.issues {
+			t.Errorf("fixErrorString(%q) = %q with %d issues, want %q with %d", tt.message, fixed, len(issues), tt.expected, tt.issues)
+		}
+	}
+}
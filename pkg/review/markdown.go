@@ -0,0 +1,149 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// markdownCodeRules instructs the LLM to review only the extracted code samples
+const markdownCodeRules = `The diff contains code samples extracted from fenced code blocks in Markdown documentation.
+Line numbers refer to the Markdown file, not to a source file.
+Only flag problems that would confuse a reader copying the sample: syntax errors, undefined or misspelled identifiers,
+wrong API usage, or code that does not match its language tag. Do not comment on style or prose.`
+
+// codeBlock is a fenced code block with a language tag, located in a Markdown file
+type codeBlock struct {
+	File     string
+	Language string
+	Lines    []diff.Line // Code lines only, without the fences
+}
+
+// isMarkdownFile checks if a file is a Markdown document
+func isMarkdownFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// parseFence reports whether a line opens or closes a fenced code block, returning its
+// fence marker and language tag
+func parseFence(content string) (fence, language string, ok bool) {
+	trimmed := strings.TrimSpace(content)
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			info := strings.TrimLeft(trimmed, marker[:1])
+			fields := strings.Fields(info)
+			if len(fields) > 0 {
+				language = strings.ToLower(fields[0])
+			}
+			return marker, language, true
+		}
+	}
+	return "", "", false
+}
+
+// extractCodeBlocks returns the language-tagged code blocks of a Markdown file that contain
+// added lines. Only blocks fully visible within a hunk are returned, since a partial block
+// would look broken to the reviewer.
+func extractCodeBlocks(file diff.FileDiff) []codeBlock {
+	var blocks []codeBlock
+
+	for _, hunk := range file.Hunks {
+		var current *codeBlock
+		var openFence string
+		var hasAdded bool
+
+		for _, line := range hunk.Lines {
+			if line.Type == diff.LineRemoved {
+				continue
+			}
+
+			fence, language, isFence := parseFence(line.Content)
+			if current == nil && openFence == "" {
+				if isFence {
+					openFence = fence
+					hasAdded = line.Type == diff.LineAdded
+					if language != "" {
+						current = &codeBlock{File: file.Filename, Language: language}
+					}
+				}
+				continue
+			}
+
+			if isFence && fence == openFence && language == "" {
+				hasAdded = hasAdded || line.Type == diff.LineAdded
+				if current != nil && hasAdded && len(current.Lines) > 0 {
+					blocks = append(blocks, *current)
+				}
+				current = nil
+				openFence = ""
+				continue
+			}
+
+			if line.Type == diff.LineAdded {
+				hasAdded = true
+			}
+			if current != nil {
+				current.Lines = append(current.Lines, line)
+			}
+		}
+	}
+
+	return blocks
+}
+
+// formatCodeBlocksForReview renders code blocks as diff hunks that keep the Markdown line
+// numbers, so comments from the LLM map directly back to the documentation
+func formatCodeBlocksForReview(blocks []codeBlock) string {
+	var files []diff.FileDiff
+	for _, block := range blocks {
+		first := block.Lines[0].NewNum
+		files = append(files, diff.FileDiff{
+			Filename: block.File,
+			Hunks: []diff.Hunk{{
+				OldStart: first,
+				OldCount: len(block.Lines),
+				NewStart: first,
+				NewCount: len(block.Lines),
+				Lines:    block.Lines,
+			}},
+		})
+	}
+	return diff.FormatForLLM(files)
+}
+
+// reviewMarkdownCodeBlocks asks the LLM for a light review of code samples in changed Markdown files
+func (e *Engine) reviewMarkdownCodeBlocks(title, description string, files []diff.FileDiff) []ai.Comment {
+	var blocks []codeBlock
+	for _, file := range files {
+		if isMarkdownFile(file.Filename) {
+			blocks = append(blocks, extractCodeBlocks(file)...)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	var rules strings.Builder
+	rules.WriteString(markdownCodeRules)
+	rules.WriteString("\n\nCode samples:\n")
+	for _, block := range blocks {
+		rules.WriteString(fmt.Sprintf("- %s lines %d-%d: %s\n",
+			block.File, block.Lines[0].NewNum, block.Lines[len(block.Lines)-1].NewNum, block.Language))
+	}
+
+	internal.Logger.Info(fmt.Sprintf("Reviewing %d code block(s) in Markdown files...", len(blocks)))
+	ctx, cancel := e.Config.OperationContext()
+	defer cancel()
+	review, err := e.AIClient.GenerateCodeReviewWithStyleGuide(ctx, title, description, formatCodeBlocksForReview(blocks), rules.String())
+	if err != nil {
+		internal.Logger.Warn(fmt.Sprintf("Failed to review Markdown code blocks: %v", err))
+		return nil
+	}
+
+	return review.Comments
+}
@@ -0,0 +1,106 @@
+package review
+
+import (
+	stdcontext "context"
+	"strings"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+const markdownDiff = `diff --git a/docs/usage.md b/docs/usage.md
+index 123..456 100644
+--- a/docs/usage.md
++++ b/docs/usage.md
+@@ -1,12 +1,16 @@
+ # Usage
+ 
+ ` + "```go" + `
+ client := manque.NewClient()
+ ` + "```" + `
+ 
++Run a review:
++
++` + "```go" + `
++result, err := client.Review(diff
++` + "```" + `
++
+ ` + "```" + `
+ plain text
+ ` + "```" + `
+`
+
+// recordingAIClient records the diff and rules passed to the code review call
+type recordingAIClient struct {
+	MockAIClient
+	diff  string
+	rules string
+}
+
+func (m *recordingAIClient) GenerateCodeReviewWithStyleGuide(ctx stdcontext.Context, title, description, diff, rules string) (*ai.ReviewResult, error) {
+	m.diff = diff
+	m.rules = rules
+	return m.Review, nil
+}
+
+func TestExtractCodeBlocks(t *testing.T) {
+	files, err := diff.ParseGitDiff(markdownDiff)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	blocks := extractCodeBlocks(files[0])
+	if len(blocks) != 1 {
+		t.Fatalf("Expected only the changed, tagged block, got %d: %+v", len(blocks), blocks)
+	}
+
+	block := blocks[0]
+	if block.Language != "go" || len(block.Lines) != 1 {
+		t.Fatalf("Unexpected block: %+v", block)
+	}
+	if block.Lines[0].NewNum != 10 || !strings.Contains(block.Lines[0].Content, "client.Review(diff") {
+		t.Errorf("Expected code line 10 of the markdown file, got %+v", block.Lines[0])
+	}
+}
+
+func TestReviewMarkdownCodeBlocks(t *testing.T) {
+	internal.InitLogger(false)
+	files, err := diff.ParseGitDiff(markdownDiff)
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+
+	client := &recordingAIClient{MockAIClient: MockAIClient{Review: &ai.ReviewResult{
+		Comments: []ai.Comment{{File: "docs/usage.md", StartLine: 10, EndLine: 10, Label: "bug"}},
+	}}}
+	engine := &Engine{AIClient: client, Config: &internal.Config{ReviewMarkdownCode: true}}
+
+	comments := engine.reviewMarkdownCodeBlocks("Docs", "", files)
+	if len(comments) != 1 || comments[0].StartLine != 10 {
+		t.Errorf("Expected the block comment to be returned, got %+v", comments)
+	}
+	if !strings.Contains(client.diff, "10 +result, err := client.Review(diff") {
+		t.Errorf("Expected block to keep markdown line numbers, got:\n%s", client.diff)
+	}
+	if strings.Contains(client.diff, "NewClient") || strings.Contains(client.diff, "plain text") {
+		t.Errorf("Expected unchanged and untagged blocks to be excluded, got:\n%s", client.diff)
+	}
+	if !strings.Contains(client.rules, "docs/usage.md lines 10-10: go") {
+		t.Errorf("Expected rules to list the block, got:\n%s", client.rules)
+	}
+}
+
+func TestReviewMarkdownCodeBlocks_NoBlocks(t *testing.T) {
+	client := &recordingAIClient{}
+	engine := &Engine{AIClient: client}
+
+	files := []diff.FileDiff{{Filename: "main.go"}}
+	if comments := engine.reviewMarkdownCodeBlocks("", "", files); comments != nil {
+		t.Errorf("Expected no comments, got %+v", comments)
+	}
+	if client.diff != "" {
+		t.Error("Expected no LLM call without code blocks")
+	}
+}
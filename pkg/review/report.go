@@ -0,0 +1,78 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+// JSONReport is the machine-readable form of a review, printed by "local --format json"
+type JSONReport struct {
+	Summary  *ai.PRSummary     `json:"summary"`
+	Review   JSONReviewSummary `json:"review"`
+	Comments []JSONComment     `json:"comments"`
+}
+
+// JSONReviewSummary holds the review scores and how many chunks were reviewed
+type JSONReviewSummary struct {
+	ai.ReviewSummary
+	Chunks       int `json:"chunks"`
+	FailedChunks int `json:"failed_chunks"`
+	TokensIn     int `json:"tokens_in,omitempty"`
+	TokensOut    int `json:"tokens_out,omitempty"`
+}
+
+// JSONComment is a single review comment with its severity spelled out
+type JSONComment struct {
+	File            string `json:"file"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	Severity        string `json:"severity"` // critical, warning, suggestion, nitpick or unknown
+	Label           string `json:"label"`
+	Header          string `json:"header"`
+	Content         string `json:"content"`
+	HighlightedCode string `json:"highlighted_code,omitempty"`
+	SuggestedCode   string `json:"suggested_code,omitempty"`
+}
+
+// FormatJSON renders the summary and review as a single indented JSON document
+func FormatJSON(summary *ai.PRSummary, result *ai.ReviewResult) ([]byte, error) {
+	report := JSONReport{
+		Summary:  summary,
+		Comments: []JSONComment{},
+	}
+
+	if result != nil {
+		report.Review = JSONReviewSummary{
+			ReviewSummary: result.Review,
+			Chunks:        result.Chunks,
+			FailedChunks:  result.FailedChunks,
+			TokensIn:      result.TokensIn,
+			TokensOut:     result.TokensOut,
+		}
+		for _, comment := range result.Comments {
+			report.Comments = append(report.Comments, JSONComment{
+				File:            comment.File,
+				StartLine:       comment.StartLine,
+				EndLine:         comment.EndLine,
+				Severity:        severityName(comment),
+				Label:           comment.Label,
+				Header:          comment.Header,
+				Content:         comment.Content,
+				HighlightedCode: comment.HighlightedCode,
+				SuggestedCode:   comment.SuggestedCode,
+			})
+		}
+	}
+
+	// Code in comments is kept readable instead of escaping <, > and &
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,86 @@
+package review
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite golden files in testdata")
+
+func TestFormatJSON_Golden(t *testing.T) {
+	summary := &ai.PRSummary{
+		Title:       "Add payment retries",
+		Description: "Retries failed payment captures with backoff.",
+		Type:        []string{"ENHANCEMENT"},
+	}
+	result := &ai.ReviewResult{
+		Review: ai.ReviewSummary{
+			EstimatedEffort:  2,
+			Score:            78,
+			HasRelevantTests: true,
+			SecurityConcerns: "No",
+		},
+		Comments: []ai.Comment{
+			{
+				File:            "payments/retry.go",
+				StartLine:       12,
+				EndLine:         14,
+				HighlightedCode: "time.Sleep(delay)",
+				Header:          "🟡 Retry ignores context cancellation",
+				Content:         "Use a timer with ctx.Done() so shutdowns aren't blocked.",
+				Label:           "bug",
+				SuggestedCode:   "select {\ncase <-time.After(delay):\ncase <-ctx.Done():\n\treturn ctx.Err()\n}",
+			},
+			{
+				File:      "payments/client.go",
+				StartLine: 3,
+				EndLine:   3,
+				Header:    "Remove hardcoded API key",
+				Content:   "Load the key from the environment.",
+				Label:     "security",
+				Critical:  true,
+			},
+			{
+				File:      "payments/retry.go",
+				StartLine: 30,
+				Header:    "💅 Name the constant",
+				Label:     "style",
+			},
+		},
+		Chunks: 2,
+	}
+
+	got, err := FormatJSON(summary, result)
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "report.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(golden, append(got, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(append(got, '\n'), want) {
+		t.Errorf("JSON report does not match %s:\n%s", golden, got)
+	}
+}
+
+func TestFormatJSON_Empty(t *testing.T) {
+	got, err := FormatJSON(nil, nil)
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+	if !bytes.Contains(got, []byte(`"comments": []`)) || !bytes.Contains(got, []byte(`"summary": null`)) {
+		t.Errorf("Expected an empty report, got:\n%s", got)
+	}
+}
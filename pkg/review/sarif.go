@@ -0,0 +1,195 @@
+package review
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/igcodinap/manque-ai"
+
+	// sarifSrcRoot is the base URI id that comment paths are relative to
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// sarifLevels maps comment severities to SARIF result levels
+var sarifLevels = map[string]string{
+	"critical":   "error",
+	"warning":    "warning",
+	"suggestion": "note",
+	"nitpick":    "note",
+	"unknown":    "warning",
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           sarifRegion      `json:"region"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// sarifRuleID derives a rule id from a comment label, e.g. "Security" -> "security"
+func sarifRuleID(label string) string {
+	id := strings.ToLower(strings.TrimSpace(label))
+	id = strings.Join(strings.Fields(id), "-")
+	if id == "" {
+		return "general"
+	}
+	return id
+}
+
+// sarifURI returns the comment path relative to repoRoot, using forward slashes
+func sarifURI(file, repoRoot string) string {
+	if repoRoot != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(repoRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return (&url.URL{Path: filepath.ToSlash(file)}).EscapedPath()
+}
+
+// FormatSARIF renders the review as a SARIF 2.1.0 log with one result per comment, for
+// upload to GitHub code scanning. Paths are relative to repoRoot, which is recorded as
+// %SRCROOT% when given.
+func FormatSARIF(result *ai.ReviewResult, repoRoot string) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "manque-ai",
+			InformationURI: sarifToolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	if repoRoot != "" {
+		if abs, err := filepath.Abs(repoRoot); err == nil {
+			rootURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs) + "/"}).String()
+			run.OriginalURIBaseIDs = map[string]sarifArtifactLoc{sarifSrcRoot: {URI: rootURI}}
+		}
+	}
+
+	var comments []ai.Comment
+	if result != nil {
+		comments = result.Comments
+	}
+
+	// Rules are listed once per label, sorted so the output is stable
+	ruleIndex := make(map[string]int)
+	var ruleIDs []string
+	for _, comment := range comments {
+		id := sarifRuleID(comment.Label)
+		if _, ok := ruleIndex[id]; !ok {
+			ruleIndex[id] = 0
+			ruleIDs = append(ruleIDs, id)
+		}
+	}
+	sort.Strings(ruleIDs)
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: "manque-ai " + id + " finding"},
+		})
+	}
+
+	for _, comment := range comments {
+		id := sarifRuleID(comment.Label)
+		region := sarifRegion{StartLine: max(comment.StartLine, 1)}
+		if comment.EndLine > region.StartLine {
+			region.EndLine = comment.EndLine
+		}
+
+		message := strings.TrimSpace(comment.Header)
+		if content := strings.TrimSpace(comment.Content); content != "" {
+			if message != "" {
+				message += "\n\n"
+			}
+			message += content
+		}
+		if message == "" {
+			message = "Review comment" // SARIF requires a message
+		}
+
+		location := sarifArtifactLoc{URI: sarifURI(comment.File, repoRoot)}
+		if run.OriginalURIBaseIDs != nil {
+			location.URIBaseID = sarifSrcRoot
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    id,
+			RuleIndex: ruleIndex[id],
+			Level:     sarifLevels[severityName(comment)],
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: location,
+				Region:           region,
+			}}},
+			// Line independent, so code scanning tracks the alert as code moves
+			PartialFingerprints: map[string]string{"manqueIssueHash/v1": BaselineHash(comment)},
+		})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
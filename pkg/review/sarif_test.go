@@ -0,0 +1,116 @@
+package review
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+// TestFormatSARIF_Schema checks the output against the properties the SARIF 2.1.0 schema
+// requires, and the subset GitHub code scanning relies on
+func TestFormatSARIF_Schema(t *testing.T) {
+	root := t.TempDir()
+	result := &ai.ReviewResult{Comments: []ai.Comment{
+		{File: "pkg/api/handler.go", StartLine: 10, EndLine: 12, Header: "🔴 SQL injection", Content: "Use a parameterized query.", Label: "Security", Critical: true},
+		{File: filepath.Join(root, "docs/read me.md"), StartLine: 4, Header: "💡 Clarify setup", Label: "docs"},
+		{File: "main.go", Header: "🟡 Unchecked error", Label: "bug"},
+		{File: "main.go", StartLine: 7, EndLine: 7, Header: "💅 Naming"},
+	}}
+
+	data, err := FormatSARIF(result, root)
+	if err != nil {
+		t.Fatalf("FormatSARIF failed: %v", err)
+	}
+
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" || log["$schema"] == "" {
+		t.Fatalf("Expected SARIF 2.1.0 with $schema, got %v / %v", log["version"], log["$schema"])
+	}
+
+	runs := log["runs"].([]any)
+	if len(runs) != 1 {
+		t.Fatalf("Expected one run, got %d", len(runs))
+	}
+	run := runs[0].(map[string]any)
+
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	if driver["name"] != "manque-ai" {
+		t.Errorf("Expected driver name manque-ai, got %v", driver["name"])
+	}
+	var ruleIDs []string
+	for _, rule := range driver["rules"].([]any) {
+		ruleIDs = append(ruleIDs, rule.(map[string]any)["id"].(string))
+	}
+
+	base := run["originalUriBaseIds"].(map[string]any)["%SRCROOT%"].(map[string]any)
+	if uri := base["uri"].(string); uri[len(uri)-1] != '/' || uri[:8] != "file:///" {
+		t.Errorf("Expected an absolute file URI ending in a slash for %%SRCROOT%%, got %s", uri)
+	}
+
+	validLevels := map[string]bool{"none": true, "note": true, "warning": true, "error": true}
+	results := run["results"].([]any)
+	if len(results) != len(result.Comments) {
+		t.Fatalf("Expected one result per comment, got %d", len(results))
+	}
+
+	wantLevels := []string{"error", "note", "warning", "note"}
+	wantRules := []string{"security", "docs", "bug", "general"}
+	wantURIs := []string{"pkg/api/handler.go", "docs/read%20me.md", "main.go", "main.go"}
+	for i, r := range results {
+		res := r.(map[string]any)
+		if text, _ := res["message"].(map[string]any)["text"].(string); text == "" {
+			t.Errorf("Result %d: message.text is required", i)
+		}
+		if level := res["level"].(string); !validLevels[level] || level != wantLevels[i] {
+			t.Errorf("Result %d: expected level %s, got %s", i, wantLevels[i], level)
+		}
+		if res["ruleId"] != wantRules[i] {
+			t.Errorf("Result %d: expected ruleId %s, got %v", i, wantRules[i], res["ruleId"])
+		}
+		if index := int(res["ruleIndex"].(float64)); index >= len(ruleIDs) || ruleIDs[index] != res["ruleId"] {
+			t.Errorf("Result %d: ruleIndex %d does not point at rule %v", i, index, res["ruleId"])
+		}
+
+		physical := res["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)
+		artifact := physical["artifactLocation"].(map[string]any)
+		if artifact["uri"] != wantURIs[i] || artifact["uriBaseId"] != "%SRCROOT%" {
+			t.Errorf("Result %d: unexpected artifact location %v", i, artifact)
+		}
+		region := physical["region"].(map[string]any)
+		start := int(region["startLine"].(float64))
+		if start < 1 {
+			t.Errorf("Result %d: startLine must be at least 1, got %d", i, start)
+		}
+		if end, ok := region["endLine"].(float64); ok && int(end) < start {
+			t.Errorf("Result %d: endLine %v before startLine %d", i, end, start)
+		}
+	}
+
+	first := results[0].(map[string]any)["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)["region"].(map[string]any)
+	if first["startLine"] != float64(10) || first["endLine"] != float64(12) {
+		t.Errorf("Expected region 10-12, got %v", first)
+	}
+}
+
+func TestFormatSARIF_NoComments(t *testing.T) {
+	data, err := FormatSARIF(&ai.ReviewResult{}, "")
+	if err != nil {
+		t.Fatalf("FormatSARIF failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Errorf("Expected one run with an empty results array, got %s", data)
+	}
+	if log.Runs[0].OriginalURIBaseIDs != nil {
+		t.Errorf("Expected no base URI without a repo root")
+	}
+}
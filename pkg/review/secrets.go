@@ -0,0 +1,36 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+	"github.com/igcodinap/manque-ai/pkg/secrets"
+)
+
+// redactSecrets masks likely secrets in the diff before it is sent to the LLM, and returns
+// a critical comment for each secret on an added line. The comments never include the secret.
+func redactSecrets(files []diff.FileDiff) ([]diff.FileDiff, []ai.Comment) {
+	findings := secrets.ScanDiff(files)
+
+	var comments []ai.Comment
+	for _, finding := range findings {
+		comments = append(comments, ai.Comment{
+			File:      finding.File,
+			StartLine: finding.Line,
+			EndLine:   finding.Line,
+			Header:    fmt.Sprintf("🔴 Remove hardcoded %s", finding.Kind),
+			Content: fmt.Sprintf("This line appears to contain a %s. It was redacted before the diff was sent to the LLM. "+
+				"Load it from a secret store or environment variable instead, and rotate it since it is now in the git history.",
+				finding.Kind),
+			Label:    "security",
+			Critical: true,
+		})
+	}
+	if len(findings) > 0 {
+		internal.Logger.Warn(fmt.Sprintf("Redacted %d likely secret(s) from the diff", len(findings)))
+	}
+
+	return secrets.RedactDiff(files), comments
+}
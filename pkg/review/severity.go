@@ -0,0 +1,130 @@
+package review
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+// Severity overrides set per path in .manque.yml
+const (
+	SeverityStrict  = "strict"  // Raise each comment one level, so warnings become critical
+	SeverityRelaxed = "relaxed" // Lower non-critical comments one level
+	SeverityOff     = "off"     // Drop non-critical comments
+)
+
+// severityLevels are the header emojis from least to most severe
+var severityLevels = []string{"💅", "💡", "🟡", "🔴"}
+
+// severityNames name the entries of severityLevels
+var severityNames = []string{"nitpick", "suggestion", "warning", "critical"}
+
+// criticalLevel is the index of 🔴 in severityLevels
+const criticalLevel = 3
+
+// commentSeverity returns the index of the comment's header emoji in severityLevels, or -1
+// if the header has none
+func commentSeverity(comment ai.Comment) int {
+	header := strings.TrimSpace(comment.Header)
+	for level, emoji := range severityLevels {
+		if strings.HasPrefix(header, emoji) {
+			return level
+		}
+	}
+	return -1
+}
+
+// severityName names the comment's severity; comments flagged critical are always "critical"
+func severityName(comment ai.Comment) string {
+	if comment.Critical {
+		return severityNames[criticalLevel]
+	}
+	if level := commentSeverity(comment); level >= 0 {
+		return severityNames[level]
+	}
+	return "unknown"
+}
+
+// withSeverity returns the comment with its header emoji replaced by the given level
+func withSeverity(comment ai.Comment, level int) ai.Comment {
+	current := commentSeverity(comment)
+	header := strings.TrimSpace(comment.Header)
+	comment.Header = severityLevels[level] + strings.TrimPrefix(header, severityLevels[current])
+	comment.Critical = level == criticalLevel
+	return comment
+}
+
+// applySeverityOverride adjusts a comment for the given override mode. It returns false if
+// the comment should be dropped. Critical comments are never lowered or dropped, and
+// comments without a severity emoji are only affected by "off".
+func applySeverityOverride(comment ai.Comment, mode string) (ai.Comment, bool) {
+	if comment.Critical {
+		return comment, true
+	}
+
+	level := commentSeverity(comment)
+	switch mode {
+	case SeverityOff:
+		return comment, false
+	case SeverityStrict:
+		if level >= 0 {
+			return withSeverity(comment, min(level+1, criticalLevel)), true
+		}
+	case SeverityRelaxed:
+		if level >= 0 && level < criticalLevel {
+			return withSeverity(comment, max(level-1, 0)), true
+		}
+	}
+	return comment, true
+}
+
+// applySeverityOverrides raises, lowers or drops comments according to the severity_override
+// of the path rule matching their file
+func (e *Engine) applySeverityOverrides(comments []ai.Comment) []ai.Comment {
+	if e.Config == nil || len(e.Config.PathRules) == 0 {
+		return comments
+	}
+
+	var kept []ai.Comment
+	for _, comment := range comments {
+		mode := e.Config.GetSeverityOverrideForFile(comment.File)
+		if mode == "" {
+			kept = append(kept, comment)
+			continue
+		}
+		adjusted, keep := applySeverityOverride(comment, mode)
+		if !keep {
+			internal.Logger.Debug("Dropping comment for path with severity override off", "file", comment.File, "header", comment.Header)
+			continue
+		}
+		kept = append(kept, adjusted)
+	}
+	return kept
+}
+
+// severityRank orders comments for LimitComments. Comments without a severity emoji rank
+// as warnings, as in SARIF output.
+func severityRank(comment ai.Comment) int {
+	if comment.Critical {
+		return criticalLevel
+	}
+	if level := commentSeverity(comment); level >= 0 {
+		return level
+	}
+	return criticalLevel - 1
+}
+
+// LimitComments sorts comments from most to least severe, keeping the original order within
+// a severity, and splits off those beyond the first limit. A limit of 0 keeps every comment.
+func LimitComments(comments []ai.Comment, limit int) (kept, overflow []ai.Comment) {
+	if limit <= 0 || len(comments) <= limit {
+		return comments, nil
+	}
+
+	sorted := make([]ai.Comment, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool { return severityRank(sorted[i]) > severityRank(sorted[j]) })
+	return sorted[:limit], sorted[limit:]
+}
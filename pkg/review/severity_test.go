@@ -0,0 +1,126 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ai"
+)
+
+func TestApplySeverityOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		comment  ai.Comment
+		kept     bool
+		header   string
+		critical bool
+	}{
+		{"strict raises warning to critical", SeverityStrict, ai.Comment{Header: "🟡 Missing check"}, true, "🔴 Missing check", true},
+		{"strict raises nitpick to suggestion", SeverityStrict, ai.Comment{Header: "💅 Naming"}, true, "💡 Naming", false},
+		{"strict keeps critical", SeverityStrict, ai.Comment{Header: "🔴 SQL injection", Critical: true}, true, "🔴 SQL injection", true},
+		{"relaxed lowers warning to suggestion", SeverityRelaxed, ai.Comment{Header: "🟡 Missing check"}, true, "💡 Missing check", false},
+		{"relaxed keeps nitpick", SeverityRelaxed, ai.Comment{Header: "💅 Naming"}, true, "💅 Naming", false},
+		{"relaxed keeps critical", SeverityRelaxed, ai.Comment{Header: "🔴 Leaked key", Critical: true}, true, "🔴 Leaked key", true},
+		{"relaxed ignores header without emoji", SeverityRelaxed, ai.Comment{Header: "Plain header"}, true, "Plain header", false},
+		{"off drops warning", SeverityOff, ai.Comment{Header: "🟡 Missing check"}, false, "", false},
+		{"off keeps critical", SeverityOff, ai.Comment{Header: "🔴 Leaked key", Critical: true}, true, "🔴 Leaked key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, kept := applySeverityOverride(tt.comment, tt.mode)
+			if kept != tt.kept {
+				t.Fatalf("Expected kept=%t, got %t", tt.kept, kept)
+			}
+			if !kept {
+				return
+			}
+			if comment.Header != tt.header || comment.Critical != tt.critical {
+				t.Errorf("Expected %q (critical=%t), got %q (critical=%t)", tt.header, tt.critical, comment.Header, comment.Critical)
+			}
+		})
+	}
+}
+
+func TestApplySeverityOverrides_ByPath(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{PathRules: []internal.PathRule{
+		{Path: "internal/payments/**", SeverityOverride: SeverityStrict},
+		{Path: "docs/**", SeverityOverride: SeverityOff},
+	}}}
+
+	comments := engine.applySeverityOverrides([]ai.Comment{
+		{File: "internal/payments/charge.go", Header: "🟡 Unchecked error"},
+		{File: "docs/guide.md", Header: "💡 Reword"},
+		{File: "docs/setup.md", Header: "🔴 Leaked key", Critical: true},
+		{File: "cmd/main.go", Header: "🟡 Unchecked error"},
+	})
+
+	if len(comments) != 3 {
+		t.Fatalf("Expected the docs suggestion to be dropped, got %+v", comments)
+	}
+	if !comments[0].Critical || comments[0].Header != "🔴 Unchecked error" {
+		t.Errorf("Expected payments warning raised to critical, got %+v", comments[0])
+	}
+	if comments[1].File != "docs/setup.md" {
+		t.Errorf("Expected critical docs comment kept, got %+v", comments[1])
+	}
+	if comments[2].Critical || comments[2].Header != "🟡 Unchecked error" {
+		t.Errorf("Expected comment without a path rule unchanged, got %+v", comments[2])
+	}
+}
+
+func TestLimitComments(t *testing.T) {
+	comments := []ai.Comment{
+		{Header: "💡 Suggestion 1"},
+		{Header: "🟡 Warning 1"},
+		{Header: "💅 Nitpick"},
+		{Header: "🔴 Critical 1"},
+		{Header: "No emoji"},
+		{Header: "💡 Suggestion 2"},
+		{Header: "Flagged", Critical: true},
+		{Header: "🟡 Warning 2"},
+	}
+
+	kept, overflow := LimitComments(comments, 5)
+
+	wantKept := []string{"🔴 Critical 1", "Flagged", "🟡 Warning 1", "No emoji", "🟡 Warning 2"}
+	wantOverflow := []string{"💡 Suggestion 1", "💡 Suggestion 2", "💅 Nitpick"}
+	if len(kept) != len(wantKept) || len(overflow) != len(wantOverflow) {
+		t.Fatalf("Expected %d kept and %d overflow, got %d and %d", len(wantKept), len(wantOverflow), len(kept), len(overflow))
+	}
+	for i, header := range wantKept {
+		if kept[i].Header != header {
+			t.Errorf("Kept %d: expected %q, got %q", i, header, kept[i].Header)
+		}
+	}
+	for i, header := range wantOverflow {
+		if overflow[i].Header != header {
+			t.Errorf("Overflow %d: expected %q, got %q", i, header, overflow[i].Header)
+		}
+	}
+	if comments[0].Header != "💡 Suggestion 1" {
+		t.Error("Expected the input slice to be left unsorted")
+	}
+}
+
+func TestLimitComments_NeverDropsCriticalBeforeSuggestions(t *testing.T) {
+	comments := []ai.Comment{{Header: "💡 A"}, {Header: "💡 B"}, {Header: "🔴 C"}}
+
+	kept, overflow := LimitComments(comments, 1)
+	if len(kept) != 1 || kept[0].Header != "🔴 C" || len(overflow) != 2 {
+		t.Errorf("Expected the critical comment to be kept, got %+v", kept)
+	}
+}
+
+func TestLimitComments_Unlimited(t *testing.T) {
+	comments := []ai.Comment{{Header: "💡 A"}, {Header: "🔴 B"}}
+
+	for _, limit := range []int{0, 2, 10} {
+		kept, overflow := LimitComments(comments, limit)
+		if len(kept) != 2 || kept[0].Header != "💡 A" || overflow != nil {
+			t.Errorf("Limit %d: expected comments unchanged, got %+v and %+v", limit, kept, overflow)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/ast"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// SymbolChangeSet lists the exported symbols a change added, removed or modified in a Go
+// file. Methods and fields are qualified with their type, e.g. "Client.Do".
+type SymbolChangeSet struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// IsEmpty reports whether the exported API of the file is unchanged
+func (s SymbolChangeSet) IsEmpty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Modified) == 0
+}
+
+// SummarizeSymbolChanges compares the exported symbols of two versions of a Go file. old is
+// empty for new files. Unparseable content yields an empty set.
+func SummarizeSymbolChanges(old, new string) SymbolChangeSet {
+	parser := ast.NewParser()
+	newSymbols, err := parser.ParseFile("file.go", new)
+	if err != nil {
+		return SymbolChangeSet{}
+	}
+	oldSymbols, err := parser.ParseFile("file.go", old)
+	if err != nil {
+		oldSymbols = nil
+	}
+
+	// Comparing only exported symbols makes unexporting one a removal
+	changes := ast.CompareSymbols(exportedSymbols(oldSymbols), exportedSymbols(newSymbols), "file.go")
+	var set SymbolChangeSet
+	for _, sym := range changes.Added {
+		set.Added = append(set.Added, qualifiedName(sym))
+	}
+	for _, sym := range changes.Removed {
+		set.Removed = append(set.Removed, qualifiedName(sym))
+	}
+	for _, mod := range changes.Modified {
+		set.Modified = append(set.Modified, qualifiedName(mod.New))
+	}
+	return set
+}
+
+// exportedSymbols drops unexported symbols and imports, which aren't part of the API
+func exportedSymbols(symbols []ast.Symbol) []ast.Symbol {
+	var exported []ast.Symbol
+	for _, sym := range symbols {
+		if sym.Exported && sym.Kind != ast.SymbolImport {
+			exported = append(exported, sym)
+		}
+	}
+	return exported
+}
+
+// qualifiedName prefixes methods and fields with their type
+func qualifiedName(sym ast.Symbol) string {
+	if sym.Parent == "" {
+		return sym.Name
+	}
+	return sym.Parent + "." + sym.Name
+}
+
+// DetectSymbolChanges summarizes the exported API changes of each changed Go file between two
+// revisions, keyed by file name. Tests and files whose API is unchanged are left out.
+func DetectSymbolChanges(files []diff.FileDiff, load FileLoader, baseRev, headRev string) map[string]SymbolChangeSet {
+	sets := make(map[string]SymbolChangeSet)
+
+	for _, file := range files {
+		if ast.DetectLanguage(file.Filename) != ast.LangGo || isTestFile(file.Filename) {
+			continue
+		}
+
+		newContent, err := load(headRev, file.Filename)
+		if err != nil {
+			internal.Logger.Debug("Skipping symbol summary", "file", file.Filename, "error", err)
+			continue // Deleted file
+		}
+		oldContent, err := load(baseRev, file.PreviousFilename())
+		if err != nil {
+			oldContent = "" // New file
+		}
+
+		if set := SummarizeSymbolChanges(oldContent, newContent); !set.IsEmpty() {
+			sets[file.Filename] = set
+		}
+	}
+
+	return sets
+}
+
+// FormatSymbolChanges renders a change set for the walkthrough table, e.g.
+// "added `New`; modified `Client.Do`"
+func FormatSymbolChanges(set SymbolChangeSet) string {
+	var parts []string
+	for _, group := range []struct {
+		verb    string
+		symbols []string
+	}{
+		{"added", set.Added},
+		{"removed", set.Removed},
+		{"modified", set.Modified},
+	} {
+		if len(group.symbols) == 0 {
+			continue
+		}
+		names := make([]string, len(group.symbols))
+		for i, name := range group.symbols {
+			names[i] = fmt.Sprintf("`%s`", name)
+		}
+		parts = append(parts, group.verb+" "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,98 @@
+package review
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestSummarizeSymbolChanges(t *testing.T) {
+	old := `package client
+
+type Client struct {
+	Timeout int
+}
+
+func (c *Client) Do(path string) error { return nil }
+
+func Legacy() {}
+
+func Exported() {}
+
+func helper() {}
+`
+	new := `package client
+
+type Client struct {
+	Timeout int
+	Retries int
+}
+
+func (c *Client) Do(path string, retry bool) error { return nil }
+
+func New() *Client { return &Client{} }
+
+func exported() {}
+
+func helper(n int) {}
+`
+	set := SummarizeSymbolChanges(old, new)
+
+	expected := SymbolChangeSet{
+		Added:    []string{"Client.Retries", "New"},
+		Removed:  []string{"Legacy", "Exported"},
+		Modified: []string{"Client.Do"},
+	}
+	if !reflect.DeepEqual(set, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, set)
+	}
+}
+
+func TestSummarizeSymbolChanges_NewAndUnparseable(t *testing.T) {
+	set := SummarizeSymbolChanges("", "package client\n\nfunc New() {}\n")
+	if !reflect.DeepEqual(set.Added, []string{"New"}) || len(set.Removed) != 0 || len(set.Modified) != 0 {
+		t.Errorf("Expected every exported symbol of a new file to be added, got %+v", set)
+	}
+
+	if set := SummarizeSymbolChanges("package client\n", "package client\nfunc {"); !set.IsEmpty() {
+		t.Errorf("Expected no changes for unparseable content, got %+v", set)
+	}
+}
+
+func TestDetectSymbolChanges(t *testing.T) {
+	revisions := map[string]map[string]string{
+		"base": {
+			"api.go":      "package api\n\nfunc Get() {}\n",
+			"internal.go": "package api\n\nfunc get() {}\n",
+		},
+		"head": {
+			"api.go":      "package api\n\nfunc Get(id int) {}\n",
+			"internal.go": "package api\n\nfunc get(id int) {}\n",
+			"api_test.go": "package api\n\nfunc TestGet() {}\n",
+		},
+	}
+	load := func(rev, path string) (string, error) {
+		content, ok := revisions[rev][path]
+		if !ok {
+			return "", fmt.Errorf("%s not found at %s", path, rev)
+		}
+		return content, nil
+	}
+
+	files := []diff.FileDiff{{Filename: "api.go"}, {Filename: "internal.go"}, {Filename: "api_test.go"}, {Filename: "README.md"}}
+	sets := DetectSymbolChanges(files, load, "base", "head")
+
+	if len(sets) != 1 || !reflect.DeepEqual(sets["api.go"].Modified, []string{"Get"}) {
+		t.Errorf("Expected only the exported API change in api.go, got %+v", sets)
+	}
+}
+
+func TestFormatSymbolChanges(t *testing.T) {
+	set := SymbolChangeSet{Added: []string{"New", "Client.Retries"}, Modified: []string{"Client.Do"}}
+	expected := "added `New`, `Client.Retries`; modified `Client.Do`"
+	if got := FormatSymbolChanges(set); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,114 @@
+package review
+
+import (
+	"strings"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+const (
+	// DefaultContextWindow is the context window assumed for models not in modelContextWindows
+	DefaultContextWindow = 32000
+	// MinChunkTokens is the smallest chunk budget, used when headroom leaves less than this
+	MinChunkTokens = 2500
+	// promptHeadroomTokens is reserved for the system prompt, style rules and PR description
+	promptHeadroomTokens = 4000
+	// defaultOutputTokens is reserved for the response when LLM_MAX_TOKENS is not set
+	defaultOutputTokens = 4096
+)
+
+// TokenEstimator estimates how many tokens a text takes up in a model's context
+type TokenEstimator interface {
+	EstimateTokens(text string) int
+}
+
+// CharTokenEstimator estimates tokens from the character count
+type CharTokenEstimator struct {
+	CharsPerToken int
+}
+
+// EstimateTokens rounds up so short texts never count as zero tokens
+func (e CharTokenEstimator) EstimateTokens(text string) int {
+	charsPerToken := e.CharsPerToken
+	if charsPerToken <= 0 {
+		charsPerToken = 4
+	}
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// DefaultTokenEstimator approximates code and English prose at about 4 characters per token
+var DefaultTokenEstimator TokenEstimator = CharTokenEstimator{CharsPerToken: 4}
+
+// modelContextWindows maps "provider/model" prefixes to context windows in tokens. OpenRouter
+// model IDs already have this form, so they are looked up without their provider.
+var modelContextWindows = map[string]int{
+	"openai/gpt-3.5-turbo":          16385,
+	"openai/gpt-4":                  8192,
+	"openai/gpt-4-turbo":            128000,
+	"openai/gpt-4o":                 128000,
+	"openai/gpt-4.1":                1047576,
+	"openai/gpt-5":                  400000,
+	"openai/o1":                     200000,
+	"openai/o3":                     200000,
+	"openai/o4-mini":                200000,
+	"anthropic/claude":              200000,
+	"google/gemini-pro":             32760,
+	"google/gemini-1.5-flash":       1048576,
+	"google/gemini-1.5-pro":         2097152,
+	"google/gemini-2":               1048576,
+	"mistralai/mistral-7b-instruct": 32768,
+	"meta-llama/llama-3":            8192,
+	"meta-llama/llama-3.1":          131072,
+}
+
+// contextWindow returns the context window for a model, matching the longest known prefix
+func contextWindow(provider, model string) int {
+	candidates := []string{strings.ToLower(provider + "/" + model), strings.ToLower(model)}
+
+	window, longest := DefaultContextWindow, 0
+	for prefix, size := range modelContextWindows {
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, prefix) && len(prefix) > longest {
+				window, longest = size, len(prefix)
+			}
+		}
+	}
+	return window
+}
+
+// tokenEstimator returns the engine's estimator, or the default
+func (e *Engine) tokenEstimator() TokenEstimator {
+	if e.TokenEstimator != nil {
+		return e.TokenEstimator
+	}
+	return DefaultTokenEstimator
+}
+
+// maxChunkTokens is the token budget for the diff of one chunk: the model's input budget
+// minus headroom for the prompt, referenced files and the expected output
+func (e *Engine) maxChunkTokens() int {
+	window := DefaultContextWindow
+	outputTokens := defaultOutputTokens
+	contextTokens := 0
+
+	if e.Config != nil {
+		window = contextWindow(e.Config.LLMProvider, e.Config.LLMModel)
+		if e.Config.LLMMaxInputTokens > 0 {
+			window = e.Config.LLMMaxInputTokens
+		}
+		if e.Config.LLMMaxTokens != nil {
+			outputTokens = *e.Config.LLMMaxTokens
+		}
+	}
+	if e.ContextFetcher != nil {
+		// Referenced files aren't known yet, so assume the default 4 characters per token
+		contextTokens = e.ContextFetcher.MaxBytes / 4
+	}
+
+	return max(window-promptHeadroomTokens-outputTokens-contextTokens, MinChunkTokens)
+}
+
+// fileTokens estimates the tokens a file's diff takes up in the prompt
+func (e *Engine) fileTokens(file diff.FileDiff) int {
+	return e.tokenEstimator().EstimateTokens(diff.FormatForLLM([]diff.FileDiff{file}))
+}
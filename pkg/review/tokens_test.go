@@ -0,0 +1,92 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/igcodinap/manque-ai/internal"
+	"github.com/igcodinap/manque-ai/pkg/context"
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+func TestCharTokenEstimator(t *testing.T) {
+	estimator := CharTokenEstimator{CharsPerToken: 4}
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+	}
+	for _, tt := range tests {
+		if got := estimator.EstimateTokens(tt.text); got != tt.expected {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		provider string
+		model    string
+		expected int
+	}{
+		{"openai", "gpt-4o", 128000},
+		{"openai", "gpt-4o-mini", 128000},
+		{"openai", "gpt-4", 8192},
+		{"openai", "gpt-4-turbo-preview", 128000},
+		{"anthropic", "claude-sonnet-4-20250514", 200000},
+		{"openrouter", "openai/gpt-4o", 128000},
+		{"openrouter", "mistralai/mistral-7b-instruct:free", 32768},
+		{"openai", "llama3", DefaultContextWindow},
+	}
+	for _, tt := range tests {
+		if got := contextWindow(tt.provider, tt.model); got != tt.expected {
+			t.Errorf("contextWindow(%s, %s) = %d, want %d", tt.provider, tt.model, got, tt.expected)
+		}
+	}
+}
+
+func TestMaxChunkTokens(t *testing.T) {
+	maxTokens := 1000
+	engine := &Engine{
+		Config:         &internal.Config{LLMProvider: "openai", LLMModel: "gpt-4o", LLMMaxTokens: &maxTokens},
+		ContextFetcher: &context.Fetcher{MaxBytes: 40000},
+	}
+	if got, want := engine.maxChunkTokens(), 128000-promptHeadroomTokens-1000-10000; got != want {
+		t.Errorf("Expected headroom for prompt, output and context, got %d want %d", got, want)
+	}
+
+	engine.Config.LLMMaxInputTokens = 50000
+	if got, want := engine.maxChunkTokens(), 50000-promptHeadroomTokens-1000-10000; got != want {
+		t.Errorf("Expected LLM_MAX_INPUT_TOKENS to override the model window, got %d want %d", got, want)
+	}
+
+	engine.Config.LLMMaxInputTokens = 8000
+	if got := engine.maxChunkTokens(); got != MinChunkTokens {
+		t.Errorf("Expected the minimum chunk budget for tiny windows, got %d", got)
+	}
+}
+
+func TestCreateFileChunks_UsesTokenEstimator(t *testing.T) {
+	internal.InitLogger(false)
+	engine := &Engine{Config: &internal.Config{LLMMaxInputTokens: 20000}}
+	budget := engine.maxChunkTokens()
+
+	files := []diff.FileDiff{
+		sizedFile("one.go", budget*2/3),
+		sizedFile("two.go", budget*2/3),
+	}
+
+	// At 4 characters per token both files fit in one chunk
+	if chunks := engine.createFileChunks(files); len(chunks) != 1 {
+		t.Errorf("Expected 1 chunk with the default estimator, got %d", len(chunks))
+	}
+
+	// A denser tokenizer makes each file take most of the budget
+	engine.TokenEstimator = CharTokenEstimator{CharsPerToken: 1}
+	if chunks := engine.createFileChunks(files); len(chunks) != 2 {
+		t.Errorf("Expected 2 chunks with 1 character per token, got %d", len(chunks))
+	}
+}
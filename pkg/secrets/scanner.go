@@ -0,0 +1,241 @@
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/igcodinap/manque-ai/pkg/diff"
+)
+
+// Redacted replaces secrets in the diff sent to the LLM
+const Redacted = "****"
+
+// entropyThreshold is the Shannon entropy, in bits per character, above which a long
+// token is treated as a random secret
+const entropyThreshold = 4.2
+
+// SecretFinding is a likely hardcoded secret on an added line
+type SecretFinding struct {
+	File  string
+	Line  int    // Line number in the new file
+	Kind  string // e.g. "AWS access key"
+	Match string // The matched secret; never post it anywhere
+}
+
+// secretPattern matches one kind of secret. Group selects the submatch holding the secret
+// itself, so the key name in "password = ..." is kept readable.
+type secretPattern struct {
+	kind  string
+	regex *regexp.Regexp
+	group int
+}
+
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`)
+
+	secretPatterns = []secretPattern{
+		{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+		{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`), 0},
+		{"private key", privateKeyBegin, 0},
+		{"password", regexp.MustCompile(`(?i)(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)["']?\s*(?::=|=|:)\s*["']([^"'\s$<{]{4,})["']`), 1},
+		{"password", regexp.MustCompile(`(?i)^\s*(?:export\s+)?\w*(?:password|passwd|secret|api_?key|token)\w*=([^"'\s$<{]{4,})\s*$`), 1},
+	}
+
+	// entropyCandidate matches long base64-like tokens checked for entropy
+	entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_-]{32,}={0,2}`)
+)
+
+// secretMatch is the byte range of a secret within a line
+type secretMatch struct {
+	kind       string
+	start, end int
+}
+
+// Entropy returns the Shannon entropy of s in bits per character
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// isHighEntropy reports whether a token looks like a random secret: it mixes upper case,
+// lower case and digits, which rules out identifiers and hex hashes
+func isHighEntropy(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit && Entropy(token) >= entropyThreshold
+}
+
+// scanLine returns the secrets in a line, ordered by position and without overlaps
+func scanLine(content string) []secretMatch {
+	var matches []secretMatch
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && end > m.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, pattern := range secretPatterns {
+		for _, loc := range pattern.regex.FindAllStringSubmatchIndex(content, -1) {
+			start, end := loc[2*pattern.group], loc[2*pattern.group+1]
+			if start >= 0 && !overlaps(start, end) {
+				matches = append(matches, secretMatch{kind: pattern.kind, start: start, end: end})
+			}
+		}
+	}
+	for _, loc := range entropyCandidate.FindAllStringIndex(content, -1) {
+		if isHighEntropy(content[loc[0]:loc[1]]) && !overlaps(loc[0], loc[1]) {
+			matches = append(matches, secretMatch{kind: "high-entropy string", start: loc[0], end: loc[1]})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	return matches
+}
+
+// redactLine replaces each match in content with Redacted
+func redactLine(content string, matches []secretMatch) string {
+	var builder strings.Builder
+	last := 0
+	for _, m := range matches {
+		builder.WriteString(content[last:m.start])
+		builder.WriteString(Redacted)
+		last = m.end
+	}
+	builder.WriteString(content[last:])
+	return builder.String()
+}
+
+// walkHunk calls fn for every line of a hunk with the secrets it contains. Lines between
+// private key markers are reported with inKey set, since their base64 body is the secret.
+func walkHunk(hunk diff.Hunk, fn func(i int, line diff.Line, matches []secretMatch, inKey bool)) {
+	inKey := false
+	for i, line := range hunk.Lines {
+		if inKey {
+			fn(i, line, nil, true)
+			if privateKeyEnd.MatchString(line.Content) {
+				inKey = false
+			}
+			continue
+		}
+
+		matches := scanLine(line.Content)
+		for _, m := range matches {
+			if m.kind == "private key" && !privateKeyEnd.MatchString(line.Content[m.end:]) {
+				inKey = true
+			}
+		}
+		fn(i, line, matches, false)
+	}
+}
+
+// ScanDiff flags likely secrets on added lines. Lock files are skipped since their
+// checksums look like random tokens.
+func ScanDiff(files []diff.FileDiff) []SecretFinding {
+	var findings []SecretFinding
+
+	for _, file := range files {
+		if diff.IsLockfile(file.Filename) {
+			continue
+		}
+		for _, hunk := range file.Hunks {
+			walkHunk(hunk, func(_ int, line diff.Line, matches []secretMatch, _ bool) {
+				if line.Type != diff.LineAdded {
+					return
+				}
+				for _, m := range matches {
+					findings = append(findings, SecretFinding{
+						File:  file.Filename,
+						Line:  line.NewNum,
+						Kind:  m.kind,
+						Match: line.Content[m.start:m.end],
+					})
+				}
+			})
+		}
+	}
+
+	return findings
+}
+
+// RedactDiff returns a copy of files with every secret replaced by Redacted. Removed and
+// context lines are redacted too, since they are sent to the LLM as well.
+func RedactDiff(files []diff.FileDiff) []diff.FileDiff {
+	redacted := make([]diff.FileDiff, len(files))
+
+	for i, file := range files {
+		redacted[i] = file
+		if diff.IsLockfile(file.Filename) {
+			continue
+		}
+
+		redacted[i].Hunks = make([]diff.Hunk, len(file.Hunks))
+		for j, hunk := range file.Hunks {
+			lines := make([]diff.Line, len(hunk.Lines))
+			walkHunk(hunk, func(k int, line diff.Line, matches []secretMatch, inKey bool) {
+				switch {
+				case inKey:
+					line.Content = Redacted
+				case len(matches) > 0:
+					line.Content = redactLine(line.Content, matches)
+				}
+				lines[k] = line
+			})
+			hunk.Lines = lines
+			redacted[i].Hunks[j] = hunk
+		}
+	}
+
+	return redacted
+}
+
+// RedactText returns text with every secret replaced by Redacted. It is used for context
+// sent alongside the diff, such as referenced files and blame output.
+func RedactText(text string) string {
+	var hunk diff.Hunk
+	for _, content := range strings.Split(text, "\n") {
+		hunk.Lines = append(hunk.Lines, diff.Line{Type: diff.LineContext, Content: content})
+	}
+
+	lines := make([]string, len(hunk.Lines))
+	walkHunk(hunk, func(i int, line diff.Line, matches []secretMatch, inKey bool) {
+		switch {
+		case inKey:
+			lines[i] = Redacted
+		case len(matches) > 0:
+			lines[i] = redactLine(line.Content, matches)
+		default:
+			lines[i] = line.Content
+		}
+	})
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,125 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultBaselineFile is the baseline file name looked up in the repository root
+const DefaultBaselineFile = ".manque-baseline.json"
+
+// Baseline records known issues that should not be reported again, so teams adopting
+// the reviewer on an existing codebase only see new findings
+type Baseline struct {
+	Version   int             `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Entries   []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is a single suppressed issue. Header is kept for readability only.
+type BaselineEntry struct {
+	Hash   string `json:"hash"`
+	File   string `json:"file"`
+	Label  string `json:"label,omitempty"`
+	Header string `json:"header"`
+}
+
+// BaselineHash identifies an issue independently of its line numbers, which shift as
+// unrelated code changes around it. The headline is left out because the model rewords
+// it from run to run; the content is normalized so formatting changes don't matter.
+func BaselineHash(file, label, content string) string {
+	data := fmt.Sprintf("%s:%s:%s", file, strings.ToLower(strings.TrimSpace(label)), normalizeIssueText(content))
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:8])
+}
+
+// normalizeIssueText lowercases text and reduces it to its words, dropping punctuation
+// and markdown
+func normalizeIssueText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// NewBaseline creates an empty baseline
+func NewBaseline() *Baseline {
+	return &Baseline{Version: 1, Entries: []BaselineEntry{}}
+}
+
+// LoadBaseline reads a baseline file. A missing file yields an empty baseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewBaseline(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	baseline := NewBaseline()
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// Save writes the baseline with entries sorted for stable diffs
+func (b *Baseline) Save(path string) error {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		if b.Entries[i].File != b.Entries[j].File {
+			return b.Entries[i].File < b.Entries[j].File
+		}
+		return b.Entries[i].Hash < b.Entries[j].Hash
+	})
+	b.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Contains checks if an issue hash is in the baseline
+func (b *Baseline) Contains(hash string) bool {
+	for _, entry := range b.Entries {
+		if entry.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Add records an issue, returning false if it was already present
+func (b *Baseline) Add(file, label, header, content string) bool {
+	hash := BaselineHash(file, label, content)
+	if b.Contains(hash) {
+		return false
+	}
+	b.Entries = append(b.Entries, BaselineEntry{Hash: hash, File: file, Label: label, Header: header})
+	return true
+}
+
+// Prune removes entries whose hash is not in current, returning how many were removed
+func (b *Baseline) Prune(current map[string]bool) int {
+	kept := b.Entries[:0]
+	for _, entry := range b.Entries {
+		if current[entry.Hash] {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(b.Entries) - len(kept)
+	b.Entries = kept
+	return removed
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBaselineHash_IgnoresLineAndLabelCase(t *testing.T) {
+	a := BaselineHash("main.go", "Bug", "Possible nil dereference of `cfg`.")
+	b := BaselineHash("main.go", "bug", " possible nil\ndereference of cfg ")
+	if a != b {
+		t.Errorf("Expected equal hashes, got %s and %s", a, b)
+	}
+	if a == BaselineHash("other.go", "bug", "Possible nil dereference of `cfg`.") {
+		t.Error("Expected different files to hash differently")
+	}
+	if a == BaselineHash("main.go", "bug", "Unchecked error from Close") {
+		t.Error("Expected different content to hash differently")
+	}
+}
+
+func TestBaseline_SaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultBaselineFile)
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("Expected missing baseline to load empty, got %v", err)
+	}
+	if len(baseline.Entries) != 0 {
+		t.Fatalf("Expected empty baseline, got %+v", baseline.Entries)
+	}
+
+	if !baseline.Add("b.go", "bug", "Second", "second issue") || !baseline.Add("a.go", "style", "First", "first issue") {
+		t.Fatal("Expected new entries to be added")
+	}
+	if baseline.Add("a.go", "style", "First", "first issue") {
+		t.Error("Expected duplicate entry to be rejected")
+	}
+	if err := baseline.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+	if len(loaded.Entries) != 2 || loaded.Entries[0].File != "a.go" {
+		t.Errorf("Expected 2 sorted entries, got %+v", loaded.Entries)
+	}
+	if !loaded.Contains(BaselineHash("b.go", "bug", "second issue")) {
+		t.Error("Expected loaded baseline to contain saved entry")
+	}
+}
+
+func TestBaseline_Prune(t *testing.T) {
+	baseline := NewBaseline()
+	baseline.Add("a.go", "bug", "Kept", "kept issue")
+	baseline.Add("b.go", "bug", "Fixed", "fixed issue")
+
+	removed := baseline.Prune(map[string]bool{BaselineHash("a.go", "bug", "kept issue"): true})
+	if removed != 1 || len(baseline.Entries) != 1 || baseline.Entries[0].Header != "Kept" {
+		t.Errorf("Expected fixed issue to be pruned, removed=%d entries=%+v", removed, baseline.Entries)
+	}
+}
@@ -1,25 +1,51 @@
 package state
 
 import (
+	"bytes"
+	"compress/gzip"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
+
+	"github.com/igcodinap/manque-ai/pkg/markers"
 )
 
-// SessionMarker is the HTML comment marker used to store session data in PR body
-const SessionMarker = "<!-- manque-session:"
+// legacySessionMarker stored session data before markers were namespaced
+const legacySessionMarker = "<!-- manque-session:"
+
+// SessionMarker returns the HTML comment marker used to store session data in PR body
+func SessionMarker() string {
+	return markers.Data("session")
+}
+
+// findSessionMarker returns the position of the session marker in body and the marker found
+func findSessionMarker(body string) (int, string) {
+	return markers.Index(body, SessionMarker(), legacySessionMarker)
+}
+
+// compressedSessionPrefix marks session data stored as gzip+base64 instead of raw JSON
+const compressedSessionPrefix = "gz:"
+
+// SessionCompressThreshold is the JSON size in bytes above which the session marker is
+// compressed, keeping busy PR descriptions small. Set to 0 to always compress.
+var SessionCompressThreshold = 2048
 
 // Session represents the accumulated review session data
 type Session struct {
-	PRNumber     int              `json:"pr_number"`
-	Repository   string           `json:"repository"`
-	Reviews      []ReviewRecord   `json:"reviews"`
-	Interactions []Interaction    `json:"interactions"`
-	Dismissed    []DismissedIssue `json:"dismissed"`
-	UpdatedAt    time.Time        `json:"updated_at"`
+	PRNumber      int              `json:"pr_number"`
+	Repository    string           `json:"repository"`
+	Reviews       []ReviewRecord   `json:"reviews"`
+	Interactions  []Interaction    `json:"interactions"`
+	Dismissed     []DismissedIssue `json:"dismissed"`
+	Resolved      []string         `json:"resolved,omitempty"`       // Hashes of issues users marked resolved
+	ResolvedFuzzy []string         `json:"resolved_fuzzy,omitempty"` // Fuzzy hashes of the resolved issues
+	Checklist     []ChecklistItem  `json:"checklist,omitempty"`
+	UpdatedAt     time.Time        `json:"updated_at"`
 }
 
 // ReviewRecord represents a single review round
@@ -43,9 +69,20 @@ type Interaction struct {
 
 // DismissedIssue represents an issue the user explicitly dismissed
 type DismissedIssue struct {
-	Hash        string    `json:"hash"` // file:line:content hash
+	Hash        string    `json:"hash"`                 // file:line:content hash
+	FuzzyHash   string    `json:"fuzzy_hash,omitempty"` // file:content hash, still matching once the lines shift
 	Reason      string    `json:"reason,omitempty"`
 	DismissedAt time.Time `json:"dismissed_at"`
+	Stale       bool      `json:"stale,omitempty"` // Made in a session that went stale; no longer filters comments
+}
+
+// ChecklistItem is a required fix listed in a changes-requested review, kept so later
+// reviews can check it off once addressed
+type ChecklistItem struct {
+	Hash   string `json:"hash"` // Same hash as the review comment
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Header string `json:"header"`
 }
 
 // SessionManager handles session persistence and retrieval
@@ -64,39 +101,83 @@ func NewSessionManager(repository string, prNumber int) *SessionManager {
 
 // ExtractSessionFromBody extracts the session from a PR body
 func ExtractSessionFromBody(body string) *Session {
-	startIdx := strings.Index(body, SessionMarker)
+	startIdx, marker := findSessionMarker(body)
 	if startIdx == -1 {
 		return nil
 	}
 
-	jsonStart := startIdx + len(SessionMarker)
+	jsonStart := startIdx + len(marker)
 	endIdx := strings.Index(body[jsonStart:], "-->")
 	if endIdx == -1 {
 		return nil
 	}
 
-	jsonContent := body[jsonStart : jsonStart+endIdx]
+	jsonContent := []byte(body[jsonStart : jsonStart+endIdx])
+
+	if encoded, ok := strings.CutPrefix(string(jsonContent), compressedSessionPrefix); ok {
+		decompressed, err := decompressSession(encoded)
+		if err != nil {
+			return nil
+		}
+		jsonContent = decompressed
+	}
 
 	var session Session
-	if err := json.Unmarshal([]byte(jsonContent), &session); err != nil {
+	if err := json.Unmarshal(jsonContent, &session); err != nil {
 		return nil
 	}
 
 	return &session
 }
 
-// CreateSessionMarker creates the HTML comment with session data
+// CreateSessionMarker creates the HTML comment with session data,
+// compressing it when it exceeds SessionCompressThreshold
 func CreateSessionMarker(session *Session) string {
 	data, err := json.Marshal(session)
 	if err != nil {
 		return ""
 	}
-	return fmt.Sprintf("%s%s-->", SessionMarker, string(data))
+
+	if len(data) > SessionCompressThreshold {
+		encoded, err := compressSession(data)
+		if err == nil {
+			return fmt.Sprintf("%s%s%s-->", SessionMarker(), compressedSessionPrefix, encoded)
+		}
+	}
+
+	return fmt.Sprintf("%s%s-->", SessionMarker(), string(data))
+}
+
+// compressSession gzips session JSON and encodes it as base64 so it is safe inside an HTML comment
+func compressSession(data []byte) (string, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decompressSession reverses compressSession
+func decompressSession(encoded string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
 }
 
 // StripSessionMarker removes the session marker from a PR body
 func StripSessionMarker(body string) string {
-	startIdx := strings.Index(body, SessionMarker)
+	startIdx, _ := findSessionMarker(body)
 	if startIdx == -1 {
 		return body
 	}
@@ -114,6 +195,21 @@ func StripSessionMarker(body string) string {
 	return body[:startIdx] + body[endPos:]
 }
 
+// ReplaceSessionMarker returns body with its session marker replaced by one for session,
+// keeping its position. The marker is appended when body has none.
+func ReplaceSessionMarker(body string, session *Session) string {
+	marker := CreateSessionMarker(session)
+	startIdx, _ := findSessionMarker(body)
+	stripped := StripSessionMarker(body)
+	if startIdx == -1 || stripped == body {
+		if strings.TrimSpace(body) == "" {
+			return marker
+		}
+		return strings.TrimRight(body, "\n") + "\n\n" + marker
+	}
+	return stripped[:startIdx] + marker + "\n" + stripped[startIdx:]
+}
+
 // GetOrCreateSession retrieves existing session or creates a new one
 func (m *SessionManager) GetOrCreateSession(prBody string) *Session {
 	existing := ExtractSessionFromBody(prBody)
@@ -157,33 +253,68 @@ func (s *Session) AddInteraction(interactionType string, commentID int64, conten
 	s.UpdatedAt = time.Now()
 }
 
-// DismissIssue marks an issue as dismissed
-func (s *Session) DismissIssue(hash, reason string) {
-	// Check if already dismissed
-	for _, d := range s.Dismissed {
+// DismissIssue marks an issue as dismissed. fuzzyHash, from ComputeFuzzyHash, keeps the
+// issue dismissed when edits nearby move it to other lines; it may be empty.
+func (s *Session) DismissIssue(hash, fuzzyHash, reason string) {
+	// Check if already dismissed, reviving a stale dismissal
+	for i, d := range s.Dismissed {
 		if d.Hash == hash {
+			if d.FuzzyHash == "" && fuzzyHash != "" {
+				s.Dismissed[i].FuzzyHash = fuzzyHash
+				s.UpdatedAt = time.Now()
+			}
+			if d.Stale {
+				s.Dismissed[i].Stale = false
+				s.Dismissed[i].Reason = reason
+				s.Dismissed[i].DismissedAt = time.Now()
+				s.UpdatedAt = time.Now()
+			}
 			return
 		}
 	}
 
 	s.Dismissed = append(s.Dismissed, DismissedIssue{
 		Hash:        hash,
+		FuzzyHash:   fuzzyHash,
 		Reason:      reason,
 		DismissedAt: time.Now(),
 	})
 	s.UpdatedAt = time.Now()
 }
 
-// IsDismissed checks if an issue has been dismissed
-func (s *Session) IsDismissed(hash string) bool {
+// IsDismissed checks if an issue has been dismissed, by its exact hash first and then by
+// fuzzyHash, which may be empty. Stale dismissals are ignored.
+func (s *Session) IsDismissed(hash, fuzzyHash string) bool {
 	for _, d := range s.Dismissed {
 		if d.Hash == hash {
+			return !d.Stale
+		}
+	}
+	if fuzzyHash == "" {
+		return false
+	}
+	for _, d := range s.Dismissed {
+		if d.FuzzyHash == fuzzyHash && !d.Stale {
 			return true
 		}
 	}
 	return false
 }
 
+// MarkStale flags the dismissals of a session last updated more than maxAge before now,
+// so a PR revived after a long pause is reviewed afresh. Dismissals are kept for reference.
+// It reports whether the session was stale; a maxAge of 0 disables the check.
+func (s *Session) MarkStale(maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || s.UpdatedAt.IsZero() || now.Sub(s.UpdatedAt) <= maxAge {
+		return false
+	}
+
+	for i := range s.Dismissed {
+		s.Dismissed[i].Stale = true
+	}
+	return true
+}
+
 // MarkAddressed marks issues as addressed in the previous review
 func (s *Session) MarkAddressed(hashes []string) {
 	if len(s.Reviews) == 0 {
@@ -196,6 +327,36 @@ func (s *Session) MarkAddressed(hashes []string) {
 	s.UpdatedAt = time.Now()
 }
 
+// ResolveIssue records an issue a user marked as resolved. Unlike MarkAddressed it is kept
+// on the session, so it survives old review records being trimmed. fuzzyHash, from
+// ComputeFuzzyHash, keeps the issue resolved when edits nearby move it; it may be empty.
+func (s *Session) ResolveIssue(hash, fuzzyHash string) {
+	if !containsHash(s.Resolved, hash) {
+		s.Resolved = append(s.Resolved, hash)
+		s.UpdatedAt = time.Now()
+	}
+	if fuzzyHash != "" && !containsHash(s.ResolvedFuzzy, fuzzyHash) {
+		s.ResolvedFuzzy = append(s.ResolvedFuzzy, fuzzyHash)
+		s.UpdatedAt = time.Now()
+	}
+}
+
+// IsResolved checks if a user marked an issue as resolved, by its exact hash or by
+// fuzzyHash, which may be empty
+func (s *Session) IsResolved(hash, fuzzyHash string) bool {
+	return containsHash(s.Resolved, hash) || (fuzzyHash != "" && containsHash(s.ResolvedFuzzy, fuzzyHash))
+}
+
+// containsHash reports whether hashes holds hash
+func containsHash(hashes []string, hash string) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
 // GetPreviousCommentHashes returns all comment hashes from previous reviews
 func (s *Session) GetPreviousCommentHashes() map[string]bool {
 	hashes := make(map[string]bool)
@@ -207,8 +368,13 @@ func (s *Session) GetPreviousCommentHashes() map[string]bool {
 	return hashes
 }
 
-// WasAddressed checks if an issue was marked as addressed
+// WasAddressed checks if an issue was marked as addressed or resolved
 func (s *Session) WasAddressed(hash string) bool {
+	for _, resolved := range s.Resolved {
+		if resolved == hash {
+			return true
+		}
+	}
 	for _, review := range s.Reviews {
 		for _, addressed := range review.Addressed {
 			if addressed == hash {
@@ -219,6 +385,49 @@ func (s *Session) WasAddressed(hash string) bool {
 	return false
 }
 
+// AddChecklistItems records required fixes, skipping items already on the checklist
+func (s *Session) AddChecklistItems(items []ChecklistItem) {
+	for _, item := range items {
+		if !s.hasChecklistItem(item.Hash) {
+			s.Checklist = append(s.Checklist, item)
+		}
+	}
+	s.UpdatedAt = time.Now()
+}
+
+func (s *Session) hasChecklistItem(hash string) bool {
+	for _, item := range s.Checklist {
+		if item.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveChecklist marks open checklist items as addressed when their file changed since the
+// last review and the same issue (by file and header) was not raised again. Issue hashes include
+// line numbers, which shift as the file is edited, so they cannot be compared directly.
+// It returns the hashes of the newly addressed items.
+func (s *Session) ResolveChecklist(changedFiles map[string]bool, raised []ChecklistItem) []string {
+	stillRaised := make(map[string]bool)
+	for _, item := range raised {
+		stillRaised[item.File+":"+item.Header] = true
+	}
+
+	var addressed []string
+	for _, item := range s.Checklist {
+		if s.WasAddressed(item.Hash) || !changedFiles[item.File] || stillRaised[item.File+":"+item.Header] {
+			continue
+		}
+		addressed = append(addressed, item.Hash)
+	}
+
+	if len(addressed) > 0 {
+		s.MarkAddressed(addressed)
+	}
+	return addressed
+}
+
 // GetSummary returns a human-readable summary of the session
 func (s *Session) GetSummary() string {
 	if len(s.Reviews) == 0 {
@@ -253,12 +462,26 @@ func ComputeCommentHash(file string, startLine, endLine int, content string) str
 	return hex.EncodeToString(hash[:8]) // Use first 8 bytes for shorter hash
 }
 
+// ComputeFuzzyHash generates a hash for a comment that ignores its line numbers and
+// whitespace, so an issue is recognized after edits nearby shift it
+func ComputeFuzzyHash(file, content string) string {
+	data := fmt.Sprintf("%s:%s", file, strings.Join(strings.Fields(content), " "))
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:8])
+}
+
 // TrimSession removes old data to keep the marker size manageable
 func (s *Session) TrimSession(maxReviews int) {
 	if len(s.Reviews) > maxReviews {
 		s.Reviews = s.Reviews[len(s.Reviews)-maxReviews:]
 	}
 
+	// Keep only recent checklist items
+	maxChecklistItems := 50
+	if len(s.Checklist) > maxChecklistItems {
+		s.Checklist = s.Checklist[len(s.Checklist)-maxChecklistItems:]
+	}
+
 	// Keep only recent interactions
 	maxInteractions := 20
 	if len(s.Interactions) > maxInteractions {
@@ -1,8 +1,12 @@
 package state
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/igcodinap/manque-ai/pkg/markers"
 )
 
 func TestExtractSessionFromBody(t *testing.T) {
@@ -12,7 +16,7 @@ func TestExtractSessionFromBody(t *testing.T) {
 	// Add some data
 	session.AddReviewRecord("abc123", []string{"hash1", "hash2"}, 85, 2)
 	session.AddInteraction("reply", 456, "test comment", "bot response")
-	session.DismissIssue("hash3", "false positive")
+	session.DismissIssue("hash3", "", "false positive")
 
 	marker := CreateSessionMarker(session)
 	body := "Some PR description\n\n" + marker + "\n\nMore content"
@@ -43,6 +47,57 @@ func TestExtractSessionFromBody(t *testing.T) {
 	}
 }
 
+func TestSessionMarkerCompressionRoundTrip(t *testing.T) {
+	manager := NewSessionManager("owner/repo", 123)
+	session := manager.GetOrCreateSession("")
+	for i := 0; i < 50; i++ {
+		session.AddReviewRecord(fmt.Sprintf("sha%d", i), []string{"hash1", "hash2", "hash3"}, 80, 3)
+		session.AddInteraction("reply", int64(i), "a reply containing --> and <!-- markers", "bot response")
+	}
+
+	marker := CreateSessionMarker(session)
+	if !strings.HasPrefix(marker, SessionMarker()+compressedSessionPrefix) {
+		t.Fatalf("Expected large session to be compressed, got marker of %d bytes", len(marker))
+	}
+	if strings.Contains(strings.TrimSuffix(marker, "-->"), "-->") {
+		t.Error("Compressed marker must not contain the comment terminator")
+	}
+
+	body := "Some PR description\n\n" + marker + "\n\nMore content"
+	extracted := ExtractSessionFromBody(body)
+	if extracted == nil {
+		t.Fatal("Failed to extract compressed session from body")
+	}
+	if len(extracted.Reviews) != 50 || len(extracted.Interactions) != 50 {
+		t.Errorf("Round trip lost data: %d reviews, %d interactions", len(extracted.Reviews), len(extracted.Interactions))
+	}
+	if extracted.Interactions[0].Content != "a reply containing --> and <!-- markers" {
+		t.Errorf("Interaction content mismatch: %q", extracted.Interactions[0].Content)
+	}
+
+	if stripped := StripSessionMarker(body); strings.Contains(stripped, SessionMarker()) {
+		t.Error("Compressed session marker should be stripped")
+	}
+}
+
+func TestSessionMarkerSmallSessionUncompressed(t *testing.T) {
+	manager := NewSessionManager("owner/repo", 123)
+	session := manager.GetOrCreateSession("")
+	session.AddReviewRecord("abc123", []string{"hash1"}, 90, 1)
+
+	marker := CreateSessionMarker(session)
+	if !strings.HasPrefix(marker, SessionMarker()+"{") {
+		t.Errorf("Expected small session to be stored as plain JSON, got %s", marker)
+	}
+}
+
+func TestExtractSessionFromBodyCorruptCompressed(t *testing.T) {
+	body := SessionMarker() + compressedSessionPrefix + "not-base64!!-->"
+	if ExtractSessionFromBody(body) != nil {
+		t.Error("Should return nil for corrupt compressed session")
+	}
+}
+
 func TestExtractSessionFromBodyNoSession(t *testing.T) {
 	body := "Just a regular PR description without any session"
 
@@ -62,7 +117,7 @@ func TestStripSessionMarker(t *testing.T) {
 
 	stripped := StripSessionMarker(body)
 
-	if strings.Contains(stripped, SessionMarker) {
+	if strings.Contains(stripped, SessionMarker()) {
 		t.Error("Session marker should be stripped")
 	}
 
@@ -75,6 +130,32 @@ func TestStripSessionMarker(t *testing.T) {
 	}
 }
 
+func TestReplaceSessionMarker(t *testing.T) {
+	manager := NewSessionManager("owner/repo", 123)
+	session := manager.GetOrCreateSession("")
+	oldMarker := CreateSessionMarker(session)
+
+	session.DismissIssue("hash1", "", "not relevant")
+	body := "Description\n<!-- ai-review-start -->\n" + oldMarker + "\n<!-- ai-review-end -->"
+
+	replaced := ReplaceSessionMarker(body, session)
+	expected := "Description\n<!-- ai-review-start -->\n" + CreateSessionMarker(session) + "\n<!-- ai-review-end -->"
+	if replaced != expected {
+		t.Errorf("Expected marker replaced in place, got %q", replaced)
+	}
+	if !ExtractSessionFromBody(replaced).IsDismissed("hash1", "") {
+		t.Error("Expected the new session to be stored")
+	}
+
+	appended := ReplaceSessionMarker("Description\n", session)
+	if appended != "Description\n\n"+CreateSessionMarker(session) {
+		t.Errorf("Expected marker appended to a body without one, got %q", appended)
+	}
+	if ReplaceSessionMarker("", session) != CreateSessionMarker(session) {
+		t.Error("Expected only the marker for an empty body")
+	}
+}
+
 func TestSessionDismissedIssues(t *testing.T) {
 	session := &Session{
 		PRNumber:   123,
@@ -83,23 +164,78 @@ func TestSessionDismissedIssues(t *testing.T) {
 
 	hash := ComputeCommentHash("file.go", 10, 15, "some issue content")
 
-	if session.IsDismissed(hash) {
+	if session.IsDismissed(hash, "") {
 		t.Error("Issue should not be dismissed initially")
 	}
 
-	session.DismissIssue(hash, "false positive")
+	session.DismissIssue(hash, "", "false positive")
 
-	if !session.IsDismissed(hash) {
+	if !session.IsDismissed(hash, "") {
 		t.Error("Issue should be dismissed after dismissal")
 	}
 
 	// Dismiss same issue again - should not duplicate
-	session.DismissIssue(hash, "another reason")
+	session.DismissIssue(hash, "", "another reason")
 	if len(session.Dismissed) != 1 {
 		t.Errorf("Should not duplicate dismissed issues: got %d, want 1", len(session.Dismissed))
 	}
 }
 
+func TestSessionIsDismissed_LineShift(t *testing.T) {
+	session := &Session{PRNumber: 123, Repository: "owner/repo"}
+	content := "Possible nil dereference of user"
+	session.DismissIssue(ComputeCommentHash("file.go", 10, 12, content), ComputeFuzzyHash("file.go", content), "false positive")
+
+	// Lines added above the issue shift it, and the LLM rewraps the same text
+	shifted := ComputeCommentHash("file.go", 14, 16, "Possible nil  dereference\nof user")
+	if !session.IsDismissed(shifted, ComputeFuzzyHash("file.go", "Possible nil  dereference\nof user")) {
+		t.Error("Expected a line-shifted reoccurrence to stay dismissed")
+	}
+	if session.IsDismissed(shifted, "") {
+		t.Error("Expected the exact hash alone not to match after the shift")
+	}
+	if session.IsDismissed(shifted, ComputeFuzzyHash("other.go", content)) {
+		t.Error("Expected the same text in another file not to be dismissed")
+	}
+
+	session.MarkStale(time.Hour, session.UpdatedAt.Add(2*time.Hour))
+	if session.IsDismissed(shifted, ComputeFuzzyHash("file.go", content)) {
+		t.Error("Expected stale dismissals not to match by fuzzy hash either")
+	}
+}
+
+func TestSessionMarkStale(t *testing.T) {
+	session := &Session{PRNumber: 123, Repository: "owner/repo"}
+	session.DismissIssue("hash1", "", "false positive")
+	updated := session.UpdatedAt
+
+	if session.MarkStale(0, updated.Add(365*24*time.Hour)) {
+		t.Error("Max age 0 should never mark the session stale")
+	}
+	if session.MarkStale(24*time.Hour, updated.Add(time.Hour)) {
+		t.Error("Recently updated session should not be stale")
+	}
+	if !session.IsDismissed("hash1", "") {
+		t.Fatal("Dismissal should still apply to a fresh session")
+	}
+
+	if !session.MarkStale(24*time.Hour, updated.Add(48*time.Hour)) {
+		t.Fatal("Session older than max age should be stale")
+	}
+	if len(session.Dismissed) != 1 || !session.Dismissed[0].Stale {
+		t.Fatalf("Expected dismissal kept and flagged stale, got %+v", session.Dismissed)
+	}
+	if session.IsDismissed("hash1", "") {
+		t.Error("Stale dismissal should not filter comments")
+	}
+
+	// Dismissing the re-surfaced issue again revives the entry
+	session.DismissIssue("hash1", "", "still a false positive")
+	if len(session.Dismissed) != 1 || !session.IsDismissed("hash1", "") {
+		t.Errorf("Expected revived dismissal, got %+v", session.Dismissed)
+	}
+}
+
 func TestSessionPreviousCommentHashes(t *testing.T) {
 	session := &Session{
 		PRNumber:   123,
@@ -140,6 +276,62 @@ func TestSessionAddressed(t *testing.T) {
 	}
 }
 
+func TestSessionResolveIssue_SurvivesTrim(t *testing.T) {
+	session := &Session{PRNumber: 123, Repository: "owner/repo"}
+	session.ResolveIssue("hash1", "fuzzy1") // Resolved before any review was recorded
+	session.ResolveIssue("hash1", "fuzzy1")
+	for i := 0; i < 12; i++ {
+		session.AddReviewRecord(fmt.Sprintf("sha%d", i), nil, 80, 0)
+	}
+	session.TrimSession(10)
+
+	if !session.WasAddressed("hash1") || len(session.Resolved) != 1 || len(session.ResolvedFuzzy) != 1 {
+		t.Errorf("Expected the resolution to be kept once across trims, got %v", session.Resolved)
+	}
+}
+
+func TestSessionIsResolved_Fuzzy(t *testing.T) {
+	session := &Session{PRNumber: 123, Repository: "owner/repo"}
+	session.ResolveIssue("hash1", "fuzzy1")
+
+	if !session.IsResolved("hash1", "") || !session.IsResolved("moved", "fuzzy1") {
+		t.Error("Expected the issue to stay resolved by its exact or fuzzy hash")
+	}
+	if session.IsResolved("hash2", "fuzzy2") || session.IsResolved("hash2", "") {
+		t.Error("Expected other issues not to be resolved")
+	}
+}
+
+func TestSessionResolveChecklist(t *testing.T) {
+	session := &Session{PRNumber: 123, Repository: "owner/repo"}
+	session.AddReviewRecord("sha1", []string{"hash1", "hash2", "hash3"}, 40, 3)
+	session.AddChecklistItems([]ChecklistItem{
+		{Hash: "hash1", File: "a.go", Line: 10, Header: "🔴 Nil dereference"},
+		{Hash: "hash2", File: "a.go", Line: 20, Header: "🟡 Missing error check"},
+		{Hash: "hash3", File: "b.go", Line: 5, Header: "🟡 Unclosed file"},
+	})
+	session.AddChecklistItems([]ChecklistItem{{Hash: "hash1", File: "a.go", Line: 10, Header: "🔴 Nil dereference"}})
+	if len(session.Checklist) != 3 {
+		t.Fatalf("Expected duplicate checklist item to be skipped, got %d items", len(session.Checklist))
+	}
+
+	// a.go changed and only the missing error check was raised again; b.go is untouched
+	raised := []ChecklistItem{{Hash: "hash4", File: "a.go", Line: 22, Header: "🟡 Missing error check"}}
+	addressed := session.ResolveChecklist(map[string]bool{"a.go": true}, raised)
+
+	if len(addressed) != 1 || addressed[0] != "hash1" {
+		t.Fatalf("Expected only hash1 to be addressed, got %v", addressed)
+	}
+	if !session.WasAddressed("hash1") || session.WasAddressed("hash2") || session.WasAddressed("hash3") {
+		t.Error("Expected only hash1 to be marked as addressed")
+	}
+
+	// Already addressed items are not reported again
+	if addressed := session.ResolveChecklist(map[string]bool{"a.go": true}, raised); len(addressed) != 0 {
+		t.Errorf("Expected no newly addressed items, got %v", addressed)
+	}
+}
+
 func TestComputeCommentHash(t *testing.T) {
 	hash1 := ComputeCommentHash("file.go", 10, 15, "content")
 	hash2 := ComputeCommentHash("file.go", 10, 15, "content")
@@ -200,7 +392,7 @@ func TestSessionSummary(t *testing.T) {
 	// With reviews
 	session.AddReviewRecord("sha1", []string{"hash1", "hash2"}, 80, 2)
 	session.MarkAddressed([]string{"hash1"})
-	session.DismissIssue("hash3", "false positive")
+	session.DismissIssue("hash3", "", "false positive")
 
 	summary = session.GetSummary()
 	if !strings.Contains(summary, "1 previous review") {
@@ -213,3 +405,39 @@ func TestSessionSummary(t *testing.T) {
 		t.Error("Summary should mention dismissed issues")
 	}
 }
+
+func TestSessionMarker_CustomNamespace(t *testing.T) {
+	markers.SetNamespace("staging")
+	t.Cleanup(func() { markers.SetNamespace("") })
+
+	session := NewSessionManager("owner/repo", 1).GetOrCreateSession("")
+	session.DismissIssue("abc", "", "intended")
+	marker := CreateSessionMarker(session)
+	if !strings.HasPrefix(marker, "<!-- staging-session:") {
+		t.Fatalf("Expected a namespaced marker, got %s", marker)
+	}
+
+	// Another instance's session in the same body is left alone
+	body := "Description\n<!-- manque-ai-session:{\"pr_number\":1}-->\n" + marker
+	extracted := ExtractSessionFromBody(body)
+	if extracted == nil || !extracted.IsDismissed("abc", "") {
+		t.Fatalf("Expected the namespaced session to round-trip, got %+v", extracted)
+	}
+	if stripped := StripSessionMarker(body); !strings.Contains(stripped, "<!-- manque-ai-session:") || strings.Contains(stripped, marker) {
+		t.Errorf("Expected only the namespaced marker to be stripped, got %q", stripped)
+	}
+}
+
+func TestSessionMarker_ReadsLegacyMarker(t *testing.T) {
+	session := NewSessionManager("owner/repo", 1).GetOrCreateSession("")
+	session.DismissIssue("abc", "", "intended")
+	legacy := strings.Replace(CreateSessionMarker(session), SessionMarker(), "<!-- manque-session:", 1)
+
+	extracted := ExtractSessionFromBody("Description\n" + legacy)
+	if extracted == nil || !extracted.IsDismissed("abc", "") {
+		t.Fatalf("Expected the legacy session marker to be read, got %+v", extracted)
+	}
+	if replaced := ReplaceSessionMarker("Description\n"+legacy, session); strings.Contains(replaced, "manque-session:") {
+		t.Errorf("Expected the legacy marker to be replaced, got %q", replaced)
+	}
+}
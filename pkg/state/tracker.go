@@ -1,11 +1,14 @@
 package state
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/igcodinap/manque-ai/pkg/markers"
 )
 
 // ReviewState represents the state of a PR review
@@ -17,8 +20,13 @@ type ReviewState struct {
 	CommitCount     int       `json:"commit_count"`
 }
 
-// PRStateMarker is the HTML comment marker used to store state in PR body
-const PRStateMarker = "<!-- manque-state:"
+// legacyPRStateMarker stored review state before markers were namespaced
+const legacyPRStateMarker = "<!-- manque-state:"
+
+// PRStateMarker returns the HTML comment marker used to store state in PR body
+func PRStateMarker() string {
+	return markers.Data("state")
+}
 
 // Tracker manages incremental review state
 type Tracker struct {
@@ -37,13 +45,13 @@ func NewTracker(repository string, prNumber int) *Tracker {
 // ExtractStateFromBody extracts the review state from a PR body
 func ExtractStateFromBody(body string) *ReviewState {
 	// Find the state marker - use a non-greedy match for the JSON content
-	startIdx := strings.Index(body, PRStateMarker)
+	startIdx, marker := markers.Index(body, PRStateMarker(), legacyPRStateMarker)
 	if startIdx == -1 {
 		return nil
 	}
 
 	// Find the closing -->
-	jsonStart := startIdx + len(PRStateMarker)
+	jsonStart := startIdx + len(marker)
 	endIdx := strings.Index(body[jsonStart:], "-->")
 	if endIdx == -1 {
 		return nil
@@ -65,12 +73,12 @@ func CreateStateMarker(state *ReviewState) string {
 	if err != nil {
 		return ""
 	}
-	return fmt.Sprintf("%s%s-->", PRStateMarker, string(data))
+	return fmt.Sprintf("%s%s-->", PRStateMarker(), string(data))
 }
 
 // StripStateMarker removes the state marker from a PR body
 func StripStateMarker(body string) string {
-	startIdx := strings.Index(body, PRStateMarker)
+	startIdx, _ := markers.Index(body, PRStateMarker(), legacyPRStateMarker)
 	if startIdx == -1 {
 		return body
 	}
@@ -91,8 +99,8 @@ func StripStateMarker(body string) string {
 }
 
 // GetCommitRange gets the commits between two SHAs
-func GetCommitRange(baseSHA, headSHA string) ([]string, error) {
-	cmd := exec.Command("git", "log", "--oneline", fmt.Sprintf("%s..%s", baseSHA, headSHA))
+func GetCommitRange(ctx context.Context, baseSHA, headSHA string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "--oneline", fmt.Sprintf("%s..%s", baseSHA, headSHA))
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit range: %w", err)
@@ -107,8 +115,8 @@ func GetCommitRange(baseSHA, headSHA string) ([]string, error) {
 }
 
 // GetIncrementalDiff gets the diff between the last reviewed commit and current HEAD
-func GetIncrementalDiff(lastReviewedSHA, currentSHA string) (string, error) {
-	cmd := exec.Command("git", "diff", lastReviewedSHA, currentSHA)
+func GetIncrementalDiff(ctx context.Context, lastReviewedSHA, currentSHA string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", lastReviewedSHA, currentSHA)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get incremental diff: %w", err)
@@ -117,6 +125,18 @@ func GetIncrementalDiff(lastReviewedSHA, currentSHA string) (string, error) {
 	return string(output), nil
 }
 
+// GetLocalPRDiff reconstructs a PR diff from the local checkout, comparing the head
+// against its merge base with the PR base. git is killed when ctx is done.
+func GetLocalPRDiff(ctx context.Context, baseSHA, headSHA string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", baseSHA+"..."+headSHA)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get local PR diff: %w", err)
+	}
+
+	return string(output), nil
+}
+
 // IsIncrementalReview determines if this is an incremental review
 func (t *Tracker) IsIncrementalReview(prBody, currentSHA string) (bool, *ReviewState) {
 	state := ExtractStateFromBody(prBody)
@@ -149,9 +169,9 @@ func (t *Tracker) CreateNewState(currentSHA string, commitCount int) *ReviewStat
 }
 
 // CountCommits counts the number of commits in a PR
-func CountCommits(baseBranch, headSHA string) (int, error) {
+func CountCommits(ctx context.Context, baseBranch, headSHA string) (int, error) {
 	// Get merge base
-	mergeBaseCmd := exec.Command("git", "merge-base", baseBranch, headSHA)
+	mergeBaseCmd := exec.CommandContext(ctx, "git", "merge-base", baseBranch, headSHA)
 	mergeBaseOut, err := mergeBaseCmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("failed to find merge base: %w", err)
@@ -159,7 +179,7 @@ func CountCommits(baseBranch, headSHA string) (int, error) {
 	mergeBase := strings.TrimSpace(string(mergeBaseOut))
 
 	// Count commits
-	countCmd := exec.Command("git", "rev-list", "--count", fmt.Sprintf("%s..%s", mergeBase, headSHA))
+	countCmd := exec.CommandContext(ctx, "git", "rev-list", "--count", fmt.Sprintf("%s..%s", mergeBase, headSHA))
 	countOut, err := countCmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("failed to count commits: %w", err)
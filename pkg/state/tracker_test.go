@@ -57,7 +57,7 @@ func TestStripStateMarker(t *testing.T) {
 
 	stripped := StripStateMarker(body)
 
-	if strings.Contains(stripped, PRStateMarker) {
+	if strings.Contains(stripped, PRStateMarker()) {
 		t.Error("State marker should be stripped")
 	}
 
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -97,6 +98,7 @@ Third summary (more duplicates!)`
 	fmt.Println("\n=== Test 4: LLM PR Summary Generation ===")
 	formattedDiff := diff.FormatForLLM(files)
 	summary, err := client.GeneratePRSummary(
+		context.Background(),
 		"Fix license text and config loading",
 		"This PR corrects the license wording and improves config loading",
 		formattedDiff,
@@ -113,6 +115,7 @@ Third summary (more duplicates!)`
 	// Test 5: LLM Code Review
 	fmt.Println("\n=== Test 5: LLM Code Review Generation ===")
 	review, err := client.GenerateCodeReview(
+		context.Background(),
 		"Fix license text and config loading",
 		"This PR corrects the license wording and improves config loading",
 		formattedDiff,